@@ -32,10 +32,18 @@ type S3App struct {
 	usecase.FileUploader
 	// S3ObjectCopier is the usecase for copying a file in S3 bucket.
 	usecase.S3ObjectCopier
+	// S3BucketReporter is the usecase for analyzing objects in S3 bucket.
+	usecase.S3BucketReporter
+	// S3ObjectsFinder is the usecase for finding objects in S3 bucket.
+	usecase.S3ObjectsFinder
+	// S3BucketsFinder is the usecase for finding S3 buckets.
+	usecase.S3BucketsFinder
+	// S3BucketEmptier is the usecase for deleting all object versions in S3 bucket.
+	usecase.S3BucketEmptier
 }
 
 // NewS3App creates a new S3App.
-func NewS3App(ctx context.Context, profile model.AWSProfile, region model.Region) (*S3App, error) {
+func NewS3App(ctx context.Context, profile model.AWSProfile, region model.Region, opts *model.AWSConfigOptions) (*S3App, error) {
 	wire.Build(
 		model.NewAWSConfig,
 		external.NewS3Client,
@@ -49,6 +57,14 @@ func NewS3App(ctx context.Context, profile model.AWSProfile, region model.Region
 		external.S3ObjectUploaderSet,
 		external.S3ObjectCopierSet,
 		external.S3ObjectVersionsListerSet,
+		external.S3ObjectDetailsListerSet,
+		external.S3MultipartUploadsListerSet,
+		external.S3ObjectTagsGetterSet,
+		external.S3ObjectMetadataGetterSet,
+		external.S3BucketTagsGetterSet,
+		external.S3MultipartUploadAborterSet,
+		external.S3ObjectLockGetterSet,
+		external.S3ObjectLegalHoldReleaserSet,
 		interactor.S3BucketCreatorSet,
 		interactor.S3BucketListerSet,
 		interactor.S3BucketDeleterSet,
@@ -57,6 +73,10 @@ func NewS3App(ctx context.Context, profile model.AWSProfile, region model.Region
 		interactor.S3ObjectDownloaderSet,
 		interactor.FileUploaderSet,
 		interactor.S3ObjectCopierSet,
+		interactor.S3BucketReporterSet,
+		interactor.S3ObjectsFinderSet,
+		interactor.S3BucketsFinderSet,
+		interactor.S3BucketEmptierSet,
 		newS3App,
 	)
 	return nil, nil
@@ -72,6 +92,10 @@ func newS3App(
 	s3ObjectDownloader usecase.S3ObjectDownloader,
 	fileUploader usecase.FileUploader,
 	s3ObjectCopier usecase.S3ObjectCopier,
+	s3BucketReporter usecase.S3BucketReporter,
+	s3ObjectsFinder usecase.S3ObjectsFinder,
+	s3BucketsFinder usecase.S3BucketsFinder,
+	s3BucketEmptier usecase.S3BucketEmptier,
 ) *S3App {
 	return &S3App{
 		S3BucketCreator:    s3BucketCreator,
@@ -82,6 +106,10 @@ func newS3App(
 		S3ObjectDownloader: s3ObjectDownloader,
 		FileUploader:       fileUploader,
 		S3ObjectCopier:     s3ObjectCopier,
+		S3BucketReporter:   s3BucketReporter,
+		S3ObjectsFinder:    s3ObjectsFinder,
+		S3BucketsFinder:    s3BucketsFinder,
+		S3BucketEmptier:    s3BucketEmptier,
 	}
 }
 
@@ -100,7 +128,7 @@ type SpareApp struct {
 }
 
 // NewSpareApp creates a new SpareApp.
-func NewSpareApp(ctx context.Context, profile model.AWSProfile, region model.Region) (*SpareApp, error) {
+func NewSpareApp(ctx context.Context, profile model.AWSProfile, region model.Region, opts *model.AWSConfigOptions) (*SpareApp, error) {
 	wire.Build(
 		model.NewAWSConfig,
 		external.NewCloudFrontClient,
@@ -144,26 +172,113 @@ type CFnApp struct {
 	usecase.CFnStackLister
 	// CFnStackEventsDescriber is the usecase for describing CloudFormation stack events.
 	usecase.CFnStackEventsDescriber
+	// CFnStacksInRegionsLister is the usecase for listing CloudFormation stacks in multiple regions.
+	usecase.CFnStacksInRegionsLister
+	// CFnStackDescriber is the usecase for describing a CloudFormation stack.
+	usecase.CFnStackDescriber
+	// CFnStackDeleter is the usecase for deleting a CloudFormation stack.
+	usecase.CFnStackDeleter
+	// CFnStackTagger is the usecase for tagging a CloudFormation stack.
+	usecase.CFnStackTagger
+	// CFnStackDeployPlanner is the usecase for creating the change set of a CloudFormation stack.
+	usecase.CFnStackDeployPlanner
+	// CFnStackDeployCanceler is the usecase for deleting the change set that is not executed.
+	usecase.CFnStackDeployCanceler
+	// CFnStackDeployer is the usecase for executing the change set of a CloudFormation stack.
+	usecase.CFnStackDeployer
+	// CFnStackEventsTailer is the usecase for following CloudFormation stack events.
+	usecase.CFnStackEventsTailer
+	// CFnStackResourceLister is the usecase for listing the resources of a CloudFormation stack.
+	usecase.CFnStackResourceLister
+	// CFnStackDriftDetector is the usecase for detecting the drift of CloudFormation stacks.
+	usecase.CFnStackDriftDetector
+	// CFnExportsLister is the usecase for listing CloudFormation exports and their importers.
+	usecase.CFnExportsLister
+	// CFnTemplateGetter is the usecase for getting the deployed template of a CloudFormation stack.
+	usecase.CFnTemplateGetter
+	// CFnTemplateDiffer is the usecase for comparing the deployed template with the local template.
+	usecase.CFnTemplateDiffer
+	// CFnStaleStacksFinder is the usecase for finding the stale CloudFormation stacks to clean up.
+	usecase.CFnStaleStacksFinder
 }
 
 // NewCFnApp creates a new CFnApp.
-func NewCFnApp(ctx context.Context, profile model.AWSProfile, region model.Region) (*CFnApp, error) {
+func NewCFnApp(ctx context.Context, profile model.AWSProfile, region model.Region, opts *model.AWSConfigOptions) (*CFnApp, error) {
 	wire.Build(
 		model.NewAWSConfig,
 		external.NewCloudFormationClient,
 		external.CFnStackListerSet,
 		external.CFnStackEventsDescriberSet,
+		external.CFnStackDescriberSet,
+		external.CFnStackDeleterSet,
+		external.CFnStackTagsUpdaterSet,
+		external.CFnChangeSetCreatorSet,
+		external.CFnChangeSetDescriberSet,
+		external.CFnChangeSetExecutorSet,
+		external.CFnChangeSetDeleterSet,
+		external.CFnStackResourceListerSet,
+		external.CFnStackDriftDetectorSet,
+		external.CFnStackDriftDetectionStatusDescriberSet,
+		external.CFnStackResourceDriftsDescriberSet,
+		external.CFnExportsListerSet,
+		external.CFnImportsListerSet,
+		external.CFnTemplateGetterSet,
 		interactor.CFnStackListerSet,
 		interactor.CFnStackEventsDescriberSet,
+		interactor.CFnStacksInRegionsListerSet,
+		interactor.CFnStackDescriberSet,
+		interactor.CFnStackDeleterSet,
+		interactor.CFnStackTaggerSet,
+		interactor.CFnStackDeployPlannerSet,
+		interactor.CFnStackDeployCancelerSet,
+		interactor.CFnStackDeployerSet,
+		interactor.CFnStackEventsTailerSet,
+		interactor.CFnStackResourceListerSet,
+		interactor.CFnStackDriftDetectorSet,
+		interactor.CFnExportsListerSet,
+		interactor.CFnTemplateGetterSet,
+		interactor.CFnTemplateDifferSet,
+		interactor.CFnStaleStacksFinderSet,
 		newCFnApp,
 	)
 	return nil, nil
 }
 
 // newCFnApp creates a new CFnApp.
-func newCFnApp(cFnStackLister usecase.CFnStackLister, cFnStackEventsDecriber usecase.CFnStackEventsDescriber) *CFnApp {
+func newCFnApp(
+	cFnStackLister usecase.CFnStackLister,
+	cFnStackEventsDecriber usecase.CFnStackEventsDescriber,
+	cFnStacksInRegionsLister usecase.CFnStacksInRegionsLister,
+	cFnStackDescriber usecase.CFnStackDescriber,
+	cFnStackDeleter usecase.CFnStackDeleter,
+	cFnStackTagger usecase.CFnStackTagger,
+	cFnStackDeployPlanner usecase.CFnStackDeployPlanner,
+	cFnStackDeployCanceler usecase.CFnStackDeployCanceler,
+	cFnStackDeployer usecase.CFnStackDeployer,
+	cFnStackEventsTailer usecase.CFnStackEventsTailer,
+	cFnStackResourceLister usecase.CFnStackResourceLister,
+	cFnStackDriftDetector usecase.CFnStackDriftDetector,
+	cFnExportsLister usecase.CFnExportsLister,
+	cFnTemplateGetter usecase.CFnTemplateGetter,
+	cFnTemplateDiffer usecase.CFnTemplateDiffer,
+	cFnStaleStacksFinder usecase.CFnStaleStacksFinder,
+) *CFnApp {
 	return &CFnApp{
-		CFnStackLister:          cFnStackLister,
-		CFnStackEventsDescriber: cFnStackEventsDecriber,
+		CFnStackLister:           cFnStackLister,
+		CFnStackEventsDescriber:  cFnStackEventsDecriber,
+		CFnStacksInRegionsLister: cFnStacksInRegionsLister,
+		CFnStackDescriber:        cFnStackDescriber,
+		CFnStackDeleter:          cFnStackDeleter,
+		CFnStackTagger:           cFnStackTagger,
+		CFnStackDeployPlanner:    cFnStackDeployPlanner,
+		CFnStackDeployCanceler:   cFnStackDeployCanceler,
+		CFnStackDeployer:         cFnStackDeployer,
+		CFnStackEventsTailer:     cFnStackEventsTailer,
+		CFnStackResourceLister:   cFnStackResourceLister,
+		CFnStackDriftDetector:    cFnStackDriftDetector,
+		CFnExportsLister:         cFnExportsLister,
+		CFnTemplateGetter:        cFnTemplateGetter,
+		CFnTemplateDiffer:        cFnTemplateDiffer,
+		CFnStaleStacksFinder:     cFnStaleStacksFinder,
 	}
 }
@@ -17,8 +17,8 @@ import (
 // Injectors from wire.go:
 
 // NewS3App creates a new S3App.
-func NewS3App(ctx context.Context, profile model.AWSProfile, region model.Region) (*S3App, error) {
-	awsConfig, err := model.NewAWSConfig(ctx, profile, region)
+func NewS3App(ctx context.Context, profile model.AWSProfile, region model.Region, opts *model.AWSConfigOptions) (*S3App, error) {
+	awsConfig, err := model.NewAWSConfig(ctx, profile, region, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -41,13 +41,25 @@ func NewS3App(ctx context.Context, profile model.AWSProfile, region model.Region
 	fileUploader := interactor.NewFileUploader(s3ObjectUploader)
 	s3ObjectCopier := external.NewS3ObjectCopier(client)
 	interactorS3ObjectCopier := interactor.NewS3ObjectCopier(s3ObjectCopier)
-	s3App := newS3App(interactorS3BucketCreator, interactorS3BucketLister, interactorS3BucketDeleter, interactorS3ObjectsLister, interactorS3ObjectsDeleter, interactorS3ObjectDownloader, fileUploader, interactorS3ObjectCopier)
+	s3ObjectDetailsLister := external.NewS3ObjectDetailsLister(client)
+	s3MultipartUploadsLister := external.NewS3MultipartUploadsLister(client)
+	s3BucketReporter := interactor.NewS3BucketReporter(s3ObjectDetailsLister, s3ObjectVersionsLister, s3MultipartUploadsLister)
+	s3ObjectTagsGetter := external.NewS3ObjectTagsGetter(client)
+	s3ObjectMetadataGetter := external.NewS3ObjectMetadataGetter(client)
+	s3ObjectsFinder := interactor.NewS3ObjectsFinder(s3ObjectDetailsLister, s3ObjectTagsGetter, s3ObjectMetadataGetter)
+	s3BucketTagsGetter := external.NewS3BucketTagsGetter(client)
+	s3BucketsFinder := interactor.NewS3BucketsFinder(s3BucketLister, s3BucketLocationGetter, s3BucketTagsGetter)
+	s3MultipartUploadAborter := external.NewS3MultipartUploadAborter(client)
+	s3ObjectLockGetter := external.NewS3ObjectLockGetter(client)
+	s3ObjectLegalHoldReleaser := external.NewS3ObjectLegalHoldReleaser(client)
+	s3BucketEmptier := interactor.NewS3BucketEmptier(s3BucketLocationGetter, s3ObjectVersionsLister, s3ObjectsDeleter, s3MultipartUploadsLister, s3MultipartUploadAborter, s3ObjectLockGetter, s3ObjectLegalHoldReleaser)
+	s3App := newS3App(interactorS3BucketCreator, interactorS3BucketLister, interactorS3BucketDeleter, interactorS3ObjectsLister, interactorS3ObjectsDeleter, interactorS3ObjectDownloader, fileUploader, interactorS3ObjectCopier, s3BucketReporter, s3ObjectsFinder, s3BucketsFinder, s3BucketEmptier)
 	return s3App, nil
 }
 
 // NewSpareApp creates a new SpareApp.
-func NewSpareApp(ctx context.Context, profile model.AWSProfile, region model.Region) (*SpareApp, error) {
-	awsConfig, err := model.NewAWSConfig(ctx, profile, region)
+func NewSpareApp(ctx context.Context, profile model.AWSProfile, region model.Region, opts *model.AWSConfigOptions) (*SpareApp, error) {
+	awsConfig, err := model.NewAWSConfig(ctx, profile, region, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -73,17 +85,45 @@ func NewSpareApp(ctx context.Context, profile model.AWSProfile, region model.Reg
 }
 
 // NewCFnApp creates a new CFnApp.
-func NewCFnApp(ctx context.Context, profile model.AWSProfile, region model.Region) (*CFnApp, error) {
-	awsConfig, err := model.NewAWSConfig(ctx, profile, region)
+func NewCFnApp(ctx context.Context, profile model.AWSProfile, region model.Region, opts *model.AWSConfigOptions) (*CFnApp, error) {
+	awsConfig, err := model.NewAWSConfig(ctx, profile, region, opts)
 	if err != nil {
 		return nil, err
 	}
 	client := external.NewCloudFormationClient(awsConfig)
 	cFnStackLister := external.NewCFnStackLister(client)
-	interactorCFnStackLister := interactor.NewCFnStackLister(cFnStackLister)
+	cFnStackDescriber := external.NewCFnStackDescriber(client)
+	interactorCFnStackLister := interactor.NewCFnStackLister(cFnStackLister, cFnStackDescriber)
 	cFnStackEventsDescriber := external.NewCFnStackEventsDescriber(client)
 	interactorCFnStackEventsDescriber := interactor.NewCFnStackEventsDescriber(cFnStackEventsDescriber)
-	cFnApp := newCFnApp(interactorCFnStackLister, interactorCFnStackEventsDescriber)
+	cFnStacksInRegionsLister := interactor.NewCFnStacksInRegionsLister(cFnStackLister, cFnStackDescriber)
+	interactorCFnStackDescriber := interactor.NewCFnStackDescriber(cFnStackDescriber)
+	cFnStackDeleter := external.NewCFnStackDeleter(client)
+	cFnImportsLister := external.NewCFnImportsLister(client)
+	interactorCFnStackDeleter := interactor.NewCFnStackDeleter(cFnStackDescriber, cFnStackDeleter, cFnStackEventsDescriber, cFnImportsLister)
+	cFnStackTagsUpdater := external.NewCFnStackTagsUpdater(client)
+	cFnStackTagger := interactor.NewCFnStackTagger(cFnStackDescriber, cFnStackTagsUpdater, cFnStackEventsDescriber)
+	cFnChangeSetCreator := external.NewCFnChangeSetCreator(client)
+	cFnChangeSetDescriber := external.NewCFnChangeSetDescriber(client)
+	cFnChangeSetDeleter := external.NewCFnChangeSetDeleter(client)
+	cFnStackDeployPlanner := interactor.NewCFnStackDeployPlanner(cFnStackDescriber, cFnChangeSetCreator, cFnChangeSetDescriber, cFnChangeSetDeleter, cFnStackDeleter)
+	cFnStackDeployCanceler := interactor.NewCFnStackDeployCanceler(cFnChangeSetDeleter, cFnStackDeleter)
+	cFnChangeSetExecutor := external.NewCFnChangeSetExecutor(client)
+	cFnStackDeployer := interactor.NewCFnStackDeployer(cFnStackDescriber, cFnChangeSetExecutor, cFnStackEventsDescriber)
+	cFnStackEventsTailer := interactor.NewCFnStackEventsTailer(cFnStackDescriber, cFnStackEventsDescriber)
+	cFnStackResourceLister := external.NewCFnStackResourceLister(client)
+	interactorCFnStackResourceLister := interactor.NewCFnStackResourceLister(cFnStackDescriber, cFnStackLister, cFnStackResourceLister)
+	cFnStackDriftDetector := external.NewCFnStackDriftDetector(client)
+	cFnStackDriftDetectionStatusDescriber := external.NewCFnStackDriftDetectionStatusDescriber(client)
+	cFnStackResourceDriftsDescriber := external.NewCFnStackResourceDriftsDescriber(client)
+	interactorCFnStackDriftDetector := interactor.NewCFnStackDriftDetector(cFnStackDriftDetector, cFnStackDriftDetectionStatusDescriber, cFnStackResourceDriftsDescriber)
+	cFnExportsLister := external.NewCFnExportsLister(client)
+	interactorCFnExportsLister := interactor.NewCFnExportsLister(cFnExportsLister, cFnImportsLister)
+	cFnTemplateGetter := external.NewCFnTemplateGetter(client)
+	interactorCFnTemplateGetter := interactor.NewCFnTemplateGetter(cFnTemplateGetter)
+	cFnTemplateDiffer := interactor.NewCFnTemplateDiffer(cFnTemplateGetter)
+	cFnStaleStacksFinder := interactor.NewCFnStaleStacksFinder(cFnStackLister, cFnStackDescriber, cFnImportsLister)
+	cFnApp := newCFnApp(interactorCFnStackLister, interactorCFnStackEventsDescriber, cFnStacksInRegionsLister, interactorCFnStackDescriber, interactorCFnStackDeleter, cFnStackTagger, cFnStackDeployPlanner, cFnStackDeployCanceler, cFnStackDeployer, cFnStackEventsTailer, interactorCFnStackResourceLister, interactorCFnStackDriftDetector, interactorCFnExportsLister, interactorCFnTemplateGetter, cFnTemplateDiffer, cFnStaleStacksFinder)
 	return cFnApp, nil
 }
 
@@ -111,10 +151,22 @@ type S3App struct {
 	// S3ObjectUploader is the usecase for uploading a file to S3 bucket.
 	usecase.FileUploader
 	usecase.S3ObjectCopier
+	usecase.S3BucketReporter
+	usecase.S3ObjectsFinder
+	usecase.S3BucketsFinder
+	usecase.S3BucketEmptier
 	// FileUploader is the usecase for uploading a file.
 
 	// S3ObjectCopier is the usecase for copying a file in S3 bucket.
 
+	// S3BucketReporter is the usecase for analyzing objects in S3 bucket.
+
+	// S3ObjectsFinder is the usecase for finding objects in S3 bucket.
+
+	// S3BucketsFinder is the usecase for finding S3 buckets.
+
+	// S3BucketEmptier is the usecase for deleting all object versions in S3 bucket.
+
 }
 
 // newS3App creates a new S3App.
@@ -127,6 +179,10 @@ func newS3App(
 	s3ObjectDownloader usecase.S3ObjectDownloader,
 	fileUploader usecase.FileUploader,
 	s3ObjectCopier usecase.S3ObjectCopier,
+	s3BucketReporter usecase.S3BucketReporter,
+	s3ObjectsFinder usecase.S3ObjectsFinder,
+	s3BucketsFinder usecase.S3BucketsFinder,
+	s3BucketEmptier usecase.S3BucketEmptier,
 ) *S3App {
 	return &S3App{
 		S3BucketCreator:    s3BucketCreator,
@@ -137,6 +193,10 @@ func newS3App(
 		S3ObjectDownloader: s3ObjectDownloader,
 		FileUploader:       fileUploader,
 		S3ObjectCopier:     s3ObjectCopier,
+		S3BucketReporter:   s3BucketReporter,
+		S3ObjectsFinder:    s3ObjectsFinder,
+		S3BucketsFinder:    s3BucketsFinder,
+		S3BucketEmptier:    s3BucketEmptier,
 	}
 }
 
@@ -182,15 +242,88 @@ type CFnApp struct {
 		// CFnStackLister is the usecase for listing CloudFormation stacks.
 		CFnStackLister
 	usecase.CFnStackEventsDescriber
+	usecase.CFnStacksInRegionsLister
+	usecase.CFnStackDescriber
+	usecase.CFnStackDeleter
+	usecase.CFnStackTagger
+	usecase.CFnStackDeployPlanner
+	usecase.CFnStackDeployCanceler
+	usecase.CFnStackDeployer
+	usecase.CFnStackEventsTailer
+	usecase.CFnStackResourceLister
+	usecase.CFnStackDriftDetector
+	usecase.CFnExportsLister
+	usecase.CFnTemplateGetter
+	usecase.CFnTemplateDiffer
+	usecase.CFnStaleStacksFinder
 
 	// CFnStackEventsDescriber is the usecase for describing CloudFormation stack events.
 
+	// CFnStacksInRegionsLister is the usecase for listing CloudFormation stacks in multiple regions.
+
+	// CFnStackDescriber is the usecase for describing a CloudFormation stack.
+
+	// CFnStackDeleter is the usecase for deleting a CloudFormation stack.
+
+	// CFnStackTagger is the usecase for tagging a CloudFormation stack.
+
+	// CFnStackDeployPlanner is the usecase for creating the change set of a CloudFormation stack.
+
+	// CFnStackDeployCanceler is the usecase for deleting the change set that is not executed.
+
+	// CFnStackDeployer is the usecase for executing the change set of a CloudFormation stack.
+
+	// CFnStackEventsTailer is the usecase for following CloudFormation stack events.
+
+	// CFnStackResourceLister is the usecase for listing the resources of a CloudFormation stack.
+
+	// CFnStackDriftDetector is the usecase for detecting the drift of CloudFormation stacks.
+
+	// CFnExportsLister is the usecase for listing CloudFormation exports and their importers.
+
+	// CFnTemplateGetter is the usecase for getting the deployed template of a CloudFormation stack.
+
+	// CFnTemplateDiffer is the usecase for comparing the deployed template with the local template.
+
+	// CFnStaleStacksFinder is the usecase for finding the stale CloudFormation stacks to clean up.
+
 }
 
 // newCFnApp creates a new CFnApp.
-func newCFnApp(cFnStackLister usecase.CFnStackLister, cFnStackEventsDecriber usecase.CFnStackEventsDescriber) *CFnApp {
+func newCFnApp(
+	cFnStackLister usecase.CFnStackLister,
+	cFnStackEventsDecriber usecase.CFnStackEventsDescriber,
+	cFnStacksInRegionsLister usecase.CFnStacksInRegionsLister,
+	cFnStackDescriber usecase.CFnStackDescriber,
+	cFnStackDeleter usecase.CFnStackDeleter,
+	cFnStackTagger usecase.CFnStackTagger,
+	cFnStackDeployPlanner usecase.CFnStackDeployPlanner,
+	cFnStackDeployCanceler usecase.CFnStackDeployCanceler,
+	cFnStackDeployer usecase.CFnStackDeployer,
+	cFnStackEventsTailer usecase.CFnStackEventsTailer,
+	cFnStackResourceLister usecase.CFnStackResourceLister,
+	cFnStackDriftDetector usecase.CFnStackDriftDetector,
+	cFnExportsLister usecase.CFnExportsLister,
+	cFnTemplateGetter usecase.CFnTemplateGetter,
+	cFnTemplateDiffer usecase.CFnTemplateDiffer,
+	cFnStaleStacksFinder usecase.CFnStaleStacksFinder,
+) *CFnApp {
 	return &CFnApp{
-		CFnStackLister:          cFnStackLister,
-		CFnStackEventsDescriber: cFnStackEventsDecriber,
+		CFnStackLister:           cFnStackLister,
+		CFnStackEventsDescriber:  cFnStackEventsDecriber,
+		CFnStacksInRegionsLister: cFnStacksInRegionsLister,
+		CFnStackDescriber:        cFnStackDescriber,
+		CFnStackDeleter:          cFnStackDeleter,
+		CFnStackTagger:           cFnStackTagger,
+		CFnStackDeployPlanner:    cFnStackDeployPlanner,
+		CFnStackDeployCanceler:   cFnStackDeployCanceler,
+		CFnStackDeployer:         cFnStackDeployer,
+		CFnStackEventsTailer:     cFnStackEventsTailer,
+		CFnStackResourceLister:   cFnStackResourceLister,
+		CFnStackDriftDetector:    cFnStackDriftDetector,
+		CFnExportsLister:         cFnExportsLister,
+		CFnTemplateGetter:        cFnTemplateGetter,
+		CFnTemplateDiffer:        cFnTemplateDiffer,
+		CFnStaleStacksFinder:     cFnStaleStacksFinder,
 	}
 }
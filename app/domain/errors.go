@@ -32,4 +32,34 @@ var (
 	ErrOriginAccessIdentifyAlreadyExists = errors.New("origin access identify already exists")
 	// ErrFileUpload is an error that occurs when the file upload fails.
 	ErrFileUpload = errors.New("failed to upload file")
+	// ErrS3ObjectsDelete is an error that occurs when some objects can not be deleted.
+	ErrS3ObjectsDelete = errors.New("failed to delete some objects")
+	// ErrSSOSessionExpired is an error that occurs when the IAM Identity Center (SSO) session has expired.
+	ErrSSOSessionExpired = errors.New("the SSO session has expired or you have not logged in")
+	// ErrRegionNotEnabled is an error that occurs when the account is not opted in to the region.
+	ErrRegionNotEnabled = errors.New("the region is not enabled for the account")
+	// ErrCFnStackNotFound is an error that occurs when the CloudFormation stack does not exist.
+	ErrCFnStackNotFound = errors.New("the stack does not exist")
+	// ErrCFnStackTerminationProtection is an error that occurs when the termination protection of the stack is enabled.
+	ErrCFnStackTerminationProtection = errors.New("termination protection is enabled for the stack")
+	// ErrCFnStackDeleteFailed is an error that occurs when the stack deletion fails.
+	ErrCFnStackDeleteFailed = errors.New("failed to delete the stack")
+	// ErrCFnStackUpdateFailed is an error that occurs when the stack update fails.
+	ErrCFnStackUpdateFailed = errors.New("failed to update the stack")
+	// ErrCFnDriftDetectionFailed is an error that occurs when the drift detection of the stack fails.
+	ErrCFnDriftDetectionFailed = errors.New("failed to detect the drift of the stack")
+	// ErrCFnStackNoUpdates is an error that occurs when the stack update does not change anything.
+	ErrCFnStackNoUpdates = errors.New("no updates are to be performed")
+	// ErrCFnChangeSetFailed is an error that occurs when the change set can not be created.
+	ErrCFnChangeSetFailed = errors.New("failed to create the change set")
+	// ErrCFnStackNotUpdatable is an error that occurs when the stack is in the status that can not be updated.
+	ErrCFnStackNotUpdatable = errors.New("the stack can not be updated")
+	// ErrCFnStackDeployFailed is an error that occurs when the stack creation or update fails.
+	ErrCFnStackDeployFailed = errors.New("failed to deploy the stack")
+	// ErrCFnTemplateTooLarge is an error that occurs when the template body exceeds the size limit.
+	ErrCFnTemplateTooLarge = errors.New("the template is too large")
+	// ErrCFnStackExportsImported is an error that occurs when the exports of the stack are imported by other stacks.
+	ErrCFnStackExportsImported = errors.New("the exports of the stack are imported by other stacks")
+	// ErrCFnOutputNotFound is an error that occurs when the stack does not have the output.
+	ErrCFnOutputNotFound = errors.New("the output does not exist")
 )
@@ -1,11 +1,21 @@
 package model
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 )
 
 // AWSProfile is the name of the AWS profile.
@@ -29,28 +39,121 @@ func (p AWSProfile) String() string {
 	return string(p)
 }
 
+// AWSConfigOptions is the options for the AWS config: the endpoint of the S3-compatible storage
+// (MinIO, Ceph, Cloudflare R2, etc.), debug mode and the role to assume.
+// Zero values mean that the settings in the shared config profile are used.
+type AWSConfigOptions struct {
+	// EndpointURL is the URL of the endpoint. e.g. "http://localhost:9000"
+	EndpointURL string
+	// PathStyle is whether to use the path-style addressing (http://endpoint/BUCKET/KEY).
+	PathStyle bool
+	// NoVerifySSL is whether to skip the verification of the TLS certificate.
+	NoVerifySSL bool
+	// Debug is whether to run debug mode. In debug mode, all requests are sent to the local endpoint
+	// (DebugLocalstackEndpoint if EndpointURL is empty) with the dummy credentials, and the shared config is not used.
+	Debug bool
+	// RoleARN is the ARN of the role to assume with the credentials of the profile. If it is empty, no role is assumed.
+	RoleARN string
+	// ExternalID is the external ID required by the trust policy of the role.
+	ExternalID string
+	// MFASerial is the serial number (or ARN) of the MFA device.
+	MFASerial string
+	// MFATokenProvider returns the MFA token code. It is used for MFASerial and mfa_serial in the profile.
+	MFATokenProvider func() (string, error)
+	// Duration is the duration of the assumed role session. 0 means the SDK default (15 minutes).
+	Duration time.Duration
+	// RoleSessionName is the name of the assumed role session.
+	RoleSessionName string
+	// Retry is the retry policy of all AWS clients. nil means DefaultRetryPolicy.
+	Retry *RetryPolicy
+	// Logger logs the API calls of all AWS clients. nil disables the API tracing.
+	Logger APILogger
+}
+
+// Endpoint returns the endpoint URL. In debug mode, it returns DebugLocalstackEndpoint if EndpointURL is empty.
+func (o *AWSConfigOptions) Endpoint() string {
+	if o.Debug && o.EndpointURL == "" {
+		return DebugLocalstackEndpoint
+	}
+	return o.EndpointURL
+}
+
+// debugCredentials is the dummy credentials for debug mode. Localstack accepts any credentials.
+var debugCredentials = aws.CredentialsProviderFunc(func(_ context.Context) (aws.Credentials, error) { //nolint:gochecknoglobals
+	return aws.Credentials{
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Source:          "rainbow debug mode",
+	}, nil
+})
+
 // AWSConfig is the AWS config.
 type AWSConfig struct {
 	*aws.Config
+	// S3UsePathStyle is whether the S3 client uses the path-style addressing.
+	S3UsePathStyle bool
+	// RetryPolicy is the retry policy of all AWS clients.
+	RetryPolicy *RetryPolicy
 }
 
 // NewAWSConfig creates a new AWS config.
-func NewAWSConfig(ctx context.Context, profile AWSProfile, region Region) (*AWSConfig, error) {
-	opts := []func(*config.LoadOptions) error{}
-	if profile.String() != "" {
-		opts = append(opts, config.WithSharedConfigProfile(profile.String()))
+// The options take precedence over the settings in the shared config profile. opts may be nil.
+func NewAWSConfig(ctx context.Context, profile AWSProfile, region Region, opts *AWSConfigOptions) (*AWSConfig, error) {
+	if opts == nil {
+		opts = &AWSConfigOptions{}
+	}
+
+	loadOpts := []func(*config.LoadOptions) error{}
+	settings := &AWSConfigOptions{}
+	if opts.Debug {
+		if region.String() == "" {
+			region = RegionUSEast1
+		}
+		loadOpts = append(loadOpts,
+			config.WithSharedConfigFiles([]string{}),
+			config.WithSharedCredentialsFiles([]string{}),
+			config.WithCredentialsProvider(debugCredentials),
+		)
+		settings.PathStyle = true // localstack and MinIO do not resolve the virtual hosted-style by default.
+	} else {
+		var err error
+		if settings, err = loadS3CompatibleSettings(profile); err != nil {
+			return nil, err
+		}
+		if profile.String() != "" {
+			loadOpts = append(loadOpts, config.WithSharedConfigProfile(profile.String()))
+		}
 	}
+	pathStyle := opts.PathStyle || settings.PathStyle
+	noVerifySSL := opts.NoVerifySSL || settings.NoVerifySSL
+
 	if region.String() != "" {
-		opts = append(opts, config.WithRegion(region.String()))
+		loadOpts = append(loadOpts, config.WithRegion(region.String()))
+	}
+	if opts.Endpoint() != "" {
+		loadOpts = append(loadOpts, config.WithBaseEndpoint(opts.Endpoint()))
+	}
+	if opts.MFATokenProvider != nil {
+		loadOpts = append(loadOpts, config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+			o.TokenProvider = opts.MFATokenProvider
+		}))
+	}
+	if noVerifySSL {
+		loadOpts = append(loadOpts, config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			if tr.TLSClientConfig == nil {
+				tr.TLSClientConfig = &tls.Config{} //nolint:gosec
+			}
+			tr.TLSClientConfig.InsecureSkipVerify = true //nolint:gosec
+		})))
 	}
 
-	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	if cfg.BaseEndpoint != nil {
-		opts = append(opts, config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(func(service, region string, opts ...interface{}) (aws.Endpoint, error) {
+		loadOpts = append(loadOpts, config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(func(service, region string, opts ...interface{}) (aws.Endpoint, error) {
 			return aws.Endpoint{
 				PartitionID:       "aws",
 				URL:               *cfg.BaseEndpoint,
@@ -58,17 +161,109 @@ func NewAWSConfig(ctx context.Context, profile AWSProfile, region Region) (*AWSC
 			}, nil
 		})))
 
-		cfg, err = config.LoadDefaultConfig(ctx, opts...)
+		cfg, err = config.LoadDefaultConfig(ctx, loadOpts...)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if opts.Logger != nil {
+		withAPITrace(&cfg, opts.Logger)
+	}
+	if opts.RoleARN != "" {
+		if cfg.Credentials, err = newAssumeRoleCredentials(cfg, profile, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	retryPolicy := opts.Retry
+	if retryPolicy == nil {
+		retryPolicy = DefaultRetryPolicy()
+	}
+	if err := retryPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &AWSConfig{
-		Config: &cfg,
+		Config:         &cfg,
+		S3UsePathStyle: pathStyle,
+		RetryPolicy:    retryPolicy,
 	}, nil
 }
 
+// loadS3CompatibleSettings reads the settings for the S3-compatible storage from the shared config profile.
+// The AWS SDK does not support these settings, so rainbow reads the shared config file by itself.
+// If the shared config file does not exist, it returns the zero value.
+func loadS3CompatibleSettings(profile AWSProfile) (*AWSConfigOptions, error) {
+	name := os.Getenv("AWS_CONFIG_FILE")
+	if name == "" {
+		name = config.DefaultSharedConfigFilename()
+	}
+	f, err := os.Open(name) //nolint:gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &AWSConfigOptions{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	return parseS3CompatibleSettings(f, profile)
+}
+
+// parseS3CompatibleSettings parses the following settings in the profile section of the shared config file.
+//
+//	[profile minio]
+//	s3_use_path_style = true
+//	no_verify_ssl = true
+//	s3 =
+//	    addressing_style = path
+//
+// The nested "addressing_style" is the same format as the AWS CLI.
+func parseS3CompatibleSettings(r io.Reader, profile AWSProfile) (*AWSConfigOptions, error) {
+	section := "profile " + profile.String()
+	if profile.String() == "" || profile.String() == "default" {
+		section = "default"
+	}
+
+	settings := &AWSConfigOptions{}
+	inSection, inS3 := false, false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.Join(strings.Fields(strings.Trim(trimmed, "[]")), " ")
+			inSection = name == section
+			inS3 = false
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		key, value, _ := strings.Cut(trimmed, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		nested := line != strings.TrimLeft(line, " \t")
+		if !nested {
+			inS3 = key == "s3" && value == ""
+		}
+
+		switch {
+		case nested && inS3 && key == "addressing_style":
+			settings.PathStyle = value == "path"
+		case !nested && key == "s3_use_path_style":
+			settings.PathStyle, _ = strconv.ParseBool(value) //nolint:errcheck
+		case !nested && key == "no_verify_ssl":
+			settings.NoVerifySSL, _ = strconv.ParseBool(value) //nolint:errcheck
+		}
+	}
+	return settings, scanner.Err()
+}
+
 // Region returns the AWS region.
 func (c *AWSConfig) Region() Region {
 	if Region(c.Config.Region) == "" {
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// credentialsExpiryWindow is the margin before the expiry. The cached credentials that expire within it are refreshed.
+const credentialsExpiryWindow = 5 * time.Minute
+
+// newAssumeRoleCredentials returns the credentials provider that assumes the role with the credentials of the profile in cfg.
+// The assumed role credentials are cached on disk until they expire, so the MFA token code is asked only once per session.
+func newAssumeRoleCredentials(cfg aws.Config, profile AWSProfile, opts *AWSConfigOptions) (aws.CredentialsProvider, error) {
+	dir, err := credentialsCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), opts.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		if opts.ExternalID != "" {
+			o.ExternalID = aws.String(opts.ExternalID)
+		}
+		if opts.MFASerial != "" {
+			o.SerialNumber = aws.String(opts.MFASerial)
+			o.TokenProvider = opts.MFATokenProvider
+		}
+		if opts.Duration > 0 {
+			o.Duration = opts.Duration
+		}
+		if opts.RoleSessionName != "" {
+			o.RoleSessionName = opts.RoleSessionName
+		}
+	})
+
+	return aws.NewCredentialsCache(&fileCacheCredentialsProvider{
+		provider: provider,
+		path:     filepath.Join(dir, assumeRoleCacheKey(profile, cfg.Region, opts)+".json"),
+	}), nil
+}
+
+// assumeRoleCacheKey returns the key of the cached assumed role credentials.
+// The key includes the source profile and the region, so the profile that is not allowed to assume the role
+// does not reuse the credentials that another profile assumed.
+func assumeRoleCacheKey(profile AWSProfile, region string, opts *AWSConfigOptions) string {
+	key := sha256.Sum256([]byte(strings.Join([]string{
+		profile.String(), region, opts.RoleARN, opts.ExternalID, opts.MFASerial, opts.RoleSessionName, opts.Duration.String(),
+	}, "|")))
+	return hex.EncodeToString(key[:])
+}
+
+// credentialsCacheDir returns the directory of the credentials cache.
+// It is $XDG_CACHE_HOME/rainbow/credentials on Linux.
+func credentialsCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rainbow", "credentials"), nil
+}
+
+// fileCacheCredentialsProvider caches the credentials of the provider in the file.
+type fileCacheCredentialsProvider struct {
+	// provider is the provider that retrieves the credentials when the cache is expired.
+	provider aws.CredentialsProvider
+	// path is the path of the cache file.
+	path string
+	// now returns the current time. It is for unit test.
+	now func() time.Time
+}
+
+// Retrieve returns the cached credentials if they are still valid. Otherwise, it retrieves and caches new credentials.
+// The failure of writing the cache is ignored because the credentials themselves are valid.
+func (p *fileCacheCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+
+	if b, err := os.ReadFile(p.path); err == nil {
+		var creds aws.Credentials
+		if err := json.Unmarshal(b, &creds); err == nil && creds.HasKeys() &&
+			(!creds.CanExpire || creds.Expires.After(now().Add(credentialsExpiryWindow))) {
+			return creds, nil
+		}
+	}
+
+	creds, err := p.provider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	if b, err := json.Marshal(creds); err == nil {
+		if err := os.MkdirAll(filepath.Dir(p.path), 0700); err == nil {
+			_ = os.WriteFile(p.path, b, 0600) //nolint:errcheck
+		}
+	}
+	return creds, nil
+}
+
+// IsSSOSessionExpired returns true if the error is caused by the expired (or not logged in) IAM Identity Center session.
+func IsSSOSessionExpired(err error) bool {
+	if err == nil {
+		return false
+	}
+	var invalidToken *ssocreds.InvalidTokenError
+	if errors.As(err, &invalidToken) {
+		return true
+	}
+	for _, msg := range []string{
+		"cached SSO token is expired",
+		"refresh cached SSO token failed",
+		"failed to read cached SSO token file",
+		"UnauthorizedException: Session token not found or invalid",
+	} {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
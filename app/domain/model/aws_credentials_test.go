@@ -0,0 +1,99 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+)
+
+func TestFileCacheCredentialsProvider_Retrieve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	inner := aws.CredentialsProviderFunc(func(_ context.Context) (aws.Credentials, error) {
+		calls++
+		return aws.Credentials{
+			AccessKeyID:     fmt.Sprintf("key-%d", calls),
+			SecretAccessKey: "secret",
+			SessionToken:    "token",
+			CanExpire:       true,
+			Expires:         now.Add(time.Hour),
+		}, nil
+	})
+	provider := &fileCacheCredentialsProvider{
+		provider: inner,
+		path:     filepath.Join(t.TempDir(), "credentials", "cache.json"),
+		now:      func() time.Time { return now },
+	}
+
+	first, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || first.AccessKeyID != "key-1" || second.AccessKeyID != "key-1" {
+		t.Errorf("the cached credentials should be used: calls=%d, first=%s, second=%s", calls, first.AccessKeyID, second.AccessKeyID)
+	}
+
+	// The credentials that expire within the window are refreshed.
+	provider.now = func() time.Time { return now.Add(time.Hour - time.Minute) }
+	third, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || third.AccessKeyID != "key-2" {
+		t.Errorf("the expired credentials should be refreshed: calls=%d, third=%s", calls, third.AccessKeyID)
+	}
+}
+
+func Test_assumeRoleCacheKey(t *testing.T) {
+	t.Parallel()
+
+	opts := &AWSConfigOptions{RoleARN: "arn:aws:iam::123456789012:role/admin", RoleSessionName: "rainbow"}
+	key := assumeRoleCacheKey(AWSProfile("dev"), "us-east-1", opts)
+
+	if got := assumeRoleCacheKey(AWSProfile("dev"), "us-east-1", opts); got != key {
+		t.Errorf("the same profile, region and role must use the same cache file: %s != %s", got, key)
+	}
+	if got := assumeRoleCacheKey(AWSProfile("other"), "us-east-1", opts); got == key {
+		t.Error("the different profiles must not share the cache file of the same role")
+	}
+	if got := assumeRoleCacheKey(AWSProfile("dev"), "ap-northeast-1", opts); got == key {
+		t.Error("the different regions must not share the cache file of the same role")
+	}
+}
+
+func TestIsSSOSessionExpired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "invalid token error", err: fmt.Errorf("wrap: %w", &ssocreds.InvalidTokenError{Err: errors.New("expired")}), want: true},
+		{name: "token is expired", err: errors.New("cached SSO token is expired, or not present, and cannot be refreshed"), want: true},
+		{name: "unauthorized", err: errors.New("operation error SSO: GetRoleCredentials, UnauthorizedException: Session token not found or invalid"), want: true},
+		{name: "other error", err: errors.New("AccessDenied"), want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsSSOSessionExpired(tt.err); got != tt.want {
+				t.Errorf("IsSSOSessionExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
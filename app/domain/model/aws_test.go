@@ -1,6 +1,8 @@
 package model
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -120,3 +122,99 @@ func TestAWSConfig_Region(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseS3CompatibleSettings(t *testing.T) {
+	t.Parallel()
+
+	const sharedConfig = `[default]
+region = us-east-1
+
+[profile minio]
+endpoint_url = http://localhost:9000
+s3_use_path_style = true
+no_verify_ssl = true
+
+[profile ceph]
+s3 =
+    addressing_style = path
+region = us-east-1
+
+[profile aws]
+s3 =
+    addressing_style = virtual
+`
+
+	tests := []struct {
+		name    string
+		profile AWSProfile
+		want    AWSConfigOptions
+	}{
+		{name: "default profile", profile: AWSProfile("default"), want: AWSConfigOptions{}},
+		{name: "flat settings", profile: AWSProfile("minio"), want: AWSConfigOptions{PathStyle: true, NoVerifySSL: true}},
+		{name: "nested addressing style (AWS CLI format)", profile: AWSProfile("ceph"), want: AWSConfigOptions{PathStyle: true}},
+		{name: "virtual hosted-style", profile: AWSProfile("aws"), want: AWSConfigOptions{}},
+		{name: "profile does not exist", profile: AWSProfile("unknown"), want: AWSConfigOptions{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseS3CompatibleSettings(strings.NewReader(sharedConfig), tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.PathStyle != tt.want.PathStyle || got.NoVerifySSL != tt.want.NoVerifySSL {
+				t.Errorf("parseS3CompatibleSettings() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAWSConfig_DebugMode(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := NewAWSConfig(context.Background(), AWSProfile("not-exist"), "", &AWSConfigOptions{Debug: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BaseEndpoint == nil || *cfg.BaseEndpoint != DebugLocalstackEndpoint {
+		t.Errorf("BaseEndpoint = %v, want %s", cfg.BaseEndpoint, DebugLocalstackEndpoint)
+	}
+	if cfg.Region() != RegionUSEast1 {
+		t.Errorf("Region() = %s, want %s", cfg.Region(), RegionUSEast1)
+	}
+	if !cfg.S3UsePathStyle {
+		t.Error("S3UsePathStyle should be true in debug mode")
+	}
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessKeyID != "test" || creds.SecretAccessKey != "test" {
+		t.Errorf("credentials = %s/%s, want dummy credentials", creds.AccessKeyID, creds.SecretAccessKey)
+	}
+}
+
+func TestAWSConfigOptions_Endpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts AWSConfigOptions
+		want string
+	}{
+		{name: "not debug mode", opts: AWSConfigOptions{}, want: ""},
+		{name: "debug mode", opts: AWSConfigOptions{Debug: true}, want: DebugLocalstackEndpoint},
+		{name: "debug mode with endpoint", opts: AWSConfigOptions{Debug: true, EndpointURL: "http://localhost:9000"}, want: "http://localhost:9000"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.opts.Endpoint(); got != tt.want {
+				t.Errorf("Endpoint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// redacted is the placeholder of the redacted values.
+const redacted = "REDACTED"
+
+// APILogger is the logger of the AWS API calls. *log.Logger of charmbracelet/log satisfies it.
+// Each API operation is logged at the info level, and each HTTP attempt is logged at the debug level.
+type APILogger interface {
+	// Debug logs the message at the debug level.
+	Debug(msg interface{}, keyvals ...interface{})
+	// Info logs the message at the info level.
+	Info(msg interface{}, keyvals ...interface{})
+	// Error logs the message at the error level.
+	Error(msg interface{}, keyvals ...interface{})
+}
+
+// apiTrace is the state of the traced API operation shared by the attempts.
+type apiTrace struct {
+	// attempts is the number of HTTP attempts.
+	attempts int
+	// endpoint is the endpoint of the last attempt without the path and the query. e.g. https://s3.us-east-1.amazonaws.com
+	endpoint string
+}
+
+// apiTraceKey is the stack value key of apiTrace.
+type apiTraceKey struct{}
+
+// withAPITrace adds the middleware that logs the API operations and the HTTP attempts of all clients to cfg.
+func withAPITrace(cfg *aws.Config, logger APILogger) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		if err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("RainbowAPITrace", func(
+			ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+		) (middleware.InitializeOutput, middleware.Metadata, error) {
+			trace := &apiTrace{}
+			ctx = middleware.WithStackValue(ctx, apiTraceKey{}, trace)
+			started := time.Now()
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			logAPIOperation(ctx, logger, trace, time.Since(started), metadata, err)
+			return out, metadata, err
+		}), middleware.After); err != nil {
+			return err
+		}
+		// The attempt middleware is added after the retry and signing middleware, so it runs for each attempt.
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("RainbowAPIAttemptTrace", func(
+			ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+		) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			req, ok := in.Request.(*smithyhttp.Request)
+			if !ok {
+				return next.HandleFinalize(ctx, in)
+			}
+			trace, _ := middleware.GetStackValue(ctx, apiTraceKey{}).(*apiTrace) //nolint:errcheck
+			if trace != nil {
+				trace.attempts++
+				trace.endpoint = req.URL.Scheme + "://" + req.URL.Host
+			}
+
+			started := time.Now()
+			out, metadata, err := next.HandleFinalize(ctx, in)
+			keyvals := []interface{}{
+				"operation", awsmiddleware.GetOperationName(ctx),
+				"method", req.Method,
+				"url", RedactURL(req.URL),
+				"latency", time.Since(started).Round(time.Millisecond),
+				"headers", redactHeader(req.Header),
+			}
+			if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok && resp.StatusCode > 0 {
+				keyvals = append(keyvals, "status", resp.StatusCode)
+			}
+			if err != nil {
+				keyvals = append(keyvals, "error", RedactURLs(err.Error()))
+			}
+			logger.Debug("aws attempt", keyvals...)
+			return out, metadata, err
+		}), middleware.After)
+	})
+}
+
+// logAPIOperation logs the API operation with the service, region, endpoint, latency, retry count and request ID.
+func logAPIOperation(ctx context.Context, logger APILogger, trace *apiTrace, latency time.Duration, metadata middleware.Metadata, err error) {
+	requestID, _ := awsmiddleware.GetRequestIDMetadata(metadata) //nolint:errcheck
+	var respErr *awshttp.ResponseError
+	if requestID == "" && errors.As(err, &respErr) {
+		requestID = respErr.ServiceRequestID()
+	}
+
+	keyvals := []interface{}{
+		"service", awsmiddleware.GetServiceID(ctx),
+		"operation", awsmiddleware.GetOperationName(ctx),
+		"region", awsmiddleware.GetRegion(ctx),
+		"endpoint", trace.endpoint,
+		"latency", latency.Round(time.Millisecond),
+		"retries", max(trace.attempts-1, 0),
+		"request_id", requestID,
+	}
+	if err != nil {
+		logger.Error("aws api", append(keyvals, "error", RedactURLs(err.Error()))...)
+		return
+	}
+	logger.Info("aws api", keyvals...)
+}
+
+// sensitiveQueryParams is the query parameters of the presigned URL that carry the credentials or the signature.
+// The query parameters that start with "x-amz-" are redacted too.
+var sensitiveQueryParams = map[string]struct{}{ //nolint:gochecknoglobals
+	"signature":      {},
+	"awsaccesskeyid": {},
+	"security-token": {},
+}
+
+// sensitiveHeaders is the HTTP headers that carry the credentials or the signature.
+var sensitiveHeaders = map[string]struct{}{ //nolint:gochecknoglobals
+	"Authorization":                                         {},
+	"X-Amz-Security-Token":                                  {},
+	"X-Amz-Server-Side-Encryption-Customer-Key":             {},
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key": {},
+}
+
+// RedactURL returns the URL whose credentials, signature and presigned query parameters are redacted.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	c := *u
+	if c.User != nil {
+		c.User = url.User(redacted)
+	}
+	query := c.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if _, ok := sensitiveQueryParams[lower]; ok || strings.HasPrefix(lower, "x-amz-") {
+			query.Set(key, redacted)
+		}
+	}
+	c.RawQuery = query.Encode()
+	return c.String()
+}
+
+// urlPattern is the pattern of the URL in the error message.
+var urlPattern = regexp.MustCompile(`https?://[^\s"']+`) //nolint:gochecknoglobals
+
+// RedactURLs redacts the URLs in s with RedactURL. e.g. the error message of the connection error contains the request URL.
+func RedactURLs(s string) string {
+	return urlPattern.ReplaceAllStringFunc(s, func(raw string) string {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return redacted
+		}
+		return RedactURL(u)
+	})
+}
+
+// redactHeader returns the HTTP headers in "Key: value" format whose credentials and signature are redacted.
+func redactHeader(header http.Header) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(header.Values(key), ",")
+		if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(key)]; ok {
+			value = redacted
+		}
+		pairs = append(pairs, key+": "+value)
+	}
+	return strings.Join(pairs, "; ")
+}
@@ -1,19 +1,32 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 )
 
 const (
-	// CloudFormationRetryMaxAttempts is the maximum number of retries for CloudFormation.
-	CloudFormationRetryMaxAttempts int = 2
 	// CloudFormationWaitNanoSecTime is the time to wait for CloudFormation.
 	// It is 1 hour.
 	CloudFormationWaitNanoSecTime = time.Duration(6000000000000000)
 	// ResourceCreationCancelled is the message for resource creation cancelled.
 	ResouceCreationCancelled = "Resource creation cancelled"
+	// MaxCFnRegionsParallelsCount is the maximum number of regions where the stacks are listed in parallel.
+	MaxCFnRegionsParallelsCount = 5
+	// CFnStackEventsPollInterval is the interval of polling the stack events while waiting for the stack operation.
+	CFnStackEventsPollInterval = 5 * time.Second
+	// CFnStackDeleteTimeout is the maximum time to wait for the stack deletion.
+	CFnStackDeleteTimeout = time.Hour
+	// CFnStackUpdateTimeout is the maximum time to wait for the stack update.
+	CFnStackUpdateTimeout = time.Hour
+	// MaxCFnTagKeyLength is the maximum length of the stack tag key.
+	MaxCFnTagKeyLength = 128
+	// MaxCFnTagValueLength is the maximum length of the stack tag value.
+	MaxCFnTagValueLength = 256
 )
 
 // StackStatus is the status of a CloudFormation stack.
@@ -79,6 +92,11 @@ func (s StackStatus) StringWithColor() string {
 	}
 }
 
+// InProgress returns true if the stack operation is in progress. The stack in other statuses is in a terminal status.
+func (s StackStatus) InProgress() bool {
+	return strings.HasSuffix(string(s), "_IN_PROGRESS")
+}
+
 // CloudFormation stack status constants
 // Ref. https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/using-cfn-describing-stacks.html
 const (
@@ -249,6 +267,29 @@ type Stack struct {
 // ResourceStatus is the status of a CloudFormation stack resource.
 type ResourceStatus string
 
+// String returns the string representation of ResourceStatus.
+func (s ResourceStatus) String() string {
+	return string(s)
+}
+
+// StringWithColor returns the string representation of ResourceStatus with color.
+// Red for failed, yellow for in-progress or skipped, and green for complete.
+func (s ResourceStatus) StringWithColor() string {
+	switch {
+	case s.Failed():
+		return color.RedString(s.String())
+	case strings.HasSuffix(s.String(), "_COMPLETE"):
+		return color.GreenString(s.String())
+	default:
+		return color.YellowString(s.String())
+	}
+}
+
+// Failed returns true if the resource operation failed.
+func (s ResourceStatus) Failed() bool {
+	return strings.HasSuffix(string(s), "_FAILED")
+}
+
 const (
 	// ResourceStatusCreateInProgress is the resource is being created.
 	ResourceStatusCreateInProgress ResourceStatus = "CREATE_IN_PROGRESS"
@@ -417,6 +458,21 @@ type StackResource struct {
 	ResourceStatusReason *string
 }
 
+// CFnNestedStackResourceType is the resource type of the nested stack.
+const CFnNestedStackResourceType = "AWS::CloudFormation::Stack"
+
+// IsNestedStack returns true if the resource is a nested stack.
+func (r *StackResource) IsNestedStack() bool {
+	return r.ResourceType != nil && *r.ResourceType == CFnNestedStackResourceType
+}
+
+// StackResourceNode is a stack resource in the tree of the stack and its nested stacks.
+type StackResourceNode struct {
+	*StackResource
+	// Children is the resources of the nested stack. It is empty if the resource is not a nested stack.
+	Children []*StackResourceNode
+}
+
 // HookFailureMode specify the hook failure mode for non-compliant resources in the followings ways.
 type HookFailureMode string
 
@@ -475,3 +531,127 @@ type StackEvent struct {
 	// in the CloudFormation User Guide.)
 	ResourceType *string
 }
+
+// RegionalStack is the CloudFormation stack with the region where it exists.
+type RegionalStack struct {
+	// Region is the region of the stack.
+	Region Region
+	// Stack is the CloudFormation stack.
+	*Stack
+}
+
+// CFnTag is the tag (key-value pair) of the CloudFormation stack.
+// The stack tags are propagated to the resources of the stack.
+type CFnTag struct {
+	// Key is the tag key.
+	Key string
+	// Value is the tag value.
+	Value string
+}
+
+// NewCFnTag parses the "key=value" string. Value may be empty.
+func NewCFnTag(s string) (CFnTag, error) {
+	key, value, found := strings.Cut(s, "=")
+	if !found {
+		return CFnTag{}, fmt.Errorf("invalid tag (must be key=value): %s", s)
+	}
+	tag := CFnTag{Key: key, Value: value}
+	if err := tag.Validate(); err != nil {
+		return CFnTag{}, err
+	}
+	return tag, nil
+}
+
+// Validate validates the tag. The key must not be empty and must not start with "aws:".
+func (t CFnTag) Validate() error {
+	if err := validateCFnTagKey(t.Key); err != nil {
+		return err
+	}
+	if len(t.Value) > MaxCFnTagValueLength {
+		return fmt.Errorf("tag value must be %d characters or less: %s", MaxCFnTagValueLength, t.Value)
+	}
+	return nil
+}
+
+// validateCFnTagKey validates the tag key.
+func validateCFnTagKey(key string) error {
+	if key == "" {
+		return errors.New("tag key must not be empty")
+	}
+	if len(key) > MaxCFnTagKeyLength {
+		return fmt.Errorf("tag key must be %d characters or less: %s", MaxCFnTagKeyLength, key)
+	}
+	if strings.HasPrefix(strings.ToLower(key), "aws:") {
+		return fmt.Errorf("tag key must not start with 'aws:': %s", key)
+	}
+	return nil
+}
+
+// NewCFnTagKey returns the tag key if it is valid.
+func NewCFnTagKey(s string) (string, error) {
+	if err := validateCFnTagKey(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// CFnTags is the set of the CFnTag. The keys are unique.
+type CFnTags []CFnTag
+
+// Merge returns the tags that the tags in other are added to. The value of the existing key is overwritten.
+func (t CFnTags) Merge(other CFnTags) CFnTags {
+	merged := make(CFnTags, 0, len(t)+len(other))
+	merged = append(merged, t...)
+	for _, tag := range other {
+		found := false
+		for i := range merged {
+			if merged[i].Key == tag.Key {
+				merged[i].Value = tag.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// Remove returns the tags without the keys.
+func (t CFnTags) Remove(keys []string) CFnTags {
+	removed := make(CFnTags, 0, len(t))
+	for _, tag := range t {
+		found := false
+		for _, key := range keys {
+			if tag.Key == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, tag)
+		}
+	}
+	return removed
+}
+
+// Equal returns true if the tags have the same keys and values regardless of the order.
+func (t CFnTags) Equal(other CFnTags) bool {
+	if len(t) != len(other) {
+		return false
+	}
+	for _, tag := range t {
+		found := false
+		for _, o := range other {
+			if tag == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CFnCleanupDefaultAge is the default minimum age of the stale stacks.
+// The younger stacks are not stale because they may be in the middle of the deployment
+// (e.g. REVIEW_IN_PROGRESS while the change set is previewed).
+const CFnCleanupDefaultAge = 24 * time.Hour
+
+// StaleReason returns why the stack in the status is regarded as stale. If the status is healthy, it returns false.
+func (s StackStatus) StaleReason() (string, bool) {
+	switch s {
+	case StackStatusRollbackComplete:
+		return "the creation failed and was rolled back. the stack can not be updated, only deleted", true
+	case StackStatusDeleteFailed:
+		return "the deletion failed. the resources that blocked the deletion remain", true
+	case StackStatusReviewInProgress:
+		return "the change set that created the stack was never executed. the stack has no resources", true
+	default:
+		return "", false
+	}
+}
+
+// CFnStaleStack is the stack that is regarded as stale by the cleanup.
+type CFnStaleStack struct {
+	// Stack is the stale stack.
+	Stack *Stack
+	// Reason is why the stack is regarded as stale.
+	Reason string
+	// Age is the time elapsed since the stack was last updated.
+	Age time.Duration
+	// DependsOn is the names of the stale stacks that import the exports of the stack.
+	// They must be deleted before the stack.
+	DependsOn []string
+	// BlockedReason is why the stack can not be deleted (e.g. a healthy stack imports its export).
+	// It is empty if the stack can be deleted.
+	BlockedReason string
+}
+
+// NewCFnStaleStack returns the CFnStaleStack if the stack in the unhealthy status is older than olderThan at now.
+// Nested stacks are not stale by themselves because they are deleted with the root stack.
+func NewCFnStaleStack(stack *Stack, olderThan time.Duration, now time.Time) (*CFnStaleStack, bool) {
+	if stack.StackName == nil || stack.ParentID != nil {
+		return nil, false
+	}
+	reason, ok := stack.StackStatus.StaleReason()
+	if !ok {
+		return nil, false
+	}
+	var age time.Duration
+	if updatedAt := stack.UpdatedAt(); updatedAt != nil {
+		age = now.Sub(*updatedAt)
+	}
+	if age < olderThan {
+		return nil, false
+	}
+	return &CFnStaleStack{Stack: stack, Reason: reason, Age: age}, true
+}
+
+// SortStaleStacksForDeletion returns the deletable stacks in the order that the importers are deleted before
+// the exporters, and the blocked stacks. The exporter whose importer is blocked is blocked too, because
+// CloudFormation can not delete the export while it is imported.
+func SortStaleStacksForDeletion(stacks []*CFnStaleStack) (deletable, blocked []*CFnStaleStack) {
+	byName := make(map[string]*CFnStaleStack, len(stacks))
+	for _, s := range stacks {
+		byName[*s.Stack.StackName] = s
+	}
+
+	// Propagate the blocked importers to the exporters until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for _, s := range stacks {
+			if s.BlockedReason != "" {
+				continue
+			}
+			for _, importer := range s.DependsOn {
+				if dep, ok := byName[importer]; ok && dep.BlockedReason != "" {
+					s.BlockedReason = fmt.Sprintf("the importer %s can not be deleted", importer)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	remaining := make([]*CFnStaleStack, 0, len(stacks))
+	for _, s := range stacks {
+		if s.BlockedReason != "" {
+			blocked = append(blocked, s)
+			continue
+		}
+		remaining = append(remaining, s)
+	}
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return *remaining[i].Stack.StackName < *remaining[j].Stack.StackName
+	})
+
+	// Delete the stacks whose importers have been deleted. The rest have circular imports.
+	deleted := make(map[string]bool, len(remaining))
+	for len(remaining) > 0 {
+		next := make([]*CFnStaleStack, 0, len(remaining))
+		for _, s := range remaining {
+			ready := true
+			for _, importer := range s.DependsOn {
+				if !deleted[importer] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				deletable = append(deletable, s)
+			} else {
+				next = append(next, s)
+			}
+		}
+		if len(next) == len(remaining) {
+			for _, s := range next {
+				s.BlockedReason = "the stack and its importers import each other"
+				blocked = append(blocked, s)
+			}
+			break
+		}
+		for _, s := range deletable {
+			deleted[*s.Stack.StackName] = true
+		}
+		remaining = next
+	}
+	return deletable, blocked
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewCFnStaleStack(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		stack   *Stack
+		want    bool
+		wantAge time.Duration
+	}{
+		{
+			name:    "old ROLLBACK_COMPLETE stack is stale",
+			stack:   &Stack{StackName: aws.String("app"), StackStatus: StackStatusRollbackComplete, CreationTime: &old},
+			want:    true,
+			wantAge: 48 * time.Hour,
+		},
+		{
+			name:  "recent REVIEW_IN_PROGRESS stack may be in the middle of the deployment",
+			stack: &Stack{StackName: aws.String("app"), StackStatus: StackStatusReviewInProgress, CreationTime: &recent},
+			want:  false,
+		},
+		{
+			name:  "healthy stack is not stale",
+			stack: &Stack{StackName: aws.String("app"), StackStatus: StackStatusCreateComplete, CreationTime: &old},
+			want:  false,
+		},
+		{
+			name:  "nested stack is deleted with the root stack",
+			stack: &Stack{StackName: aws.String("app-Nested"), StackStatus: StackStatusDeleteFailed, CreationTime: &old, ParentID: aws.String("root")},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := NewCFnStaleStack(tt.stack, CFnCleanupDefaultAge, now)
+			if ok != tt.want {
+				t.Fatalf("want %t, got %t", tt.want, ok)
+			}
+			if !ok {
+				return
+			}
+			if got.Age != tt.wantAge {
+				t.Errorf("want age %s, got %s", tt.wantAge, got.Age)
+			}
+			if got.Reason == "" {
+				t.Error("the reason must not be empty")
+			}
+		})
+	}
+}
+
+func TestSortStaleStacksForDeletion(t *testing.T) {
+	t.Parallel()
+
+	newStale := func(name string, dependsOn []string, blockedReason string) *CFnStaleStack {
+		return &CFnStaleStack{
+			Stack:         &Stack{StackName: aws.String(name), StackStatus: StackStatusDeleteFailed},
+			DependsOn:     dependsOn,
+			BlockedReason: blockedReason,
+		}
+	}
+	names := func(stacks []*CFnStaleStack) []string {
+		got := make([]string, 0, len(stacks))
+		for _, s := range stacks {
+			got = append(got, *s.Stack.StackName)
+		}
+		return got
+	}
+
+	t.Run("the importers are deleted before the exporters", func(t *testing.T) {
+		t.Parallel()
+
+		deletable, blocked := SortStaleStacksForDeletion([]*CFnStaleStack{
+			newStale("a-network", []string{"b-db", "c-app"}, ""),
+			newStale("b-db", []string{"c-app"}, ""),
+			newStale("c-app", nil, ""),
+			newStale("d-other", nil, ""),
+		})
+		if diff := cmp.Diff([]string{"c-app", "d-other", "b-db", "a-network"}, names(deletable)); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+		if len(blocked) != 0 {
+			t.Errorf("unexpected blocked stacks: %v", names(blocked))
+		}
+	})
+
+	t.Run("the exporter of the blocked importer is blocked", func(t *testing.T) {
+		t.Parallel()
+
+		deletable, blocked := SortStaleStacksForDeletion([]*CFnStaleStack{
+			newStale("network", []string{"db"}, ""),
+			newStale("db", nil, "termination protection is enabled"),
+			newStale("app", nil, ""),
+		})
+		if diff := cmp.Diff([]string{"app"}, names(deletable)); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"network", "db"}, names(blocked)); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+		if blocked[0].BlockedReason != "the importer db can not be deleted" {
+			t.Errorf("unexpected reason: %s", blocked[0].BlockedReason)
+		}
+	})
+}
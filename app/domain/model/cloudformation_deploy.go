@@ -0,0 +1,147 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxCFnTemplateBodySize is the maximum size of the template body passed to CloudFormation directly.
+	// The larger template must be uploaded to S3.
+	MaxCFnTemplateBodySize = 51200
+	// CFnChangeSetPollInterval is the interval of polling the change set while it is created.
+	CFnChangeSetPollInterval = 2 * time.Second
+	// CFnChangeSetCreateTimeout is the maximum time to wait for the change set creation.
+	CFnChangeSetCreateTimeout = 10 * time.Minute
+	// CFnStackDeployTimeout is the maximum time to wait for the stack creation or update.
+	CFnStackDeployTimeout = 2 * time.Hour
+)
+
+// CFnParameter is the parameter of the CloudFormation stack.
+type CFnParameter struct {
+	// Key is the parameter key.
+	Key string `json:"ParameterKey"`
+	// Value is the parameter value.
+	Value string `json:"ParameterValue"`
+	// UsePreviousValue is whether the previous value of the stack is used. If true, Value is ignored.
+	UsePreviousValue bool `json:"UsePreviousValue,omitempty"`
+}
+
+// CFnParameters is the set of the CFnParameter.
+type CFnParameters []CFnParameter
+
+// NewCFnParameters parses the parameters file in the AWS CLI format.
+// e.g. [{"ParameterKey": "Env", "ParameterValue": "dev"}]
+func NewCFnParameters(data []byte) (CFnParameters, error) {
+	var parameters CFnParameters
+	if err := json.Unmarshal(data, &parameters); err != nil {
+		return nil, fmt.Errorf("invalid parameters (must be [{\"ParameterKey\": \"KEY\", \"ParameterValue\": \"VALUE\"}]): %w", err)
+	}
+	seen := make(map[string]struct{}, len(parameters))
+	for _, p := range parameters {
+		if p.Key == "" {
+			return nil, errors.New("parameter key must not be empty")
+		}
+		if _, ok := seen[p.Key]; ok {
+			return nil, fmt.Errorf("duplicate parameter key: %s", p.Key)
+		}
+		seen[p.Key] = struct{}{}
+	}
+	return parameters, nil
+}
+
+// CFnCapability is the capability that acknowledges the template contains the certain resources.
+type CFnCapability string
+
+const (
+	// CFnCapabilityIAM acknowledges that the template creates IAM resources.
+	CFnCapabilityIAM CFnCapability = "CAPABILITY_IAM"
+	// CFnCapabilityNamedIAM acknowledges that the template creates IAM resources with custom names.
+	CFnCapabilityNamedIAM CFnCapability = "CAPABILITY_NAMED_IAM"
+	// CFnCapabilityAutoExpand acknowledges that the template contains macros (e.g. AWS::Serverless transform).
+	CFnCapabilityAutoExpand CFnCapability = "CAPABILITY_AUTO_EXPAND"
+)
+
+// NewCFnCapability returns the CFnCapability. The prefix "CAPABILITY_" can be omitted and the case is ignored.
+func NewCFnCapability(s string) (CFnCapability, error) {
+	c := CFnCapability(strings.ToUpper(s))
+	if !strings.HasPrefix(string(c), "CAPABILITY_") {
+		c = "CAPABILITY_" + c
+	}
+	switch c {
+	case CFnCapabilityIAM, CFnCapabilityNamedIAM, CFnCapabilityAutoExpand:
+		return c, nil
+	default:
+		return "", fmt.Errorf("invalid capability (must be %s, %s or %s): %s",
+			CFnCapabilityIAM, CFnCapabilityNamedIAM, CFnCapabilityAutoExpand, s)
+	}
+}
+
+// CFnChangeSetType is the type of the change set.
+type CFnChangeSetType string
+
+const (
+	// CFnChangeSetTypeCreate is the change set that creates a new stack.
+	CFnChangeSetTypeCreate CFnChangeSetType = "CREATE"
+	// CFnChangeSetTypeUpdate is the change set that updates the existing stack.
+	CFnChangeSetTypeUpdate CFnChangeSetType = "UPDATE"
+)
+
+// CFnChangeSetStatus is the status of the change set creation.
+type CFnChangeSetStatus string
+
+const (
+	// CFnChangeSetStatusCreatePending is the status that the change set is waiting to be created.
+	CFnChangeSetStatusCreatePending CFnChangeSetStatus = "CREATE_PENDING"
+	// CFnChangeSetStatusCreateInProgress is the status that the change set is being created.
+	CFnChangeSetStatusCreateInProgress CFnChangeSetStatus = "CREATE_IN_PROGRESS"
+	// CFnChangeSetStatusCreateComplete is the status that the change set is created and can be executed.
+	CFnChangeSetStatusCreateComplete CFnChangeSetStatus = "CREATE_COMPLETE"
+	// CFnChangeSetStatusFailed is the status that the change set can not be created.
+	CFnChangeSetStatusFailed CFnChangeSetStatus = "FAILED"
+)
+
+// CFnChangeAction is the action that CloudFormation takes on the resource.
+type CFnChangeAction string
+
+const (
+	// CFnChangeActionAdd adds the resource.
+	CFnChangeActionAdd CFnChangeAction = "Add"
+	// CFnChangeActionModify modifies the resource.
+	CFnChangeActionModify CFnChangeAction = "Modify"
+	// CFnChangeActionRemove removes the resource.
+	CFnChangeActionRemove CFnChangeAction = "Remove"
+	// CFnChangeActionImport imports the resource.
+	CFnChangeActionImport CFnChangeAction = "Import"
+	// CFnChangeActionDynamic is the change that can not be determined until the change set is executed.
+	CFnChangeActionDynamic CFnChangeAction = "Dynamic"
+)
+
+// CFnReplacement is whether the modified resource is recreated.
+type CFnReplacement string
+
+const (
+	// CFnReplacementTrue means that the resource is recreated.
+	CFnReplacementTrue CFnReplacement = "True"
+	// CFnReplacementFalse means that the resource is updated in place.
+	CFnReplacementFalse CFnReplacement = "False"
+	// CFnReplacementConditional means that the resource may be recreated depending on the property values.
+	CFnReplacementConditional CFnReplacement = "Conditional"
+)
+
+// CFnResourceChange is the change of the resource in the change set.
+type CFnResourceChange struct {
+	// Action is the action that CloudFormation takes on the resource.
+	Action CFnChangeAction
+	// LogicalResourceID is the logical ID of the resource in the template.
+	LogicalResourceID string
+	// PhysicalResourceID is the physical ID of the resource. It is empty for the added resource.
+	PhysicalResourceID string
+	// ResourceType is the type of the resource. e.g. AWS::S3::Bucket
+	ResourceType string
+	// Replacement is whether the modified resource is recreated. It is empty except for the Modify action.
+	Replacement CFnReplacement
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewCFnParameters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    CFnParameters
+		wantErr bool
+	}{
+		{
+			name: "AWS CLI format",
+			data: `[{"ParameterKey": "Env", "ParameterValue": "dev"}, {"ParameterKey": "Name", "UsePreviousValue": true}]`,
+			want: CFnParameters{{Key: "Env", Value: "dev"}, {Key: "Name", UsePreviousValue: true}},
+		},
+		{
+			name: "empty array",
+			data: `[]`,
+			want: CFnParameters{},
+		},
+		{
+			name:    "not array",
+			data:    `{"Env": "dev"}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			data:    `[{"ParameterValue": "dev"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key",
+			data:    `[{"ParameterKey": "Env", "ParameterValue": "dev"}, {"ParameterKey": "Env", "ParameterValue": "prd"}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewCFnParameters([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCFnParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewCFnCapability(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s       string
+		want    CFnCapability
+		wantErr bool
+	}{
+		{s: "CAPABILITY_IAM", want: CFnCapabilityIAM},
+		{s: "capability_named_iam", want: CFnCapabilityNamedIAM},
+		{s: "auto_expand", want: CFnCapabilityAutoExpand},
+		{s: "CAPABILITY_ADMIN", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewCFnCapability(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCFnCapability() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewCFnCapability() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
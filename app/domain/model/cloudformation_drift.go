@@ -0,0 +1,110 @@
+package model
+
+import (
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// CFnDriftDetectionPollInterval is the interval of polling the drift detection status.
+	CFnDriftDetectionPollInterval = 2 * time.Second
+	// CFnDriftDetectionTimeout is the maximum time to wait for the drift detection of a stack.
+	CFnDriftDetectionTimeout = 15 * time.Minute
+	// MaxCFnDriftDetectionParallelsCount is the maximum number of stacks whose drift is detected in parallel.
+	MaxCFnDriftDetectionParallelsCount = 5
+)
+
+// DriftDetectable returns true if CloudFormation can detect the drift of the stack in the status.
+// Ref. https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/detect-drift-stack.html
+func (s StackStatus) DriftDetectable() bool {
+	switch s {
+	case StackStatusCreateComplete, StackStatusUpdateComplete, StackStatusUpdateRollbackComplete, StackStatusUpdateRollbackFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// StringWithColor returns the string representation of StackDriftStatus with color.
+// Red for drifted, green for in sync, and yellow for not checked or unknown.
+func (s StackDriftStatus) StringWithColor() string {
+	switch s {
+	case StackDriftStatusDrifted:
+		return color.RedString(string(s))
+	case StackDriftStatusInSync:
+		return color.GreenString(string(s))
+	default:
+		return color.YellowString(string(s))
+	}
+}
+
+// CFnDriftDetectionStatus is the status of the stack drift detection operation.
+type CFnDriftDetectionStatus string
+
+const (
+	// CFnDriftDetectionStatusInProgress means that the drift detection is in progress.
+	CFnDriftDetectionStatusInProgress CFnDriftDetectionStatus = "DETECTION_IN_PROGRESS"
+	// CFnDriftDetectionStatusComplete means that the drift detection is completed for all the resources that support it.
+	CFnDriftDetectionStatusComplete CFnDriftDetectionStatus = "DETECTION_COMPLETE"
+	// CFnDriftDetectionStatusFailed means that the drift detection failed for at least one resource.
+	// The results of the other resources are available.
+	CFnDriftDetectionStatusFailed CFnDriftDetectionStatus = "DETECTION_FAILED"
+)
+
+// CFnPropertyDifferenceType is how the actual property value differs from the expected value.
+type CFnPropertyDifferenceType string
+
+const (
+	// CFnPropertyDifferenceTypeAdd means that the property is added to the resource.
+	CFnPropertyDifferenceTypeAdd CFnPropertyDifferenceType = "ADD"
+	// CFnPropertyDifferenceTypeRemove means that the property is removed from the resource.
+	CFnPropertyDifferenceTypeRemove CFnPropertyDifferenceType = "REMOVE"
+	// CFnPropertyDifferenceTypeNotEqual means that the property value is changed.
+	CFnPropertyDifferenceTypeNotEqual CFnPropertyDifferenceType = "NOT_EQUAL"
+)
+
+// CFnPropertyDifference is the resource property whose actual value differs from the expected value.
+type CFnPropertyDifference struct {
+	// PropertyPath is the path of the property. e.g. /VersioningConfiguration/Status
+	PropertyPath string
+	// ExpectedValue is the value defined in the template. It is empty for the added property.
+	ExpectedValue string
+	// ActualValue is the current value of the resource. It is empty for the removed property.
+	ActualValue string
+	// DifferenceType is how the actual value differs from the expected value.
+	DifferenceType CFnPropertyDifferenceType
+}
+
+// CFnStackResourceDrift is the drift of the stack resource.
+type CFnStackResourceDrift struct {
+	// LogicalResourceID is the logical ID of the resource in the template.
+	LogicalResourceID string
+	// PhysicalResourceID is the physical ID of the resource.
+	PhysicalResourceID string
+	// ResourceType is the type of the resource. e.g. AWS::S3::Bucket
+	ResourceType string
+	// DriftStatus is the drift status of the resource.
+	DriftStatus StackResourceDriftStatus
+	// PropertyDifferences is the properties that differ. It is empty except for the MODIFIED resource.
+	PropertyDifferences []CFnPropertyDifference
+}
+
+// CFnStackDrift is the result of the drift detection of the stack.
+type CFnStackDrift struct {
+	// StackName is the name of the stack.
+	StackName string
+	// DetectionStatus is the status of the drift detection.
+	DetectionStatus CFnDriftDetectionStatus
+	// DetectionStatusReason is the reason of the detection status. e.g. the resources whose drift detection failed.
+	DetectionStatusReason string
+	// DriftStatus is the drift status of the stack.
+	DriftStatus StackDriftStatus
+	// DriftedResourceCount is the number of the resources that drifted.
+	DriftedResourceCount int
+	// Resources is the resources that drifted (MODIFIED or DELETED).
+	Resources []*CFnStackResourceDrift
+	// Err is the error that stopped the drift detection of the stack (e.g. throttling, the stack was deleted).
+	// If it is not nil, the other fields except StackName are zero values.
+	Err error
+}
@@ -0,0 +1,18 @@
+package model
+
+import "testing"
+
+func TestStackStatus_DriftDetectable(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []StackStatus{StackStatusCreateComplete, StackStatusUpdateComplete, StackStatusUpdateRollbackComplete, StackStatusUpdateRollbackFailed} {
+		if !s.DriftDetectable() {
+			t.Errorf("%s.DriftDetectable() = false, want true", s)
+		}
+	}
+	for _, s := range []StackStatus{StackStatusCreateInProgress, StackStatusRollbackComplete, StackStatusDeleteComplete, StackStatusReviewInProgress} {
+		if s.DriftDetectable() {
+			t.Errorf("%s.DriftDetectable() = true, want false", s)
+		}
+	}
+}
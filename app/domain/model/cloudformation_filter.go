@@ -0,0 +1,276 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCFnStackDescribeParallelsCount is the maximum number of stacks described in parallel to get their tags.
+const MaxCFnStackDescribeParallelsCount = 5
+
+// StackStatuses returns all the CloudFormation stack statuses.
+func StackStatuses() []StackStatus {
+	return []StackStatus{
+		StackStatusCreateInProgress,
+		StackStatusCreateComplete,
+		StackStatusCreateFailed,
+		StackStatusRollbackInProgress,
+		StackStatusRollbackComplete,
+		StackStatusRollbackFailed,
+		StackStatusDeleteInProgress,
+		StackStatusDeleteComplete,
+		StackStatusDeleteFailed,
+		StackStatusReviewInProgress,
+		StackStatusUpdateInProgress,
+		StackStatusUpdateCompleteCleanupInProgress,
+		StackStatusUpdateComplete,
+		StackStatusUpdateFailed,
+		StackStatusUpdateRollbackComplete,
+		StackStatusUpdateRollbackCompleteCleanupInProgress,
+		StackStatusUpdateRollbackFailed,
+		StackStatusUpdateRollbackInProgress,
+		StackStatusImportInProgress,
+		StackStatusImportComplete,
+		StackStatusImportRollbackInProgress,
+		StackStatusImportRollbackComplete,
+		StackStatusImportRollbackFailed,
+	}
+}
+
+// Failed returns true if the stack operation failed. ROLLBACK_COMPLETE is regarded as failed
+// because the stack failed to be created and can only be deleted.
+func (s StackStatus) Failed() bool {
+	return strings.HasSuffix(string(s), "_FAILED") || s == StackStatusRollbackComplete
+}
+
+// CFnStackStatusGroup is the group of the stack statuses that can be specified instead of the statuses.
+type CFnStackStatusGroup string
+
+const (
+	// CFnStackStatusGroupFailed is the statuses that end with _FAILED, and ROLLBACK_COMPLETE.
+	CFnStackStatusGroupFailed CFnStackStatusGroup = "failed"
+	// CFnStackStatusGroupInProgress is the statuses that end with _IN_PROGRESS.
+	CFnStackStatusGroupInProgress CFnStackStatusGroup = "in-progress"
+	// CFnStackStatusGroupComplete is the statuses that end with _COMPLETE except ROLLBACK_COMPLETE.
+	CFnStackStatusGroupComplete CFnStackStatusGroup = "complete"
+)
+
+// Statuses returns the stack statuses in the group.
+func (g CFnStackStatusGroup) Statuses() []StackStatus {
+	statuses := make([]StackStatus, 0)
+	for _, s := range StackStatuses() {
+		switch {
+		case g == CFnStackStatusGroupFailed && s.Failed(),
+			g == CFnStackStatusGroupInProgress && s.InProgress(),
+			g == CFnStackStatusGroupComplete && strings.HasSuffix(s.String(), "_COMPLETE") && !s.Failed():
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses
+}
+
+// NewStackStatuses parses the stack statuses (e.g. CREATE_COMPLETE) and the status groups (failed, in-progress, complete).
+// They are case-insensitive. The duplicated statuses are removed.
+func NewStackStatuses(values []string) ([]StackStatus, error) {
+	statuses := make([]StackStatus, 0, len(values))
+	add := func(s StackStatus) {
+		if !slices.Contains(statuses, s) {
+			statuses = append(statuses, s)
+		}
+	}
+	for _, v := range values {
+		switch group := CFnStackStatusGroup(strings.ToLower(v)); group {
+		case CFnStackStatusGroupFailed, CFnStackStatusGroupInProgress, CFnStackStatusGroupComplete:
+			for _, s := range group.Statuses() {
+				add(s)
+			}
+			continue
+		}
+		status := StackStatus(strings.ToUpper(v))
+		if !slices.Contains(StackStatuses(), status) {
+			return nil, fmt.Errorf("unknown stack status: %s (e.g. CREATE_COMPLETE, failed, in-progress or complete)", v)
+		}
+		add(status)
+	}
+	return statuses, nil
+}
+
+// NewCFnStackAge parses the age such as "30d", "12h" or "2w".
+// Supported suffixes are s, m, h, d and w. If the suffix is omitted, d is used.
+func NewCFnStackAge(s string) (time.Duration, error) {
+	unit := "d"
+	rest := s
+	if rest != "" {
+		if _, ok := ageUnits[rest[len(rest)-1:]]; ok {
+			unit = rest[len(rest)-1:]
+			rest = rest[:len(rest)-1]
+		}
+	}
+	n, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid age (e.g. 30d, 12h or 2w): %s", s)
+	}
+	return time.Duration(n) * ageUnits[unit], nil
+}
+
+// UpdatedAt returns the last updated time of the stack. If the stack has never been updated,
+// it returns the creation time. If both are unknown, it returns nil.
+func (s *Stack) UpdatedAt() *time.Time {
+	if s.LastUpdatedTime != nil {
+		return s.LastUpdatedTime
+	}
+	return s.CreationTime
+}
+
+// CFnStackFilter is the condition of the stacks to be listed. The zero value matches all the stacks except the deleted stacks.
+type CFnStackFilter struct {
+	// Statuses is the statuses of the stacks. If it is empty, the stacks in any status match.
+	Statuses []StackStatus
+	// Name is the regular expression of the stack name. If it is nil, any name matches.
+	Name *regexp.Regexp
+	// Tags is the tags that the stacks must have. If it is empty, the tags are not checked.
+	Tags CFnTags
+	// OlderThan is the minimum time elapsed since the stack was last updated. If it is zero, the time is not checked.
+	OlderThan time.Duration
+	// IncludeDeleted is whether the deleted (DELETE_COMPLETE) stacks match.
+	IncludeDeleted bool
+}
+
+// Match returns true if the stack matches the filter at now. The tags are not checked because
+// the stack summaries do not have the tags. Use MatchTags with the tags of the stack.
+func (f *CFnStackFilter) Match(stack *Stack, now time.Time) bool {
+	if stack.StackName == nil {
+		return false
+	}
+	if stack.StackStatus == StackStatusDeleteComplete && !f.IncludeDeleted {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, stack.StackStatus) {
+		return false
+	}
+	if f.Name != nil && !f.Name.MatchString(*stack.StackName) {
+		return false
+	}
+	if f.OlderThan > 0 {
+		updatedAt := stack.UpdatedAt()
+		if updatedAt == nil || now.Sub(*updatedAt) < f.OlderThan {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchTags returns true if tags has all the tags in the filter.
+func (f *CFnStackFilter) MatchTags(tags CFnTags) bool {
+	for _, want := range f.Tags {
+		if !slices.Contains(tags, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// CFnStackSortKey is the key to sort the stacks.
+type CFnStackSortKey string
+
+const (
+	// CFnStackSortKeyName sorts the stacks by the name in ascending order.
+	CFnStackSortKeyName CFnStackSortKey = "name"
+	// CFnStackSortKeyUpdated sorts the stacks by the last updated time, the most recent first.
+	CFnStackSortKeyUpdated CFnStackSortKey = "updated"
+	// CFnStackSortKeyCreated sorts the stacks by the creation time, the most recent first.
+	CFnStackSortKeyCreated CFnStackSortKey = "created"
+	// CFnStackSortKeyStatus sorts the stacks by the status, and then by the name.
+	CFnStackSortKeyStatus CFnStackSortKey = "status"
+)
+
+// NewCFnStackSortKey returns the CFnStackSortKey. The key is case-insensitive.
+func NewCFnStackSortKey(s string) (CFnStackSortKey, error) {
+	switch key := CFnStackSortKey(strings.ToLower(s)); key {
+	case CFnStackSortKeyName, CFnStackSortKeyUpdated, CFnStackSortKeyCreated, CFnStackSortKeyStatus:
+		return key, nil
+	default:
+		return "", fmt.Errorf("unknown sort key: %s (name, updated, created or status)", s)
+	}
+}
+
+// String returns the string representation of the CFnStackSortKey.
+func (k CFnStackSortKey) String() string {
+	return string(k)
+}
+
+// SortStacks sorts the stacks by the key. The stacks that have the same key keep the API order.
+// If the key is empty, the stacks are not sorted.
+func SortStacks(stacks []*Stack, key CFnStackSortKey) {
+	less, ok := stackLess(key)
+	if !ok {
+		return
+	}
+	sort.SliceStable(stacks, func(i, j int) bool { return less(stacks[i], stacks[j]) })
+}
+
+// SortRegionalStacks sorts the stacks in the regions by the key, and then by the region.
+// The stacks that have the same key and region keep the API order. If the key is empty, the stacks are not sorted.
+func SortRegionalStacks(stacks []*RegionalStack, key CFnStackSortKey) {
+	less, ok := stackLess(key)
+	if !ok {
+		return
+	}
+	sort.SliceStable(stacks, func(i, j int) bool {
+		a, b := stacks[i], stacks[j]
+		if less(a.Stack, b.Stack) {
+			return true
+		}
+		if less(b.Stack, a.Stack) {
+			return false
+		}
+		return a.Region < b.Region
+	})
+}
+
+// stackLess returns the function that reports whether the stack a sorts before b by the key.
+// If the key is empty or unknown, it returns false.
+func stackLess(key CFnStackSortKey) (func(a, b *Stack) bool, bool) {
+	switch key {
+	case CFnStackSortKeyName:
+		return func(a, b *Stack) bool { return stackName(a) < stackName(b) }, true
+	case CFnStackSortKeyUpdated:
+		return func(a, b *Stack) bool { return timeAfter(a.UpdatedAt(), b.UpdatedAt()) }, true
+	case CFnStackSortKeyCreated:
+		return func(a, b *Stack) bool { return timeAfter(a.CreationTime, b.CreationTime) }, true
+	case CFnStackSortKeyStatus:
+		return func(a, b *Stack) bool {
+			if a.StackStatus != b.StackStatus {
+				return a.StackStatus < b.StackStatus
+			}
+			return stackName(a) < stackName(b)
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// stackName returns the name of the stack. If the name is unknown, it returns the empty string.
+func stackName(stack *Stack) string {
+	if stack.StackName == nil {
+		return ""
+	}
+	return *stack.StackName
+}
+
+// timeAfter returns true if a is after b. The unknown time is regarded as the oldest.
+func timeAfter(a, b *time.Time) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	default:
+		return a.After(*b)
+	}
+}
@@ -0,0 +1,258 @@
+package model
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewStackStatuses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    []StackStatus
+		wantErr bool
+	}{
+		{
+			name:   "the statuses are case-insensitive and the duplicates are removed",
+			values: []string{"create_complete", "CREATE_COMPLETE", "UPDATE_COMPLETE"},
+			want:   []StackStatus{StackStatusCreateComplete, StackStatusUpdateComplete},
+		},
+		{
+			name:   "failed group includes ROLLBACK_COMPLETE",
+			values: []string{"Failed"},
+			want: []StackStatus{
+				StackStatusCreateFailed,
+				StackStatusRollbackComplete,
+				StackStatusRollbackFailed,
+				StackStatusDeleteFailed,
+				StackStatusUpdateFailed,
+				StackStatusUpdateRollbackFailed,
+				StackStatusImportRollbackFailed,
+			},
+		},
+		{
+			name:   "in-progress group",
+			values: []string{"in-progress", "CREATE_IN_PROGRESS"},
+			want: []StackStatus{
+				StackStatusCreateInProgress,
+				StackStatusRollbackInProgress,
+				StackStatusDeleteInProgress,
+				StackStatusReviewInProgress,
+				StackStatusUpdateInProgress,
+				StackStatusUpdateCompleteCleanupInProgress,
+				StackStatusUpdateRollbackCompleteCleanupInProgress,
+				StackStatusUpdateRollbackInProgress,
+				StackStatusImportInProgress,
+				StackStatusImportRollbackInProgress,
+			},
+		},
+		{
+			name:   "no statuses",
+			values: nil,
+			want:   []StackStatus{},
+		},
+		{
+			name:    "unknown status",
+			values:  []string{"CREATED"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewStackStatuses(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewCFnStackAge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{s: "30d", want: 30 * 24 * time.Hour},
+		{s: "12h", want: 12 * time.Hour},
+		{s: "2w", want: 14 * 24 * time.Hour},
+		{s: "7", want: 7 * 24 * time.Hour},
+		{s: "0d", wantErr: true},
+		{s: "-1d", wantErr: true},
+		{s: "1y", wantErr: true},
+		{s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.s, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewCFnStackAge(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCFnStackFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-60 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		filter *CFnStackFilter
+		stack  *Stack
+		want   bool
+	}{
+		{
+			name:   "the zero value matches the stack",
+			filter: &CFnStackFilter{},
+			stack:  &Stack{StackName: aws.String("app"), StackStatus: StackStatusCreateComplete},
+			want:   true,
+		},
+		{
+			name:   "the zero value does not match the deleted stack",
+			filter: &CFnStackFilter{},
+			stack:  &Stack{StackName: aws.String("app"), StackStatus: StackStatusDeleteComplete},
+			want:   false,
+		},
+		{
+			name:   "include the deleted stack",
+			filter: &CFnStackFilter{IncludeDeleted: true},
+			stack:  &Stack{StackName: aws.String("app"), StackStatus: StackStatusDeleteComplete},
+			want:   true,
+		},
+		{
+			name:   "the status does not match",
+			filter: &CFnStackFilter{Statuses: []StackStatus{StackStatusCreateFailed}},
+			stack:  &Stack{StackName: aws.String("app"), StackStatus: StackStatusCreateComplete},
+			want:   false,
+		},
+		{
+			name:   "the name does not match",
+			filter: &CFnStackFilter{Name: regexp.MustCompile("^dev-")},
+			stack:  &Stack{StackName: aws.String("prd-app"), StackStatus: StackStatusCreateComplete},
+			want:   false,
+		},
+		{
+			name:   "the stack updated recently is not older than the age",
+			filter: &CFnStackFilter{OlderThan: 30 * 24 * time.Hour},
+			stack:  &Stack{StackName: aws.String("app"), StackStatus: StackStatusUpdateComplete, CreationTime: &old, LastUpdatedTime: &recent},
+			want:   false,
+		},
+		{
+			name:   "the stack never updated is older than the age by the creation time",
+			filter: &CFnStackFilter{OlderThan: 30 * 24 * time.Hour},
+			stack:  &Stack{StackName: aws.String("app"), StackStatus: StackStatusCreateComplete, CreationTime: &old},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.filter.Match(tt.stack, now); got != tt.want {
+				t.Errorf("want %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCFnStackFilter_MatchTags(t *testing.T) {
+	t.Parallel()
+
+	filter := &CFnStackFilter{Tags: CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: "a"}}}
+	if !filter.MatchTags(CFnTags{{Key: "team", Value: "a"}, {Key: "owner", Value: "me"}, {Key: "env", Value: "dev"}}) {
+		t.Error("the stack that has all the tags must match")
+	}
+	if filter.MatchTags(CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: "b"}}) {
+		t.Error("the stack whose tag value differs must not match")
+	}
+}
+
+func TestSortStacks(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	t3 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	newStacks := func() []*Stack {
+		return []*Stack{
+			{StackName: aws.String("b"), StackStatus: StackStatusUpdateComplete, CreationTime: &t1, LastUpdatedTime: &t3},
+			{StackName: aws.String("c"), StackStatus: StackStatusCreateComplete, CreationTime: &t2},
+			{StackName: aws.String("a"), StackStatus: StackStatusCreateComplete},
+		}
+	}
+
+	tests := []struct {
+		key  CFnStackSortKey
+		want []string
+	}{
+		{key: "", want: []string{"b", "c", "a"}},
+		{key: CFnStackSortKeyName, want: []string{"a", "b", "c"}},
+		{key: CFnStackSortKeyUpdated, want: []string{"b", "c", "a"}},
+		{key: CFnStackSortKeyCreated, want: []string{"c", "b", "a"}},
+		{key: CFnStackSortKeyStatus, want: []string{"a", "c", "b"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.key.String(), func(t *testing.T) {
+			t.Parallel()
+
+			stacks := newStacks()
+			SortStacks(stacks, tt.key)
+			got := make([]string, 0, len(stacks))
+			for _, s := range stacks {
+				got = append(got, *s.StackName)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSortRegionalStacks(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	stacks := []*RegionalStack{
+		{Region: RegionUSEast1, Stack: &Stack{StackName: aws.String("a"), CreationTime: &t1}},
+		{Region: RegionUSEast1, Stack: &Stack{StackName: aws.String("b"), CreationTime: &t2}},
+		{Region: RegionAPNortheast1, Stack: &Stack{StackName: aws.String("c"), CreationTime: &t1}},
+		{Region: RegionAPNortheast1, Stack: &Stack{StackName: aws.String("d"), CreationTime: &t2}},
+	}
+	SortRegionalStacks(stacks, CFnStackSortKeyCreated)
+
+	got := make([]string, 0, len(stacks))
+	for _, s := range stacks {
+		got = append(got, s.Region.String()+"/"+*s.StackName)
+	}
+	want := []string{"ap-northeast-1/d", "us-east-1/b", "ap-northeast-1/c", "us-east-1/a"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
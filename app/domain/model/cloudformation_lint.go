@@ -0,0 +1,331 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CFnLintSeverity is the severity of the lint issue.
+type CFnLintSeverity string
+
+const (
+	// CFnLintSeverityError means that CloudFormation rejects the template.
+	CFnLintSeverityError CFnLintSeverity = "ERROR"
+	// CFnLintSeverityWarning means that the template works but is probably wrong.
+	CFnLintSeverityWarning CFnLintSeverity = "WARNING"
+)
+
+// String returns the string representation of the CFnLintSeverity.
+func (s CFnLintSeverity) String() string {
+	return string(s)
+}
+
+// CFnLintIssue is the problem found in the template by the linter.
+type CFnLintIssue struct {
+	// Severity is the severity of the issue.
+	Severity CFnLintSeverity
+	// Path is the path of the value that has the issue. e.g. Resources.Bucket.Properties.BucketName
+	Path string
+	// Message is the description of the issue.
+	Message string
+}
+
+// CFnPseudoParameters returns the pseudo parameters that can be referenced without the declaration.
+// Ref. https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/pseudo-parameter-reference.html
+func CFnPseudoParameters() []string {
+	return []string{
+		"AWS::AccountId",
+		"AWS::NotificationARNs",
+		"AWS::NoValue",
+		"AWS::Partition",
+		"AWS::Region",
+		"AWS::StackId",
+		"AWS::StackName",
+		"AWS::URLSuffix",
+	}
+}
+
+// subVariablePattern matches the variables of Fn::Sub. ${!Literal} is not a variable.
+var subVariablePattern = regexp.MustCompile(`\$\{([^!}][^}]*)\}`) //nolint:gochecknoglobals
+
+// cfnReference is the reference from the template value to the parameter or the resource.
+type cfnReference struct {
+	// path is the path of the intrinsic function.
+	path string
+	// function is the intrinsic function. Ref, Fn::GetAtt or Fn::Sub.
+	function string
+	// target is the name of the parameter or the resource.
+	target string
+	// local is true if the target is the variable defined in the Fn::Sub variable map.
+	local bool
+}
+
+// Lint checks the template without AWS. It reports the undefined Ref, Fn::GetAtt and Fn::Sub targets,
+// the unused parameters, the invalid DependsOn, the circular dependencies between the resources and
+// the missing Resources section. The issues are sorted by the path.
+//
+// If the template has Transform (e.g. AWS::Serverless-2016-10-31), the undefined references are warnings
+// because the transform may generate the resources.
+func (t *CFnTemplate) Lint() []CFnLintIssue {
+	issues := make([]CFnLintIssue, 0)
+	add := func(severity CFnLintSeverity, path, format string, a ...any) {
+		issues = append(issues, CFnLintIssue{Severity: severity, Path: path, Message: fmt.Sprintf(format, a...)})
+	}
+
+	resources := t.Section("Resources")
+	if len(resources) == 0 {
+		add(CFnLintSeverityError, "Resources", "the Resources section is required and must have at least one resource")
+	}
+	parameters := t.Section("Parameters")
+	root, _ := t.root.(map[string]any) //nolint:errcheck // NewCFnTemplate guarantees the mapping.
+	undefinedSeverity := CFnLintSeverityError
+	if _, ok := root["Transform"]; ok {
+		undefinedSeverity = CFnLintSeverityWarning
+	}
+
+	isResource := func(name string) bool { _, ok := resources[name]; return ok }
+	isParameter := func(name string) bool { _, ok := parameters[name]; return ok }
+
+	used := make(map[string]bool, len(parameters))
+	dependencies := make(map[string][]string, len(resources))
+	for _, section := range sortedKeys(root) {
+		refs := collectCFnReferences(section, root[section])
+		for _, ref := range refs {
+			if ref.local {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(ref.target, "AWS::"):
+				if ref.function == "Fn::GetAtt" || !slices.Contains(CFnPseudoParameters(), ref.target) {
+					add(CFnLintSeverityError, ref.path, "%s refers to the unknown pseudo parameter %s", ref.function, ref.target)
+				}
+			case ref.function == "Fn::GetAtt" && !isResource(ref.target):
+				add(undefinedSeverity, ref.path, "Fn::GetAtt refers to the undefined resource %s", ref.target)
+			case !isResource(ref.target) && !isParameter(ref.target):
+				add(undefinedSeverity, ref.path, "%s refers to the undefined parameter or resource %s", ref.function, ref.target)
+			case isParameter(ref.target):
+				used[ref.target] = true
+			}
+			if resource, ok := resourceOfPath(ref.path); ok && section == "Resources" && isResource(ref.target) {
+				dependencies[resource] = append(dependencies[resource], ref.target)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(parameters) {
+		if !used[name] {
+			add(CFnLintSeverityWarning, joinTemplatePath("Parameters", name), "the parameter %s is not used", name)
+		}
+	}
+
+	for _, name := range sortedKeys(resources) {
+		path := joinTemplatePath("Resources", name)
+		resource, ok := resources[name].(map[string]any)
+		if !ok {
+			add(CFnLintSeverityError, path, "the resource must be a mapping")
+			continue
+		}
+		if typ, ok := resource["Type"].(string); !ok || typ == "" {
+			add(CFnLintSeverityError, path, "the resource must have Type")
+		}
+		for _, target := range lintDependsOn(joinTemplatePath(path, "DependsOn"), name, resource["DependsOn"], isResource, add) {
+			dependencies[name] = append(dependencies[name], target)
+		}
+	}
+
+	for _, cycle := range findCircularDependencies(dependencies) {
+		add(CFnLintSeverityError, joinTemplatePath("Resources", cycle[0]), "circular dependency: %s", strings.Join(cycle, " -> "))
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Path != issues[j].Path {
+			return issues[i].Path < issues[j].Path
+		}
+		return issues[i].Message < issues[j].Message
+	})
+	return issues
+}
+
+// lintDependsOn checks DependsOn of the resource and returns the valid targets.
+func lintDependsOn(path, resource string, dependsOn any, isResource func(string) bool, add func(CFnLintSeverity, string, string, ...any)) []string {
+	var targets []any
+	switch v := dependsOn.(type) {
+	case nil:
+		return nil
+	case string:
+		targets = []any{v}
+	case []any:
+		targets = v
+	default:
+		add(CFnLintSeverityError, path, "DependsOn must be a resource name or a list of resource names")
+		return nil
+	}
+
+	valid := make([]string, 0, len(targets))
+	for i, v := range targets {
+		p := path
+		if _, ok := dependsOn.([]any); ok {
+			p += "[" + strconv.Itoa(i) + "]"
+		}
+		target, ok := v.(string)
+		switch {
+		case !ok:
+			add(CFnLintSeverityError, p, "DependsOn must be a resource name or a list of resource names")
+		case target == resource:
+			add(CFnLintSeverityError, p, "the resource %s depends on itself", resource)
+		case !isResource(target):
+			add(CFnLintSeverityError, p, "DependsOn refers to the undefined resource %s", target)
+		default:
+			valid = append(valid, target)
+		}
+	}
+	return valid
+}
+
+// collectCFnReferences returns the Ref, Fn::GetAtt and Fn::Sub references in the value.
+func collectCFnReferences(path string, value any) []cfnReference {
+	refs := make([]cfnReference, 0)
+	var walk func(path string, value any)
+	walk = func(path string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			if len(v) == 1 {
+				refs = append(refs, intrinsicReferences(path, v)...)
+			}
+			for _, k := range sortedKeys(v) {
+				walk(joinTemplatePath(path, k), v[k])
+			}
+		case []any:
+			for i, item := range v {
+				walk(path+"["+strconv.Itoa(i)+"]", item)
+			}
+		}
+	}
+	walk(path, value)
+	return refs
+}
+
+// intrinsicReferences returns the references of the intrinsic function in the single-key mapping.
+func intrinsicReferences(path string, m map[string]any) []cfnReference {
+	if target, ok := m["Ref"].(string); ok {
+		return []cfnReference{{path: joinTemplatePath(path, "Ref"), function: "Ref", target: target}}
+	}
+	if getAtt, ok := m["Fn::GetAtt"].([]any); ok && len(getAtt) > 0 {
+		if target, ok := getAtt[0].(string); ok {
+			return []cfnReference{{path: joinTemplatePath(path, "Fn::GetAtt"), function: "Fn::GetAtt", target: target}}
+		}
+	}
+	sub, ok := m["Fn::Sub"]
+	if !ok {
+		return nil
+	}
+	var (
+		s    string
+		vars map[string]any
+	)
+	switch v := sub.(type) {
+	case string:
+		s = v
+	case []any:
+		if len(v) > 0 {
+			s, _ = v[0].(string) //nolint:errcheck // The non-string template is not checked.
+		}
+		if len(v) > 1 {
+			vars, _ = v[1].(map[string]any) //nolint:errcheck // The non-mapping variables are not checked.
+		}
+	}
+
+	refs := make([]cfnReference, 0)
+	for _, match := range subVariablePattern.FindAllStringSubmatch(s, -1) {
+		name := strings.TrimSpace(match[1])
+		function := "Fn::Sub"
+		if _, ok := vars[name]; ok {
+			refs = append(refs, cfnReference{path: joinTemplatePath(path, function), function: function, target: name, local: true})
+			continue
+		}
+		if !strings.HasPrefix(name, "AWS::") {
+			// ${Resource.Attribute} is Fn::GetAtt.
+			if resource, _, found := strings.Cut(name, "."); found {
+				name = resource
+				function = "Fn::GetAtt"
+			}
+		}
+		refs = append(refs, cfnReference{path: joinTemplatePath(path, "Fn::Sub"), function: function, target: name})
+	}
+	return refs
+}
+
+// resourceOfPath returns the logical ID of the resource in the path under the Resources section.
+func resourceOfPath(path string) (string, bool) {
+	rest, found := strings.CutPrefix(path, "Resources.")
+	if !found {
+		return "", false
+	}
+	resource, _, _ := strings.Cut(rest, ".")
+	return resource, true
+}
+
+// findCircularDependencies returns the circular dependencies between the resources.
+// Each cycle starts and ends with the same resource, e.g. [A B A]. Each cycle is reported once.
+func findCircularDependencies(dependencies map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(dependencies))
+	stack := make([]string, 0)
+	cycles := make([][]string, 0)
+	reported := make(map[string]bool)
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		targets := slices.Clone(dependencies[name])
+		sort.Strings(targets)
+		for _, target := range slices.Compact(targets) {
+			switch state[target] {
+			case unvisited:
+				visit(target)
+			case visiting:
+				start := slices.Index(stack, target)
+				cycle := append(slices.Clone(stack[start:]), target)
+				key := cycleKey(cycle)
+				if !reported[key] {
+					reported[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+	for _, name := range sortedKeys(dependencies) {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return cycles
+}
+
+// cycleKey returns the key of the cycle that does not depend on the start of the cycle.
+func cycleKey(cycle []string) string {
+	members := slices.Clone(cycle[:len(cycle)-1])
+	sort.Strings(members)
+	return strings.Join(members, ",")
+}
+
+// sortedKeys returns the keys of the mapping in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
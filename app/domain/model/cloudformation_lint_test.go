@@ -0,0 +1,135 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCFnTemplate_Lint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		want     []CFnLintIssue
+	}{
+		{
+			name: "valid template with the short form and the long form",
+			template: `Parameters:
+  Env:
+    Type: String
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub "${Env}-${AWS::Region}-${!Literal}"
+  Policy:
+    Type: AWS::S3::BucketPolicy
+    DependsOn: Bucket
+    Properties:
+      Bucket: {"Ref": "Bucket"}
+      Resource: !Sub
+        - "${Arn}/*"
+        - Arn: !GetAtt Bucket.Arn
+Outputs:
+  Domain:
+    Value: !Sub "${Bucket.DomainName}"
+`,
+			want: []CFnLintIssue{},
+		},
+		{
+			name:     "missing Resources",
+			template: `AWSTemplateFormatVersion: "2010-09-09"`,
+			want: []CFnLintIssue{
+				{Severity: CFnLintSeverityError, Path: "Resources", Message: "the Resources section is required and must have at least one resource"},
+			},
+		},
+		{
+			name: "undefined references and unused parameter",
+			template: `Parameters:
+  Unused:
+    Type: String
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Ref Name
+      Tags:
+        - Key: arn
+          Value: !GetAtt Topic.Arn
+        - Key: region
+          Value: !Sub "${AWS::Regoin}-${Suffix}"
+`,
+			want: []CFnLintIssue{
+				{Severity: CFnLintSeverityWarning, Path: "Parameters.Unused", Message: "the parameter Unused is not used"},
+				{Severity: CFnLintSeverityError, Path: "Resources.Bucket.Properties.BucketName.Ref", Message: "Ref refers to the undefined parameter or resource Name"},
+				{Severity: CFnLintSeverityError, Path: "Resources.Bucket.Properties.Tags[0].Value.Fn::GetAtt", Message: "Fn::GetAtt refers to the undefined resource Topic"},
+				{Severity: CFnLintSeverityError, Path: "Resources.Bucket.Properties.Tags[1].Value.Fn::Sub", Message: "Fn::Sub refers to the undefined parameter or resource Suffix"},
+				{Severity: CFnLintSeverityError, Path: "Resources.Bucket.Properties.Tags[1].Value.Fn::Sub", Message: "Fn::Sub refers to the unknown pseudo parameter AWS::Regoin"},
+			},
+		},
+		{
+			name: "undefined references are warnings with the transform",
+			template: `Transform: AWS::Serverless-2016-10-31
+Resources:
+  Function:
+    Type: AWS::Serverless::Function
+    Properties:
+      Role: !GetAtt FunctionRole.Arn
+`,
+			want: []CFnLintIssue{
+				{Severity: CFnLintSeverityWarning, Path: "Resources.Function.Properties.Role.Fn::GetAtt", Message: "Fn::GetAtt refers to the undefined resource FunctionRole"},
+			},
+		},
+		{
+			name: "invalid DependsOn and missing Type",
+			template: `Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    DependsOn: [Bucket, Missing]
+  Topic:
+    DependsOn: {Ref: Bucket}
+`,
+			want: []CFnLintIssue{
+				{Severity: CFnLintSeverityError, Path: "Resources.Bucket.DependsOn[0]", Message: "the resource Bucket depends on itself"},
+				{Severity: CFnLintSeverityError, Path: "Resources.Bucket.DependsOn[1]", Message: "DependsOn refers to the undefined resource Missing"},
+				{Severity: CFnLintSeverityError, Path: "Resources.Topic", Message: "the resource must have Type"},
+				{Severity: CFnLintSeverityError, Path: "Resources.Topic.DependsOn", Message: "DependsOn must be a resource name or a list of resource names"},
+			},
+		},
+		{
+			name: "circular dependency through Ref, GetAtt in Sub and DependsOn",
+			template: `Resources:
+  A:
+    Type: AWS::SNS::Topic
+    Properties:
+      TopicName: !Ref B
+  B:
+    Type: AWS::SNS::Topic
+    Properties:
+      TopicName: !Sub "${C.TopicName}"
+  C:
+    Type: AWS::SNS::Topic
+    DependsOn: A
+`,
+			want: []CFnLintIssue{
+				{Severity: CFnLintSeverityError, Path: "Resources.A", Message: "circular dependency: A -> B -> C -> A"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := NewCFnTemplate([]byte(tt.template))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, template.Lint()); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package model
+
+import "strings"
+
+// MaxCFnImportsParallelsCount is the maximum number of exports whose importers are listed in parallel.
+const MaxCFnImportsParallelsCount = 5
+
+// CFnOutput is the output of the CloudFormation stack.
+type CFnOutput struct {
+	// Key is the key of the output.
+	Key string `json:"key"`
+	// Value is the value of the output.
+	Value string `json:"value"`
+	// Description is the description of the output.
+	Description string `json:"description,omitempty"`
+	// ExportName is the name of the export. It is empty if the output is not exported.
+	ExportName string `json:"export_name,omitempty"`
+}
+
+// CFnOutputs is the outputs of the CloudFormation stack.
+type CFnOutputs []CFnOutput
+
+// Find returns the output with the key. If the output does not exist, it returns false.
+func (o CFnOutputs) Find(key string) (CFnOutput, bool) {
+	for _, output := range o {
+		if output.Key == key {
+			return output, true
+		}
+	}
+	return CFnOutput{}, false
+}
+
+// ExportNames returns the names of the exported outputs.
+func (o CFnOutputs) ExportNames() []string {
+	names := make([]string, 0, len(o))
+	for _, output := range o {
+		if output.ExportName != "" {
+			names = append(names, output.ExportName)
+		}
+	}
+	return names
+}
+
+// CFnExport is the value exported by the CloudFormation stack for cross-stack references (Fn::ImportValue).
+type CFnExport struct {
+	// Name is the name of the export. It is unique in the region.
+	Name string
+	// Value is the value of the export.
+	Value string
+	// ExportingStackID is the ID of the stack that exports the value.
+	ExportingStackID string
+	// Importers is the names of the stacks that import the value.
+	Importers []string
+}
+
+// StackNameFromID returns the stack name in the stack ID.
+// e.g. arn:aws:cloudformation:us-east-1:123456789012:stack/NAME/UUID returns NAME.
+// If the ID is not the stack ARN, it returns the ID as it is.
+func StackNameFromID(id string) string {
+	_, resource, found := strings.Cut(id, ":stack/")
+	if !found {
+		return id
+	}
+	name, _, _ := strings.Cut(resource, "/")
+	return name
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCFnOutputs(t *testing.T) {
+	t.Parallel()
+
+	outputs := CFnOutputs{
+		{Key: "Region", Value: "us-east-1"},
+		{Key: "BucketName", Value: "bucket", ExportName: "app-BucketName"},
+	}
+	if got, ok := outputs.Find("BucketName"); !ok || got.Value != "bucket" {
+		t.Errorf("Find(BucketName) = %v, %v, want bucket, true", got, ok)
+	}
+	if _, ok := outputs.Find("Unknown"); ok {
+		t.Error("Find(Unknown) = true, want false")
+	}
+	if diff := cmp.Diff([]string{"app-BucketName"}, outputs.ExportNames()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestStackNameFromID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1a2b3c4d", want: "app"},
+		{id: "arn:aws-cn:cloudformation:cn-north-1:123456789012:stack/app-Nested-1ABC/1a2b3c4d", want: "app-Nested-1ABC"},
+		{id: "app", want: "app"},
+	}
+	for _, tt := range tests {
+		if got := StackNameFromID(tt.id); got != tt.want {
+			t.Errorf("StackNameFromID(%s) = %s, want %s", tt.id, got, tt.want)
+		}
+	}
+}
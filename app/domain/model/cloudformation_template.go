@@ -0,0 +1,264 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CFnTemplateStage is the stage of the template that CloudFormation returns.
+type CFnTemplateStage string
+
+const (
+	// CFnTemplateStageOriginal is the template that the user submitted.
+	CFnTemplateStageOriginal CFnTemplateStage = "Original"
+	// CFnTemplateStageProcessed is the template after all transforms (e.g. AWS::Serverless) are processed.
+	CFnTemplateStageProcessed CFnTemplateStage = "Processed"
+)
+
+// NewCFnTemplateStage returns the CFnTemplateStage. The stage is case-insensitive. If s is empty, it returns CFnTemplateStageOriginal.
+func NewCFnTemplateStage(s string) (CFnTemplateStage, error) {
+	switch strings.ToLower(s) {
+	case "", strings.ToLower(string(CFnTemplateStageOriginal)):
+		return CFnTemplateStageOriginal, nil
+	case strings.ToLower(string(CFnTemplateStageProcessed)):
+		return CFnTemplateStageProcessed, nil
+	default:
+		return "", fmt.Errorf("unknown template stage: %s (Original or Processed)", s)
+	}
+}
+
+// String returns the string representation of the CFnTemplateStage.
+func (s CFnTemplateStage) String() string {
+	return string(s)
+}
+
+// CFnTemplate is the parsed CloudFormation template in YAML or JSON.
+// The short form of the intrinsic functions (e.g. !Ref) is converted to the long form (e.g. {"Ref": ...}),
+// and the scalar values are converted to strings, so that the templates can be compared semantically.
+type CFnTemplate struct {
+	// root is the template. The value is map[string]any, []any, string or nil.
+	root any
+}
+
+// NewCFnTemplate parses the template body in YAML or JSON.
+func NewCFnTemplate(body []byte) (*CFnTemplate, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("can not parse the template: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, errors.New("the template is empty")
+	}
+	root, err := convertTemplateNode(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := root.(map[string]any); !ok {
+		return nil, errors.New("the template must be a mapping")
+	}
+	return &CFnTemplate{root: root}, nil
+}
+
+// Section returns the top-level section of the template (e.g. Resources). If the section does not exist, it returns nil.
+func (t *CFnTemplate) Section(name string) map[string]any {
+	section, ok := t.root.(map[string]any)[name].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return section
+}
+
+// HasSection returns true if the template has the top-level section (e.g. AWSTemplateFormatVersion).
+func (t *CFnTemplate) HasSection(name string) bool {
+	_, ok := t.root.(map[string]any)[name]
+	return ok
+}
+
+// convertTemplateNode converts the YAML node to map[string]any, []any, string or nil.
+// The short form of the intrinsic functions is converted to the long form.
+func convertTemplateNode(node *yaml.Node) (any, error) {
+	var value any
+	switch node.Kind {
+	case yaml.AliasNode:
+		return convertTemplateNode(node.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := convertTemplateNode(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[node.Content[i].Value] = v
+		}
+		value = m
+	case yaml.SequenceNode:
+		s := make([]any, 0, len(node.Content))
+		for _, n := range node.Content {
+			v, err := convertTemplateNode(n)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		value = s
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!null" {
+			value = nil
+		} else {
+			value = node.Value
+		}
+	default:
+		return nil, fmt.Errorf("unsupported YAML node at line %d", node.Line)
+	}
+
+	if !isIntrinsicFunctionTag(node.Tag) {
+		return normalizeGetAtt(value), nil
+	}
+	name := strings.TrimPrefix(node.Tag, "!")
+	if name != "Ref" && name != "Condition" {
+		name = "Fn::" + name
+	}
+	return normalizeGetAtt(map[string]any{name: value}), nil
+}
+
+// isIntrinsicFunctionTag returns true if the tag is the short form of the intrinsic function (e.g. !Ref, !Sub).
+func isIntrinsicFunctionTag(tag string) bool {
+	return strings.HasPrefix(tag, "!") && !strings.HasPrefix(tag, "!!")
+}
+
+// normalizeGetAtt converts {"Fn::GetAtt": "Resource.Attribute"} to {"Fn::GetAtt": ["Resource", "Attribute"]}.
+// Both forms are valid, and the short form !GetAtt Resource.Attribute is converted to the former.
+func normalizeGetAtt(value any) any {
+	m, ok := value.(map[string]any)
+	if !ok || len(m) != 1 {
+		return value
+	}
+	s, ok := m["Fn::GetAtt"].(string)
+	if !ok {
+		return value
+	}
+	resource, attribute, found := strings.Cut(s, ".")
+	if !found {
+		return value
+	}
+	return map[string]any{"Fn::GetAtt": []any{resource, attribute}}
+}
+
+// CFnTemplateDifferenceType is the type of the difference between the templates.
+type CFnTemplateDifferenceType string
+
+const (
+	// CFnTemplateDifferenceTypeAdd means that the value is added.
+	CFnTemplateDifferenceTypeAdd CFnTemplateDifferenceType = "ADD"
+	// CFnTemplateDifferenceTypeRemove means that the value is removed.
+	CFnTemplateDifferenceTypeRemove CFnTemplateDifferenceType = "REMOVE"
+	// CFnTemplateDifferenceTypeChange means that the value is changed.
+	CFnTemplateDifferenceTypeChange CFnTemplateDifferenceType = "CHANGE"
+)
+
+// CFnTemplateDifference is the difference between the templates.
+type CFnTemplateDifference struct {
+	// Path is the path of the value. e.g. Resources.Bucket.Properties.Tags[0].Value
+	Path string
+	// Type is the type of the difference.
+	Type CFnTemplateDifferenceType
+	// Old is the value in the old template in JSON. It is empty for the added value.
+	Old string
+	// New is the value in the new template in JSON. It is empty for the removed value.
+	New string
+}
+
+// Diff returns the differences from the template t to the template other, sorted by the path.
+// The key ordering and the forms of the intrinsic functions are not regarded as differences.
+func (t *CFnTemplate) Diff(other *CFnTemplate) []CFnTemplateDifference {
+	diffs := make([]CFnTemplateDifference, 0)
+	diffTemplateValue("", t.root, other.root, &diffs)
+	return diffs
+}
+
+// diffTemplateValue appends the differences between the old and new values to diffs.
+func diffTemplateValue(path string, oldValue, newValue any, diffs *[]CFnTemplateDifference) {
+	switch o := oldValue.(type) {
+	case map[string]any:
+		n, ok := newValue.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(o)+len(n))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		for k := range n {
+			if _, ok := o[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := joinTemplatePath(path, k)
+			ov, inOld := o[k]
+			nv, inNew := n[k]
+			switch {
+			case !inOld:
+				*diffs = append(*diffs, CFnTemplateDifference{Path: childPath, Type: CFnTemplateDifferenceTypeAdd, New: templateValueJSON(nv)})
+			case !inNew:
+				*diffs = append(*diffs, CFnTemplateDifference{Path: childPath, Type: CFnTemplateDifferenceTypeRemove, Old: templateValueJSON(ov)})
+			default:
+				diffTemplateValue(childPath, ov, nv, diffs)
+			}
+		}
+		return
+	case []any:
+		n, ok := newValue.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(o), len(n)); i++ {
+			childPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(o):
+				*diffs = append(*diffs, CFnTemplateDifference{Path: childPath, Type: CFnTemplateDifferenceTypeAdd, New: templateValueJSON(n[i])})
+			case i >= len(n):
+				*diffs = append(*diffs, CFnTemplateDifference{Path: childPath, Type: CFnTemplateDifferenceTypeRemove, Old: templateValueJSON(o[i])})
+			default:
+				diffTemplateValue(childPath, o[i], n[i], diffs)
+			}
+		}
+		return
+	default:
+		if oldValue == newValue {
+			return
+		}
+	}
+	*diffs = append(*diffs, CFnTemplateDifference{
+		Path: path,
+		Type: CFnTemplateDifferenceTypeChange,
+		Old:  templateValueJSON(oldValue),
+		New:  templateValueJSON(newValue),
+	})
+}
+
+// joinTemplatePath returns the path of the key under the path.
+func joinTemplatePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// templateValueJSON returns the value in compact JSON. The keys of the maps are sorted.
+func templateValueJSON(value any) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false) // The values often contain <, > or & (e.g. the policy conditions).
+	if err := enc.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewCFnTemplateStage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    CFnTemplateStage
+		wantErr bool
+	}{
+		{in: "", want: CFnTemplateStageOriginal},
+		{in: "original", want: CFnTemplateStageOriginal},
+		{in: "Processed", want: CFnTemplateStageProcessed},
+		{in: "deployed", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NewCFnTemplateStage(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewCFnTemplateStage(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("NewCFnTemplateStage(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCFnTemplate_Diff(t *testing.T) {
+	t.Parallel()
+
+	const yamlTemplate = `
+AWSTemplateFormatVersion: "2010-09-09"
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub "${AWS::StackName}-bucket"
+      VersioningConfiguration:
+        Status: Enabled
+  Queue:
+    Type: AWS::SQS::Queue
+    Properties:
+      DelaySeconds: 5
+Outputs:
+  BucketArn:
+    Value: !GetAtt Bucket.Arn
+  QueueURL:
+    Value: !Ref Queue
+    Condition: IsProd
+`
+
+	tests := []struct {
+		name    string
+		oldBody string
+		newBody string
+		want    []CFnTemplateDifference
+	}{
+		{
+			name:    "the same template in the long form JSON with the different key ordering",
+			oldBody: yamlTemplate,
+			newBody: `{
+  "Outputs": {
+    "QueueURL": {"Condition": "IsProd", "Value": {"Ref": "Queue"}},
+    "BucketArn": {"Value": {"Fn::GetAtt": ["Bucket", "Arn"]}}
+  },
+  "Resources": {
+    "Queue": {"Properties": {"DelaySeconds": "5"}, "Type": "AWS::SQS::Queue"},
+    "Bucket": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {
+        "VersioningConfiguration": {"Status": "Enabled"},
+        "BucketName": {"Fn::Sub": "${AWS::StackName}-bucket"}
+      }
+    }
+  },
+  "AWSTemplateFormatVersion": "2010-09-09"
+}`,
+			want: []CFnTemplateDifference{},
+		},
+		{
+			name:    "added, removed and changed values",
+			oldBody: yamlTemplate,
+			newBody: `
+AWSTemplateFormatVersion: "2010-09-09"
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub "${AWS::StackName}-bucket"
+      VersioningConfiguration:
+        Status: Suspended
+      Tags:
+        - Key: env
+          Value: dev
+Outputs:
+  BucketArn:
+    Value: !GetAtt [Bucket, Arn]
+  QueueURL:
+    Value: !Ref Queue
+    Condition: IsProd
+`,
+			want: []CFnTemplateDifference{
+				{Path: "Resources.Bucket.Properties.Tags", Type: CFnTemplateDifferenceTypeAdd, New: `[{"Key":"env","Value":"dev"}]`},
+				{Path: "Resources.Bucket.Properties.VersioningConfiguration.Status", Type: CFnTemplateDifferenceTypeChange, Old: `"Enabled"`, New: `"Suspended"`},
+				{Path: "Resources.Queue", Type: CFnTemplateDifferenceTypeRemove, Old: `{"Properties":{"DelaySeconds":"5"},"Type":"AWS::SQS::Queue"}`},
+			},
+		},
+		{
+			name:    "the changed type of the value and the list items",
+			oldBody: `{"Resources": {"Topic": {"Type": "AWS::SNS::Topic", "Properties": {"DisplayName": {"Ref": "Name"}, "Subscription": [{"Endpoint": "a"}]}}}}`,
+			newBody: `{"Resources": {"Topic": {"Type": "AWS::SNS::Topic", "Properties": {"DisplayName": "name", "Subscription": [{"Endpoint": "a"}, {"Endpoint": "b"}]}}}}`,
+			want: []CFnTemplateDifference{
+				{Path: "Resources.Topic.Properties.DisplayName", Type: CFnTemplateDifferenceTypeChange, Old: `{"Ref":"Name"}`, New: `"name"`},
+				{Path: "Resources.Topic.Properties.Subscription[1]", Type: CFnTemplateDifferenceTypeAdd, New: `{"Endpoint":"b"}`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			oldTemplate, err := NewCFnTemplate([]byte(tt.oldBody))
+			if err != nil {
+				t.Fatal(err)
+			}
+			newTemplate, err := NewCFnTemplate([]byte(tt.newBody))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, oldTemplate.Diff(newTemplate)); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewCFnTemplate_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, body := range []string{"", "- a\n- b\n", "Resources: [\n"} {
+		if _, err := NewCFnTemplate([]byte(body)); err == nil {
+			t.Errorf("NewCFnTemplate(%q) error = nil, want error", body)
+		}
+	}
+}
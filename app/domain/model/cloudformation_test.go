@@ -0,0 +1,101 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewCFnTag(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewCFnTag("env=dev=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (CFnTag{Key: "env", Value: "dev=1"}); got != want {
+		t.Errorf("NewCFnTag() = %v, want %v", got, want)
+	}
+	if got, err := NewCFnTag("env="); err != nil || got.Value != "" {
+		t.Errorf("NewCFnTag(\"env=\") = %v, %v, want empty value", got, err)
+	}
+
+	for _, s := range []string{"env", "=dev", "", "aws:cloudformation:stack-name=app", "AWS:env=dev", strings.Repeat("k", MaxCFnTagKeyLength+1) + "=v", "env=" + strings.Repeat("v", MaxCFnTagValueLength+1)} {
+		if _, err := NewCFnTag(s); err == nil {
+			t.Errorf("NewCFnTag(%q) should return error", s)
+		}
+	}
+}
+
+func TestCFnTags(t *testing.T) {
+	t.Parallel()
+
+	tags := CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: "infra"}}
+
+	t.Run("merge overwrites the existing key and appends the new key", func(t *testing.T) {
+		t.Parallel()
+
+		got := tags.Merge(CFnTags{{Key: "env", Value: "prd"}, {Key: "owner", Value: "nao"}})
+		want := CFnTags{{Key: "env", Value: "prd"}, {Key: "team", Value: "infra"}, {Key: "owner", Value: "nao"}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+		if tags[0].Value != "dev" {
+			t.Error("Merge() must not modify the receiver")
+		}
+	})
+
+	t.Run("remove ignores the key that does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		got := tags.Remove([]string{"env", "owner"})
+		if diff := cmp.Diff(CFnTags{{Key: "team", Value: "infra"}}, got); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("equal ignores the order", func(t *testing.T) {
+		t.Parallel()
+
+		if !tags.Equal(CFnTags{{Key: "team", Value: "infra"}, {Key: "env", Value: "dev"}}) {
+			t.Error("Equal() = false, want true")
+		}
+		if tags.Equal(CFnTags{{Key: "env", Value: "prd"}, {Key: "team", Value: "infra"}}) {
+			t.Error("Equal() = true, want false")
+		}
+		if tags.Equal(CFnTags{{Key: "env", Value: "dev"}}) {
+			t.Error("Equal() = true, want false")
+		}
+	})
+}
+
+func TestStackStatus_InProgress(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []StackStatus{StackStatusCreateInProgress, StackStatusUpdateCompleteCleanupInProgress, StackStatusReviewInProgress} {
+		if !s.InProgress() {
+			t.Errorf("%s.InProgress() = false, want true", s)
+		}
+	}
+	for _, s := range []StackStatus{StackStatusCreateComplete, StackStatusUpdateRollbackFailed, StackStatusDeleteComplete} {
+		if s.InProgress() {
+			t.Errorf("%s.InProgress() = true, want false", s)
+		}
+	}
+}
+
+func TestResourceStatus_Failed(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []ResourceStatus{ResourceStatusCreateFailed, ResourceStatusDeleteFailed, ResourceStatusUpdateRollbackFailed} {
+		if !s.Failed() {
+			t.Errorf("%s.Failed() = false, want true", s)
+		}
+	}
+	for _, s := range []ResourceStatus{ResourceStatusCreateComplete, ResourceStatusDeleteSkipped, ResourceStatusUpdateInProgress} {
+		if s.Failed() {
+			t.Errorf("%s.Failed() = true, want false", s)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package model
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/nao1215/rainbow/app/domain"
+)
+
+// Region is the name of the AWS region.
+type Region string
+
+const (
+	// RegionUSEast1 US East (N. Virginia)
+	RegionUSEast1 Region = "us-east-1"
+	// RegionUSEast2 US East (Ohio)
+	RegionUSEast2 Region = "us-east-2"
+	// RegionUSWest1 US West (N. California)
+	RegionUSWest1 Region = "us-west-1"
+	// RegionUSWest2 US West (Oregon)
+	RegionUSWest2 Region = "us-west-2"
+	// RegionAFSouth1 Africa (Cape Town)
+	RegionAFSouth1 Region = "af-south-1"
+	// RegionAPEast1 Asia Pacific (Hong Kong)
+	RegionAPEast1 Region = "ap-east-1"
+	// RegionAPEast2 Asia Pacific (Taipei)
+	RegionAPEast2 Region = "ap-east-2"
+	// RegionAPSouth1 Asia Pacific (Mumbai)
+	RegionAPSouth1 Region = "ap-south-1"
+	// RegionAPSouth2 Asia Pacific (Hyderabad)
+	RegionAPSouth2 Region = "ap-south-2"
+	// RegionAPNortheast1 Asia Pacific (Tokyo)
+	RegionAPNortheast1 Region = "ap-northeast-1"
+	// RegionAPNortheast2 Asia Pacific (Seoul)
+	RegionAPNortheast2 Region = "ap-northeast-2"
+	// RegionAPNortheast3 Asia Pacific (Osaka-Local)
+	RegionAPNortheast3 Region = "ap-northeast-3"
+	// RegionAPSoutheast1 Asia Pacific (Singapore)
+	RegionAPSoutheast1 Region = "ap-southeast-1"
+	// RegionAPSoutheast2 Asia Pacific (Sydney)
+	RegionAPSoutheast2 Region = "ap-southeast-2"
+	// RegionAPSoutheast3 Asia Pacific (Jakarta)
+	RegionAPSoutheast3 Region = "ap-southeast-3"
+	// RegionAPSoutheast4 Asia Pacific (Melbourne)
+	RegionAPSoutheast4 Region = "ap-southeast-4"
+	// RegionAPSoutheast5 Asia Pacific (Malaysia)
+	RegionAPSoutheast5 Region = "ap-southeast-5"
+	// RegionAPSoutheast7 Asia Pacific (Thailand)
+	RegionAPSoutheast7 Region = "ap-southeast-7"
+	// RegionCACentral1 Canada (Central)
+	RegionCACentral1 Region = "ca-central-1"
+	// RegionCAWest1 Canada West (Calgary)
+	RegionCAWest1 Region = "ca-west-1"
+	// RegionCNNorth1 China (Beijing)
+	RegionCNNorth1 Region = "cn-north-1"
+	// RegionCNNorthwest1 China (Ningxia)
+	RegionCNNorthwest1 Region = "cn-northwest-1"
+	// RegionEUCentral1 Europe (Frankfurt)
+	RegionEUCentral1 Region = "eu-central-1"
+	// RegionEUCentral2 Europe (Zurich)
+	RegionEUCentral2 Region = "eu-central-2"
+	// RegionEUNorth1 Europe (Stockholm)
+	RegionEUNorth1 Region = "eu-north-1"
+	// RegionEUSouth1 Europe (Milan)
+	RegionEUSouth1 Region = "eu-south-1"
+	// RegionEUSouth2 Europe (Spain)
+	RegionEUSouth2 Region = "eu-south-2"
+	// RegionEUWest1 Europe (Ireland)
+	RegionEUWest1 Region = "eu-west-1"
+	// RegionEUWest2 Europe (London)
+	RegionEUWest2 Region = "eu-west-2"
+	// RegionEUWest3 Europe (Paris)
+	RegionEUWest3 Region = "eu-west-3"
+	// RegionILCentral1 Israel (Tel Aviv)
+	RegionILCentral1 Region = "il-central-1"
+	// RegionMECentral1 Middle East (UAE)
+	RegionMECentral1 Region = "me-central-1"
+	// RegionMESouth1 Middle East (Bahrain)
+	RegionMESouth1 Region = "me-south-1"
+	// RegionMXCentral1 Mexico (Central)
+	RegionMXCentral1 Region = "mx-central-1"
+	// RegionSAEast1 South America (São Paulo)
+	RegionSAEast1 Region = "sa-east-1"
+	// RegionSASouth1 South America (São Paulo)
+	//
+	// Deprecated: "sa-south-1" does not exist. Use RegionSAEast1.
+	RegionSASouth1 = RegionSAEast1
+	// RegionUSGovEast1 AWS GovCloud (US-East)
+	RegionUSGovEast1 Region = "us-gov-east-1"
+	// RegionUSGovWest1 AWS GovCloud (US)
+	RegionUSGovWest1 Region = "us-gov-west-1"
+)
+
+// Partition is the AWS partition, the group of regions that share the DNS suffix and the credentials.
+type Partition string
+
+const (
+	// PartitionAWS is the standard partition.
+	PartitionAWS Partition = "aws"
+	// PartitionAWSCN is the China partition.
+	PartitionAWSCN Partition = "aws-cn"
+	// PartitionAWSUSGov is the AWS GovCloud (US) partition.
+	PartitionAWSUSGov Partition = "aws-us-gov"
+)
+
+// String returns the string representation of the Partition.
+func (p Partition) String() string {
+	return string(p)
+}
+
+// DNSSuffix returns the DNS suffix of the partition. If the partition is unknown, it returns "".
+func (p Partition) DNSSuffix() string {
+	switch p {
+	case PartitionAWS, PartitionAWSUSGov:
+		return "amazonaws.com"
+	case PartitionAWSCN:
+		return "amazonaws.com.cn"
+	default:
+		return ""
+	}
+}
+
+// RegionInfo is the entry of the region catalog.
+type RegionInfo struct {
+	// Region is the region name. e.g. "us-east-1"
+	Region Region
+	// Partition is the partition that the region belongs to.
+	Partition Partition
+	// DisplayName is the human-readable name. e.g. "US East (N. Virginia)"
+	DisplayName string
+	// S3EndpointSuffix is the DNS suffix of the S3 endpoint. e.g. "amazonaws.com"
+	S3EndpointSuffix string
+	// OptIn is whether the region must be enabled for the account before use.
+	OptIn bool
+}
+
+// regionCatalog is the list of regions that rainbow knows. The order is used by Next and Prev.
+var regionCatalog = defaultRegionCatalog() //nolint:gochecknoglobals
+
+// defaultRegionCatalog returns the built-in region catalog.
+func defaultRegionCatalog() []RegionInfo {
+	catalog := []RegionInfo{
+		{Region: RegionUSEast1, Partition: PartitionAWS, DisplayName: "US East (N. Virginia)"},
+		{Region: RegionUSEast2, Partition: PartitionAWS, DisplayName: "US East (Ohio)"},
+		{Region: RegionUSWest1, Partition: PartitionAWS, DisplayName: "US West (N. California)"},
+		{Region: RegionUSWest2, Partition: PartitionAWS, DisplayName: "US West (Oregon)"},
+		{Region: RegionAFSouth1, Partition: PartitionAWS, DisplayName: "Africa (Cape Town)", OptIn: true},
+		{Region: RegionAPEast1, Partition: PartitionAWS, DisplayName: "Asia Pacific (Hong Kong)", OptIn: true},
+		{Region: RegionAPEast2, Partition: PartitionAWS, DisplayName: "Asia Pacific (Taipei)", OptIn: true},
+		{Region: RegionAPSouth1, Partition: PartitionAWS, DisplayName: "Asia Pacific (Mumbai)"},
+		{Region: RegionAPSouth2, Partition: PartitionAWS, DisplayName: "Asia Pacific (Hyderabad)", OptIn: true},
+		{Region: RegionAPNortheast1, Partition: PartitionAWS, DisplayName: "Asia Pacific (Tokyo)"},
+		{Region: RegionAPNortheast2, Partition: PartitionAWS, DisplayName: "Asia Pacific (Seoul)"},
+		{Region: RegionAPNortheast3, Partition: PartitionAWS, DisplayName: "Asia Pacific (Osaka)"},
+		{Region: RegionAPSoutheast1, Partition: PartitionAWS, DisplayName: "Asia Pacific (Singapore)"},
+		{Region: RegionAPSoutheast2, Partition: PartitionAWS, DisplayName: "Asia Pacific (Sydney)"},
+		{Region: RegionAPSoutheast3, Partition: PartitionAWS, DisplayName: "Asia Pacific (Jakarta)", OptIn: true},
+		{Region: RegionAPSoutheast4, Partition: PartitionAWS, DisplayName: "Asia Pacific (Melbourne)", OptIn: true},
+		{Region: RegionAPSoutheast5, Partition: PartitionAWS, DisplayName: "Asia Pacific (Malaysia)", OptIn: true},
+		{Region: RegionAPSoutheast7, Partition: PartitionAWS, DisplayName: "Asia Pacific (Thailand)", OptIn: true},
+		{Region: RegionCACentral1, Partition: PartitionAWS, DisplayName: "Canada (Central)"},
+		{Region: RegionCAWest1, Partition: PartitionAWS, DisplayName: "Canada West (Calgary)", OptIn: true},
+		{Region: RegionCNNorth1, Partition: PartitionAWSCN, DisplayName: "China (Beijing)"},
+		{Region: RegionCNNorthwest1, Partition: PartitionAWSCN, DisplayName: "China (Ningxia)"},
+		{Region: RegionEUCentral1, Partition: PartitionAWS, DisplayName: "Europe (Frankfurt)"},
+		{Region: RegionEUCentral2, Partition: PartitionAWS, DisplayName: "Europe (Zurich)", OptIn: true},
+		{Region: RegionEUNorth1, Partition: PartitionAWS, DisplayName: "Europe (Stockholm)"},
+		{Region: RegionEUSouth1, Partition: PartitionAWS, DisplayName: "Europe (Milan)", OptIn: true},
+		{Region: RegionEUSouth2, Partition: PartitionAWS, DisplayName: "Europe (Spain)", OptIn: true},
+		{Region: RegionEUWest1, Partition: PartitionAWS, DisplayName: "Europe (Ireland)"},
+		{Region: RegionEUWest2, Partition: PartitionAWS, DisplayName: "Europe (London)"},
+		{Region: RegionEUWest3, Partition: PartitionAWS, DisplayName: "Europe (Paris)"},
+		{Region: RegionILCentral1, Partition: PartitionAWS, DisplayName: "Israel (Tel Aviv)", OptIn: true},
+		{Region: RegionMECentral1, Partition: PartitionAWS, DisplayName: "Middle East (UAE)", OptIn: true},
+		{Region: RegionMESouth1, Partition: PartitionAWS, DisplayName: "Middle East (Bahrain)", OptIn: true},
+		{Region: RegionMXCentral1, Partition: PartitionAWS, DisplayName: "Mexico (Central)", OptIn: true},
+		{Region: RegionSAEast1, Partition: PartitionAWS, DisplayName: "South America (São Paulo)"},
+		{Region: RegionUSGovEast1, Partition: PartitionAWSUSGov, DisplayName: "AWS GovCloud (US-East)"},
+		{Region: RegionUSGovWest1, Partition: PartitionAWSUSGov, DisplayName: "AWS GovCloud (US-West)"},
+	}
+	for i := range catalog {
+		catalog[i].S3EndpointSuffix = catalog[i].Partition.DNSSuffix()
+	}
+	return catalog
+}
+
+// regionCatalogMu protects regionCatalog because RegisterRegions may be called while the catalog is read.
+var regionCatalogMu sync.RWMutex //nolint:gochecknoglobals
+
+// RegisterRegions adds the regions to the region catalog, or replaces the entries that have the same name.
+// It is used to extend the catalog from the user configuration, e.g. for the regions launched after the release.
+// If S3EndpointSuffix is empty, the DNS suffix of the partition is used. If Partition is empty, PartitionAWS is used.
+func RegisterRegions(infos ...RegionInfo) error {
+	for i := range infos {
+		if infos[i].Region == "" {
+			return domain.ErrEmptyRegion
+		}
+		if infos[i].Partition == "" {
+			infos[i].Partition = PartitionAWS
+		}
+		if infos[i].S3EndpointSuffix == "" {
+			infos[i].S3EndpointSuffix = infos[i].Partition.DNSSuffix()
+		}
+		if infos[i].S3EndpointSuffix == "" {
+			return fmt.Errorf("%w: %s: the S3 endpoint suffix of the partition %s is unknown",
+				domain.ErrInvalidRegion, infos[i].Region, infos[i].Partition)
+		}
+	}
+
+	regionCatalogMu.Lock()
+	defer regionCatalogMu.Unlock()
+	for _, info := range infos {
+		replaced := false
+		for i := range regionCatalog {
+			if regionCatalog[i].Region == info.Region {
+				regionCatalog[i] = info
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			regionCatalog = append(regionCatalog, info)
+		}
+	}
+	return nil
+}
+
+// RegionInfos returns the copy of the region catalog.
+func RegionInfos() []RegionInfo {
+	regionCatalogMu.RLock()
+	defer regionCatalogMu.RUnlock()
+	return append([]RegionInfo{}, regionCatalog...)
+}
+
+// Regions returns all regions that rainbow knows.
+func Regions() []Region {
+	infos := RegionInfos()
+	regions := make([]Region, 0, len(infos))
+	for _, info := range infos {
+		regions = append(regions, info.Region)
+	}
+	return regions
+}
+
+// Info returns the entry of the region catalog. If the region is not in the catalog, ok is false.
+func (r Region) Info() (info RegionInfo, ok bool) {
+	regionCatalogMu.RLock()
+	defer regionCatalogMu.RUnlock()
+	for _, info := range regionCatalog {
+		if info.Region == r {
+			return info, true
+		}
+	}
+	return RegionInfo{}, false
+}
+
+// Validate returns true if the Region exists.
+func (r Region) Validate() error {
+	if r == "" {
+		return domain.ErrEmptyRegion
+	}
+	if _, ok := r.Info(); !ok {
+		return domain.ErrInvalidRegion
+	}
+	return nil
+}
+
+// String returns the string representation of the Region.
+func (r Region) String() string {
+	return string(r)
+}
+
+// Partition returns the partition of the region. If the region is unknown, it returns PartitionAWS.
+func (r Region) Partition() Partition {
+	if info, ok := r.Info(); ok {
+		return info.Partition
+	}
+	return PartitionAWS
+}
+
+// DisplayName returns the human-readable name of the region. If the region is unknown, it returns "".
+func (r Region) DisplayName() string {
+	info, _ := r.Info()
+	return info.DisplayName
+}
+
+// Next returns the next region.
+// If the region is the last one, it returns the first region.
+// If the region is invalid, it returns "ap-northeast-1".
+func (r Region) Next() Region {
+	regions := Regions()
+	for i, region := range regions {
+		if r == region {
+			if i == len(regions)-1 {
+				return regions[0]
+			}
+			return regions[i+1]
+		}
+	}
+	return RegionAPNortheast1
+}
+
+// Prev returns the previous region.
+// If the region is the first one, it returns the last region.
+// If the region is invalid, it returns "ap-northeast-1".
+func (r Region) Prev() Region {
+	regions := Regions()
+	for i, region := range regions {
+		if r == region {
+			if i == 0 {
+				return regions[len(regions)-1]
+			}
+			return regions[i-1]
+		}
+	}
+	return RegionAPNortheast1
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain"
+)
+
+func TestRegion_Info(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		r      Region
+		want   RegionInfo
+		wantOK bool
+	}{
+		{
+			name: "success. aws partition",
+			r:    RegionAPSoutheast3,
+			want: RegionInfo{
+				Region:           RegionAPSoutheast3,
+				Partition:        PartitionAWS,
+				DisplayName:      "Asia Pacific (Jakarta)",
+				S3EndpointSuffix: "amazonaws.com",
+				OptIn:            true,
+			},
+			wantOK: true,
+		},
+		{
+			name: "success. aws-cn partition",
+			r:    RegionCNNorthwest1,
+			want: RegionInfo{
+				Region:           RegionCNNorthwest1,
+				Partition:        PartitionAWSCN,
+				DisplayName:      "China (Ningxia)",
+				S3EndpointSuffix: "amazonaws.com.cn",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "failure. unknown region",
+			r:      Region("invalid"),
+			want:   RegionInfo{},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := tt.r.Info()
+			if ok != tt.wantOK {
+				t.Errorf("Region.Info() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegion_Partition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		r    Region
+		want Partition
+	}{
+		{name: "aws", r: RegionEUSouth2, want: PartitionAWS},
+		{name: "aws-cn", r: RegionCNNorth1, want: PartitionAWSCN},
+		{name: "aws-us-gov", r: RegionUSGovEast1, want: PartitionAWSUSGov},
+		{name: "unknown region is aws", r: Region("invalid"), want: PartitionAWS},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.r.Partition(); got != tt.want {
+				t.Errorf("Region.Partition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterRegions(t *testing.T) { //nolint:paralleltest
+	// RegisterRegions changes the global region catalog, so this test is not run in parallel.
+	t.Cleanup(func() {
+		regionCatalogMu.Lock()
+		defer regionCatalogMu.Unlock()
+		regionCatalog = defaultRegionCatalog()
+	})
+
+	t.Run("success to add a new region and replace the existing region", func(t *testing.T) {
+		err := RegisterRegions(
+			RegionInfo{Region: Region("xx-test-1"), DisplayName: "Test"},
+			RegionInfo{Region: RegionUSEast1, Partition: PartitionAWS, DisplayName: "Virginia"},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := Region("xx-test-1").Validate(); err != nil {
+			t.Errorf("Region.Validate() error = %v", err)
+		}
+		if got := Bucket("abc").Domain(Region("xx-test-1")); got != "abc.s3.xx-test-1.amazonaws.com" {
+			t.Errorf("Bucket.Domain() = %v", got)
+		}
+		if got := RegionUSEast1.DisplayName(); got != "Virginia" {
+			t.Errorf("Region.DisplayName() = %v, want Virginia", got)
+		}
+		if got := RegionUSGovWest1.Next(); got != Region("xx-test-1") {
+			t.Errorf("Region.Next() = %v, want xx-test-1", got)
+		}
+		if got := RegionUSEast1.Prev(); got != Region("xx-test-1") {
+			t.Errorf("Region.Prev() = %v, want xx-test-1", got)
+		}
+	})
+
+	t.Run("fail to add the region without name", func(t *testing.T) {
+		if err := RegisterRegions(RegionInfo{DisplayName: "Test"}); !errors.Is(err, domain.ErrEmptyRegion) {
+			t.Errorf("want ErrEmptyRegion, but got %v", err)
+		}
+	})
+
+	t.Run("fail to add the region of unknown partition without S3 endpoint suffix", func(t *testing.T) {
+		err := RegisterRegions(RegionInfo{Region: Region("xx-test-2"), Partition: Partition("aws-unknown")})
+		if !errors.Is(err, domain.ErrInvalidRegion) {
+			t.Errorf("want ErrInvalidRegion, but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the default maximum number of attempts including the first request.
+	DefaultRetryMaxAttempts = 5
+	// DefaultRetryBaseDelay is the default base delay of the exponential backoff.
+	DefaultRetryBaseDelay = 200 * time.Millisecond
+	// DefaultRetryThrottleBaseDelay is the default base delay of the exponential backoff for the throttling errors
+	// (e.g. SlowDown, Throttling). It is longer than DefaultRetryBaseDelay so that the server can recover.
+	DefaultRetryThrottleBaseDelay = time.Second
+	// DefaultRetryMaxBackoff is the default upper limit of the delay between attempts.
+	DefaultRetryMaxBackoff = 20 * time.Second
+	// DefaultRetryBudget is the default size of the retry budget. Each retry consumes RetryCost tokens.
+	DefaultRetryBudget = 500
+	// RetryCost is the number of tokens consumed by a retry. The tokens are returned when the retried request succeeds.
+	RetryCost = 5
+)
+
+// RetryPolicy is the retry policy shared by all AWS clients.
+// The delay before the n-th retry is a random duration in [0, min(MaxBackoff, BaseDelay * 2^(n-1))) (full jitter).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first request. 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is the base delay of the exponential backoff.
+	BaseDelay time.Duration
+	// ThrottleBaseDelay is the base delay of the exponential backoff for the throttling errors.
+	ThrottleBaseDelay time.Duration
+	// MaxBackoff is the upper limit of the delay between attempts. 0 means DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+	// Budget is the number of tokens for retries per client. When the tokens run out, failed requests are not retried
+	// until successful requests refill them. It prevents the retry storm when the service is down.
+	Budget int
+	// Operations is the per-operation overrides keyed by the API operation name. e.g. "DeleteObjects"
+	// Zero values in the override mean that the value of the policy is used.
+	Operations map[string]RetryPolicy
+}
+
+// DefaultRetryPolicy returns the default retry policy.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:       DefaultRetryMaxAttempts,
+		BaseDelay:         DefaultRetryBaseDelay,
+		ThrottleBaseDelay: DefaultRetryThrottleBaseDelay,
+		MaxBackoff:        DefaultRetryMaxBackoff,
+		Budget:            DefaultRetryBudget,
+		Operations: map[string]RetryPolicy{
+			// DeleteObjects often returns SlowDown when many objects are deleted in parallel.
+			"DeleteObjects": {
+				MaxAttempts:       MaxS3DeleteObjectsRetryCount,
+				ThrottleBaseDelay: S3DeleteObjectsDelayTimeSec * time.Second,
+			},
+		},
+	}
+}
+
+// Validate validates the RetryPolicy.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must be 0 or more: %d", p.MaxAttempts)
+	}
+	if p.BaseDelay < 0 || p.ThrottleBaseDelay < 0 || p.MaxBackoff < 0 {
+		return fmt.Errorf("retry delay must be 0 or more")
+	}
+	if p.Budget < 0 {
+		return fmt.Errorf("retry budget must be 0 or more: %d", p.Budget)
+	}
+	for name, op := range p.Operations {
+		if len(op.Operations) > 0 {
+			return fmt.Errorf("retry policy of %s can not have operations", name)
+		}
+		if err := op.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Merge returns the copy of the policy overwritten with the non-zero values of override.
+// The per-operation overrides in override are added to (or replace) those of the policy.
+func (p RetryPolicy) Merge(override RetryPolicy) RetryPolicy {
+	if override.MaxAttempts > 0 {
+		p.MaxAttempts = override.MaxAttempts
+	}
+	if override.BaseDelay > 0 {
+		p.BaseDelay = override.BaseDelay
+	}
+	if override.ThrottleBaseDelay > 0 {
+		p.ThrottleBaseDelay = override.ThrottleBaseDelay
+	}
+	if override.MaxBackoff > 0 {
+		p.MaxBackoff = override.MaxBackoff
+	}
+	if override.Budget > 0 {
+		p.Budget = override.Budget
+	}
+	operations := make(map[string]RetryPolicy, len(p.Operations)+len(override.Operations))
+	for name, op := range p.Operations {
+		operations[name] = op
+	}
+	for name, op := range override.Operations {
+		operations[name] = operations[name].Merge(op)
+	}
+	p.Operations = operations
+	return p
+}
+
+// ForOperation returns the policy for the API operation. The per-operation override is applied if it exists.
+func (p RetryPolicy) ForOperation(name string) RetryPolicy {
+	op, ok := p.Operations[name]
+	if !ok {
+		return p
+	}
+	merged := p.Merge(op)
+	merged.Operations = nil
+	return merged
+}
+
+// Backoff returns the upper limit of the delay before the attempt-th retry (1-origin).
+// The actual delay is a random duration in [0, Backoff) (full jitter).
+func (p RetryPolicy) Backoff(attempt int, throttled bool) time.Duration {
+	base := p.BaseDelay
+	if throttled {
+		base = p.ThrottleBaseDelay
+	}
+	if base <= 0 || attempt <= 0 {
+		return 0
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+	backoff := base
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// IsThrottlingError returns true if the error means that the service is throttling the requests
+// (e.g. S3 503 SlowDown). It is used after the retries are exhausted.
+func IsThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, code := range []string{
+		"api error SlowDown",
+		"api error Throttling",
+		"api error ThrottlingException",
+		"api error TooManyRequestsException",
+		"api error RequestLimitExceeded",
+		"api error RequestThrottled",
+	} {
+		if strings.Contains(err.Error(), code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{
+		BaseDelay:         100 * time.Millisecond,
+		ThrottleBaseDelay: time.Second,
+		MaxBackoff:        5 * time.Second,
+	}
+	tests := []struct {
+		name      string
+		attempt   int
+		throttled bool
+		want      time.Duration
+	}{
+		{name: "first retry", attempt: 1, want: 100 * time.Millisecond},
+		{name: "third retry grows exponentially", attempt: 3, want: 400 * time.Millisecond},
+		{name: "throttling error waits longer", attempt: 2, throttled: true, want: 2 * time.Second},
+		{name: "capped by max backoff", attempt: 10, throttled: true, want: 5 * time.Second},
+		{name: "large attempt does not overflow", attempt: 1000, want: 5 * time.Second},
+		{name: "invalid attempt", attempt: 0, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := policy.Backoff(tt.attempt, tt.throttled); got != tt.want {
+				t.Errorf("RetryPolicy.Backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_ForOperation(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultRetryPolicy().Merge(RetryPolicy{
+		MaxBackoff: 10 * time.Second,
+		Operations: map[string]RetryPolicy{
+			"DeleteObjects": {MaxAttempts: 8},
+			"ListObjectsV2": {BaseDelay: time.Second},
+		},
+	})
+
+	want := RetryPolicy{
+		MaxAttempts:       8,
+		BaseDelay:         DefaultRetryBaseDelay,
+		ThrottleBaseDelay: S3DeleteObjectsDelayTimeSec * time.Second,
+		MaxBackoff:        10 * time.Second,
+		Budget:            DefaultRetryBudget,
+	}
+	if diff := cmp.Diff(want, policy.ForOperation("DeleteObjects")); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+
+	if got := policy.ForOperation("ListObjectsV2").BaseDelay; got != time.Second {
+		t.Errorf("RetryPolicy.ForOperation().BaseDelay = %v, want 1s", got)
+	}
+	if got := policy.ForOperation("GetObject").MaxAttempts; got != DefaultRetryMaxAttempts {
+		t.Errorf("RetryPolicy.ForOperation().MaxAttempts = %v, want %v", got, DefaultRetryMaxAttempts)
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr bool
+	}{
+		{name: "default", policy: *DefaultRetryPolicy(), wantErr: false},
+		{name: "negative max attempts", policy: RetryPolicy{MaxAttempts: -1}, wantErr: true},
+		{name: "negative delay", policy: RetryPolicy{MaxBackoff: -time.Second}, wantErr: true},
+		{name: "negative budget", policy: RetryPolicy{Budget: -1}, wantErr: true},
+		{
+			name:    "invalid operation",
+			policy:  RetryPolicy{Operations: map[string]RetryPolicy{"DeleteObjects": {MaxAttempts: -1}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RetryPolicy.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsThrottlingError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{
+			name: "SlowDown",
+			err:  errors.New("operation error S3: DeleteObjects, exceeded maximum number of attempts, 6, https response error StatusCode: 503, api error SlowDown: Please reduce your request rate."),
+			want: true,
+		},
+		{name: "Throttling", err: errors.New("api error Throttling: Rate exceeded"), want: true},
+		{name: "AccessDenied", err: errors.New("api error AccessDenied: Access Denied"), want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsThrottlingError(tt.err); got != tt.want {
+				t.Errorf("IsThrottlingError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
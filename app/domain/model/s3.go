@@ -13,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/utils/aimd"
 	"github.com/nao1215/rainbow/utils/errfmt"
 	"github.com/nao1215/rainbow/utils/xregex"
 	"github.com/wailsapp/mimetype"
@@ -21,16 +22,38 @@ import (
 const (
 	// S3DeleteObjectChunksSize is the maximum number of objects that can be deleted in a single request.
 	S3DeleteObjectChunksSize = 500
-	// MaxS3DeleteObjectsParallelsCount is the maximum number of parallel executions of DeleteObjects.
+	// MaxS3DeleteObjectsParallelsCount is the initial number of parallel executions of DeleteObjects.
+	// The adaptive concurrency grows it up to MaxS3AdaptiveParallelsCount.
 	MaxS3DeleteObjectsParallelsCount = 5
+	// S3CopyObjectsParallelsCount is the initial number of objects copied (uploaded, downloaded) in parallel.
+	S3CopyObjectsParallelsCount = 8
 	// MaxS3DeleteObjectsRetryCount is the maximum number of retries for DeleteObjects.
 	MaxS3DeleteObjectsRetryCount = 6
 	// S3DeleteObjectsDelayTimeSec is the delay time in seconds.
 	S3DeleteObjectsDelayTimeSec = 5
 	// MaxS3Keys is the maximum number of keys that can be specified in a single request.
 	MaxS3Keys = 1000
+	// MaxS3DeleteBucketsParallelsCount is the maximum number of buckets deleted in parallel.
+	MaxS3DeleteBucketsParallelsCount = 3
+	// MaxS3AdaptiveParallelsCount is the upper limit of the adaptive concurrency of the S3 API calls.
+	// The concurrency grows from the initial value up to this while the calls are not throttled.
+	MaxS3AdaptiveParallelsCount = 32
 )
 
+// NewS3Limiter returns the adaptive concurrency limiter for the S3 API calls.
+// The concurrency starts with initial and grows up to maxParallels (MaxS3AdaptiveParallelsCount if it is 0).
+func NewS3Limiter(initial, maxParallels int) *aimd.Limiter {
+	if maxParallels <= 0 {
+		maxParallels = MaxS3AdaptiveParallelsCount
+	}
+	return aimd.NewLimiter(aimd.Options{
+		Initial:    initial,
+		Min:        1,
+		Max:        maxParallels,
+		IsThrottle: IsThrottlingError,
+	})
+}
+
 // DeleteObjectsRetryCount is the number of retries for DeleteObjects.
 type DeleteObjectsRetryCount int
 
@@ -47,123 +70,6 @@ func NewDeleteRetryCount(i int) DeleteObjectsRetryCount {
 	return DeleteObjectsRetryCount(i)
 }
 
-// Region is the name of the AWS region.
-type Region string
-
-const (
-	// RegionUSEast1 US East (N. Virginia)
-	RegionUSEast1 Region = "us-east-1"
-	// RegionUSEast2 US East (Ohio)
-	RegionUSEast2 Region = "us-east-2"
-	// RegionUSWest1 US West (N. California)
-	RegionUSWest1 Region = "us-west-1"
-	// RegionUSWest2 US West (Oregon)
-	RegionUSWest2 Region = "us-west-2"
-	// RegionAFSouth1 Africa (Cape Town)
-	RegionAFSouth1 Region = "af-south-1"
-	// RegionAPEast1 Asia Pacific (Hong Kong)
-	RegionAPEast1 Region = "ap-east-1"
-	// RegionAPSouth1 Asia Pacific (Mumbai)
-	RegionAPSouth1 Region = "ap-south-1"
-	// RegionAPNortheast1 Asia Pacific (Tokyo)
-	RegionAPNortheast1 Region = "ap-northeast-1"
-	// RegionAPNortheast2 Asia Pacific (Seoul)
-	RegionAPNortheast2 Region = "ap-northeast-2"
-	// RegionAPNortheast3 Asia Pacific (Osaka-Local)
-	RegionAPNortheast3 Region = "ap-northeast-3"
-	// RegionAPSoutheast1 Asia Pacific (Singapore)
-	RegionAPSoutheast1 Region = "ap-southeast-1"
-	// RegionAPSoutheast2 Asia Pacific (Sydney)
-	RegionAPSoutheast2 Region = "ap-southeast-2"
-	// RegionCACentral1 Canada (Central)
-	RegionCACentral1 Region = "ca-central-1"
-	// RegionCNNorth1 China (Beijing)
-	RegionCNNorth1 Region = "cn-north-1"
-	// RegionCNNorthwest1 China (Ningxia)
-	RegionCNNorthwest1 Region = "cn-northwest-1"
-	// RegionEUCentral1 Europe (Frankfurt)
-	RegionEUCentral1 Region = "eu-central-1"
-	// RegionEUNorth1 Europe (Stockholm)
-	RegionEUNorth1 Region = "eu-north-1"
-	// RegionEUSouth1 Europe (Milan)
-	RegionEUSouth1 Region = "eu-south-1"
-	// RegionEUWest1 Europe (Ireland)
-	RegionEUWest1 Region = "eu-west-1"
-	// RegionEUWest2 Europe (London)
-	RegionEUWest2 Region = "eu-west-2"
-	// RegionEUWest3 Europe (Paris)
-	RegionEUWest3 Region = "eu-west-3"
-	// RegionMESouth1 Middle East (Bahrain)
-	RegionMESouth1 Region = "me-south-1"
-	// RegionSASouth1 South America (São Paulo)
-	RegionSASouth1 Region = "sa-south-1"
-	// RegionUSGovEast1 AWS GovCloud (US-East)
-	RegionUSGovEast1 Region = "us-gov-east-1"
-	// RegionUSGovWest1 AWS GovCloud (US)
-	RegionUSGovWest1 Region = "us-gov-west-1"
-)
-
-var regions = []Region{
-	RegionUSEast1, RegionUSEast2, RegionUSWest1, RegionUSWest2, RegionAFSouth1, RegionAPEast1,
-	RegionAPSouth1, RegionAPNortheast1, RegionAPNortheast2, RegionAPNortheast3, RegionAPSoutheast1,
-	RegionAPSoutheast2, RegionCACentral1, RegionCNNorth1, RegionCNNorthwest1, RegionEUCentral1,
-	RegionEUNorth1, RegionEUSouth1, RegionEUWest1, RegionEUWest2, RegionEUWest3, RegionMESouth1,
-	RegionSASouth1, RegionUSGovEast1, RegionUSGovWest1,
-}
-
-// Validate returns true if the Region exists.
-func (r Region) Validate() error {
-	switch r {
-	case
-		RegionUSEast1, RegionUSEast2, RegionUSWest1, RegionUSWest2, RegionAFSouth1,
-		RegionAPEast1, RegionAPSouth1, RegionAPNortheast1, RegionAPNortheast2,
-		RegionAPNortheast3, RegionAPSoutheast1, RegionAPSoutheast2, RegionCACentral1,
-		RegionCNNorth1, RegionCNNorthwest1, RegionEUCentral1, RegionEUNorth1,
-		RegionEUSouth1, RegionEUWest1, RegionEUWest2, RegionEUWest3, RegionMESouth1,
-		RegionSASouth1, RegionUSGovEast1, RegionUSGovWest1:
-		return nil
-	case Region(""):
-		return domain.ErrEmptyRegion
-	default:
-		return domain.ErrInvalidRegion
-	}
-}
-
-// String returns the string representation of the Region.
-func (r Region) String() string {
-	return string(r)
-}
-
-// Next returns the next region.
-// If the region is the last one, it returns the first region.
-// If the region is invalid, it returns "ap-northeast-1".
-func (r Region) Next() Region {
-	for i, region := range regions {
-		if r == region {
-			if i == len(regions)-1 {
-				return regions[0]
-			}
-			return regions[i+1]
-		}
-	}
-	return RegionAPNortheast1
-}
-
-// Prev returns the previous region.
-// If the region is the first one, it returns the last region.
-// If the region is invalid, it returns "ap-northeast-1".
-func (r Region) Prev() Region {
-	for i, region := range regions {
-		if r == region {
-			if i == 0 {
-				return regions[len(regions)-1]
-			}
-			return regions[i-1]
-		}
-	}
-	return RegionAPNortheast1
-}
-
 const (
 	// MinBucketNameLength is the minimum length of the bucket name.
 	MinBucketNameLength = 3
@@ -211,9 +117,15 @@ func (b Bucket) Empty() bool {
 	return b == ""
 }
 
-// Domain returns the domain name of the Bucket.
-func (b Bucket) Domain() string {
-	return fmt.Sprintf("%s.s3.amazonaws.com", b.String())
+// Domain returns the domain name of the Bucket in the region.
+// The domain is built from the S3 endpoint suffix of the region in the region catalog,
+// e.g. "bucket.s3.cn-north-1.amazonaws.com.cn". If the region is empty or unknown, it returns the global domain "bucket.s3.amazonaws.com".
+func (b Bucket) Domain(region Region) string {
+	info, ok := region.Info()
+	if !ok {
+		return fmt.Sprintf("%s.s3.%s", b.String(), PartitionAWS.DNSSuffix())
+	}
+	return fmt.Sprintf("%s.s3.%s.%s", b.String(), info.Region.String(), info.S3EndpointSuffix)
 }
 
 // TrimKey returns the Bucket without the key.
@@ -462,3 +374,66 @@ func (s *S3Object) ContentType() string {
 func (s *S3Object) ContentLength() int64 {
 	return int64(s.Len())
 }
+
+// S3ObjectDetail is the metadata of the object returned by the list API.
+type S3ObjectDetail struct {
+	// S3Key is the name of the object.
+	S3Key S3Key
+	// Size is the size of the object in bytes.
+	Size int64
+	// StorageClass is the class of storage used to store the object.
+	StorageClass string
+	// LastModified is the creation date of the object.
+	LastModified time.Time
+	// ETag is the entity tag of the object. It does not contain double quotes.
+	ETag string
+}
+
+// S3ObjectDetails is the set of the S3ObjectDetail.
+type S3ObjectDetails []S3ObjectDetail
+
+// Len returns the length of the S3ObjectDetails.
+func (s S3ObjectDetails) Len() int {
+	return len(s)
+}
+
+// TotalSize returns the sum of the object sizes.
+func (s S3ObjectDetails) TotalSize() int64 {
+	var total int64
+	for _, o := range s {
+		total += o.Size
+	}
+	return total
+}
+
+// S3ObjectVersion is a version (or a delete marker) of the object in the versioned S3 bucket.
+type S3ObjectVersion struct {
+	// S3Key is the name of the object.
+	S3Key S3Key
+	// VersionID is the version ID of the object.
+	VersionID VersionID
+	// Size is the size of the object in bytes. Delete markers have no size.
+	Size int64
+	// IsLatest is whether the version is the current version of the object.
+	IsLatest bool
+	// IsDeleteMarker is whether the version is a delete marker.
+	IsDeleteMarker bool
+	// LastModified is the date the version was created.
+	LastModified time.Time
+}
+
+// S3ObjectVersions is the set of the S3ObjectVersion.
+type S3ObjectVersions []S3ObjectVersion
+
+// S3MultipartUpload is an incomplete multipart upload in the S3 bucket.
+type S3MultipartUpload struct {
+	// S3Key is the key of the object for which the multipart upload was initiated.
+	S3Key S3Key
+	// UploadID is the ID that identifies the multipart upload.
+	UploadID string
+	// Initiated is the date the multipart upload was initiated.
+	Initiated time.Time
+}
+
+// S3MultipartUploads is the set of the S3MultipartUpload.
+type S3MultipartUploads []S3MultipartUpload
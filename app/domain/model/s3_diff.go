@@ -0,0 +1,161 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// S3DiffCompareMode is how to decide whether the contents of two objects are the same.
+type S3DiffCompareMode string
+
+const (
+	// S3DiffCompareSize compares only the object size.
+	S3DiffCompareSize S3DiffCompareMode = "size"
+	// S3DiffCompareETag compares the size and the ETag (MD5 for local files).
+	// If either ETag is a multipart ETag, it can not be compared with MD5, so only the size is compared.
+	S3DiffCompareETag S3DiffCompareMode = "etag"
+)
+
+// NewS3DiffCompareMode returns the S3DiffCompareMode. If s is empty, it returns S3DiffCompareETag.
+func NewS3DiffCompareMode(s string) (S3DiffCompareMode, error) {
+	switch S3DiffCompareMode(strings.ToLower(s)) {
+	case "", S3DiffCompareETag:
+		return S3DiffCompareETag, nil
+	case S3DiffCompareSize:
+		return S3DiffCompareSize, nil
+	default:
+		return "", fmt.Errorf("unsupported compare mode: %s (size or etag)", s)
+	}
+}
+
+// S3DiffStatus is the kind of the difference.
+type S3DiffStatus string
+
+const (
+	// S3DiffOnlyInA means the object exists only in the A side.
+	S3DiffOnlyInA S3DiffStatus = "only_in_a"
+	// S3DiffOnlyInB means the object exists only in the B side.
+	S3DiffOnlyInB S3DiffStatus = "only_in_b"
+	// S3DiffContentDiffers means the object exists in both sides, but the content differs.
+	S3DiffContentDiffers S3DiffStatus = "content_differs"
+)
+
+// S3DiffObject is the object (or local file) to be compared.
+type S3DiffObject struct {
+	// Key is the path relative to the root of the compared side.
+	Key string `json:"key"`
+	// Size is the size in bytes.
+	Size int64 `json:"size"`
+	// ETag is the ETag of the S3 object or the MD5 hex digest of the local file. It does not contain double quotes.
+	ETag string `json:"etag,omitempty"`
+}
+
+// isMultipartETag returns true if the ETag is calculated from the multipart upload.
+// e.g. "d41d8cd98f00b204e9800998ecf8427e-2"
+func (o S3DiffObject) isMultipartETag() bool {
+	return strings.Contains(o.ETag, "-")
+}
+
+// S3DiffEntry is a difference between the two sides.
+type S3DiffEntry struct {
+	// Key is the path relative to the root of the compared side.
+	Key string `json:"key"`
+	// Status is the kind of the difference.
+	Status S3DiffStatus `json:"status"`
+	// Reason is the human-readable reason why the content differs.
+	Reason string `json:"reason,omitempty"`
+	// A is the object in the A side. It is nil if Status is S3DiffOnlyInB.
+	A *S3DiffObject `json:"a,omitempty"`
+	// B is the object in the B side. It is nil if Status is S3DiffOnlyInA.
+	B *S3DiffObject `json:"b,omitempty"`
+}
+
+// S3DiffResult is the result of comparing the two sides.
+type S3DiffResult struct {
+	// A is the name of the A side. e.g. "s3://bucket/prefix"
+	A string `json:"a"`
+	// B is the name of the B side. e.g. "/path/to/dir"
+	B string `json:"b"`
+	// CompareMode is how the contents were compared.
+	CompareMode S3DiffCompareMode `json:"compare_mode"`
+	// OnlyInA is the number of objects that exist only in A.
+	OnlyInA int `json:"only_in_a"`
+	// OnlyInB is the number of objects that exist only in B.
+	OnlyInB int `json:"only_in_b"`
+	// ContentDiffers is the number of objects whose content differs.
+	ContentDiffers int `json:"content_differs"`
+	// Identical is the number of objects that are the same.
+	Identical int `json:"identical"`
+	// Entries is the list of differences sorted by key.
+	Entries []S3DiffEntry `json:"entries"`
+}
+
+// HasDifference returns true if there is at least one difference.
+func (r *S3DiffResult) HasDifference() bool {
+	return len(r.Entries) > 0
+}
+
+// NewS3DiffResult compares the objects of the two sides.
+func NewS3DiffResult(nameA, nameB string, a, b []S3DiffObject, mode S3DiffCompareMode) *S3DiffResult {
+	result := &S3DiffResult{
+		A:           nameA,
+		B:           nameB,
+		CompareMode: mode,
+		Entries:     []S3DiffEntry{},
+	}
+
+	objectsB := make(map[string]S3DiffObject, len(b))
+	for _, o := range b {
+		objectsB[o.Key] = o
+	}
+
+	for _, oa := range a {
+		oa := oa
+		ob, ok := objectsB[oa.Key]
+		if !ok {
+			result.OnlyInA++
+			result.Entries = append(result.Entries, S3DiffEntry{Key: oa.Key, Status: S3DiffOnlyInA, A: &oa})
+			continue
+		}
+		delete(objectsB, oa.Key)
+
+		reason := compareS3DiffObject(oa, ob, mode)
+		if reason == "" {
+			result.Identical++
+			continue
+		}
+		result.ContentDiffers++
+		result.Entries = append(result.Entries, S3DiffEntry{Key: oa.Key, Status: S3DiffContentDiffers, Reason: reason, A: &oa, B: &ob})
+	}
+
+	for _, ob := range objectsB {
+		ob := ob
+		result.OnlyInB++
+		result.Entries = append(result.Entries, S3DiffEntry{Key: ob.Key, Status: S3DiffOnlyInB, B: &ob})
+	}
+
+	sort.Slice(result.Entries, func(i, j int) bool {
+		return result.Entries[i].Key < result.Entries[j].Key
+	})
+	return result
+}
+
+// compareS3DiffObject returns the reason why the contents differ. If they are the same, it returns "".
+func compareS3DiffObject(a, b S3DiffObject, mode S3DiffCompareMode) string {
+	if a.Size != b.Size {
+		return fmt.Sprintf("size %d != %d", a.Size, b.Size)
+	}
+	if mode != S3DiffCompareETag {
+		return ""
+	}
+	if a.ETag == "" || b.ETag == "" || a.isMultipartETag() || b.isMultipartETag() {
+		// The multipart ETag is not the MD5 of the content. It also depends on the part size,
+		// so even two multipart ETags of the same content can differ.
+		return ""
+	}
+	if !strings.EqualFold(a.ETag, b.ETag) {
+		return fmt.Sprintf("etag %s != %s", a.ETag, b.ETag)
+	}
+	return ""
+}
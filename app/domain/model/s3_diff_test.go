@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewS3DiffResult(t *testing.T) {
+	t.Parallel()
+
+	a := []S3DiffObject{
+		{Key: "same.txt", Size: 3, ETag: "900150983cd24fb0d6963f7d28e17f72"},
+		{Key: "size.txt", Size: 3, ETag: "900150983cd24fb0d6963f7d28e17f72"},
+		{Key: "etag.txt", Size: 3, ETag: "900150983cd24fb0d6963f7d28e17f72"},
+		{Key: "multipart.bin", Size: 10, ETag: "d41d8cd98f00b204e9800998ecf8427e-2"},
+		{Key: "only-a.txt", Size: 1},
+		{Key: "part-size.bin", Size: 20, ETag: "d41d8cd98f00b204e9800998ecf8427e-3"},
+	}
+	b := []S3DiffObject{
+		{Key: "same.txt", Size: 3, ETag: "900150983CD24FB0D6963F7D28E17F72"},
+		{Key: "size.txt", Size: 4, ETag: "900150983cd24fb0d6963f7d28e17f72"},
+		{Key: "etag.txt", Size: 3, ETag: "d41d8cd98f00b204e9800998ecf8427e"},
+		{Key: "multipart.bin", Size: 10, ETag: "f96b697d7cb7938d525a2f31aaf161d0"},
+		{Key: "only-b.txt", Size: 2},
+		{Key: "part-size.bin", Size: 20, ETag: "f96b697d7cb7938d525a2f31aaf161d0-5"},
+	}
+
+	t.Run("compare with etag", func(t *testing.T) {
+		t.Parallel()
+
+		got := NewS3DiffResult("A", "B", a, b, S3DiffCompareETag)
+		want := &S3DiffResult{
+			A:              "A",
+			B:              "B",
+			CompareMode:    S3DiffCompareETag,
+			OnlyInA:        1,
+			OnlyInB:        1,
+			ContentDiffers: 2,
+			Identical:      3,
+			Entries: []S3DiffEntry{
+				{Key: "etag.txt", Status: S3DiffContentDiffers, Reason: "etag 900150983cd24fb0d6963f7d28e17f72 != d41d8cd98f00b204e9800998ecf8427e", A: &a[2], B: &b[2]},
+				{Key: "only-a.txt", Status: S3DiffOnlyInA, A: &a[4]},
+				{Key: "only-b.txt", Status: S3DiffOnlyInB, B: &b[4]},
+				{Key: "size.txt", Status: S3DiffContentDiffers, Reason: "size 3 != 4", A: &a[1], B: &b[1]},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("NewS3DiffResult() mismatch (-want +got):\n%s", diff)
+		}
+		if !got.HasDifference() {
+			t.Error("HasDifference() should return true")
+		}
+	})
+
+	t.Run("compare with size", func(t *testing.T) {
+		t.Parallel()
+
+		got := NewS3DiffResult("A", "B", a, b, S3DiffCompareSize)
+		if got.ContentDiffers != 1 || got.Identical != 4 {
+			t.Errorf("ContentDiffers = %d, Identical = %d, want 1, 4", got.ContentDiffers, got.Identical)
+		}
+	})
+
+	t.Run("no difference", func(t *testing.T) {
+		t.Parallel()
+
+		got := NewS3DiffResult("A", "B", a[:1], b[:1], S3DiffCompareETag)
+		if got.HasDifference() {
+			t.Errorf("HasDifference() should return false: %v", got.Entries)
+		}
+	})
+}
+
+func TestNewS3DiffCompareMode(t *testing.T) {
+	t.Parallel()
+
+	if got, err := NewS3DiffCompareMode(""); err != nil || got != S3DiffCompareETag {
+		t.Errorf("NewS3DiffCompareMode(\"\") = %v, %v", got, err)
+	}
+	if got, err := NewS3DiffCompareMode("SIZE"); err != nil || got != S3DiffCompareSize {
+		t.Errorf("NewS3DiffCompareMode(\"SIZE\") = %v, %v", got, err)
+	}
+	if _, err := NewS3DiffCompareMode("sha256"); err == nil {
+		t.Error("NewS3DiffCompareMode(\"sha256\") should return error")
+	}
+}
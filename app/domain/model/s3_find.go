@@ -0,0 +1,317 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxS3FindParallelsCount is the maximum number of parallel executions of the per-object API
+// (HeadObject, GetObjectTagging) during the find operation.
+const MaxS3FindParallelsCount = 10
+
+// Comparison is the comparison operator used by find predicates.
+// It follows the find(1) convention: "+N" means greater than N, "-N" means less than N and "N" means exactly N.
+type Comparison int
+
+const (
+	// ComparisonEqual means the value is equal to the threshold.
+	ComparisonEqual Comparison = iota
+	// ComparisonGreater means the value is greater than the threshold.
+	ComparisonGreater
+	// ComparisonLess means the value is less than the threshold.
+	ComparisonLess
+)
+
+// parseComparison splits the sign from s and returns the comparison operator and the rest of s.
+func parseComparison(s string) (Comparison, string) {
+	switch {
+	case strings.HasPrefix(s, "+"):
+		return ComparisonGreater, s[1:]
+	case strings.HasPrefix(s, "-"):
+		return ComparisonLess, s[1:]
+	default:
+		return ComparisonEqual, s
+	}
+}
+
+// S3SizeCondition is the condition of the object size. e.g. "+100M" means larger than 100 MiB.
+type S3SizeCondition struct {
+	// Comparison is the comparison operator.
+	Comparison Comparison
+	// Bytes is the threshold in bytes.
+	Bytes int64
+}
+
+// sizeUnits is the multiplier of the size suffix. Units are powers of 1024.
+var sizeUnits = map[string]int64{ //nolint:gochecknoglobals
+	"":  1,
+	"B": 1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// NewS3SizeCondition parses the size condition such as "+100M", "-1K" or "512".
+// Supported suffixes are B, K, M, G and T (powers of 1024).
+func NewS3SizeCondition(s string) (*S3SizeCondition, error) {
+	cmp, rest := parseComparison(s)
+	rest = strings.ToUpper(rest)
+
+	i := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+	num, unit := rest, ""
+	if i >= 0 {
+		num, unit = rest[:i], rest[i:]
+	}
+	multiplier, ok := sizeUnits[unit]
+	if !ok || num == "" {
+		return nil, fmt.Errorf("invalid size condition: %s", s)
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size condition: %s: %w", s, err)
+	}
+	return &S3SizeCondition{Comparison: cmp, Bytes: n * multiplier}, nil
+}
+
+// Match returns true if the size satisfies the condition.
+func (c *S3SizeCondition) Match(size int64) bool {
+	switch c.Comparison {
+	case ComparisonGreater:
+		return size > c.Bytes
+	case ComparisonLess:
+		return size < c.Bytes
+	default:
+		return size == c.Bytes
+	}
+}
+
+// S3AgeCondition is the condition of the time elapsed since the object was last modified.
+// e.g. "-7d" means modified within 7 days, "+30d" means modified more than 30 days ago.
+type S3AgeCondition struct {
+	// Comparison is the comparison operator.
+	Comparison Comparison
+	// Age is the threshold.
+	Age time.Duration
+	// unit is the unit of Age. It is used to round the age when Comparison is ComparisonEqual.
+	unit time.Duration
+}
+
+// ageUnits is the duration of the age suffix.
+var ageUnits = map[string]time.Duration{ //nolint:gochecknoglobals
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// NewS3AgeCondition parses the age condition such as "-7d", "+12h" or "3w".
+// Supported suffixes are s, m, h, d and w. If the suffix is omitted, d is used.
+func NewS3AgeCondition(s string) (*S3AgeCondition, error) {
+	cmp, rest := parseComparison(s)
+
+	unit := "d"
+	if rest != "" {
+		if _, ok := ageUnits[rest[len(rest)-1:]]; ok {
+			unit = rest[len(rest)-1:]
+			rest = rest[:len(rest)-1]
+		}
+	}
+	n, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid time condition: %s", s)
+	}
+	return &S3AgeCondition{
+		Comparison: cmp,
+		Age:        time.Duration(n) * ageUnits[unit],
+		unit:       ageUnits[unit],
+	}, nil
+}
+
+// Match returns true if the object last modified at lastModified satisfies the condition at now.
+func (c *S3AgeCondition) Match(lastModified, now time.Time) bool {
+	age := now.Sub(lastModified)
+	switch c.Comparison {
+	case ComparisonGreater:
+		return age > c.Age
+	case ComparisonLess:
+		return age < c.Age
+	default:
+		return age >= c.Age && age < c.Age+c.unit
+	}
+}
+
+// S3Tag is the tag (key-value pair) of the S3 object.
+type S3Tag struct {
+	// Key is the tag key.
+	Key string
+	// Value is the tag value.
+	Value string
+}
+
+// NewS3Tag parses the "key=value" string. Value may be empty.
+func NewS3Tag(s string) (S3Tag, error) {
+	key, value, found := strings.Cut(s, "=")
+	if !found || key == "" {
+		return S3Tag{}, fmt.Errorf("invalid tag (must be key=value): %s", s)
+	}
+	return S3Tag{Key: key, Value: value}, nil
+}
+
+// S3Tags is the set of the S3Tag.
+type S3Tags []S3Tag
+
+// Contains returns true if the tags contain the tag with the same key and value.
+func (t S3Tags) Contains(tag S3Tag) bool {
+	for _, v := range t {
+		if v == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// S3ObjectFilter is the set of predicates for finding S3 objects. All predicates are combined with AND.
+// Zero values mean that the predicate is not used.
+type S3ObjectFilter struct {
+	// Name is the glob pattern matched against the base name of the key.
+	Name string
+	// Regex is the regular expression matched against the whole key.
+	Regex *regexp.Regexp
+	// Size is the condition of the object size.
+	Size *S3SizeCondition
+	// Age is the condition of the last modified time.
+	Age *S3AgeCondition
+	// StorageClasses is the list of storage classes. The object matches if it is in any of them.
+	StorageClasses []string
+	// Tags is the list of tags. The object matches if it has all of them.
+	Tags S3Tags
+	// ContentType is the glob pattern matched against the Content-Type. e.g. "image/*"
+	ContentType string
+}
+
+// Validate validates the glob patterns in the filter.
+func (f *S3ObjectFilter) Validate() error {
+	if _, err := path.Match(f.Name, ""); err != nil {
+		return fmt.Errorf("invalid name pattern: %s: %w", f.Name, err)
+	}
+	if _, err := path.Match(f.ContentType, ""); err != nil {
+		return fmt.Errorf("invalid content type pattern: %s: %w", f.ContentType, err)
+	}
+	return nil
+}
+
+// NeedsTags returns true if the filter requires the object tags.
+func (f *S3ObjectFilter) NeedsTags() bool {
+	return len(f.Tags) > 0
+}
+
+// NeedsContentType returns true if the filter requires the object Content-Type.
+func (f *S3ObjectFilter) NeedsContentType() bool {
+	return f.ContentType != ""
+}
+
+// MatchDetail returns true if the object satisfies the predicates that can be evaluated
+// with the list API result (name, regex, size, age and storage class).
+func (f *S3ObjectFilter) MatchDetail(o S3ObjectDetail, now time.Time) bool {
+	if f.Name != "" {
+		if ok, err := path.Match(f.Name, path.Base(o.S3Key.String())); err != nil || !ok {
+			return false
+		}
+	}
+	if f.Regex != nil && !f.Regex.MatchString(o.S3Key.String()) {
+		return false
+	}
+	if f.Size != nil && !f.Size.Match(o.Size) {
+		return false
+	}
+	if f.Age != nil && !f.Age.Match(o.LastModified, now) {
+		return false
+	}
+	if len(f.StorageClasses) > 0 {
+		class := o.StorageClass
+		if class == "" {
+			class = "STANDARD"
+		}
+		found := false
+		for _, c := range f.StorageClasses {
+			if strings.EqualFold(c, class) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchTags returns true if tags contain all tags in the filter.
+func (f *S3ObjectFilter) MatchTags(tags S3Tags) bool {
+	for _, t := range f.Tags {
+		if !tags.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchContentType returns true if the content type matches the pattern in the filter.
+// Parameters such as "; charset=utf-8" are ignored.
+func (f *S3ObjectFilter) MatchContentType(contentType string) bool {
+	if f.ContentType == "" {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	ok, err := path.Match(f.ContentType, strings.TrimSpace(mediaType))
+	return err == nil && ok
+}
+
+// S3BucketFilter is the set of predicates for selecting S3 buckets. All predicates are combined with AND.
+// Zero values mean that the predicate is not used.
+type S3BucketFilter struct {
+	// Name is the regular expression matched against the bucket name.
+	Name *regexp.Regexp
+	// CreatedBefore selects the buckets created before this time.
+	CreatedBefore time.Time
+	// Tags is the list of tags. The bucket matches if it has all of them.
+	Tags S3Tags
+}
+
+// Empty returns true if no predicate is specified.
+func (f *S3BucketFilter) Empty() bool {
+	return f.Name == nil && f.CreatedBefore.IsZero() && len(f.Tags) == 0
+}
+
+// NeedsTags returns true if the filter requires the bucket tags.
+func (f *S3BucketFilter) NeedsTags() bool {
+	return len(f.Tags) > 0
+}
+
+// MatchBucket returns true if the bucket satisfies the predicates that can be evaluated
+// with the list API result (name and creation date).
+func (f *S3BucketFilter) MatchBucket(b BucketSet) bool {
+	if f.Name != nil && !f.Name.MatchString(b.Bucket.String()) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !b.CreationDate.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// MatchTags returns true if tags contain all tags in the filter.
+func (f *S3BucketFilter) MatchTags(tags S3Tags) bool {
+	for _, t := range f.Tags {
+		if !tags.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewS3SizeCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    *S3SizeCondition
+		wantErr bool
+	}{
+		{name: "greater than 100 MiB", s: "+100M", want: &S3SizeCondition{Comparison: ComparisonGreater, Bytes: 100 << 20}},
+		{name: "less than 1 KiB with lower case", s: "-1k", want: &S3SizeCondition{Comparison: ComparisonLess, Bytes: 1 << 10}},
+		{name: "exactly 512 bytes", s: "512", want: &S3SizeCondition{Comparison: ComparisonEqual, Bytes: 512}},
+		{name: "exactly 2 bytes with B", s: "2B", want: &S3SizeCondition{Comparison: ComparisonEqual, Bytes: 2}},
+		{name: "unknown unit", s: "+10X", wantErr: true},
+		{name: "no number", s: "+M", wantErr: true},
+		{name: "empty", s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewS3SizeCondition(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewS3SizeCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("NewS3SizeCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS3AgeCondition_Match(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		condition    string
+		lastModified time.Time
+		want         bool
+	}{
+		{name: "-7d matches 2 days ago", condition: "-7d", lastModified: now.Add(-48 * time.Hour), want: true},
+		{name: "-7d does not match 8 days ago", condition: "-7d", lastModified: now.Add(-8 * 24 * time.Hour), want: false},
+		{name: "+30d matches 31 days ago", condition: "+30d", lastModified: now.Add(-31 * 24 * time.Hour), want: true},
+		{name: "+12h does not match 1 hour ago", condition: "+12h", lastModified: now.Add(-time.Hour), want: false},
+		{name: "3 (days) matches 3.5 days ago", condition: "3", lastModified: now.Add(-84 * time.Hour), want: true},
+		{name: "1w does not match 2 weeks ago", condition: "1w", lastModified: now.Add(-14 * 24 * time.Hour), want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewS3AgeCondition(tt.condition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Match(tt.lastModified, now); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid condition", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []string{"", "-", "7y", "+-7d"} {
+			if _, err := NewS3AgeCondition(s); err == nil {
+				t.Errorf("NewS3AgeCondition(%q) should return error", s)
+			}
+		}
+	})
+}
+
+func TestNewS3Tag(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewS3Tag("env=dev=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (S3Tag{Key: "env", Value: "dev=1"}); got != want {
+		t.Errorf("NewS3Tag() = %v, want %v", got, want)
+	}
+
+	for _, s := range []string{"env", "=dev", ""} {
+		if _, err := NewS3Tag(s); err == nil {
+			t.Errorf("NewS3Tag(%q) should return error", s)
+		}
+	}
+}
+
+func TestS3ObjectFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	object := S3ObjectDetail{
+		S3Key:        "images/2024/cat.png",
+		Size:         2 << 20,
+		StorageClass: "",
+		LastModified: now.Add(-time.Hour),
+	}
+
+	tests := []struct {
+		name   string
+		filter *S3ObjectFilter
+		want   bool
+	}{
+		{name: "empty filter matches everything", filter: &S3ObjectFilter{}, want: true},
+		{name: "name glob matches base name", filter: &S3ObjectFilter{Name: "*.png"}, want: true},
+		{name: "name glob does not match directory", filter: &S3ObjectFilter{Name: "images*"}, want: false},
+		{name: "regex matches whole key", filter: &S3ObjectFilter{Regex: regexp.MustCompile(`^images/\d+/`)}, want: true},
+		{name: "size +1M matches", filter: &S3ObjectFilter{Size: &S3SizeCondition{Comparison: ComparisonGreater, Bytes: 1 << 20}}, want: true},
+		{name: "size -1M does not match", filter: &S3ObjectFilter{Size: &S3SizeCondition{Comparison: ComparisonLess, Bytes: 1 << 20}}, want: false},
+		{name: "empty storage class is STANDARD", filter: &S3ObjectFilter{StorageClasses: []string{"glacier", "standard"}}, want: true},
+		{name: "storage class does not match", filter: &S3ObjectFilter{StorageClasses: []string{"GLACIER"}}, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.filter.MatchDetail(object, now); got != tt.want {
+				t.Errorf("MatchDetail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("tags and content type", func(t *testing.T) {
+		t.Parallel()
+
+		filter := &S3ObjectFilter{
+			Tags:        S3Tags{{Key: "env", Value: "dev"}},
+			ContentType: "image/*",
+		}
+		if !filter.NeedsTags() || !filter.NeedsContentType() {
+			t.Error("filter should need tags and content type")
+		}
+		if !filter.MatchTags(S3Tags{{Key: "owner", Value: "me"}, {Key: "env", Value: "dev"}}) {
+			t.Error("MatchTags() should return true")
+		}
+		if filter.MatchTags(S3Tags{{Key: "env", Value: "prd"}}) {
+			t.Error("MatchTags() should return false")
+		}
+		if !filter.MatchContentType("image/png; charset=binary") {
+			t.Error("MatchContentType() should return true")
+		}
+		if filter.MatchContentType("text/plain") {
+			t.Error("MatchContentType() should return false")
+		}
+	})
+
+	t.Run("invalid glob pattern", func(t *testing.T) {
+		t.Parallel()
+		if err := (&S3ObjectFilter{Name: "[a-"}).Validate(); err == nil {
+			t.Error("Validate() should return error")
+		}
+	})
+}
+
+func TestS3BucketFilter(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := BucketSet{Bucket: "test-bucket", CreationDate: created}
+
+	tests := []struct {
+		name   string
+		filter *S3BucketFilter
+		want   bool
+	}{
+		{name: "empty filter", filter: &S3BucketFilter{}, want: true},
+		{name: "name matches", filter: &S3BucketFilter{Name: regexp.MustCompile("^test-")}, want: true},
+		{name: "name does not match", filter: &S3BucketFilter{Name: regexp.MustCompile("^prod-")}, want: false},
+		{name: "created before", filter: &S3BucketFilter{CreatedBefore: created.Add(time.Second)}, want: true},
+		{name: "created at the same time", filter: &S3BucketFilter{CreatedBefore: created}, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.filter.MatchBucket(bucket); got != tt.want {
+				t.Errorf("MatchBucket() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty and tags", func(t *testing.T) {
+		t.Parallel()
+
+		if !(&S3BucketFilter{}).Empty() {
+			t.Error("Empty() should return true")
+		}
+		filter := &S3BucketFilter{Tags: S3Tags{{Key: "env", Value: "test"}}}
+		if filter.Empty() || !filter.NeedsTags() {
+			t.Error("filter with tags should not be empty and needs tags")
+		}
+		if !filter.MatchTags(S3Tags{{Key: "env", Value: "test"}}) || filter.MatchTags(nil) {
+			t.Error("MatchTags() returns unexpected result")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// S3RetentionMode is the object lock retention mode.
+type S3RetentionMode string
+
+const (
+	// S3RetentionModeNone means the object version has no retention.
+	S3RetentionModeNone S3RetentionMode = ""
+	// S3RetentionModeGovernance means the object version can be deleted only with the bypass governance permission.
+	S3RetentionModeGovernance S3RetentionMode = "GOVERNANCE"
+	// S3RetentionModeCompliance means the object version can not be deleted by any user until the retention expires.
+	S3RetentionModeCompliance S3RetentionMode = "COMPLIANCE"
+)
+
+// S3ObjectLock is the object lock status of the object version.
+type S3ObjectLock struct {
+	// LegalHold is whether the legal hold is ON.
+	LegalHold bool
+	// Mode is the retention mode.
+	Mode S3RetentionMode
+	// RetainUntil is the date the retention expires.
+	RetainUntil time.Time
+}
+
+// UndeletableReason returns the reason why the object version can not be deleted with the options.
+// It returns "" if the object version can be deleted (after releasing the legal hold if releaseLegalHold is true).
+func (l *S3ObjectLock) UndeletableReason(now time.Time, bypassGovernance, releaseLegalHold bool) string {
+	retained := l.RetainUntil.After(now)
+	switch {
+	case retained && l.Mode == S3RetentionModeCompliance:
+		return fmt.Sprintf("COMPLIANCE mode retention until %s (no user can delete it until then)", l.RetainUntil.Format(time.RFC3339))
+	case retained && l.Mode == S3RetentionModeGovernance && !bypassGovernance:
+		return fmt.Sprintf("GOVERNANCE mode retention until %s (use --bypass-governance)", l.RetainUntil.Format(time.RFC3339))
+	case l.LegalHold && !releaseLegalHold:
+		return "legal hold is ON (use --release-legal-hold)"
+	default:
+		return ""
+	}
+}
+
+// S3ObjectDeleteError is the error of deleting the object version returned by DeleteObjects.
+type S3ObjectDeleteError struct {
+	// S3ObjectIdentifier is the object version that could not be deleted.
+	S3ObjectIdentifier
+	// Code is the error code such as "AccessDenied".
+	Code string
+	// Message is the error message.
+	Message string
+}
+
+// Error returns the error message.
+func (e S3ObjectDeleteError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// S3UndeletableObject is the object version that could not be deleted.
+type S3UndeletableObject struct {
+	// S3ObjectIdentifier is the object version.
+	S3ObjectIdentifier
+	// Reason is the human-readable reason why the object version could not be deleted.
+	Reason string
+}
+
+// S3UndeletableObjects is the set of the S3UndeletableObject.
+type S3UndeletableObjects []S3UndeletableObject
+
+// Len returns the length of the S3UndeletableObjects.
+func (s S3UndeletableObjects) Len() int {
+	return len(s)
+}
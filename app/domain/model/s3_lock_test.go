@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestS3ObjectLock_UndeletableReason(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	future := now.Add(24 * time.Hour)
+	past := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name             string
+		lock             S3ObjectLock
+		bypassGovernance bool
+		releaseLegalHold bool
+		want             string
+	}{
+		{name: "no lock", lock: S3ObjectLock{}, want: ""},
+		{name: "expired compliance retention", lock: S3ObjectLock{Mode: S3RetentionModeCompliance, RetainUntil: past}, want: ""},
+		{name: "compliance retention", lock: S3ObjectLock{Mode: S3RetentionModeCompliance, RetainUntil: future}, bypassGovernance: true, want: "COMPLIANCE"},
+		{name: "governance retention without bypass", lock: S3ObjectLock{Mode: S3RetentionModeGovernance, RetainUntil: future}, want: "--bypass-governance"},
+		{name: "governance retention with bypass", lock: S3ObjectLock{Mode: S3RetentionModeGovernance, RetainUntil: future}, bypassGovernance: true, want: ""},
+		{name: "legal hold without release", lock: S3ObjectLock{LegalHold: true}, want: "--release-legal-hold"},
+		{name: "legal hold with release", lock: S3ObjectLock{LegalHold: true}, releaseLegalHold: true, want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.lock.UndeletableReason(now, tt.bypassGovernance, tt.releaseLegalHold)
+			if tt.want == "" && got != "" {
+				t.Errorf("UndeletableReason() = %q, want empty", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("UndeletableReason() = %q, want to contain %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultS3ReportTopN is the default number of the largest objects in the S3 bucket report.
+	DefaultS3ReportTopN = 10
+	// S3ReportRootPrefix is the prefix name for objects that are not under any top-level prefix.
+	S3ReportRootPrefix = "(root)"
+)
+
+// S3ReportBreakdown is the number of objects and bytes aggregated by a key such as storage class or prefix.
+type S3ReportBreakdown struct {
+	// Name is the aggregation key.
+	Name string `json:"name"`
+	// Objects is the number of objects.
+	Objects int64 `json:"objects"`
+	// Bytes is the total size of objects in bytes.
+	Bytes int64 `json:"bytes"`
+}
+
+// S3ReportAgeRange is a bin of the age histogram.
+type S3ReportAgeRange struct {
+	// Label is the human-readable name of the bin.
+	Label string `json:"label"`
+	// Objects is the number of objects in the bin.
+	Objects int64 `json:"objects"`
+	// Bytes is the total size of objects in the bin.
+	Bytes int64 `json:"bytes"`
+	// upperBound is the exclusive upper bound of the object age. Zero means no upper bound.
+	upperBound time.Duration
+}
+
+// S3ReportObject is an object listed in the S3 bucket report.
+type S3ReportObject struct {
+	// Key is the name of the object.
+	Key string `json:"key"`
+	// Bytes is the size of the object in bytes.
+	Bytes int64 `json:"bytes"`
+	// StorageClass is the class of storage used to store the object.
+	StorageClass string `json:"storage_class"`
+	// LastModified is the creation date of the object.
+	LastModified time.Time `json:"last_modified"`
+}
+
+// S3BucketReport is the result of analyzing all objects in the S3 bucket.
+type S3BucketReport struct {
+	// Bucket is the name of the analyzed bucket.
+	Bucket Bucket `json:"bucket"`
+	// GeneratedAt is the date the report was generated.
+	GeneratedAt time.Time `json:"generated_at"`
+	// TotalObjects is the number of current objects.
+	TotalObjects int64 `json:"total_objects"`
+	// TotalBytes is the total size of current objects.
+	TotalBytes int64 `json:"total_bytes"`
+	// StorageClasses is the breakdown by storage class, sorted by bytes in descending order.
+	StorageClasses []S3ReportBreakdown `json:"storage_classes"`
+	// Prefixes is the breakdown by top-level prefix, sorted by bytes in descending order.
+	Prefixes []S3ReportBreakdown `json:"prefixes"`
+	// LargestObjects is the largest N objects.
+	LargestObjects []S3ReportObject `json:"largest_objects"`
+	// AgeHistogram is the histogram of the object age calculated from LastModified.
+	AgeHistogram []S3ReportAgeRange `json:"age_histogram"`
+	// IncompleteMultipartUploads is the number of multipart uploads that have not been completed or aborted.
+	IncompleteMultipartUploads int64 `json:"incomplete_multipart_uploads"`
+	// NoncurrentVersions is the number of noncurrent object versions.
+	NoncurrentVersions int64 `json:"noncurrent_versions"`
+	// NoncurrentVersionBytes is the total size of noncurrent object versions.
+	NoncurrentVersionBytes int64 `json:"noncurrent_version_bytes"`
+	// DeleteMarkers is the number of delete markers.
+	DeleteMarkers int64 `json:"delete_markers"`
+}
+
+// newS3ReportAgeHistogram returns the empty age histogram.
+func newS3ReportAgeHistogram() []S3ReportAgeRange {
+	const day = 24 * time.Hour
+	return []S3ReportAgeRange{
+		{Label: "< 1 day", upperBound: day},
+		{Label: "1-7 days", upperBound: 7 * day},
+		{Label: "7-30 days", upperBound: 30 * day},
+		{Label: "30-90 days", upperBound: 90 * day},
+		{Label: "90-365 days", upperBound: 365 * day},
+		{Label: ">= 1 year", upperBound: 0},
+	}
+}
+
+// NewS3BucketReport aggregates objects, versions and multipart uploads into the S3BucketReport.
+// now is used as the base time of the age histogram. If topN is less than 1, DefaultS3ReportTopN is used.
+func NewS3BucketReport(
+	bucket Bucket,
+	objects S3ObjectDetails,
+	versions S3ObjectVersions,
+	uploads S3MultipartUploads,
+	topN int,
+	now time.Time,
+) *S3BucketReport {
+	if topN < 1 {
+		topN = DefaultS3ReportTopN
+	}
+
+	report := &S3BucketReport{
+		Bucket:                     bucket,
+		GeneratedAt:                now,
+		TotalObjects:               int64(objects.Len()),
+		TotalBytes:                 objects.TotalSize(),
+		AgeHistogram:               newS3ReportAgeHistogram(),
+		IncompleteMultipartUploads: int64(len(uploads)),
+	}
+
+	storageClasses := make(map[string]*S3ReportBreakdown)
+	prefixes := make(map[string]*S3ReportBreakdown)
+	for _, o := range objects {
+		class := o.StorageClass
+		if class == "" {
+			class = "STANDARD"
+		}
+		addBreakdown(storageClasses, class, o.Size)
+		addBreakdown(prefixes, topLevelPrefix(o.S3Key), o.Size)
+		report.addAge(now.Sub(o.LastModified), o.Size)
+	}
+	report.StorageClasses = sortBreakdowns(storageClasses)
+	report.Prefixes = sortBreakdowns(prefixes)
+	report.LargestObjects = largestObjects(objects, topN)
+
+	for _, v := range versions {
+		if v.IsDeleteMarker {
+			report.DeleteMarkers++
+			continue
+		}
+		if !v.IsLatest {
+			report.NoncurrentVersions++
+			report.NoncurrentVersionBytes += v.Size
+		}
+	}
+	return report
+}
+
+// addAge adds the object to the age histogram.
+func (r *S3BucketReport) addAge(age time.Duration, size int64) {
+	for i := range r.AgeHistogram {
+		if r.AgeHistogram[i].upperBound == 0 || age < r.AgeHistogram[i].upperBound {
+			r.AgeHistogram[i].Objects++
+			r.AgeHistogram[i].Bytes += size
+			return
+		}
+	}
+}
+
+// addBreakdown adds the object size to the breakdown identified by name.
+func addBreakdown(m map[string]*S3ReportBreakdown, name string, size int64) {
+	b, ok := m[name]
+	if !ok {
+		b = &S3ReportBreakdown{Name: name}
+		m[name] = b
+	}
+	b.Objects++
+	b.Bytes += size
+}
+
+// sortBreakdowns returns the breakdowns sorted by bytes in descending order.
+// Breakdowns with the same bytes are sorted by name.
+func sortBreakdowns(m map[string]*S3ReportBreakdown) []S3ReportBreakdown {
+	breakdowns := make([]S3ReportBreakdown, 0, len(m))
+	for _, b := range m {
+		breakdowns = append(breakdowns, *b)
+	}
+	sort.Slice(breakdowns, func(i, j int) bool {
+		if breakdowns[i].Bytes == breakdowns[j].Bytes {
+			return breakdowns[i].Name < breakdowns[j].Name
+		}
+		return breakdowns[i].Bytes > breakdowns[j].Bytes
+	})
+	return breakdowns
+}
+
+// topLevelPrefix returns the first path element of the key with a trailing slash.
+// e.g. "logs/2024/01.log" -> "logs/", "index.html" -> "(root)"
+func topLevelPrefix(key S3Key) string {
+	i := strings.Index(key.String(), "/")
+	if i < 0 {
+		return S3ReportRootPrefix
+	}
+	return key.String()[:i+1]
+}
+
+// largestObjects returns the largest n objects sorted by size in descending order.
+func largestObjects(objects S3ObjectDetails, n int) []S3ReportObject {
+	sorted := make(S3ObjectDetails, objects.Len())
+	copy(sorted, objects)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Size > sorted[j].Size
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	largest := make([]S3ReportObject, 0, len(sorted))
+	for _, o := range sorted {
+		largest = append(largest, S3ReportObject{
+			Key:          o.S3Key.String(),
+			Bytes:        o.Size,
+			StorageClass: o.StorageClass,
+			LastModified: o.LastModified,
+		})
+	}
+	return largest
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNewS3BucketReport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	const day = 24 * time.Hour
+
+	objects := S3ObjectDetails{
+		{S3Key: "logs/2024/01.log", Size: 300, StorageClass: "STANDARD", LastModified: now.Add(-2 * day)},
+		{S3Key: "logs/2024/02.log", Size: 100, StorageClass: "", LastModified: now.Add(-time.Hour)},
+		{S3Key: "archive/old.tar", Size: 1000, StorageClass: "GLACIER", LastModified: now.Add(-400 * day)},
+		{S3Key: "index.html", Size: 10, StorageClass: "STANDARD", LastModified: now.Add(-40 * day)},
+	}
+	versions := S3ObjectVersions{
+		{S3Key: "index.html", VersionID: "2", Size: 10, IsLatest: true},
+		{S3Key: "index.html", VersionID: "1", Size: 8},
+		{S3Key: "index.html", VersionID: "0", Size: 5},
+		{S3Key: "deleted.txt", VersionID: "3", IsLatest: true, IsDeleteMarker: true},
+	}
+	uploads := S3MultipartUploads{{S3Key: "big.bin", UploadID: "1", Initiated: now}}
+
+	got := NewS3BucketReport(Bucket("bucket"), objects, versions, uploads, 2, now)
+
+	want := &S3BucketReport{
+		Bucket:       "bucket",
+		GeneratedAt:  now,
+		TotalObjects: 4,
+		TotalBytes:   1410,
+		StorageClasses: []S3ReportBreakdown{
+			{Name: "GLACIER", Objects: 1, Bytes: 1000},
+			{Name: "STANDARD", Objects: 3, Bytes: 410},
+		},
+		Prefixes: []S3ReportBreakdown{
+			{Name: "archive/", Objects: 1, Bytes: 1000},
+			{Name: "logs/", Objects: 2, Bytes: 400},
+			{Name: S3ReportRootPrefix, Objects: 1, Bytes: 10},
+		},
+		LargestObjects: []S3ReportObject{
+			{Key: "archive/old.tar", Bytes: 1000, StorageClass: "GLACIER", LastModified: now.Add(-400 * day)},
+			{Key: "logs/2024/01.log", Bytes: 300, StorageClass: "STANDARD", LastModified: now.Add(-2 * day)},
+		},
+		AgeHistogram: []S3ReportAgeRange{
+			{Label: "< 1 day", Objects: 1, Bytes: 100},
+			{Label: "1-7 days", Objects: 1, Bytes: 300},
+			{Label: "7-30 days"},
+			{Label: "30-90 days", Objects: 1, Bytes: 10},
+			{Label: "90-365 days"},
+			{Label: ">= 1 year", Objects: 1, Bytes: 1000},
+		},
+		IncompleteMultipartUploads: 1,
+		NoncurrentVersions:         2,
+		NoncurrentVersionBytes:     13,
+		DeleteMarkers:              1,
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(S3ReportAgeRange{})); diff != "" {
+		t.Errorf("NewS3BucketReport() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewS3BucketReport_DefaultTopN(t *testing.T) {
+	t.Parallel()
+
+	objects := make(S3ObjectDetails, 0, DefaultS3ReportTopN+5)
+	for i := 0; i < DefaultS3ReportTopN+5; i++ {
+		objects = append(objects, S3ObjectDetail{S3Key: S3Key("key"), Size: int64(i)})
+	}
+
+	got := NewS3BucketReport(Bucket("bucket"), objects, nil, nil, 0, time.Now())
+	if len(got.LargestObjects) != DefaultS3ReportTopN {
+		t.Errorf("len(LargestObjects) = %d, want %d", len(got.LargestObjects), DefaultS3ReportTopN)
+	}
+}
@@ -58,6 +58,12 @@ func TestRegionValidate(t *testing.T) {
 			wantErr: false,
 			e:       nil,
 		},
+		{
+			name:    "success. newer opt-in region",
+			r:       RegionILCentral1,
+			wantErr: false,
+			e:       nil,
+		},
 		{
 			name:    "failure. region is empty",
 			r:       Region(""),
@@ -347,21 +353,35 @@ func TestBucketDomain(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name string
-		b    Bucket
-		want string
+		name   string
+		b      Bucket
+		region Region
+		want   string
 	}{
 		{
-			name: "success",
-			b:    Bucket("abc"),
-			want: "abc.s3.amazonaws.com",
+			name:   "success. region is empty",
+			b:      Bucket("abc"),
+			region: Region(""),
+			want:   "abc.s3.amazonaws.com",
+		},
+		{
+			name:   "success. aws partition",
+			b:      Bucket("abc"),
+			region: RegionAPNortheast1,
+			want:   "abc.s3.ap-northeast-1.amazonaws.com",
+		},
+		{
+			name:   "success. aws-cn partition",
+			b:      Bucket("abc"),
+			region: RegionCNNorth1,
+			want:   "abc.s3.cn-north-1.amazonaws.com.cn",
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			if got := tt.b.Domain(); got != tt.want {
+			if got := tt.b.Domain(tt.region); got != tt.want {
 				t.Errorf("Bucket.Domain() = %v, want %v", got, tt.want)
 			}
 		})
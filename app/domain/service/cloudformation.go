@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/nao1215/rainbow/app/domain/model"
 )
@@ -61,16 +62,53 @@ type CFnStackCreator interface {
 	CreatCFnStack(ctx context.Context, input *CFnStackCreatorInput) (*CFnStackCreatorOutput, error)
 }
 
+// CFnStackDescriberInput is the input of the CFnStackDescriber method.
+type CFnStackDescriberInput struct {
+	// StackName is the name or the ID of the stack. Use the ID to describe the deleted stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+}
+
+// CFnStackDescriberOutput is the output of the CFnStackDescriber method.
+type CFnStackDescriberOutput struct {
+	// Stack is the CloudFormation stack.
+	Stack *model.Stack
+	// TerminationProtection is whether the termination protection is enabled.
+	TerminationProtection bool
+	// Tags is the tags of the stack.
+	Tags model.CFnTags
+	// ParameterKeys is the keys of the stack parameters.
+	ParameterKeys []string
+	// Capabilities is the capabilities that the stack was created or updated with.
+	Capabilities []string
+	// Outputs is the outputs of the stack.
+	Outputs model.CFnOutputs
+}
+
+// CFnStackDescriber is the interface that wraps the basic DescribeCFnStack method.
+// If the stack does not exist, it returns domain.ErrCFnStackNotFound.
+type CFnStackDescriber interface {
+	DescribeCFnStack(ctx context.Context, input *CFnStackDescriberInput) (*CFnStackDescriberOutput, error)
+}
+
 // CFnStackDeleterInput is the input of the CFnStackDeleter method.
 type CFnStackDeleterInput struct {
 	// StackName is the name of the stack.
 	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// RetainResources is the logical IDs of the resources that are retained. It is only for the DELETE_FAILED stack.
+	RetainResources []string
+	// ClientRequestToken is the token that identifies the events of this deletion.
+	ClientRequestToken string
 }
 
 // CFnStackDeleterOutput is the output of the CFnStackDeleter method.
 type CFnStackDeleterOutput struct{}
 
 // CFnStackDeleter is the interface that wraps the basic CFnStackDeleter method.
+// It only requests the deletion. It does not wait for the deletion to complete.
 type CFnStackDeleter interface {
 	DeleteCFnStack(ctx context.Context, input *CFnStackDeleterInput) (*CFnStackDeleterOutput, error)
 }
@@ -81,6 +119,8 @@ type CFnStackEventsDescriberInput struct {
 	StackName string
 	// Region is the region of the stack.
 	Region model.Region
+	// Since is the time from which the events are returned. If it is zero, only the latest page of the events is returned.
+	Since time.Time
 }
 
 // CFnStackEventsDescriberOutput is the output of the CFnStackEventsDescriber method.
@@ -92,3 +132,250 @@ type CFnStackEventsDescriberOutput struct {
 type CFnStackEventsDescriber interface {
 	DescribeCFnStackEvents(ctx context.Context, input *CFnStackEventsDescriberInput) (*CFnStackEventsDescriberOutput, error)
 }
+
+// CFnStackTagsUpdaterInput is the input of the CFnStackTagsUpdater method.
+type CFnStackTagsUpdaterInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// Tags is the new tag set of the stack. The tags that are not in Tags are removed.
+	Tags model.CFnTags
+	// ParameterKeys is the keys of the stack parameters. Their previous values are reused.
+	ParameterKeys []string
+	// Capabilities is the capabilities that the stack was created or updated with.
+	Capabilities []string
+	// ClientRequestToken is the token that identifies the events of this update.
+	ClientRequestToken string
+}
+
+// CFnStackTagsUpdaterOutput is the output of the CFnStackTagsUpdater method.
+type CFnStackTagsUpdaterOutput struct{}
+
+// CFnStackTagsUpdater is the interface that wraps the basic UpdateCFnStackTags method.
+// It updates the stack with the previous template and parameters and the new tag set.
+// It only requests the update. It does not wait for the update to complete.
+// If the tags are not changed, it returns domain.ErrCFnStackNoUpdates.
+type CFnStackTagsUpdater interface {
+	UpdateCFnStackTags(ctx context.Context, input *CFnStackTagsUpdaterInput) (*CFnStackTagsUpdaterOutput, error)
+}
+
+// CFnChangeSetCreatorInput is the input of the CFnChangeSetCreator method.
+type CFnChangeSetCreatorInput struct {
+	// StackName is the name of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// ChangeSetName is the name of the change set. It must be unique in the stack.
+	ChangeSetName string
+	// ChangeSetType is CREATE for the new stack and UPDATE for the existing stack.
+	ChangeSetType model.CFnChangeSetType
+	// TemplateBody is the template body.
+	TemplateBody string
+	// Parameters is the parameters of the stack.
+	Parameters model.CFnParameters
+	// Capabilities is the capabilities that acknowledge the template contains the certain resources.
+	Capabilities []model.CFnCapability
+	// Tags is the tags of the stack. If nil, the tags of the existing stack are not changed.
+	Tags model.CFnTags
+}
+
+// CFnChangeSetCreatorOutput is the output of the CFnChangeSetCreator method.
+type CFnChangeSetCreatorOutput struct {
+	// ChangeSetID is the ID (ARN) of the change set.
+	ChangeSetID string
+	// StackID is the ID of the stack.
+	StackID string
+}
+
+// CFnChangeSetCreator is the interface that wraps the basic CreateCFnChangeSet method.
+// It only requests the creation. It does not wait for the change set to be created.
+type CFnChangeSetCreator interface {
+	CreateCFnChangeSet(ctx context.Context, input *CFnChangeSetCreatorInput) (*CFnChangeSetCreatorOutput, error)
+}
+
+// CFnChangeSetDescriberInput is the input of the CFnChangeSetDescriber method.
+type CFnChangeSetDescriberInput struct {
+	// ChangeSetName is the name or the ID of the change set.
+	ChangeSetName string
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+}
+
+// CFnChangeSetDescriberOutput is the output of the CFnChangeSetDescriber method.
+type CFnChangeSetDescriberOutput struct {
+	// Status is the status of the change set creation.
+	Status model.CFnChangeSetStatus
+	// StatusReason is the reason of the status. e.g. the reason why the change set can not be created.
+	StatusReason string
+	// Changes is the resource changes of the change set.
+	Changes []*model.CFnResourceChange
+}
+
+// CFnChangeSetDescriber is the interface that wraps the basic DescribeCFnChangeSet method.
+type CFnChangeSetDescriber interface {
+	DescribeCFnChangeSet(ctx context.Context, input *CFnChangeSetDescriberInput) (*CFnChangeSetDescriberOutput, error)
+}
+
+// CFnChangeSetExecutorInput is the input of the CFnChangeSetExecutor method.
+type CFnChangeSetExecutorInput struct {
+	// ChangeSetName is the name or the ID of the change set.
+	ChangeSetName string
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// ClientRequestToken is the token that identifies the events of this execution.
+	ClientRequestToken string
+}
+
+// CFnChangeSetExecutorOutput is the output of the CFnChangeSetExecutor method.
+type CFnChangeSetExecutorOutput struct{}
+
+// CFnChangeSetExecutor is the interface that wraps the basic ExecuteCFnChangeSet method.
+// It only requests the execution. It does not wait for the stack operation to complete.
+type CFnChangeSetExecutor interface {
+	ExecuteCFnChangeSet(ctx context.Context, input *CFnChangeSetExecutorInput) (*CFnChangeSetExecutorOutput, error)
+}
+
+// CFnChangeSetDeleterInput is the input of the CFnChangeSetDeleter method.
+type CFnChangeSetDeleterInput struct {
+	// ChangeSetName is the name or the ID of the change set.
+	ChangeSetName string
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+}
+
+// CFnChangeSetDeleterOutput is the output of the CFnChangeSetDeleter method.
+type CFnChangeSetDeleterOutput struct{}
+
+// CFnChangeSetDeleter is the interface that wraps the basic DeleteCFnChangeSet method.
+type CFnChangeSetDeleter interface {
+	DeleteCFnChangeSet(ctx context.Context, input *CFnChangeSetDeleterInput) (*CFnChangeSetDeleterOutput, error)
+}
+
+// CFnStackDriftDetectorInput is the input of the CFnStackDriftDetector method.
+type CFnStackDriftDetectorInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+}
+
+// CFnStackDriftDetectorOutput is the output of the CFnStackDriftDetector method.
+type CFnStackDriftDetectorOutput struct {
+	// DetectionID is the ID of the drift detection operation.
+	DetectionID string
+}
+
+// CFnStackDriftDetector is the interface that wraps the basic DetectCFnStackDrift method.
+// It starts the drift detection of the stack and returns without waiting for it.
+type CFnStackDriftDetector interface {
+	DetectCFnStackDrift(ctx context.Context, input *CFnStackDriftDetectorInput) (*CFnStackDriftDetectorOutput, error)
+}
+
+// CFnStackDriftDetectionStatusDescriberInput is the input of the CFnStackDriftDetectionStatusDescriber method.
+type CFnStackDriftDetectionStatusDescriberInput struct {
+	// DetectionID is the ID of the drift detection operation.
+	DetectionID string
+	// Region is the region of the stack.
+	Region model.Region
+}
+
+// CFnStackDriftDetectionStatusDescriberOutput is the output of the CFnStackDriftDetectionStatusDescriber method.
+type CFnStackDriftDetectionStatusDescriberOutput struct {
+	// DetectionStatus is the status of the drift detection.
+	DetectionStatus model.CFnDriftDetectionStatus
+	// DetectionStatusReason is the reason of the detection status.
+	DetectionStatusReason string
+	// DriftStatus is the drift status of the stack.
+	DriftStatus model.StackDriftStatus
+	// DriftedResourceCount is the number of the resources that drifted.
+	DriftedResourceCount int
+}
+
+// CFnStackDriftDetectionStatusDescriber is the interface that wraps the basic DescribeCFnStackDriftDetectionStatus method.
+type CFnStackDriftDetectionStatusDescriber interface {
+	DescribeCFnStackDriftDetectionStatus(ctx context.Context, input *CFnStackDriftDetectionStatusDescriberInput) (*CFnStackDriftDetectionStatusDescriberOutput, error)
+}
+
+// CFnStackResourceDriftsDescriberInput is the input of the CFnStackResourceDriftsDescriber method.
+type CFnStackResourceDriftsDescriberInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+}
+
+// CFnStackResourceDriftsDescriberOutput is the output of the CFnStackResourceDriftsDescriber method.
+type CFnStackResourceDriftsDescriberOutput struct {
+	// Drifts is the drifts of the resources that are MODIFIED or DELETED in the last drift detection.
+	Drifts []*model.CFnStackResourceDrift
+}
+
+// CFnStackResourceDriftsDescriber is the interface that wraps the basic DescribeCFnStackResourceDrifts method.
+type CFnStackResourceDriftsDescriber interface {
+	DescribeCFnStackResourceDrifts(ctx context.Context, input *CFnStackResourceDriftsDescriberInput) (*CFnStackResourceDriftsDescriberOutput, error)
+}
+
+// CFnExportsListerInput is the input of the CFnExportsLister method.
+type CFnExportsListerInput struct {
+	// Region is the region of the exports.
+	Region model.Region
+}
+
+// CFnExportsListerOutput is the output of the CFnExportsLister method.
+type CFnExportsListerOutput struct {
+	// Exports is the exports in the region. Importers of each export are not set.
+	Exports []*model.CFnExport
+}
+
+// CFnExportsLister is the interface that wraps the basic ListCFnExports method.
+type CFnExportsLister interface {
+	ListCFnExports(ctx context.Context, input *CFnExportsListerInput) (*CFnExportsListerOutput, error)
+}
+
+// CFnImportsListerInput is the input of the CFnImportsLister method.
+type CFnImportsListerInput struct {
+	// ExportName is the name of the export.
+	ExportName string
+	// Region is the region of the export.
+	Region model.Region
+}
+
+// CFnImportsListerOutput is the output of the CFnImportsLister method.
+type CFnImportsListerOutput struct {
+	// StackNames is the names of the stacks that import the export. It is empty if no stack imports it.
+	StackNames []string
+}
+
+// CFnImportsLister is the interface that wraps the basic ListCFnImports method.
+type CFnImportsLister interface {
+	ListCFnImports(ctx context.Context, input *CFnImportsListerInput) (*CFnImportsListerOutput, error)
+}
+
+// CFnTemplateGetterInput is the input of the CFnTemplateGetter method.
+type CFnTemplateGetterInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// Stage is the stage of the template.
+	Stage model.CFnTemplateStage
+}
+
+// CFnTemplateGetterOutput is the output of the CFnTemplateGetter method.
+type CFnTemplateGetterOutput struct {
+	// TemplateBody is the template body in YAML or JSON.
+	TemplateBody string
+}
+
+// CFnTemplateGetter is the interface that wraps the basic GetCFnTemplate method.
+// If the stack does not exist, it returns domain.ErrCFnStackNotFound.
+type CFnTemplateGetter interface {
+	GetCFnTemplate(ctx context.Context, input *CFnTemplateGetterInput) (*CFnTemplateGetterOutput, error)
+}
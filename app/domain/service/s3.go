@@ -77,10 +77,15 @@ type S3ObjectsDeleterInput struct {
 	Region model.Region
 	// S3ObjectSets is the list of the objects to delete.
 	S3ObjectSets model.S3ObjectIdentifiers
+	// BypassGovernance is whether to delete the objects under GOVERNANCE mode retention.
+	BypassGovernance bool
 }
 
 // S3ObjectsDeleterOutput is the output of the DeleteBucketObjects method.
-type S3ObjectsDeleterOutput struct{}
+type S3ObjectsDeleterOutput struct {
+	// Errors is the list of the objects that could not be deleted.
+	Errors []model.S3ObjectDeleteError
+}
 
 // S3ObjectsDeleter is the interface that wraps the basic DeleteBucketObjects method.
 type S3ObjectsDeleter interface {
@@ -186,9 +191,163 @@ type S3ObjectVersionsListerInput struct {
 type S3ObjectVersionsListerOutput struct {
 	// Objects is the list of the objects.
 	Objects model.S3ObjectIdentifiers
+	// Versions is the list of the object versions and delete markers with their metadata.
+	Versions model.S3ObjectVersions
 }
 
 // S3ObjectVersionsLister is the interface that wraps the basic ListBucketObjectVersions method.
 type S3ObjectVersionsLister interface {
 	ListS3ObjectVersions(ctx context.Context, input *S3ObjectVersionsListerInput) (*S3ObjectVersionsListerOutput, error)
 }
+
+// S3ObjectDetailsListerInput is the input of the ListS3ObjectDetails method.
+type S3ObjectDetailsListerInput struct {
+	// Bucket is the name of the bucket to list.
+	Bucket model.Bucket
+	// Prefix limits the response to keys that begin with the specified prefix.
+	Prefix model.S3Key
+}
+
+// S3ObjectDetailsListerOutput is the output of the ListS3ObjectDetails method.
+type S3ObjectDetailsListerOutput struct {
+	// Objects is the list of the objects with their metadata.
+	Objects model.S3ObjectDetails
+}
+
+// S3ObjectDetailsLister is the interface that wraps the basic ListS3ObjectDetails method.
+type S3ObjectDetailsLister interface {
+	ListS3ObjectDetails(ctx context.Context, input *S3ObjectDetailsListerInput) (*S3ObjectDetailsListerOutput, error)
+}
+
+// S3MultipartUploadsListerInput is the input of the ListS3MultipartUploads method.
+type S3MultipartUploadsListerInput struct {
+	// Bucket is the name of the bucket to list.
+	Bucket model.Bucket
+}
+
+// S3MultipartUploadsListerOutput is the output of the ListS3MultipartUploads method.
+type S3MultipartUploadsListerOutput struct {
+	// Uploads is the list of the incomplete multipart uploads.
+	Uploads model.S3MultipartUploads
+}
+
+// S3MultipartUploadsLister is the interface that wraps the basic ListS3MultipartUploads method.
+type S3MultipartUploadsLister interface {
+	ListS3MultipartUploads(ctx context.Context, input *S3MultipartUploadsListerInput) (*S3MultipartUploadsListerOutput, error)
+}
+
+// S3ObjectTagsGetterInput is the input of the GetS3ObjectTags method.
+type S3ObjectTagsGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.Bucket
+	// S3Key is the name of the object.
+	S3Key model.S3Key
+}
+
+// S3ObjectTagsGetterOutput is the output of the GetS3ObjectTags method.
+type S3ObjectTagsGetterOutput struct {
+	// Tags is the tag set of the object.
+	Tags model.S3Tags
+}
+
+// S3ObjectTagsGetter is the interface that wraps the basic GetS3ObjectTags method.
+type S3ObjectTagsGetter interface {
+	GetS3ObjectTags(ctx context.Context, input *S3ObjectTagsGetterInput) (*S3ObjectTagsGetterOutput, error)
+}
+
+// S3ObjectMetadataGetterInput is the input of the GetS3ObjectMetadata method.
+type S3ObjectMetadataGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.Bucket
+	// S3Key is the name of the object.
+	S3Key model.S3Key
+}
+
+// S3ObjectMetadataGetterOutput is the output of the GetS3ObjectMetadata method.
+type S3ObjectMetadataGetterOutput struct {
+	// ContentType is a standard MIME type describing the format of the object data.
+	ContentType string
+	// ContentLength is the size of the object in bytes.
+	ContentLength int64
+}
+
+// S3ObjectMetadataGetter is the interface that wraps the basic GetS3ObjectMetadata method.
+type S3ObjectMetadataGetter interface {
+	GetS3ObjectMetadata(ctx context.Context, input *S3ObjectMetadataGetterInput) (*S3ObjectMetadataGetterOutput, error)
+}
+
+// S3BucketTagsGetterInput is the input of the GetS3BucketTags method.
+type S3BucketTagsGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.Bucket
+	// Region is the region of the bucket.
+	Region model.Region
+}
+
+// S3BucketTagsGetterOutput is the output of the GetS3BucketTags method.
+type S3BucketTagsGetterOutput struct {
+	// Tags is the tag set of the bucket. It is empty if the bucket has no tags.
+	Tags model.S3Tags
+}
+
+// S3BucketTagsGetter is the interface that wraps the basic GetS3BucketTags method.
+type S3BucketTagsGetter interface {
+	GetS3BucketTags(ctx context.Context, input *S3BucketTagsGetterInput) (*S3BucketTagsGetterOutput, error)
+}
+
+// S3MultipartUploadAborterInput is the input of the AbortS3MultipartUpload method.
+type S3MultipartUploadAborterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.Bucket
+	// Region is the region of the bucket.
+	Region model.Region
+	// Upload is the multipart upload to abort.
+	Upload model.S3MultipartUpload
+}
+
+// S3MultipartUploadAborterOutput is the output of the AbortS3MultipartUpload method.
+type S3MultipartUploadAborterOutput struct{}
+
+// S3MultipartUploadAborter is the interface that wraps the basic AbortS3MultipartUpload method.
+type S3MultipartUploadAborter interface {
+	AbortS3MultipartUpload(ctx context.Context, input *S3MultipartUploadAborterInput) (*S3MultipartUploadAborterOutput, error)
+}
+
+// S3ObjectLockGetterInput is the input of the GetS3ObjectLock method.
+type S3ObjectLockGetterInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.Bucket
+	// Region is the region of the bucket.
+	Region model.Region
+	// S3ObjectIdentifier is the object version.
+	S3ObjectIdentifier model.S3ObjectIdentifier
+}
+
+// S3ObjectLockGetterOutput is the output of the GetS3ObjectLock method.
+type S3ObjectLockGetterOutput struct {
+	// Lock is the legal hold and retention status of the object version.
+	Lock *model.S3ObjectLock
+}
+
+// S3ObjectLockGetter is the interface that wraps the basic GetS3ObjectLock method.
+type S3ObjectLockGetter interface {
+	GetS3ObjectLock(ctx context.Context, input *S3ObjectLockGetterInput) (*S3ObjectLockGetterOutput, error)
+}
+
+// S3ObjectLegalHoldReleaserInput is the input of the ReleaseS3ObjectLegalHold method.
+type S3ObjectLegalHoldReleaserInput struct {
+	// Bucket is the name of the bucket.
+	Bucket model.Bucket
+	// Region is the region of the bucket.
+	Region model.Region
+	// S3ObjectIdentifier is the object version.
+	S3ObjectIdentifier model.S3ObjectIdentifier
+}
+
+// S3ObjectLegalHoldReleaserOutput is the output of the ReleaseS3ObjectLegalHold method.
+type S3ObjectLegalHoldReleaserOutput struct{}
+
+// S3ObjectLegalHoldReleaser is the interface that wraps the basic ReleaseS3ObjectLegalHold method.
+type S3ObjectLegalHoldReleaser interface {
+	ReleaseS3ObjectLegalHold(ctx context.Context, input *S3ObjectLegalHoldReleaserInput) (*S3ObjectLegalHoldReleaserOutput, error)
+}
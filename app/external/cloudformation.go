@@ -2,28 +2,61 @@ package external
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/smithy-go"
 	"github.com/google/wire"
+	"github.com/nao1215/rainbow/app/domain"
 	"github.com/nao1215/rainbow/app/domain/model"
 	"github.com/nao1215/rainbow/app/domain/service"
 )
 
 // NewCloudFormationClient returns a new CloudFormation client.
 func NewCloudFormationClient(cfg *model.AWSConfig) *cloudformation.Client {
-	return cloudformation.NewFromConfig(
-		*cfg.Config,
-		func(o *cloudformation.Options) {
-			o.RetryMaxAttempts = model.CloudFormationRetryMaxAttempts
-			o.RetryMode = aws.RetryModeStandard
-		})
+	return cloudformation.NewFromConfig(awsConfigWithRetryPolicy(cfg))
+}
+
+// isRegionNotEnabledError returns true if the account is not opted in to the region.
+// OptInRequired always means the disabled region. In the disabled opt-in region, the credentials are
+// not recognized either, so InvalidClientTokenId and UnrecognizedClientException mean the disabled region
+// only if the region requires opt-in. Otherwise they are the invalid or expired credentials.
+func isRegionNotEnabledError(err error, region model.Region) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "OptInRequired":
+		return true
+	case "InvalidClientTokenId", "UnrecognizedClientException":
+		info, ok := region.Info()
+		return ok && info.OptIn
+	default:
+		return false
+	}
 }
 
-// NewCloudFormationStackDeleteCompleteWaiter returns a new CloudFormationStackDeleteCompleteWaiter.
-func NewCloudFormationStackDeleteCompleteWaiter(client *cloudformation.Client) *cloudformation.StackDeleteCompleteWaiter {
-	return cloudformation.NewStackDeleteCompleteWaiter(client)
+// isNoUpdatesError returns true if the stack update does not change anything.
+// CloudFormation returns ValidationError with the message "No updates are to be performed.".
+func isNoUpdatesError(err error) bool {
+	return strings.Contains(err.Error(), "ValidationError") && strings.Contains(err.Error(), "No updates are to be performed")
+}
+
+// isStackNotFoundError returns true if the stack does not exist.
+// CloudFormation returns ValidationError with the message "Stack with id XXX does not exist".
+func isStackNotFoundError(err error) bool {
+	return strings.Contains(err.Error(), "ValidationError") && strings.Contains(err.Error(), "does not exist")
+}
+
+// isNotImportedError returns true if no stack imports the export.
+// CloudFormation returns ValidationError with the message "Export 'XXX' is not imported by any stack.".
+func isNotImportedError(err error) bool {
+	return strings.Contains(err.Error(), "ValidationError") && strings.Contains(err.Error(), "is not imported by any stack")
 }
 
 // CFnStackLister implements the CFnStackLister interface.
@@ -65,6 +98,9 @@ func (l *CFnStackLister) ListCFnStack(ctx context.Context, input *service.CFnSta
 
 		out, err := l.client.ListStacks(ctx, in, opt)
 		if err != nil {
+			if isRegionNotEnabledError(err, input.Region) {
+				return nil, fmt.Errorf("%w: region=%s", domain.ErrRegionNotEnabled, input.Region)
+			}
 			return nil, err
 		}
 
@@ -139,22 +175,28 @@ func (l *CFnStackResourceLister) ListCFnStackResource(ctx context.Context, input
 
 		out, err := l.client.ListStackResources(ctx, in, opt)
 		if err != nil {
+			if isStackNotFoundError(err) {
+				return nil, fmt.Errorf("%w: stack=%s", domain.ErrCFnStackNotFound, input.StackName)
+			}
 			return nil, err
 		}
 
 		for _, resource := range out.StackResourceSummaries {
-			resources = append(resources, &model.StackResource{
+			r := &model.StackResource{
 				LastUpdatedTimestamp: resource.LastUpdatedTimestamp,
 				LogicalResourceID:    resource.LogicalResourceId,
 				ResourceStatus:       model.ResourceStatus(resource.ResourceStatus),
 				ResourceType:         resource.ResourceType,
-				DriftInformation: &model.StackResourceDriftInformationSummary{
-					StackResourceDriftStatus: model.StackResourceDriftStatus(resource.DriftInformation.StackResourceDriftStatus),
-					LastCheckTimestamp:       resource.DriftInformation.LastCheckTimestamp,
-				},
 				PhysicalResourceID:   resource.PhysicalResourceId,
 				ResourceStatusReason: resource.ResourceStatusReason,
-			})
+			}
+			if resource.DriftInformation != nil {
+				r.DriftInformation = &model.StackResourceDriftInformationSummary{
+					StackResourceDriftStatus: model.StackResourceDriftStatus(resource.DriftInformation.StackResourceDriftStatus),
+					LastCheckTimestamp:       resource.DriftInformation.LastCheckTimestamp,
+				}
+			}
+			resources = append(resources, r)
 		}
 		if out.NextToken == nil {
 			break
@@ -203,10 +245,93 @@ func (c *CFnStackCreator) CreatCFnStack(ctx context.Context, input *service.CFnS
 	}, nil
 }
 
+// CFnStackDescriber implements the CFnStackDescriber interface.
+type CFnStackDescriber struct {
+	client *cloudformation.Client
+}
+
+// CFnStackDescriberSet is a set of CFnStackDescriber.
+//
+//nolint:gochecknoglobals
+var CFnStackDescriberSet = wire.NewSet(
+	NewCFnStackDescriber,
+	wire.Bind(new(service.CFnStackDescriber), new(*CFnStackDescriber)),
+)
+
+var _ service.CFnStackDescriber = (*CFnStackDescriber)(nil)
+
+// NewCFnStackDescriber returns a new CFnStackDescriber.
+func NewCFnStackDescriber(client *cloudformation.Client) *CFnStackDescriber {
+	return &CFnStackDescriber{client: client}
+}
+
+// DescribeCFnStack returns the CloudFormation stack.
+func (d *CFnStackDescriber) DescribeCFnStack(ctx context.Context, input *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+	out, err := d.client.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(input.StackName),
+	}, opt)
+	if err != nil {
+		if isStackNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+		}
+		return nil, err
+	}
+	if len(out.Stacks) == 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+	}
+
+	stack := out.Stacks[0]
+	output := &service.CFnStackDescriberOutput{
+		Stack: &model.Stack{
+			CreationTime:        stack.CreationTime,
+			StackName:           stack.StackName,
+			StackStatus:         model.StackStatus(stack.StackStatus),
+			DeletionTime:        stack.DeletionTime,
+			LastUpdatedTime:     stack.LastUpdatedTime,
+			ParentID:            stack.ParentId,
+			RootID:              stack.RootId,
+			StackID:             stack.StackId,
+			StackStatusReason:   stack.StackStatusReason,
+			TemplateDescription: stack.Description,
+		},
+		TerminationProtection: aws.ToBool(stack.EnableTerminationProtection),
+		Tags:                  make(model.CFnTags, 0, len(stack.Tags)),
+		ParameterKeys:         make([]string, 0, len(stack.Parameters)),
+		Capabilities:          make([]string, 0, len(stack.Capabilities)),
+		Outputs:               make(model.CFnOutputs, 0, len(stack.Outputs)),
+	}
+	for _, tag := range stack.Tags {
+		output.Tags = append(output.Tags, model.CFnTag{Key: aws.ToString(tag.Key), Value: aws.ToString(tag.Value)})
+	}
+	for _, parameter := range stack.Parameters {
+		output.ParameterKeys = append(output.ParameterKeys, aws.ToString(parameter.ParameterKey))
+	}
+	for _, capability := range stack.Capabilities {
+		output.Capabilities = append(output.Capabilities, string(capability))
+	}
+	for _, o := range stack.Outputs {
+		output.Outputs = append(output.Outputs, model.CFnOutput{
+			Key:         aws.ToString(o.OutputKey),
+			Value:       aws.ToString(o.OutputValue),
+			Description: aws.ToString(o.Description),
+			ExportName:  aws.ToString(o.ExportName),
+		})
+	}
+	if stack.DriftInformation != nil {
+		output.Stack.DriftInformation = &model.StackDriftInformationSummary{
+			StackDriftStatus:   model.StackDriftStatus(stack.DriftInformation.StackDriftStatus),
+			LastCheckTimestamp: stack.DriftInformation.LastCheckTimestamp,
+		}
+	}
+	return output, nil
+}
+
 // CFnStackDeleter implements the CFnStackDeleter interface.
 type CFnStackDeleter struct {
 	client *cloudformation.Client
-	waiter *cloudformation.StackDeleteCompleteWaiter
 }
 
 // CFnStackDeleterSet is a set of CFnStackDeleter.
@@ -220,29 +345,28 @@ var CFnStackDeleterSet = wire.NewSet(
 var _ service.CFnStackDeleter = (*CFnStackDeleter)(nil)
 
 // NewCFnStackDeleter returns a new CloudFormationStackDeleter.
-func NewCFnStackDeleter(client *cloudformation.Client, waiter *cloudformation.StackDeleteCompleteWaiter) *CFnStackDeleter {
-	return &CFnStackDeleter{
-		client: client,
-		waiter: waiter,
-	}
+func NewCFnStackDeleter(client *cloudformation.Client) *CFnStackDeleter {
+	return &CFnStackDeleter{client: client}
 }
 
-// DeleteCFnStack deletes a CloudFormation stack.
+// DeleteCFnStack requests the deletion of a CloudFormation stack.
 func (d *CFnStackDeleter) DeleteCFnStack(ctx context.Context, input *service.CFnStackDeleterInput) (*service.CFnStackDeleterOutput, error) {
 	in := &cloudformation.DeleteStackInput{
 		StackName: aws.String(input.StackName),
 	}
-	_, err := d.client.DeleteStack(ctx, in)
-	if err != nil {
-		return nil, err
+	if len(input.RetainResources) > 0 {
+		in.RetainResources = input.RetainResources
+	}
+	if input.ClientRequestToken != "" {
+		in.ClientRequestToken = aws.String(input.ClientRequestToken)
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
 	}
 
-	if err = d.waiter.Wait(ctx, &cloudformation.DescribeStacksInput{
-		StackName: aws.String(input.StackName),
-	}, model.CloudFormationWaitNanoSecTime); err != nil && !strings.Contains(err.Error(), "waiter state transitioned to Failure") {
+	if _, err := d.client.DeleteStack(ctx, in, opt); err != nil {
 		return nil, err
 	}
-
 	return &service.CFnStackDeleterOutput{}, nil
 }
 
@@ -266,7 +390,7 @@ func NewCFnStackEventsDescriber(client *cloudformation.Client) *CFnStackEventsDe
 	return &CFnStackEventsDescriber{client: client}
 }
 
-// DescribeCFnStackEvents returns a list of CloudFormation stack events.
+// DescribeCFnStackEvents returns a list of CloudFormation stack events in reverse chronological order.
 func (d *CFnStackEventsDescriber) DescribeCFnStackEvents(ctx context.Context, input *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error) {
 	in := &cloudformation.DescribeStackEventsInput{
 		StackName: aws.String(input.StackName),
@@ -275,33 +399,609 @@ func (d *CFnStackEventsDescriber) DescribeCFnStackEvents(ctx context.Context, in
 		o.Region = input.Region.String()
 	}
 
-	out, err := d.client.DescribeStackEvents(ctx, in, opt)
+	events := make([]*model.StackEvent, 0, 100)
+	for {
+		out, err := d.client.DescribeStackEvents(ctx, in, opt)
+		if err != nil {
+			if isStackNotFoundError(err) {
+				return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+			}
+			return nil, err
+		}
+
+		older := false
+		for _, event := range out.StackEvents {
+			if !input.Since.IsZero() && event.Timestamp != nil && event.Timestamp.Before(input.Since) {
+				older = true
+				break
+			}
+			events = append(events, &model.StackEvent{
+				EventID:              event.EventId,
+				StackID:              event.StackId,
+				StackName:            event.StackName,
+				Timestamp:            event.Timestamp,
+				ClientRequestToken:   event.ClientRequestToken,
+				HookFailureMode:      model.HookFailureMode(event.HookFailureMode),
+				HookInvocationPoint:  model.HookInvocationPoint(event.HookInvocationPoint),
+				HookStatus:           model.HookStatus(event.HookStatus),
+				HookStatusReason:     event.HookStatusReason,
+				HookType:             event.HookType,
+				LogicalResourceID:    event.LogicalResourceId,
+				PhysicalResourceID:   event.PhysicalResourceId,
+				ResourceProperties:   event.ResourceProperties,
+				ResourceStatus:       model.ResourceStatus(event.ResourceStatus),
+				ResourceStatusReason: event.ResourceStatusReason,
+				ResourceType:         event.ResourceType,
+			})
+		}
+		// The events are sorted in reverse chronological order, so the older pages are not needed.
+		if input.Since.IsZero() || older || out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+	return &service.CFnStackEventsDescriberOutput{
+		Events: events,
+	}, nil
+}
+
+// CFnStackTagsUpdater implements the CFnStackTagsUpdater interface.
+type CFnStackTagsUpdater struct {
+	client *cloudformation.Client
+}
+
+// CFnStackTagsUpdaterSet is a set of CFnStackTagsUpdater.
+//
+//nolint:gochecknoglobals
+var CFnStackTagsUpdaterSet = wire.NewSet(
+	NewCFnStackTagsUpdater,
+	wire.Bind(new(service.CFnStackTagsUpdater), new(*CFnStackTagsUpdater)),
+)
+
+var _ service.CFnStackTagsUpdater = (*CFnStackTagsUpdater)(nil)
+
+// NewCFnStackTagsUpdater returns a new CFnStackTagsUpdater.
+func NewCFnStackTagsUpdater(client *cloudformation.Client) *CFnStackTagsUpdater {
+	return &CFnStackTagsUpdater{client: client}
+}
+
+// UpdateCFnStackTags requests the update of the CloudFormation stack with the previous template and parameters and the new tag set.
+func (u *CFnStackTagsUpdater) UpdateCFnStackTags(ctx context.Context, input *service.CFnStackTagsUpdaterInput) (*service.CFnStackTagsUpdaterOutput, error) {
+	in := &cloudformation.UpdateStackInput{
+		StackName:           aws.String(input.StackName),
+		UsePreviousTemplate: aws.Bool(true),
+		Parameters:          make([]types.Parameter, 0, len(input.ParameterKeys)),
+		Capabilities:        make([]types.Capability, 0, len(input.Capabilities)),
+		// The empty (non-nil) tags remove all tags of the stack. The nil tags do not change the tags.
+		Tags: make([]types.Tag, 0, len(input.Tags)),
+	}
+	for _, key := range input.ParameterKeys {
+		in.Parameters = append(in.Parameters, types.Parameter{
+			ParameterKey:     aws.String(key),
+			UsePreviousValue: aws.Bool(true),
+		})
+	}
+	for _, capability := range input.Capabilities {
+		in.Capabilities = append(in.Capabilities, types.Capability(capability))
+	}
+	for _, tag := range input.Tags {
+		in.Tags = append(in.Tags, types.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)})
+	}
+	if input.ClientRequestToken != "" {
+		in.ClientRequestToken = aws.String(input.ClientRequestToken)
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	if _, err := u.client.UpdateStack(ctx, in, opt); err != nil {
+		if isNoUpdatesError(err) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNoUpdates, input.StackName)
+		}
+		if isStackNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+		}
+		return nil, err
+	}
+	return &service.CFnStackTagsUpdaterOutput{}, nil
+}
+
+// CFnChangeSetCreator implements the CFnChangeSetCreator interface.
+type CFnChangeSetCreator struct {
+	client *cloudformation.Client
+}
+
+// CFnChangeSetCreatorSet is a set of CFnChangeSetCreator.
+//
+//nolint:gochecknoglobals
+var CFnChangeSetCreatorSet = wire.NewSet(
+	NewCFnChangeSetCreator,
+	wire.Bind(new(service.CFnChangeSetCreator), new(*CFnChangeSetCreator)),
+)
+
+var _ service.CFnChangeSetCreator = (*CFnChangeSetCreator)(nil)
+
+// NewCFnChangeSetCreator returns a new CFnChangeSetCreator.
+func NewCFnChangeSetCreator(client *cloudformation.Client) *CFnChangeSetCreator {
+	return &CFnChangeSetCreator{client: client}
+}
+
+// CreateCFnChangeSet requests the creation of a CloudFormation change set.
+func (c *CFnChangeSetCreator) CreateCFnChangeSet(ctx context.Context, input *service.CFnChangeSetCreatorInput) (*service.CFnChangeSetCreatorOutput, error) {
+	in := &cloudformation.CreateChangeSetInput{
+		StackName:     aws.String(input.StackName),
+		ChangeSetName: aws.String(input.ChangeSetName),
+		ChangeSetType: types.ChangeSetType(input.ChangeSetType),
+		TemplateBody:  aws.String(input.TemplateBody),
+		Parameters:    make([]types.Parameter, 0, len(input.Parameters)),
+		Capabilities:  make([]types.Capability, 0, len(input.Capabilities)),
+	}
+	for _, p := range input.Parameters {
+		parameter := types.Parameter{ParameterKey: aws.String(p.Key)}
+		if p.UsePreviousValue {
+			parameter.UsePreviousValue = aws.Bool(true)
+		} else {
+			parameter.ParameterValue = aws.String(p.Value)
+		}
+		in.Parameters = append(in.Parameters, parameter)
+	}
+	for _, capability := range input.Capabilities {
+		in.Capabilities = append(in.Capabilities, types.Capability(capability))
+	}
+	if input.Tags != nil {
+		in.Tags = make([]types.Tag, 0, len(input.Tags))
+		for _, tag := range input.Tags {
+			in.Tags = append(in.Tags, types.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)})
+		}
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	out, err := c.client.CreateChangeSet(ctx, in, opt)
 	if err != nil {
 		return nil, err
 	}
+	return &service.CFnChangeSetCreatorOutput{
+		ChangeSetID: aws.ToString(out.Id),
+		StackID:     aws.ToString(out.StackId),
+	}, nil
+}
 
-	events := make([]*model.StackEvent, 0, 100)
-	for _, event := range out.StackEvents {
-		events = append(events, &model.StackEvent{
-			EventID:              event.EventId,
-			StackID:              event.StackId,
-			StackName:            event.StackName,
-			Timestamp:            event.Timestamp,
-			ClientRequestToken:   event.ClientRequestToken,
-			HookFailureMode:      model.HookFailureMode(event.HookFailureMode),
-			HookInvocationPoint:  model.HookInvocationPoint(event.HookInvocationPoint),
-			HookStatus:           model.HookStatus(event.HookStatus),
-			HookStatusReason:     event.HookStatusReason,
-			HookType:             event.HookType,
-			LogicalResourceID:    event.LogicalResourceId,
-			PhysicalResourceID:   event.PhysicalResourceId,
-			ResourceProperties:   event.ResourceProperties,
-			ResourceStatus:       model.ResourceStatus(event.ResourceStatus),
-			ResourceStatusReason: event.ResourceStatusReason,
-			ResourceType:         event.ResourceType,
-		})
+// CFnChangeSetDescriber implements the CFnChangeSetDescriber interface.
+type CFnChangeSetDescriber struct {
+	client *cloudformation.Client
+}
+
+// CFnChangeSetDescriberSet is a set of CFnChangeSetDescriber.
+//
+//nolint:gochecknoglobals
+var CFnChangeSetDescriberSet = wire.NewSet(
+	NewCFnChangeSetDescriber,
+	wire.Bind(new(service.CFnChangeSetDescriber), new(*CFnChangeSetDescriber)),
+)
+
+var _ service.CFnChangeSetDescriber = (*CFnChangeSetDescriber)(nil)
+
+// NewCFnChangeSetDescriber returns a new CFnChangeSetDescriber.
+func NewCFnChangeSetDescriber(client *cloudformation.Client) *CFnChangeSetDescriber {
+	return &CFnChangeSetDescriber{client: client}
+}
+
+// DescribeCFnChangeSet returns the status and the resource changes of the CloudFormation change set.
+func (d *CFnChangeSetDescriber) DescribeCFnChangeSet(ctx context.Context, input *service.CFnChangeSetDescriberInput) (*service.CFnChangeSetDescriberOutput, error) {
+	in := &cloudformation.DescribeChangeSetInput{
+		ChangeSetName: aws.String(input.ChangeSetName),
 	}
-	return &service.CFnStackEventsDescriberOutput{
-		Events: events,
+	if input.StackName != "" {
+		in.StackName = aws.String(input.StackName)
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	output := &service.CFnChangeSetDescriberOutput{
+		Changes: make([]*model.CFnResourceChange, 0),
+	}
+	for {
+		out, err := d.client.DescribeChangeSet(ctx, in, opt)
+		if err != nil {
+			return nil, err
+		}
+		output.Status = model.CFnChangeSetStatus(out.Status)
+		output.StatusReason = aws.ToString(out.StatusReason)
+		for _, change := range out.Changes {
+			if change.ResourceChange == nil {
+				continue
+			}
+			output.Changes = append(output.Changes, &model.CFnResourceChange{
+				Action:             model.CFnChangeAction(change.ResourceChange.Action),
+				LogicalResourceID:  aws.ToString(change.ResourceChange.LogicalResourceId),
+				PhysicalResourceID: aws.ToString(change.ResourceChange.PhysicalResourceId),
+				ResourceType:       aws.ToString(change.ResourceChange.ResourceType),
+				Replacement:        model.CFnReplacement(change.ResourceChange.Replacement),
+			})
+		}
+		if out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+	return output, nil
+}
+
+// CFnChangeSetExecutor implements the CFnChangeSetExecutor interface.
+type CFnChangeSetExecutor struct {
+	client *cloudformation.Client
+}
+
+// CFnChangeSetExecutorSet is a set of CFnChangeSetExecutor.
+//
+//nolint:gochecknoglobals
+var CFnChangeSetExecutorSet = wire.NewSet(
+	NewCFnChangeSetExecutor,
+	wire.Bind(new(service.CFnChangeSetExecutor), new(*CFnChangeSetExecutor)),
+)
+
+var _ service.CFnChangeSetExecutor = (*CFnChangeSetExecutor)(nil)
+
+// NewCFnChangeSetExecutor returns a new CFnChangeSetExecutor.
+func NewCFnChangeSetExecutor(client *cloudformation.Client) *CFnChangeSetExecutor {
+	return &CFnChangeSetExecutor{client: client}
+}
+
+// ExecuteCFnChangeSet requests the execution of the CloudFormation change set.
+func (e *CFnChangeSetExecutor) ExecuteCFnChangeSet(ctx context.Context, input *service.CFnChangeSetExecutorInput) (*service.CFnChangeSetExecutorOutput, error) {
+	in := &cloudformation.ExecuteChangeSetInput{
+		ChangeSetName: aws.String(input.ChangeSetName),
+	}
+	if input.StackName != "" {
+		in.StackName = aws.String(input.StackName)
+	}
+	if input.ClientRequestToken != "" {
+		in.ClientRequestToken = aws.String(input.ClientRequestToken)
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	if _, err := e.client.ExecuteChangeSet(ctx, in, opt); err != nil {
+		return nil, err
+	}
+	return &service.CFnChangeSetExecutorOutput{}, nil
+}
+
+// CFnChangeSetDeleter implements the CFnChangeSetDeleter interface.
+type CFnChangeSetDeleter struct {
+	client *cloudformation.Client
+}
+
+// CFnChangeSetDeleterSet is a set of CFnChangeSetDeleter.
+//
+//nolint:gochecknoglobals
+var CFnChangeSetDeleterSet = wire.NewSet(
+	NewCFnChangeSetDeleter,
+	wire.Bind(new(service.CFnChangeSetDeleter), new(*CFnChangeSetDeleter)),
+)
+
+var _ service.CFnChangeSetDeleter = (*CFnChangeSetDeleter)(nil)
+
+// NewCFnChangeSetDeleter returns a new CFnChangeSetDeleter.
+func NewCFnChangeSetDeleter(client *cloudformation.Client) *CFnChangeSetDeleter {
+	return &CFnChangeSetDeleter{client: client}
+}
+
+// DeleteCFnChangeSet deletes the CloudFormation change set.
+func (d *CFnChangeSetDeleter) DeleteCFnChangeSet(ctx context.Context, input *service.CFnChangeSetDeleterInput) (*service.CFnChangeSetDeleterOutput, error) {
+	in := &cloudformation.DeleteChangeSetInput{
+		ChangeSetName: aws.String(input.ChangeSetName),
+	}
+	if input.StackName != "" {
+		in.StackName = aws.String(input.StackName)
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	if _, err := d.client.DeleteChangeSet(ctx, in, opt); err != nil {
+		return nil, err
+	}
+	return &service.CFnChangeSetDeleterOutput{}, nil
+}
+
+// CFnStackDriftDetector implements the CFnStackDriftDetector interface.
+type CFnStackDriftDetector struct {
+	client *cloudformation.Client
+}
+
+// CFnStackDriftDetectorSet is a set of CFnStackDriftDetector.
+//
+//nolint:gochecknoglobals
+var CFnStackDriftDetectorSet = wire.NewSet(
+	NewCFnStackDriftDetector,
+	wire.Bind(new(service.CFnStackDriftDetector), new(*CFnStackDriftDetector)),
+)
+
+var _ service.CFnStackDriftDetector = (*CFnStackDriftDetector)(nil)
+
+// NewCFnStackDriftDetector returns a new CFnStackDriftDetector.
+func NewCFnStackDriftDetector(client *cloudformation.Client) *CFnStackDriftDetector {
+	return &CFnStackDriftDetector{client: client}
+}
+
+// DetectCFnStackDrift starts the drift detection of the CloudFormation stack.
+func (d *CFnStackDriftDetector) DetectCFnStackDrift(ctx context.Context, input *service.CFnStackDriftDetectorInput) (*service.CFnStackDriftDetectorOutput, error) {
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+	out, err := d.client.DetectStackDrift(ctx, &cloudformation.DetectStackDriftInput{
+		StackName: aws.String(input.StackName),
+	}, opt)
+	if err != nil {
+		if isStackNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+		}
+		return nil, err
+	}
+	return &service.CFnStackDriftDetectorOutput{
+		DetectionID: aws.ToString(out.StackDriftDetectionId),
+	}, nil
+}
+
+// CFnStackDriftDetectionStatusDescriber implements the CFnStackDriftDetectionStatusDescriber interface.
+type CFnStackDriftDetectionStatusDescriber struct {
+	client *cloudformation.Client
+}
+
+// CFnStackDriftDetectionStatusDescriberSet is a set of CFnStackDriftDetectionStatusDescriber.
+//
+//nolint:gochecknoglobals
+var CFnStackDriftDetectionStatusDescriberSet = wire.NewSet(
+	NewCFnStackDriftDetectionStatusDescriber,
+	wire.Bind(new(service.CFnStackDriftDetectionStatusDescriber), new(*CFnStackDriftDetectionStatusDescriber)),
+)
+
+var _ service.CFnStackDriftDetectionStatusDescriber = (*CFnStackDriftDetectionStatusDescriber)(nil)
+
+// NewCFnStackDriftDetectionStatusDescriber returns a new CFnStackDriftDetectionStatusDescriber.
+func NewCFnStackDriftDetectionStatusDescriber(client *cloudformation.Client) *CFnStackDriftDetectionStatusDescriber {
+	return &CFnStackDriftDetectionStatusDescriber{client: client}
+}
+
+// DescribeCFnStackDriftDetectionStatus returns the status of the drift detection.
+func (d *CFnStackDriftDetectionStatusDescriber) DescribeCFnStackDriftDetectionStatus(
+	ctx context.Context,
+	input *service.CFnStackDriftDetectionStatusDescriberInput,
+) (*service.CFnStackDriftDetectionStatusDescriberOutput, error) {
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+	out, err := d.client.DescribeStackDriftDetectionStatus(ctx, &cloudformation.DescribeStackDriftDetectionStatusInput{
+		StackDriftDetectionId: aws.String(input.DetectionID),
+	}, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &service.CFnStackDriftDetectionStatusDescriberOutput{
+		DetectionStatus:       model.CFnDriftDetectionStatus(out.DetectionStatus),
+		DetectionStatusReason: aws.ToString(out.DetectionStatusReason),
+		DriftStatus:           model.StackDriftStatus(out.StackDriftStatus),
+		DriftedResourceCount:  int(aws.ToInt32(out.DriftedStackResourceCount)),
+	}, nil
+}
+
+// CFnStackResourceDriftsDescriber implements the CFnStackResourceDriftsDescriber interface.
+type CFnStackResourceDriftsDescriber struct {
+	client *cloudformation.Client
+}
+
+// CFnStackResourceDriftsDescriberSet is a set of CFnStackResourceDriftsDescriber.
+//
+//nolint:gochecknoglobals
+var CFnStackResourceDriftsDescriberSet = wire.NewSet(
+	NewCFnStackResourceDriftsDescriber,
+	wire.Bind(new(service.CFnStackResourceDriftsDescriber), new(*CFnStackResourceDriftsDescriber)),
+)
+
+var _ service.CFnStackResourceDriftsDescriber = (*CFnStackResourceDriftsDescriber)(nil)
+
+// NewCFnStackResourceDriftsDescriber returns a new CFnStackResourceDriftsDescriber.
+func NewCFnStackResourceDriftsDescriber(client *cloudformation.Client) *CFnStackResourceDriftsDescriber {
+	return &CFnStackResourceDriftsDescriber{client: client}
+}
+
+// DescribeCFnStackResourceDrifts returns the drifts of the resources that are MODIFIED or DELETED.
+func (d *CFnStackResourceDriftsDescriber) DescribeCFnStackResourceDrifts(
+	ctx context.Context,
+	input *service.CFnStackResourceDriftsDescriberInput,
+) (*service.CFnStackResourceDriftsDescriberOutput, error) {
+	in := &cloudformation.DescribeStackResourceDriftsInput{
+		StackName: aws.String(input.StackName),
+		StackResourceDriftStatusFilters: []types.StackResourceDriftStatus{
+			types.StackResourceDriftStatusModified,
+			types.StackResourceDriftStatusDeleted,
+		},
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	drifts := make([]*model.CFnStackResourceDrift, 0)
+	for {
+		out, err := d.client.DescribeStackResourceDrifts(ctx, in, opt)
+		if err != nil {
+			if isStackNotFoundError(err) {
+				return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+			}
+			return nil, err
+		}
+
+		for _, drift := range out.StackResourceDrifts {
+			resource := &model.CFnStackResourceDrift{
+				LogicalResourceID:   aws.ToString(drift.LogicalResourceId),
+				PhysicalResourceID:  aws.ToString(drift.PhysicalResourceId),
+				ResourceType:        aws.ToString(drift.ResourceType),
+				DriftStatus:         model.StackResourceDriftStatus(drift.StackResourceDriftStatus),
+				PropertyDifferences: make([]model.CFnPropertyDifference, 0, len(drift.PropertyDifferences)),
+			}
+			for _, diff := range drift.PropertyDifferences {
+				resource.PropertyDifferences = append(resource.PropertyDifferences, model.CFnPropertyDifference{
+					PropertyPath:   aws.ToString(diff.PropertyPath),
+					ExpectedValue:  aws.ToString(diff.ExpectedValue),
+					ActualValue:    aws.ToString(diff.ActualValue),
+					DifferenceType: model.CFnPropertyDifferenceType(diff.DifferenceType),
+				})
+			}
+			drifts = append(drifts, resource)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+	return &service.CFnStackResourceDriftsDescriberOutput{
+		Drifts: drifts,
+	}, nil
+}
+
+// CFnExportsLister implements the CFnExportsLister interface.
+type CFnExportsLister struct {
+	client *cloudformation.Client
+}
+
+// CFnExportsListerSet is a set of CFnExportsLister.
+//
+//nolint:gochecknoglobals
+var CFnExportsListerSet = wire.NewSet(
+	NewCFnExportsLister,
+	wire.Bind(new(service.CFnExportsLister), new(*CFnExportsLister)),
+)
+
+var _ service.CFnExportsLister = (*CFnExportsLister)(nil)
+
+// NewCFnExportsLister returns a new CFnExportsLister.
+func NewCFnExportsLister(client *cloudformation.Client) *CFnExportsLister {
+	return &CFnExportsLister{client: client}
+}
+
+// ListCFnExports returns the exports in the region.
+func (l *CFnExportsLister) ListCFnExports(ctx context.Context, input *service.CFnExportsListerInput) (*service.CFnExportsListerOutput, error) {
+	in := &cloudformation.ListExportsInput{}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	exports := make([]*model.CFnExport, 0)
+	for {
+		out, err := l.client.ListExports(ctx, in, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, export := range out.Exports {
+			exports = append(exports, &model.CFnExport{
+				Name:             aws.ToString(export.Name),
+				Value:            aws.ToString(export.Value),
+				ExportingStackID: aws.ToString(export.ExportingStackId),
+			})
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+	return &service.CFnExportsListerOutput{
+		Exports: exports,
+	}, nil
+}
+
+// CFnImportsLister implements the CFnImportsLister interface.
+type CFnImportsLister struct {
+	client *cloudformation.Client
+}
+
+// CFnImportsListerSet is a set of CFnImportsLister.
+//
+//nolint:gochecknoglobals
+var CFnImportsListerSet = wire.NewSet(
+	NewCFnImportsLister,
+	wire.Bind(new(service.CFnImportsLister), new(*CFnImportsLister)),
+)
+
+var _ service.CFnImportsLister = (*CFnImportsLister)(nil)
+
+// NewCFnImportsLister returns a new CFnImportsLister.
+func NewCFnImportsLister(client *cloudformation.Client) *CFnImportsLister {
+	return &CFnImportsLister{client: client}
+}
+
+// ListCFnImports returns the names of the stacks that import the export.
+func (l *CFnImportsLister) ListCFnImports(ctx context.Context, input *service.CFnImportsListerInput) (*service.CFnImportsListerOutput, error) {
+	in := &cloudformation.ListImportsInput{
+		ExportName: aws.String(input.ExportName),
+	}
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+
+	stackNames := make([]string, 0)
+	for {
+		out, err := l.client.ListImports(ctx, in, opt)
+		if err != nil {
+			if isNotImportedError(err) {
+				return &service.CFnImportsListerOutput{StackNames: stackNames}, nil
+			}
+			return nil, err
+		}
+		stackNames = append(stackNames, out.Imports...)
+
+		if out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+	return &service.CFnImportsListerOutput{
+		StackNames: stackNames,
+	}, nil
+}
+
+// CFnTemplateGetter implements the CFnTemplateGetter interface.
+type CFnTemplateGetter struct {
+	client *cloudformation.Client
+}
+
+// CFnTemplateGetterSet is a set of CFnTemplateGetter.
+//
+//nolint:gochecknoglobals
+var CFnTemplateGetterSet = wire.NewSet(
+	NewCFnTemplateGetter,
+	wire.Bind(new(service.CFnTemplateGetter), new(*CFnTemplateGetter)),
+)
+
+var _ service.CFnTemplateGetter = (*CFnTemplateGetter)(nil)
+
+// NewCFnTemplateGetter returns a new CFnTemplateGetter.
+func NewCFnTemplateGetter(client *cloudformation.Client) *CFnTemplateGetter {
+	return &CFnTemplateGetter{client: client}
+}
+
+// GetCFnTemplate returns the template of the CloudFormation stack.
+func (g *CFnTemplateGetter) GetCFnTemplate(ctx context.Context, input *service.CFnTemplateGetterInput) (*service.CFnTemplateGetterOutput, error) {
+	opt := func(o *cloudformation.Options) {
+		o.Region = input.Region.String()
+	}
+	out, err := g.client.GetTemplate(ctx, &cloudformation.GetTemplateInput{
+		StackName:     aws.String(input.StackName),
+		TemplateStage: types.TemplateStage(input.Stage),
+	}, opt)
+	if err != nil {
+		if isStackNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+		}
+		return nil, err
+	}
+	return &service.CFnTemplateGetterOutput{
+		TemplateBody: aws.ToString(out.TemplateBody),
 	}, nil
 }
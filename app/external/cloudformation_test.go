@@ -0,0 +1,67 @@
+package external
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_isRegionNotEnabledError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		err    error
+		region model.Region
+		want   bool
+	}{
+		{
+			name:   "opt-in required in any region",
+			err:    &smithy.GenericAPIError{Code: "OptInRequired"},
+			region: model.RegionUSEast1,
+			want:   true,
+		},
+		{
+			name:   "invalid token in the opt-in region",
+			err:    fmt.Errorf("list stacks: %w", &smithy.GenericAPIError{Code: "InvalidClientTokenId"}),
+			region: model.RegionAFSouth1,
+			want:   true,
+		},
+		{
+			name:   "unrecognized client in the opt-in region",
+			err:    &smithy.GenericAPIError{Code: "UnrecognizedClientException"},
+			region: model.RegionMESouth1,
+			want:   true,
+		},
+		{
+			name:   "invalid token in the default region is the invalid credentials",
+			err:    &smithy.GenericAPIError{Code: "InvalidClientTokenId"},
+			region: model.RegionUSEast1,
+			want:   false,
+		},
+		{
+			name:   "expired token in the opt-in region",
+			err:    &smithy.GenericAPIError{Code: "ExpiredToken"},
+			region: model.RegionAFSouth1,
+			want:   false,
+		},
+		{
+			name:   "error code only in the message",
+			err:    errors.New("OptInRequired"),
+			region: model.RegionAFSouth1,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isRegionNotEnabledError(tt.err, tt.region); got != tt.want {
+				t.Errorf("isRegionNotEnabledError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
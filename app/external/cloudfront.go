@@ -16,7 +16,7 @@ import (
 
 // NewCloudFrontClient returns a new CloudFront client.
 func NewCloudFrontClient(cfg *model.AWSConfig) *cloudfront.Client {
-	return cloudfront.NewFromConfig(*cfg.Config)
+	return cloudfront.NewFromConfig(awsConfigWithRetryPolicy(cfg))
 }
 
 // CloudFrontCreatorSet is a provider set for CloudFrontCreator.
@@ -81,7 +81,7 @@ func (c *CloudFrontCreator) CreateCloudFront(ctx context.Context, input *service
 				Items: []types.Origin{
 					{
 						Id:         aws.String("S3 Origin ID Generated by Spare"),
-						DomainName: aws.String(input.Bucket.Domain()),
+						DomainName: aws.String(input.Bucket.Domain(model.Region(c.Options().Region))),
 						S3OriginConfig: &types.S3OriginConfig{
 							OriginAccessIdentity: aws.String(
 								fmt.Sprintf("origin-access-identity/cloudfront/%s", *input.OAIID),
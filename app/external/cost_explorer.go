@@ -14,7 +14,7 @@ import (
 
 // NewCostExplorerClient returns a new CostExplorer client.
 func NewCostExplorerClient(cfg *model.AWSConfig) *costexplorer.Client {
-	return costexplorer.NewFromConfig(*cfg.Config)
+	return costexplorer.NewFromConfig(awsConfigWithRetryPolicy(cfg))
 }
 
 // CostGetter is an interface for getting cost.
@@ -13,3 +13,123 @@ type CFnStackLister func(ctx context.Context, input *service.CFnStackListerInput
 func (m CFnStackLister) ListCFnStack(ctx context.Context, input *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
 	return m(ctx, input)
 }
+
+// CFnStackResourceLister is a mock of the CFnStackResourceLister interface.
+type CFnStackResourceLister func(ctx context.Context, input *service.CFnStackResourceListerInput) (*service.CFnStackResourceListerOutput, error)
+
+// ListCFnStackResource calls the CFnStackResourceListerFunc.
+func (m CFnStackResourceLister) ListCFnStackResource(ctx context.Context, input *service.CFnStackResourceListerInput) (*service.CFnStackResourceListerOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnStackDescriber is a mock of the CFnStackDescriber interface.
+type CFnStackDescriber func(ctx context.Context, input *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error)
+
+// DescribeCFnStack calls the CFnStackDescriberFunc.
+func (m CFnStackDescriber) DescribeCFnStack(ctx context.Context, input *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnStackDeleter is a mock of the CFnStackDeleter interface.
+type CFnStackDeleter func(ctx context.Context, input *service.CFnStackDeleterInput) (*service.CFnStackDeleterOutput, error)
+
+// DeleteCFnStack calls the CFnStackDeleterFunc.
+func (m CFnStackDeleter) DeleteCFnStack(ctx context.Context, input *service.CFnStackDeleterInput) (*service.CFnStackDeleterOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnStackEventsDescriber is a mock of the CFnStackEventsDescriber interface.
+type CFnStackEventsDescriber func(ctx context.Context, input *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error)
+
+// DescribeCFnStackEvents calls the CFnStackEventsDescriberFunc.
+func (m CFnStackEventsDescriber) DescribeCFnStackEvents(ctx context.Context, input *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnStackTagsUpdater is a mock of the CFnStackTagsUpdater interface.
+type CFnStackTagsUpdater func(ctx context.Context, input *service.CFnStackTagsUpdaterInput) (*service.CFnStackTagsUpdaterOutput, error)
+
+// UpdateCFnStackTags calls the CFnStackTagsUpdaterFunc.
+func (m CFnStackTagsUpdater) UpdateCFnStackTags(ctx context.Context, input *service.CFnStackTagsUpdaterInput) (*service.CFnStackTagsUpdaterOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnChangeSetCreator is a mock of the CFnChangeSetCreator interface.
+type CFnChangeSetCreator func(ctx context.Context, input *service.CFnChangeSetCreatorInput) (*service.CFnChangeSetCreatorOutput, error)
+
+// CreateCFnChangeSet calls the CFnChangeSetCreatorFunc.
+func (m CFnChangeSetCreator) CreateCFnChangeSet(ctx context.Context, input *service.CFnChangeSetCreatorInput) (*service.CFnChangeSetCreatorOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnChangeSetDescriber is a mock of the CFnChangeSetDescriber interface.
+type CFnChangeSetDescriber func(ctx context.Context, input *service.CFnChangeSetDescriberInput) (*service.CFnChangeSetDescriberOutput, error)
+
+// DescribeCFnChangeSet calls the CFnChangeSetDescriberFunc.
+func (m CFnChangeSetDescriber) DescribeCFnChangeSet(ctx context.Context, input *service.CFnChangeSetDescriberInput) (*service.CFnChangeSetDescriberOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnChangeSetExecutor is a mock of the CFnChangeSetExecutor interface.
+type CFnChangeSetExecutor func(ctx context.Context, input *service.CFnChangeSetExecutorInput) (*service.CFnChangeSetExecutorOutput, error)
+
+// ExecuteCFnChangeSet calls the CFnChangeSetExecutorFunc.
+func (m CFnChangeSetExecutor) ExecuteCFnChangeSet(ctx context.Context, input *service.CFnChangeSetExecutorInput) (*service.CFnChangeSetExecutorOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnChangeSetDeleter is a mock of the CFnChangeSetDeleter interface.
+type CFnChangeSetDeleter func(ctx context.Context, input *service.CFnChangeSetDeleterInput) (*service.CFnChangeSetDeleterOutput, error)
+
+// DeleteCFnChangeSet calls the CFnChangeSetDeleterFunc.
+func (m CFnChangeSetDeleter) DeleteCFnChangeSet(ctx context.Context, input *service.CFnChangeSetDeleterInput) (*service.CFnChangeSetDeleterOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnStackDriftDetector is a mock of the CFnStackDriftDetector interface.
+type CFnStackDriftDetector func(ctx context.Context, input *service.CFnStackDriftDetectorInput) (*service.CFnStackDriftDetectorOutput, error)
+
+// DetectCFnStackDrift calls the CFnStackDriftDetectorFunc.
+func (m CFnStackDriftDetector) DetectCFnStackDrift(ctx context.Context, input *service.CFnStackDriftDetectorInput) (*service.CFnStackDriftDetectorOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnStackDriftDetectionStatusDescriber is a mock of the CFnStackDriftDetectionStatusDescriber interface.
+type CFnStackDriftDetectionStatusDescriber func(ctx context.Context, input *service.CFnStackDriftDetectionStatusDescriberInput) (*service.CFnStackDriftDetectionStatusDescriberOutput, error)
+
+// DescribeCFnStackDriftDetectionStatus calls the CFnStackDriftDetectionStatusDescriberFunc.
+func (m CFnStackDriftDetectionStatusDescriber) DescribeCFnStackDriftDetectionStatus(ctx context.Context, input *service.CFnStackDriftDetectionStatusDescriberInput) (*service.CFnStackDriftDetectionStatusDescriberOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnStackResourceDriftsDescriber is a mock of the CFnStackResourceDriftsDescriber interface.
+type CFnStackResourceDriftsDescriber func(ctx context.Context, input *service.CFnStackResourceDriftsDescriberInput) (*service.CFnStackResourceDriftsDescriberOutput, error)
+
+// DescribeCFnStackResourceDrifts calls the CFnStackResourceDriftsDescriberFunc.
+func (m CFnStackResourceDriftsDescriber) DescribeCFnStackResourceDrifts(ctx context.Context, input *service.CFnStackResourceDriftsDescriberInput) (*service.CFnStackResourceDriftsDescriberOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnExportsLister is a mock of the CFnExportsLister interface.
+type CFnExportsLister func(ctx context.Context, input *service.CFnExportsListerInput) (*service.CFnExportsListerOutput, error)
+
+// ListCFnExports calls the CFnExportsListerFunc.
+func (m CFnExportsLister) ListCFnExports(ctx context.Context, input *service.CFnExportsListerInput) (*service.CFnExportsListerOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnImportsLister is a mock of the CFnImportsLister interface.
+type CFnImportsLister func(ctx context.Context, input *service.CFnImportsListerInput) (*service.CFnImportsListerOutput, error)
+
+// ListCFnImports calls the CFnImportsListerFunc.
+func (m CFnImportsLister) ListCFnImports(ctx context.Context, input *service.CFnImportsListerInput) (*service.CFnImportsListerOutput, error) {
+	return m(ctx, input)
+}
+
+// CFnTemplateGetter is a mock of the CFnTemplateGetter interface.
+type CFnTemplateGetter func(ctx context.Context, input *service.CFnTemplateGetterInput) (*service.CFnTemplateGetterOutput, error)
+
+// GetCFnTemplate calls the CFnTemplateGetterFunc.
+func (m CFnTemplateGetter) GetCFnTemplate(ctx context.Context, input *service.CFnTemplateGetterInput) (*service.CFnTemplateGetterOutput, error) {
+	return m(ctx, input)
+}
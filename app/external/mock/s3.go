@@ -101,3 +101,67 @@ type S3ObjectVersionsLister func(ctx context.Context, input *service.S3ObjectVer
 func (m S3ObjectVersionsLister) ListS3ObjectVersions(ctx context.Context, input *service.S3ObjectVersionsListerInput) (*service.S3ObjectVersionsListerOutput, error) {
 	return m(ctx, input)
 }
+
+// S3ObjectDetailsLister is a mock of the S3ObjectDetailsLister interface.
+type S3ObjectDetailsLister func(ctx context.Context, input *service.S3ObjectDetailsListerInput) (*service.S3ObjectDetailsListerOutput, error)
+
+// ListS3ObjectDetails calls the ListS3ObjectDetailsFunc.
+func (m S3ObjectDetailsLister) ListS3ObjectDetails(ctx context.Context, input *service.S3ObjectDetailsListerInput) (*service.S3ObjectDetailsListerOutput, error) {
+	return m(ctx, input)
+}
+
+// S3MultipartUploadsLister is a mock of the S3MultipartUploadsLister interface.
+type S3MultipartUploadsLister func(ctx context.Context, input *service.S3MultipartUploadsListerInput) (*service.S3MultipartUploadsListerOutput, error)
+
+// ListS3MultipartUploads calls the ListS3MultipartUploadsFunc.
+func (m S3MultipartUploadsLister) ListS3MultipartUploads(ctx context.Context, input *service.S3MultipartUploadsListerInput) (*service.S3MultipartUploadsListerOutput, error) {
+	return m(ctx, input)
+}
+
+// S3ObjectTagsGetter is a mock of the S3ObjectTagsGetter interface.
+type S3ObjectTagsGetter func(ctx context.Context, input *service.S3ObjectTagsGetterInput) (*service.S3ObjectTagsGetterOutput, error)
+
+// GetS3ObjectTags calls the GetS3ObjectTagsFunc.
+func (m S3ObjectTagsGetter) GetS3ObjectTags(ctx context.Context, input *service.S3ObjectTagsGetterInput) (*service.S3ObjectTagsGetterOutput, error) {
+	return m(ctx, input)
+}
+
+// S3ObjectMetadataGetter is a mock of the S3ObjectMetadataGetter interface.
+type S3ObjectMetadataGetter func(ctx context.Context, input *service.S3ObjectMetadataGetterInput) (*service.S3ObjectMetadataGetterOutput, error)
+
+// GetS3ObjectMetadata calls the GetS3ObjectMetadataFunc.
+func (m S3ObjectMetadataGetter) GetS3ObjectMetadata(ctx context.Context, input *service.S3ObjectMetadataGetterInput) (*service.S3ObjectMetadataGetterOutput, error) {
+	return m(ctx, input)
+}
+
+// S3BucketTagsGetter is a mock of the S3BucketTagsGetter interface.
+type S3BucketTagsGetter func(ctx context.Context, input *service.S3BucketTagsGetterInput) (*service.S3BucketTagsGetterOutput, error)
+
+// GetS3BucketTags calls the GetS3BucketTagsFunc.
+func (m S3BucketTagsGetter) GetS3BucketTags(ctx context.Context, input *service.S3BucketTagsGetterInput) (*service.S3BucketTagsGetterOutput, error) {
+	return m(ctx, input)
+}
+
+// S3MultipartUploadAborter is a mock of the S3MultipartUploadAborter interface.
+type S3MultipartUploadAborter func(ctx context.Context, input *service.S3MultipartUploadAborterInput) (*service.S3MultipartUploadAborterOutput, error)
+
+// AbortS3MultipartUpload calls the AbortS3MultipartUploadFunc.
+func (m S3MultipartUploadAborter) AbortS3MultipartUpload(ctx context.Context, input *service.S3MultipartUploadAborterInput) (*service.S3MultipartUploadAborterOutput, error) {
+	return m(ctx, input)
+}
+
+// S3ObjectLockGetter is a mock of the S3ObjectLockGetter interface.
+type S3ObjectLockGetter func(ctx context.Context, input *service.S3ObjectLockGetterInput) (*service.S3ObjectLockGetterOutput, error)
+
+// GetS3ObjectLock calls the GetS3ObjectLockFunc.
+func (m S3ObjectLockGetter) GetS3ObjectLock(ctx context.Context, input *service.S3ObjectLockGetterInput) (*service.S3ObjectLockGetterOutput, error) {
+	return m(ctx, input)
+}
+
+// S3ObjectLegalHoldReleaser is a mock of the S3ObjectLegalHoldReleaser interface.
+type S3ObjectLegalHoldReleaser func(ctx context.Context, input *service.S3ObjectLegalHoldReleaserInput) (*service.S3ObjectLegalHoldReleaserOutput, error)
+
+// ReleaseS3ObjectLegalHold calls the ReleaseS3ObjectLegalHoldFunc.
+func (m S3ObjectLegalHoldReleaser) ReleaseS3ObjectLegalHold(ctx context.Context, input *service.S3ObjectLegalHoldReleaserInput) (*service.S3ObjectLegalHoldReleaserOutput, error) {
+	return m(ctx, input)
+}
@@ -0,0 +1,190 @@
+package external
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/utils/aimd"
+)
+
+var _ aws.RetryerV2 = (*Retryer)(nil)
+
+// errRetryBudgetExhausted is the error when the retry budget has no tokens.
+var errRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// Retryer implements the aws.RetryerV2 interface with model.RetryPolicy.
+// It retries the errors that the AWS SDK regards as retryable (throttling, 5xx, connection errors, etc.)
+// with the exponential backoff and full jitter. The throttling errors (e.g. SlowDown) wait longer.
+type Retryer struct {
+	// policy is the retry policy.
+	policy model.RetryPolicy
+	// budget is the retry budget shared by the retryers of the client.
+	budget *RetryBudget
+	// retryables determines whether the error is retryable.
+	retryables retry.IsErrorRetryables
+	// throttles determines whether the error is a throttling error.
+	throttles retry.IsErrorThrottles
+}
+
+// NewRetryer creates a new Retryer. If budget is nil, the number of retries is not limited by the budget.
+func NewRetryer(policy model.RetryPolicy, budget *RetryBudget) *Retryer {
+	if budget == nil {
+		budget = NewRetryBudget(0)
+	}
+	return &Retryer{
+		policy:     policy,
+		budget:     budget,
+		retryables: retry.IsErrorRetryables(retry.DefaultRetryables),
+		throttles:  retry.IsErrorThrottles(retry.DefaultThrottles),
+	}
+}
+
+// IsErrorRetryable returns true if the error is retryable.
+func (r *Retryer) IsErrorRetryable(err error) bool {
+	return r.retryables.IsErrorRetryable(err).Bool()
+}
+
+// MaxAttempts returns the maximum number of attempts.
+func (r *Retryer) MaxAttempts() int {
+	if r.policy.MaxAttempts <= 0 {
+		return model.DefaultRetryMaxAttempts
+	}
+	return r.policy.MaxAttempts
+}
+
+// RetryDelay returns the random delay in [0, backoff) where backoff grows exponentially with the attempt.
+func (r *Retryer) RetryDelay(attempt int, err error) (time.Duration, error) {
+	backoff := r.policy.Backoff(attempt, r.throttles.IsErrorThrottle(err).Bool())
+	if backoff <= 0 {
+		return 0, nil
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(backoff)))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n.Int64()), nil
+}
+
+// GetRetryToken consumes the tokens of the retry budget. If the budget is exhausted, the request is not retried.
+// The tokens are returned when the retried request succeeds.
+// The throttling error is reported to the adaptive concurrency limiter of the call, if any.
+func (r *Retryer) GetRetryToken(ctx context.Context, err error) (func(error) error, error) {
+	if r.throttles.IsErrorThrottle(err).Bool() {
+		aimd.NotifyThrottle(ctx)
+	}
+	if !r.budget.acquire(model.RetryCost) {
+		return nil, errRetryBudgetExhausted
+	}
+	return func(err error) error {
+		if err == nil {
+			r.budget.release(model.RetryCost)
+		}
+		return nil
+	}, nil
+}
+
+// GetInitialToken returns the initial token. This is not used.
+func (r *Retryer) GetInitialToken() func(error) error {
+	return func(error) error { return nil }
+}
+
+// GetAttemptToken returns the attempt token. The successful attempt refills a token of the retry budget.
+func (r *Retryer) GetAttemptToken(context.Context) (func(error) error, error) {
+	return func(err error) error {
+		if err == nil {
+			r.budget.release(1)
+		}
+		return nil
+	}, nil
+}
+
+// RetryBudget is the token bucket that limits the number of retries.
+// It prevents the retry storm when the service keeps failing.
+type RetryBudget struct {
+	// mu protects remaining.
+	mu sync.Mutex
+	// capacity is the maximum number of tokens. 0 means unlimited.
+	capacity int
+	// remaining is the number of tokens left.
+	remaining int
+}
+
+// NewRetryBudget creates a new RetryBudget. If capacity is 0, the budget is unlimited.
+func NewRetryBudget(capacity int) *RetryBudget {
+	return &RetryBudget{
+		capacity:  capacity,
+		remaining: capacity,
+	}
+}
+
+// acquire consumes n tokens. It returns false if there are not enough tokens.
+func (b *RetryBudget) acquire(n int) bool {
+	if b.capacity <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.remaining < n {
+		return false
+	}
+	b.remaining -= n
+	return true
+}
+
+// release returns n tokens up to the capacity.
+func (b *RetryBudget) release(n int) {
+	if b.capacity <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remaining = min(b.remaining+n, b.capacity)
+}
+
+// awsConfigWithRetryPolicy returns the copy of the AWS config whose clients retry with the retry policy.
+// The operations of the client share a retry budget.
+// The per-operation overrides are applied by replacing the retry middleware of the operation.
+func awsConfigWithRetryPolicy(cfg *model.AWSConfig) aws.Config {
+	policy := cfg.RetryPolicy
+	if policy == nil {
+		policy = model.DefaultRetryPolicy()
+	}
+	budget := NewRetryBudget(policy.Budget)
+	base := *policy
+	base.Operations = nil
+
+	c := cfg.Config.Copy()
+	c.Retryer = func() aws.Retryer {
+		return NewRetryer(base, budget)
+	}
+	if len(policy.Operations) == 0 {
+		return c
+	}
+
+	c.APIOptions = append(c.APIOptions, func(stack *middleware.Stack) error {
+		if _, ok := policy.Operations[stack.ID()]; !ok {
+			return nil
+		}
+		current, ok := stack.Finalize.Get("Retry")
+		if !ok {
+			return nil
+		}
+		attempt := retry.NewAttemptMiddleware(NewRetryer(policy.ForOperation(stack.ID()), budget), smithyhttp.RequestCloner)
+		if a, ok := current.(*retry.Attempt); ok {
+			attempt.LogAttempts = a.LogAttempts
+			attempt.OperationMeter = a.OperationMeter
+		}
+		_, err := stack.Finalize.Swap("Retry", attempt)
+		return err
+	})
+	return c
+}
@@ -0,0 +1,96 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/utils/aimd"
+)
+
+func TestRetryer(t *testing.T) {
+	t.Parallel()
+
+	policy := model.RetryPolicy{
+		MaxAttempts:       3,
+		BaseDelay:         10 * time.Millisecond,
+		ThrottleBaseDelay: time.Second,
+		MaxBackoff:        2 * time.Second,
+	}
+	throttle := &smithy.GenericAPIError{Code: "SlowDown", Message: "Please reduce your request rate."}
+
+	t.Run("throttling error is retryable and waits longer", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRetryer(policy, nil)
+		if !r.IsErrorRetryable(throttle) {
+			t.Error("SlowDown should be retryable")
+		}
+		if r.IsErrorRetryable(&smithy.GenericAPIError{Code: "AccessDenied"}) {
+			t.Error("AccessDenied should not be retryable")
+		}
+		if got := r.MaxAttempts(); got != 3 {
+			t.Errorf("MaxAttempts() = %d, want 3", got)
+		}
+
+		for attempt := 1; attempt <= 5; attempt++ {
+			delay, err := r.RetryDelay(attempt, errors.New("connection reset"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if limit := policy.Backoff(attempt, false); delay < 0 || delay >= limit {
+				t.Errorf("RetryDelay(%d) = %v, want [0, %v)", attempt, delay, limit)
+			}
+			delay, err = r.RetryDelay(attempt, throttle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if limit := policy.Backoff(attempt, true); delay < 0 || delay >= limit {
+				t.Errorf("RetryDelay(%d) = %v, want [0, %v)", attempt, delay, limit)
+			}
+		}
+	})
+
+	t.Run("retry budget is exhausted and refilled", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRetryer(policy, NewRetryBudget(model.RetryCost*2))
+		release1, err := r.GetRetryToken(context.Background(), throttle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.GetRetryToken(context.Background(), throttle); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.GetRetryToken(context.Background(), throttle); !errors.Is(err, errRetryBudgetExhausted) {
+			t.Fatalf("GetRetryToken() error = %v, want %v", err, errRetryBudgetExhausted)
+		}
+
+		// The successful retry returns the tokens.
+		if err := release1(nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.GetRetryToken(context.Background(), throttle); err != nil {
+			t.Errorf("GetRetryToken() error = %v, want nil", err)
+		}
+	})
+	t.Run("throttling error is reported to the limiter of the call", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRetryer(policy, nil)
+		notified := 0
+		ctx := aimd.WithThrottleObserver(context.Background(), func() { notified++ })
+		if _, err := r.GetRetryToken(ctx, errors.New("connection reset")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.GetRetryToken(ctx, throttle); err != nil {
+			t.Fatal(err)
+		}
+		if notified != 1 {
+			t.Errorf("notified = %d, want 1", notified)
+		}
+	})
+}
@@ -21,7 +21,9 @@ import (
 // NewS3Client creates a new S3 service client.
 // If profile is empty, the default profile is used.
 func NewS3Client(cfg *model.AWSConfig) *s3.Client {
-	return s3.NewFromConfig(*cfg.Config)
+	return s3.NewFromConfig(awsConfigWithRetryPolicy(cfg), func(o *s3.Options) {
+		o.UsePathStyle = cfg.S3UsePathStyle
+	})
 }
 
 // S3BucketCreator implements the S3BucketCreator interface.
@@ -141,11 +143,19 @@ func (c *S3BucketLocationGetter) GetS3BucketLocation(ctx context.Context, input
 		if errors.As(err, &noSuchBucket) {
 			return nil, fmt.Errorf("%w: bucket name=%s", domain.ErrNoSuchBucket, input.Bucket.String())
 		}
+		if isNotImplementedError(err) {
+			// Some S3-compatible storages do not support GetBucketLocation. Use the region of the client.
+			return &service.S3BucketLocationGetterOutput{Region: c.clientRegion()}, nil
+		}
 		return nil, err
 	}
 
 	region := model.Region(out.LocationConstraint)
 	if region == "" {
+		if c.Options().BaseEndpoint != nil {
+			// The S3-compatible storage returns the empty location regardless of us-east-1.
+			return &service.S3BucketLocationGetterOutput{Region: c.clientRegion()}, nil
+		}
 		region = model.RegionUSEast1
 	}
 
@@ -154,6 +164,19 @@ func (c *S3BucketLocationGetter) GetS3BucketLocation(ctx context.Context, input
 	}, nil
 }
 
+// clientRegion returns the region of the client. If it is empty, it returns us-east-1.
+func (c *S3BucketLocationGetter) clientRegion() model.Region {
+	if c.Options().Region == "" {
+		return model.RegionUSEast1
+	}
+	return model.Region(c.Options().Region)
+}
+
+// isNotImplementedError returns true if the API is not supported by the S3-compatible storage.
+func isNotImplementedError(err error) bool {
+	return strings.Contains(err.Error(), "NotImplemented") || strings.Contains(err.Error(), "MethodNotAllowed")
+}
+
 // S3BucketDeleter implements the S3BucketDeleter interface.
 type S3BucketDeleter struct {
 	*s3.Client
@@ -217,13 +240,10 @@ func NewS3ObjectsDeleter(client *s3.Client) *S3ObjectsDeleter {
 // If the bucket has versioning enabled, all versions of the specified objects are deleted.
 func (c *S3ObjectsDeleter) DeleteS3Objects(ctx context.Context, input *service.S3ObjectsDeleterInput) (*service.S3ObjectsDeleterOutput, error) {
 	optFn := func(o *s3.Options) {
-		o.Retryer = NewRetryer(func(err error) bool {
-			return strings.Contains(err.Error(), "api error SlowDown")
-		}, model.S3DeleteObjectsDelayTimeSec)
 		o.Region = input.Region.String()
 	}
 
-	if _, err := c.DeleteObjects(
+	output, err := c.DeleteObjects(
 		ctx,
 		&s3.DeleteObjectsInput{
 			Bucket: aws.String(input.Bucket.String()),
@@ -231,12 +251,26 @@ func (c *S3ObjectsDeleter) DeleteS3Objects(ctx context.Context, input *service.S
 				Objects: input.S3ObjectSets.ToS3ObjectIdentifiers(),
 				Quiet:   aws.Bool(true),
 			},
+			BypassGovernanceRetention: aws.Bool(input.BypassGovernance),
 		},
 		optFn,
-	); err != nil {
+	)
+	if err != nil {
 		return nil, err
 	}
-	return &service.S3ObjectsDeleterOutput{}, nil
+
+	errs := make([]model.S3ObjectDeleteError, 0, len(output.Errors))
+	for _, e := range output.Errors {
+		errs = append(errs, model.S3ObjectDeleteError{
+			S3ObjectIdentifier: model.S3ObjectIdentifier{
+				S3Key:     model.S3Key(aws.ToString(e.Key)),
+				VersionID: model.VersionID(aws.ToString(e.VersionId)),
+			},
+			Code:    aws.ToString(e.Code),
+			Message: aws.ToString(e.Message),
+		})
+	}
+	return &service.S3ObjectsDeleterOutput{Errors: errs}, nil
 }
 
 // S3ObjectsLister implements the S3ObjectsLister interface.
@@ -437,6 +471,7 @@ func NewS3ObjectVersionsLister(client *s3.Client) *S3ObjectVersionsLister {
 // ListS3ObjectVersions lists the object versions in the bucket.
 func (c *S3ObjectVersionsLister) ListS3ObjectVersions(ctx context.Context, input *service.S3ObjectVersionsListerInput) (*service.S3ObjectVersionsListerOutput, error) {
 	var objects model.S3ObjectIdentifiers
+	var versions model.S3ObjectVersions
 	listObjectVersionsInput := &s3.ListObjectVersionsInput{
 		Bucket:  aws.String(input.Bucket.String()),
 		MaxKeys: aws.Int32(model.MaxS3Keys),
@@ -452,12 +487,26 @@ func (c *S3ObjectVersionsLister) ListS3ObjectVersions(ctx context.Context, input
 				S3Key:     model.S3Key(*version.Key),
 				VersionID: model.VersionID(*version.VersionId),
 			})
+			versions = append(versions, model.S3ObjectVersion{
+				S3Key:        model.S3Key(*version.Key),
+				VersionID:    model.VersionID(*version.VersionId),
+				Size:         aws.ToInt64(version.Size),
+				IsLatest:     aws.ToBool(version.IsLatest),
+				LastModified: aws.ToTime(version.LastModified),
+			})
 		}
 		for _, deleteMarker := range listObjectVersionsOutput.DeleteMarkers {
 			objects = append(objects, model.S3ObjectIdentifier{
 				S3Key:     model.S3Key(*deleteMarker.Key),
 				VersionID: model.VersionID(*deleteMarker.VersionId),
 			})
+			versions = append(versions, model.S3ObjectVersion{
+				S3Key:          model.S3Key(*deleteMarker.Key),
+				VersionID:      model.VersionID(*deleteMarker.VersionId),
+				IsLatest:       aws.ToBool(deleteMarker.IsLatest),
+				IsDeleteMarker: true,
+				LastModified:   aws.ToTime(deleteMarker.LastModified),
+			})
 		}
 
 		if !*listObjectVersionsOutput.IsTruncated {
@@ -467,5 +516,378 @@ func (c *S3ObjectVersionsLister) ListS3ObjectVersions(ctx context.Context, input
 		listObjectVersionsInput.KeyMarker = listObjectVersionsOutput.NextKeyMarker
 		listObjectVersionsInput.VersionIdMarker = listObjectVersionsOutput.NextVersionIdMarker
 	}
-	return &service.S3ObjectVersionsListerOutput{Objects: objects}, nil
+	return &service.S3ObjectVersionsListerOutput{Objects: objects, Versions: versions}, nil
+}
+
+// S3ObjectDetailsLister implements the S3ObjectDetailsLister interface.
+type S3ObjectDetailsLister struct {
+	*s3.Client
+}
+
+// S3ObjectDetailsListerSet is a provider set for S3ObjectDetailsLister.
+//
+//nolint:gochecknoglobals
+var S3ObjectDetailsListerSet = wire.NewSet(
+	NewS3ObjectDetailsLister,
+	wire.Bind(new(service.S3ObjectDetailsLister), new(*S3ObjectDetailsLister)),
+)
+
+var _ service.S3ObjectDetailsLister = (*S3ObjectDetailsLister)(nil)
+
+// NewS3ObjectDetailsLister creates a new S3ObjectDetailsLister.
+func NewS3ObjectDetailsLister(client *s3.Client) *S3ObjectDetailsLister {
+	return &S3ObjectDetailsLister{Client: client}
+}
+
+// ListS3ObjectDetails lists the objects in the bucket with their size, storage class, last modified time and ETag.
+func (c *S3ObjectDetailsLister) ListS3ObjectDetails(ctx context.Context, input *service.S3ObjectDetailsListerInput) (*service.S3ObjectDetailsListerOutput, error) {
+	var objects model.S3ObjectDetails
+	in := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(input.Bucket.String()),
+		MaxKeys: aws.Int32(model.MaxS3Keys),
+	}
+	if !input.Prefix.Empty() {
+		in.Prefix = aws.String(input.Prefix.String())
+	}
+
+	for {
+		output, err := c.ListObjectsV2(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range output.Contents {
+			objects = append(objects, model.S3ObjectDetail{
+				S3Key:        model.S3Key(aws.ToString(o.Key)),
+				Size:         aws.ToInt64(o.Size),
+				StorageClass: string(o.StorageClass),
+				LastModified: aws.ToTime(o.LastModified),
+				ETag:         strings.Trim(aws.ToString(o.ETag), `"`),
+			})
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			break
+		}
+		in.ContinuationToken = output.NextContinuationToken
+	}
+	return &service.S3ObjectDetailsListerOutput{Objects: objects}, nil
+}
+
+// S3MultipartUploadsLister implements the S3MultipartUploadsLister interface.
+type S3MultipartUploadsLister struct {
+	*s3.Client
+}
+
+// S3MultipartUploadsListerSet is a provider set for S3MultipartUploadsLister.
+//
+//nolint:gochecknoglobals
+var S3MultipartUploadsListerSet = wire.NewSet(
+	NewS3MultipartUploadsLister,
+	wire.Bind(new(service.S3MultipartUploadsLister), new(*S3MultipartUploadsLister)),
+)
+
+var _ service.S3MultipartUploadsLister = (*S3MultipartUploadsLister)(nil)
+
+// NewS3MultipartUploadsLister creates a new S3MultipartUploadsLister.
+func NewS3MultipartUploadsLister(client *s3.Client) *S3MultipartUploadsLister {
+	return &S3MultipartUploadsLister{Client: client}
+}
+
+// ListS3MultipartUploads lists the incomplete multipart uploads in the bucket.
+func (c *S3MultipartUploadsLister) ListS3MultipartUploads(ctx context.Context, input *service.S3MultipartUploadsListerInput) (*service.S3MultipartUploadsListerOutput, error) {
+	var uploads model.S3MultipartUploads
+	in := &s3.ListMultipartUploadsInput{
+		Bucket:     aws.String(input.Bucket.String()),
+		MaxUploads: aws.Int32(model.MaxS3Keys),
+	}
+
+	for {
+		output, err := c.ListMultipartUploads(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range output.Uploads {
+			uploads = append(uploads, model.S3MultipartUpload{
+				S3Key:     model.S3Key(aws.ToString(u.Key)),
+				UploadID:  aws.ToString(u.UploadId),
+				Initiated: aws.ToTime(u.Initiated),
+			})
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			break
+		}
+		in.KeyMarker = output.NextKeyMarker
+		in.UploadIdMarker = output.NextUploadIdMarker
+	}
+	return &service.S3MultipartUploadsListerOutput{Uploads: uploads}, nil
+}
+
+// S3ObjectTagsGetter implements the S3ObjectTagsGetter interface.
+type S3ObjectTagsGetter struct {
+	*s3.Client
+}
+
+// S3ObjectTagsGetterSet is a provider set for S3ObjectTagsGetter.
+//
+//nolint:gochecknoglobals
+var S3ObjectTagsGetterSet = wire.NewSet(
+	NewS3ObjectTagsGetter,
+	wire.Bind(new(service.S3ObjectTagsGetter), new(*S3ObjectTagsGetter)),
+)
+
+var _ service.S3ObjectTagsGetter = (*S3ObjectTagsGetter)(nil)
+
+// NewS3ObjectTagsGetter creates a new S3ObjectTagsGetter.
+func NewS3ObjectTagsGetter(client *s3.Client) *S3ObjectTagsGetter {
+	return &S3ObjectTagsGetter{Client: client}
+}
+
+// GetS3ObjectTags gets the tag set of the object.
+func (c *S3ObjectTagsGetter) GetS3ObjectTags(ctx context.Context, input *service.S3ObjectTagsGetterInput) (*service.S3ObjectTagsGetterOutput, error) {
+	output, err := c.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(input.Bucket.String()),
+		Key:    aws.String(input.S3Key.String()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(model.S3Tags, 0, len(output.TagSet))
+	for _, t := range output.TagSet {
+		tags = append(tags, model.S3Tag{
+			Key:   aws.ToString(t.Key),
+			Value: aws.ToString(t.Value),
+		})
+	}
+	return &service.S3ObjectTagsGetterOutput{Tags: tags}, nil
+}
+
+// S3ObjectMetadataGetter implements the S3ObjectMetadataGetter interface.
+type S3ObjectMetadataGetter struct {
+	*s3.Client
+}
+
+// S3ObjectMetadataGetterSet is a provider set for S3ObjectMetadataGetter.
+//
+//nolint:gochecknoglobals
+var S3ObjectMetadataGetterSet = wire.NewSet(
+	NewS3ObjectMetadataGetter,
+	wire.Bind(new(service.S3ObjectMetadataGetter), new(*S3ObjectMetadataGetter)),
+)
+
+var _ service.S3ObjectMetadataGetter = (*S3ObjectMetadataGetter)(nil)
+
+// NewS3ObjectMetadataGetter creates a new S3ObjectMetadataGetter.
+func NewS3ObjectMetadataGetter(client *s3.Client) *S3ObjectMetadataGetter {
+	return &S3ObjectMetadataGetter{Client: client}
+}
+
+// GetS3ObjectMetadata gets the metadata of the object without downloading the object body.
+func (c *S3ObjectMetadataGetter) GetS3ObjectMetadata(ctx context.Context, input *service.S3ObjectMetadataGetterInput) (*service.S3ObjectMetadataGetterOutput, error) {
+	output, err := c.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(input.Bucket.String()),
+		Key:    aws.String(input.S3Key.String()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service.S3ObjectMetadataGetterOutput{
+		ContentType:   aws.ToString(output.ContentType),
+		ContentLength: aws.ToInt64(output.ContentLength),
+	}, nil
+}
+
+// S3BucketTagsGetter implements the S3BucketTagsGetter interface.
+type S3BucketTagsGetter struct {
+	*s3.Client
+}
+
+// S3BucketTagsGetterSet is a provider set for S3BucketTagsGetter.
+//
+//nolint:gochecknoglobals
+var S3BucketTagsGetterSet = wire.NewSet(
+	NewS3BucketTagsGetter,
+	wire.Bind(new(service.S3BucketTagsGetter), new(*S3BucketTagsGetter)),
+)
+
+var _ service.S3BucketTagsGetter = (*S3BucketTagsGetter)(nil)
+
+// NewS3BucketTagsGetter creates a new S3BucketTagsGetter.
+func NewS3BucketTagsGetter(client *s3.Client) *S3BucketTagsGetter {
+	return &S3BucketTagsGetter{Client: client}
+}
+
+// GetS3BucketTags gets the tag set of the bucket. If the bucket has no tags, it returns empty tags.
+func (c *S3BucketTagsGetter) GetS3BucketTags(ctx context.Context, input *service.S3BucketTagsGetterInput) (*service.S3BucketTagsGetterOutput, error) {
+	output, err := c.GetBucketTagging(ctx,
+		&s3.GetBucketTaggingInput{
+			Bucket: aws.String(input.Bucket.String()),
+		},
+		func(o *s3.Options) {
+			o.Region = input.Region.String()
+		})
+	if err != nil {
+		if strings.Contains(err.Error(), "NoSuchTagSet") || isNotImplementedError(err) {
+			return &service.S3BucketTagsGetterOutput{Tags: model.S3Tags{}}, nil
+		}
+		return nil, err
+	}
+
+	tags := make(model.S3Tags, 0, len(output.TagSet))
+	for _, t := range output.TagSet {
+		tags = append(tags, model.S3Tag{
+			Key:   aws.ToString(t.Key),
+			Value: aws.ToString(t.Value),
+		})
+	}
+	return &service.S3BucketTagsGetterOutput{Tags: tags}, nil
+}
+
+// S3MultipartUploadAborter implements the S3MultipartUploadAborter interface.
+type S3MultipartUploadAborter struct {
+	*s3.Client
+}
+
+// S3MultipartUploadAborterSet is a provider set for S3MultipartUploadAborter.
+//
+//nolint:gochecknoglobals
+var S3MultipartUploadAborterSet = wire.NewSet(
+	NewS3MultipartUploadAborter,
+	wire.Bind(new(service.S3MultipartUploadAborter), new(*S3MultipartUploadAborter)),
+)
+
+var _ service.S3MultipartUploadAborter = (*S3MultipartUploadAborter)(nil)
+
+// NewS3MultipartUploadAborter creates a new S3MultipartUploadAborter.
+func NewS3MultipartUploadAborter(client *s3.Client) *S3MultipartUploadAborter {
+	return &S3MultipartUploadAborter{Client: client}
+}
+
+// AbortS3MultipartUpload aborts the multipart upload. The uploaded parts are deleted.
+func (c *S3MultipartUploadAborter) AbortS3MultipartUpload(ctx context.Context, input *service.S3MultipartUploadAborterInput) (*service.S3MultipartUploadAborterOutput, error) {
+	if _, err := c.AbortMultipartUpload(ctx,
+		&s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(input.Bucket.String()),
+			Key:      aws.String(input.Upload.S3Key.String()),
+			UploadId: aws.String(input.Upload.UploadID),
+		},
+		func(o *s3.Options) {
+			o.Region = input.Region.String()
+		}); err != nil {
+		return nil, err
+	}
+	return &service.S3MultipartUploadAborterOutput{}, nil
+}
+
+// S3ObjectLockGetter implements the S3ObjectLockGetter interface.
+type S3ObjectLockGetter struct {
+	*s3.Client
+}
+
+// S3ObjectLockGetterSet is a provider set for S3ObjectLockGetter.
+//
+//nolint:gochecknoglobals
+var S3ObjectLockGetterSet = wire.NewSet(
+	NewS3ObjectLockGetter,
+	wire.Bind(new(service.S3ObjectLockGetter), new(*S3ObjectLockGetter)),
+)
+
+var _ service.S3ObjectLockGetter = (*S3ObjectLockGetter)(nil)
+
+// NewS3ObjectLockGetter creates a new S3ObjectLockGetter.
+func NewS3ObjectLockGetter(client *s3.Client) *S3ObjectLockGetter {
+	return &S3ObjectLockGetter{Client: client}
+}
+
+// GetS3ObjectLock gets the legal hold and retention status of the object version.
+// If the bucket does not have the object lock configuration, it returns the empty status.
+func (c *S3ObjectLockGetter) GetS3ObjectLock(ctx context.Context, input *service.S3ObjectLockGetterInput) (*service.S3ObjectLockGetterOutput, error) {
+	optFn := func(o *s3.Options) {
+		o.Region = input.Region.String()
+	}
+	var versionID *string
+	if input.S3ObjectIdentifier.VersionID != "" {
+		versionID = aws.String(input.S3ObjectIdentifier.VersionID.String())
+	}
+	lock := &model.S3ObjectLock{}
+
+	legalHold, err := c.GetObjectLegalHold(ctx, &s3.GetObjectLegalHoldInput{
+		Bucket:    aws.String(input.Bucket.String()),
+		Key:       aws.String(input.S3ObjectIdentifier.S3Key.String()),
+		VersionId: versionID,
+	}, optFn)
+	if err != nil && !isNoObjectLockError(err) {
+		return nil, err
+	}
+	if err == nil && legalHold.LegalHold != nil {
+		lock.LegalHold = legalHold.LegalHold.Status == types.ObjectLockLegalHoldStatusOn
+	}
+
+	retention, err := c.GetObjectRetention(ctx, &s3.GetObjectRetentionInput{
+		Bucket:    aws.String(input.Bucket.String()),
+		Key:       aws.String(input.S3ObjectIdentifier.S3Key.String()),
+		VersionId: versionID,
+	}, optFn)
+	if err != nil && !isNoObjectLockError(err) {
+		return nil, err
+	}
+	if err == nil && retention.Retention != nil {
+		lock.Mode = model.S3RetentionMode(retention.Retention.Mode)
+		lock.RetainUntil = aws.ToTime(retention.Retention.RetainUntilDate)
+	}
+	return &service.S3ObjectLockGetterOutput{Lock: lock}, nil
+}
+
+// isNoObjectLockError returns true if the error means that the object has no legal hold or retention.
+func isNoObjectLockError(err error) bool {
+	for _, code := range []string{"NoSuchObjectLockConfiguration", "ObjectLockConfigurationNotFoundError", "InvalidRequest"} {
+		if strings.Contains(err.Error(), code) {
+			return true
+		}
+	}
+	return isNotImplementedError(err)
+}
+
+// S3ObjectLegalHoldReleaser implements the S3ObjectLegalHoldReleaser interface.
+type S3ObjectLegalHoldReleaser struct {
+	*s3.Client
+}
+
+// S3ObjectLegalHoldReleaserSet is a provider set for S3ObjectLegalHoldReleaser.
+//
+//nolint:gochecknoglobals
+var S3ObjectLegalHoldReleaserSet = wire.NewSet(
+	NewS3ObjectLegalHoldReleaser,
+	wire.Bind(new(service.S3ObjectLegalHoldReleaser), new(*S3ObjectLegalHoldReleaser)),
+)
+
+var _ service.S3ObjectLegalHoldReleaser = (*S3ObjectLegalHoldReleaser)(nil)
+
+// NewS3ObjectLegalHoldReleaser creates a new S3ObjectLegalHoldReleaser.
+func NewS3ObjectLegalHoldReleaser(client *s3.Client) *S3ObjectLegalHoldReleaser {
+	return &S3ObjectLegalHoldReleaser{Client: client}
+}
+
+// ReleaseS3ObjectLegalHold turns off the legal hold of the object version.
+func (c *S3ObjectLegalHoldReleaser) ReleaseS3ObjectLegalHold(ctx context.Context, input *service.S3ObjectLegalHoldReleaserInput) (*service.S3ObjectLegalHoldReleaserOutput, error) {
+	var versionID *string
+	if input.S3ObjectIdentifier.VersionID != "" {
+		versionID = aws.String(input.S3ObjectIdentifier.VersionID.String())
+	}
+	if _, err := c.PutObjectLegalHold(ctx,
+		&s3.PutObjectLegalHoldInput{
+			Bucket:    aws.String(input.Bucket.String()),
+			Key:       aws.String(input.S3ObjectIdentifier.S3Key.String()),
+			VersionId: versionID,
+			LegalHold: &types.ObjectLockLegalHold{Status: types.ObjectLockLegalHoldStatusOff},
+		},
+		func(o *s3.Options) {
+			o.Region = input.Region.String()
+		}); err != nil {
+		return nil, err
+	}
+	return &service.S3ObjectLegalHoldReleaserOutput{}, nil
 }
@@ -13,7 +13,7 @@ import (
 // S3Client returns a new S3 client.
 func S3Client(t *testing.T) *s3.Client {
 	t.Helper()
-	config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+	config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -17,7 +17,7 @@ func TestS3BucketCreator_CreateS3Bucket(t *testing.T) {
 	t.Run("success to create bucket", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -40,7 +40,7 @@ func TestS3BucketCreator_CreateS3Bucket(t *testing.T) {
 	t.Run("fail to create bucket because the bucket already exists", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -64,7 +64,7 @@ func TestS3BucketCreator_CreateS3Bucket(t *testing.T) {
 	t.Run("fail to create bucket because the bucket name is invalid", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -84,7 +84,7 @@ func TestS3BucketCreator_CreateS3Bucket(t *testing.T) {
 	t.Run("fail to create bucket because the region is invalid", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -104,7 +104,7 @@ func TestS3BucketCreator_CreateS3Bucket(t *testing.T) {
 	t.Run("Create S3 Bucket at 'us-east-1'", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionUSEast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionUSEast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -128,7 +128,7 @@ func TestS3BucketLister_ListS3Buckets(t *testing.T) {
 	t.Run("success to list buckets", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -169,7 +169,7 @@ func TestS3BucketLister_ListS3Buckets(t *testing.T) {
 	t.Run("success to list buckets when there is no bucket", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -195,7 +195,7 @@ func TestS3BucketLocationGetter_GetS3BucketLocation(t *testing.T) {
 	t.Run("success to get bucket location", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -225,7 +225,7 @@ func TestS3BucketLocationGetter_GetS3BucketLocation(t *testing.T) {
 	t.Run("fail to get bucket location because the bucket does not exist", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionAPNortheast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -243,7 +243,7 @@ func TestS3BucketLocationGetter_GetS3BucketLocation(t *testing.T) {
 	t.Run("Get S3 bucket location that is at 'us-east-1'", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionUSEast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionUSEast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -293,7 +293,7 @@ func TestS3BucketDeleter_DeleteS3Bucket(t *testing.T) {
 	t.Run("success to delete bucket", func(t *testing.T) {
 		DeleteAllS3BucketDelete(t, S3Client(t))
 
-		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionUSEast1)
+		config, err := model.NewAWSConfig(context.Background(), model.NewAWSProfile(""), model.RegionUSEast1, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -12,7 +12,7 @@ import (
 
 // NewSNSClient returns a new SNSClient.
 func NewSNSClient(cfg *model.AWSConfig) *sns.Client {
-	return sns.NewFromConfig(*cfg.Config)
+	return sns.NewFromConfig(awsConfigWithRetryPolicy(cfg))
 }
 
 // SNSPublisher is an implementation for SNSPublisher.
@@ -2,10 +2,21 @@ package interactor
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/uuid"
 	"github.com/google/wire"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
 	"github.com/nao1215/rainbow/app/domain/service"
 	"github.com/nao1215/rainbow/app/usecase"
+	"golang.org/x/sync/errgroup"
 )
 
 // CFnStackListerSet is a set of CFnStackLister.
@@ -21,16 +32,18 @@ var _ usecase.CFnStackLister = (*CFnStackLister)(nil)
 // CFnStackLister is an implementation for CFnStackLister.
 type CFnStackLister struct {
 	service.CFnStackLister
+	service.CFnStackDescriber
 }
 
 // NewCFnStackLister returns a new CFnStackLister struct.
-func NewCFnStackLister(lister service.CFnStackLister) *CFnStackLister {
+func NewCFnStackLister(lister service.CFnStackLister, describer service.CFnStackDescriber) *CFnStackLister {
 	return &CFnStackLister{
-		CFnStackLister: lister,
+		CFnStackLister:    lister,
+		CFnStackDescriber: describer,
 	}
 }
 
-// ListCFnStack returns a list of CloudFormation stacks.
+// ListCFnStack returns a list of CloudFormation stacks that match the filter.
 func (l *CFnStackLister) ListCFnStack(ctx context.Context, input *usecase.CFnStackListerInput) (*usecase.CFnStackListerOutput, error) {
 	output, err := l.CFnStackLister.ListCFnStack(ctx, &service.CFnStackListerInput{
 		Region: input.Region,
@@ -38,11 +51,156 @@ func (l *CFnStackLister) ListCFnStack(ctx context.Context, input *usecase.CFnSta
 	if err != nil {
 		return nil, err
 	}
+	stacks, err := filterCFnStacks(ctx, l.CFnStackDescriber, input.Region, output.Stacks, input.Filter)
+	if err != nil {
+		return nil, err
+	}
+	model.SortStacks(stacks, input.SortKey)
 	return &usecase.CFnStackListerOutput{
-		Stacks: output.Stacks,
+		Stacks: stacks,
 	}, nil
 }
 
+// filterCFnStacks returns the stacks that match the filter. If the filter is nil, it returns the stacks as they are.
+// The tags are not in the stack summaries, so the stacks are described only when the filter has the tags.
+func filterCFnStacks(ctx context.Context, describer service.CFnStackDescriber, region model.Region, stacks []*model.Stack, filter *model.CFnStackFilter) ([]*model.Stack, error) {
+	if filter == nil {
+		return stacks, nil
+	}
+	now := time.Now()
+	matched := make([]*model.Stack, 0, len(stacks))
+	for _, stack := range stacks {
+		if filter.Match(stack, now) {
+			matched = append(matched, stack)
+		}
+	}
+	if len(filter.Tags) == 0 {
+		return matched, nil
+	}
+
+	// Each goroutine writes only its own index, so no lock is needed.
+	tagged := make([]bool, len(matched))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(model.MaxCFnStackDescribeParallelsCount)
+	for i, stack := range matched {
+		i, stack := i, stack
+		eg.Go(func() error {
+			// The deleted stack can be described only by the ID.
+			name := aws.ToString(stack.StackID)
+			if name == "" {
+				name = aws.ToString(stack.StackName)
+			}
+			out, err := describer.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+				StackName: name,
+				Region:    region,
+			})
+			if err != nil {
+				if errors.Is(err, domain.ErrCFnStackNotFound) {
+					return nil // The stack was deleted after listing.
+				}
+				return fmt.Errorf("can not get the tags of %s: %w", aws.ToString(stack.StackName), err)
+			}
+			tagged[i] = filter.MatchTags(out.Tags)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Stack, 0, len(matched))
+	for i, stack := range matched {
+		if tagged[i] {
+			result = append(result, stack)
+		}
+	}
+	return result, nil
+}
+
+// CFnStacksInRegionsListerSet is a set of CFnStacksInRegionsLister.
+//
+//nolint:gochecknoglobals
+var CFnStacksInRegionsListerSet = wire.NewSet(
+	NewCFnStacksInRegionsLister,
+	wire.Bind(new(usecase.CFnStacksInRegionsLister), new(*CFnStacksInRegionsLister)),
+)
+
+var _ usecase.CFnStacksInRegionsLister = (*CFnStacksInRegionsLister)(nil)
+
+// CFnStacksInRegionsLister is an implementation for CFnStacksInRegionsLister.
+type CFnStacksInRegionsLister struct {
+	service.CFnStackLister
+	service.CFnStackDescriber
+}
+
+// NewCFnStacksInRegionsLister returns a new CFnStacksInRegionsLister struct.
+func NewCFnStacksInRegionsLister(lister service.CFnStackLister, describer service.CFnStackDescriber) *CFnStacksInRegionsLister {
+	return &CFnStacksInRegionsLister{
+		CFnStackLister:    lister,
+		CFnStackDescriber: describer,
+	}
+}
+
+// ListCFnStacksInRegions returns a list of CloudFormation stacks in the regions.
+// The regions are listed concurrently. The regions where the account is not opted in are skipped.
+func (l *CFnStacksInRegionsLister) ListCFnStacksInRegions(ctx context.Context, input *usecase.CFnStacksInRegionsListerInput) (*usecase.CFnStacksInRegionsListerOutput, error) {
+	for _, region := range input.Regions {
+		if err := region.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, region)
+		}
+	}
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = model.MaxCFnRegionsParallelsCount
+	}
+
+	// Each goroutine writes only its own index, so no lock is needed.
+	stacks := make([][]*model.Stack, len(input.Regions))
+	skipped := make([]bool, len(input.Regions))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+	for i, region := range input.Regions {
+		i, region := i, region
+		eg.Go(func() error {
+			output, err := l.CFnStackLister.ListCFnStack(ctx, &service.CFnStackListerInput{
+				Region: region,
+			})
+			if err != nil {
+				if errors.Is(err, domain.ErrRegionNotEnabled) {
+					skipped[i] = true
+					return nil
+				}
+				return fmt.Errorf("can not list stacks in %s: %w", region, err)
+			}
+			filtered, err := filterCFnStacks(ctx, l.CFnStackDescriber, region, output.Stacks, input.Filter)
+			if err != nil {
+				return fmt.Errorf("can not list stacks in %s: %w", region, err)
+			}
+			stacks[i] = filtered
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	output := &usecase.CFnStacksInRegionsListerOutput{
+		Stacks:         []*model.RegionalStack{},
+		SkippedRegions: []model.Region{},
+	}
+	for i, region := range input.Regions {
+		if skipped[i] {
+			output.SkippedRegions = append(output.SkippedRegions, region)
+			continue
+		}
+		for _, stack := range stacks[i] {
+			output.Stacks = append(output.Stacks, &model.RegionalStack{Region: region, Stack: stack})
+		}
+	}
+	model.SortRegionalStacks(output.Stacks, input.SortKey)
+	return output, nil
+}
+
 // CFnStackEventsDescriberSet is a set of CFnStackEventsDescriber.
 //
 //nolint:gochecknoglobals
@@ -70,6 +228,7 @@ func (d *CFnStackEventsDescriber) DescribeCFnStackEvents(ctx context.Context, in
 	output, err := d.CFnStackEventsDescriber.DescribeCFnStackEvents(ctx, &service.CFnStackEventsDescriberInput{
 		StackName: input.StackName,
 		Region:    input.Region,
+		Since:     input.Since,
 	})
 	if err != nil {
 		return nil, err
@@ -78,3 +237,1285 @@ func (d *CFnStackEventsDescriber) DescribeCFnStackEvents(ctx context.Context, in
 		Events: output.Events,
 	}, nil
 }
+
+// CFnStackDescriberSet is a set of CFnStackDescriber.
+//
+//nolint:gochecknoglobals
+var CFnStackDescriberSet = wire.NewSet(
+	NewCFnStackDescriber,
+	wire.Bind(new(usecase.CFnStackDescriber), new(*CFnStackDescriber)),
+)
+
+var _ usecase.CFnStackDescriber = (*CFnStackDescriber)(nil)
+
+// CFnStackDescriber is an implementation for CFnStackDescriber.
+type CFnStackDescriber struct {
+	service.CFnStackDescriber
+}
+
+// NewCFnStackDescriber returns a new CFnStackDescriber struct.
+func NewCFnStackDescriber(describer service.CFnStackDescriber) *CFnStackDescriber {
+	return &CFnStackDescriber{
+		CFnStackDescriber: describer,
+	}
+}
+
+// DescribeCFnStack returns the CloudFormation stack.
+func (d *CFnStackDescriber) DescribeCFnStack(ctx context.Context, input *usecase.CFnStackDescriberInput) (*usecase.CFnStackDescriberOutput, error) {
+	output, err := d.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: input.StackName,
+		Region:    input.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.CFnStackDescriberOutput{
+		Stack:                 output.Stack,
+		TerminationProtection: output.TerminationProtection,
+		Tags:                  output.Tags,
+		Outputs:               output.Outputs,
+	}, nil
+}
+
+// CFnStackDeleterSet is a set of CFnStackDeleter.
+//
+//nolint:gochecknoglobals
+var CFnStackDeleterSet = wire.NewSet(
+	NewCFnStackDeleter,
+	wire.Bind(new(usecase.CFnStackDeleter), new(*CFnStackDeleter)),
+)
+
+var _ usecase.CFnStackDeleter = (*CFnStackDeleter)(nil)
+
+// CFnStackDeleter is an implementation for CFnStackDeleter.
+type CFnStackDeleter struct {
+	service.CFnStackDescriber
+	service.CFnStackDeleter
+	service.CFnStackEventsDescriber
+	service.CFnImportsLister
+}
+
+// NewCFnStackDeleter returns a new CFnStackDeleter struct.
+func NewCFnStackDeleter(
+	describer service.CFnStackDescriber,
+	deleter service.CFnStackDeleter,
+	eventsDescriber service.CFnStackEventsDescriber,
+	importsLister service.CFnImportsLister,
+) *CFnStackDeleter {
+	return &CFnStackDeleter{
+		CFnStackDescriber:       describer,
+		CFnStackDeleter:         deleter,
+		CFnStackEventsDescriber: eventsDescriber,
+		CFnImportsLister:        importsLister,
+	}
+}
+
+// DeleteCFnStack deletes the CloudFormation stack and waits until the deletion completes or fails.
+// The stack whose termination protection is enabled, or whose exports are imported by other stacks, is not deleted.
+// The stack events of the deletion are passed to input.OnEvent while waiting.
+func (d *CFnStackDeleter) DeleteCFnStack(ctx context.Context, input *usecase.CFnStackDeleterInput) (*usecase.CFnStackDeleterOutput, error) {
+	described, err := d.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: input.StackName,
+		Region:    input.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if described.TerminationProtection {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackTerminationProtection, input.StackName)
+	}
+	for _, exportName := range described.Outputs.ExportNames() {
+		imports, err := d.CFnImportsLister.ListCFnImports(ctx, &service.CFnImportsListerInput{
+			ExportName: exportName,
+			Region:     input.Region,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(imports.StackNames) > 0 {
+			return nil, fmt.Errorf("%w: %s exports %s imported by %s",
+				domain.ErrCFnStackExportsImported, input.StackName, exportName, strings.Join(imports.StackNames, ", "))
+		}
+	}
+
+	// The deleted stack can be described only by the stack ID.
+	stackID := aws.ToString(described.Stack.StackID)
+	token := "rainbow-" + uuid.NewString()
+	if _, err := d.CFnStackDeleter.DeleteCFnStack(ctx, &service.CFnStackDeleterInput{
+		StackName:          stackID,
+		Region:             input.Region,
+		RetainResources:    input.RetainResources,
+		ClientRequestToken: token,
+	}); err != nil {
+		return nil, err
+	}
+
+	stack, failed, err := waitCFnStackOperation(ctx, d.CFnStackDescriber, d.CFnStackEventsDescriber, &cfnStackOperation{
+		stackName: input.StackName,
+		stackID:   stackID,
+		before:    described.Stack,
+		region:    input.Region,
+		token:     token,
+		onEvent:   input.OnEvent,
+		interval:  input.PollInterval,
+		timeout:   model.CFnStackDeleteTimeout,
+		finished: func(status model.StackStatus) bool {
+			return status == model.StackStatusDeleteComplete || status == model.StackStatusDeleteFailed
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if stack.StackStatus == model.StackStatusDeleteComplete {
+		return &usecase.CFnStackDeleterOutput{
+			Status:          stack.StackStatus,
+			FailedResources: []*model.StackEvent{},
+		}, nil
+	}
+	return &usecase.CFnStackDeleterOutput{
+		Status:          stack.StackStatus,
+		StatusReason:    aws.ToString(stack.StackStatusReason),
+		FailedResources: failed,
+	}, nil
+}
+
+// cfnStackOperation is the stack operation (deletion, update or change set execution) to wait for.
+type cfnStackOperation struct {
+	// stackName is the name of the stack. It is used in the error message.
+	stackName string
+	// stackID is the ID of the stack. The deleted stack can be described only by the stack ID.
+	stackID string
+	// before is the stack described before the operation is requested.
+	before *model.Stack
+	// region is the region of the stack.
+	region model.Region
+	// token is the client request token of the operation. The events of other operations are ignored.
+	token string
+	// onEvent is called with each stack event of the operation in chronological order. It may be nil.
+	onEvent func(event *model.StackEvent)
+	// interval is the interval of polling. 0 means model.CFnStackEventsPollInterval.
+	interval time.Duration
+	// timeout is the maximum time to wait for the operation.
+	timeout time.Duration
+	// finished returns true if the stack status is the final status of the operation.
+	finished func(status model.StackStatus) bool
+}
+
+// waitCFnStackOperation polls the stack and its events until the operation finishes.
+// It returns the stack in the final status and the events of the resources that failed in the operation.
+func waitCFnStackOperation(
+	ctx context.Context,
+	describer service.CFnStackDescriber,
+	eventsDescriber service.CFnStackEventsDescriber,
+	op *cfnStackOperation,
+) (*model.Stack, []*model.StackEvent, error) {
+	interval := op.interval
+	if interval <= 0 {
+		interval = model.CFnStackEventsPollInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, op.timeout)
+	defer cancel()
+
+	seen := make(map[string]struct{})
+	failed := make([]*model.StackEvent, 0)
+	// started is true once the stack shows that the operation has started. Until then, the stack status
+	// may still be the final status of the previous operation (e.g. UPDATE_COMPLETE before the update).
+	started := false
+	for {
+		startedBeforeDescribe := started
+		// The status is described before the events, so that the events up to the final status are reported.
+		stack, err := describer.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+			StackName: op.stackID,
+			Region:    op.region,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		events, err := eventsDescriber.DescribeCFnStackEvents(ctx, &service.CFnStackEventsDescriberInput{
+			StackName: op.stackID,
+			Region:    op.region,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The events are sorted in reverse chronological order.
+		for i := len(events.Events) - 1; i >= 0; i-- {
+			event := events.Events[i]
+			if aws.ToString(event.ClientRequestToken) != op.token {
+				continue
+			}
+			started = true
+			if _, ok := seen[aws.ToString(event.EventID)]; ok {
+				continue
+			}
+			seen[aws.ToString(event.EventID)] = struct{}{}
+
+			if event.ResourceStatus.Failed() && aws.ToString(event.PhysicalResourceID) != op.stackID {
+				failed = append(failed, event)
+			}
+			if op.onEvent != nil {
+				op.onEvent(event)
+			}
+		}
+
+		if op.finished(stack.Stack.StackStatus) && (startedBeforeDescribe || stackChanged(op.before, stack.Stack)) {
+			return stack.Stack, failed, nil
+		}
+		if stack.Stack.StackStatus.InProgress() {
+			started = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("can not wait for the operation of %s: %w", op.stackName, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// stackChanged returns true if the status or the last updated time of the stack differs from before.
+func stackChanged(before, after *model.Stack) bool {
+	if before == nil {
+		return true
+	}
+	if before.StackStatus != after.StackStatus {
+		return true
+	}
+	if before.LastUpdatedTime == nil || after.LastUpdatedTime == nil {
+		return before.LastUpdatedTime != after.LastUpdatedTime
+	}
+	return !before.LastUpdatedTime.Equal(*after.LastUpdatedTime)
+}
+
+// CFnStackTaggerSet is a set of CFnStackTagger.
+//
+//nolint:gochecknoglobals
+var CFnStackTaggerSet = wire.NewSet(
+	NewCFnStackTagger,
+	wire.Bind(new(usecase.CFnStackTagger), new(*CFnStackTagger)),
+)
+
+var _ usecase.CFnStackTagger = (*CFnStackTagger)(nil)
+
+// CFnStackTagger is an implementation for CFnStackTagger.
+type CFnStackTagger struct {
+	service.CFnStackDescriber
+	service.CFnStackTagsUpdater
+	service.CFnStackEventsDescriber
+}
+
+// NewCFnStackTagger returns a new CFnStackTagger struct.
+func NewCFnStackTagger(
+	describer service.CFnStackDescriber,
+	updater service.CFnStackTagsUpdater,
+	eventsDescriber service.CFnStackEventsDescriber,
+) *CFnStackTagger {
+	return &CFnStackTagger{
+		CFnStackDescriber:       describer,
+		CFnStackTagsUpdater:     updater,
+		CFnStackEventsDescriber: eventsDescriber,
+	}
+}
+
+// TagCFnStack adds and removes the tags of the CloudFormation stack and waits until the update completes or fails.
+// The stack is updated with the previous template and parameters. If the tags are not changed, the stack is not updated.
+func (t *CFnStackTagger) TagCFnStack(ctx context.Context, input *usecase.CFnStackTaggerInput) (*usecase.CFnStackTaggerOutput, error) {
+	described, err := t.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: input.StackName,
+		Region:    input.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := described.Tags.Remove(input.RemoveKeys).Merge(input.Tags)
+	if tags.Equal(described.Tags) {
+		return &usecase.CFnStackTaggerOutput{
+			Tags:            described.Tags,
+			Status:          described.Stack.StackStatus,
+			FailedResources: []*model.StackEvent{},
+		}, nil
+	}
+
+	stackID := aws.ToString(described.Stack.StackID)
+	token := "rainbow-" + uuid.NewString()
+	if _, err := t.CFnStackTagsUpdater.UpdateCFnStackTags(ctx, &service.CFnStackTagsUpdaterInput{
+		StackName:          stackID,
+		Region:             input.Region,
+		Tags:               tags,
+		ParameterKeys:      described.ParameterKeys,
+		Capabilities:       described.Capabilities,
+		ClientRequestToken: token,
+	}); err != nil {
+		if errors.Is(err, domain.ErrCFnStackNoUpdates) {
+			return &usecase.CFnStackTaggerOutput{
+				Tags:            described.Tags,
+				Status:          described.Stack.StackStatus,
+				FailedResources: []*model.StackEvent{},
+			}, nil
+		}
+		return nil, err
+	}
+
+	stack, failed, err := waitCFnStackOperation(ctx, t.CFnStackDescriber, t.CFnStackEventsDescriber, &cfnStackOperation{
+		stackName: input.StackName,
+		stackID:   stackID,
+		before:    described.Stack,
+		region:    input.Region,
+		token:     token,
+		onEvent:   input.OnEvent,
+		interval:  input.PollInterval,
+		timeout:   model.CFnStackUpdateTimeout,
+		finished: func(status model.StackStatus) bool {
+			switch status {
+			case model.StackStatusUpdateComplete, model.StackStatusUpdateFailed,
+				model.StackStatusUpdateRollbackComplete, model.StackStatusUpdateRollbackFailed:
+				return true
+			default:
+				return false
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := &usecase.CFnStackTaggerOutput{
+		Changed:         true,
+		Tags:            tags,
+		Status:          stack.StackStatus,
+		StatusReason:    aws.ToString(stack.StackStatusReason),
+		FailedResources: failed,
+	}
+	if stack.StackStatus != model.StackStatusUpdateComplete {
+		output.Tags = described.Tags
+	}
+	return output, nil
+}
+
+// CFnStackDeployPlannerSet is a set of CFnStackDeployPlanner.
+//
+//nolint:gochecknoglobals
+var CFnStackDeployPlannerSet = wire.NewSet(
+	NewCFnStackDeployPlanner,
+	wire.Bind(new(usecase.CFnStackDeployPlanner), new(*CFnStackDeployPlanner)),
+)
+
+var _ usecase.CFnStackDeployPlanner = (*CFnStackDeployPlanner)(nil)
+
+// CFnStackDeployPlanner is an implementation for CFnStackDeployPlanner.
+type CFnStackDeployPlanner struct {
+	service.CFnStackDescriber
+	service.CFnChangeSetCreator
+	service.CFnChangeSetDescriber
+	service.CFnChangeSetDeleter
+	service.CFnStackDeleter
+}
+
+// NewCFnStackDeployPlanner returns a new CFnStackDeployPlanner struct.
+func NewCFnStackDeployPlanner(
+	stackDescriber service.CFnStackDescriber,
+	changeSetCreator service.CFnChangeSetCreator,
+	changeSetDescriber service.CFnChangeSetDescriber,
+	changeSetDeleter service.CFnChangeSetDeleter,
+	stackDeleter service.CFnStackDeleter,
+) *CFnStackDeployPlanner {
+	return &CFnStackDeployPlanner{
+		CFnStackDescriber:     stackDescriber,
+		CFnChangeSetCreator:   changeSetCreator,
+		CFnChangeSetDescriber: changeSetDescriber,
+		CFnChangeSetDeleter:   changeSetDeleter,
+		CFnStackDeleter:       stackDeleter,
+	}
+}
+
+// PlanCFnStackDeploy creates the change set of the stack and returns the proposed resource changes.
+// If the stack does not exist, the change set creates it. If the change set contains no changes, it is deleted.
+func (p *CFnStackDeployPlanner) PlanCFnStackDeploy(ctx context.Context, input *usecase.CFnStackDeployPlannerInput) (*usecase.CFnStackDeployPlannerOutput, error) {
+	if len(input.TemplateBody) > model.MaxCFnTemplateBodySize {
+		return nil, fmt.Errorf("%w: %d bytes (the limit is %d bytes)", domain.ErrCFnTemplateTooLarge, len(input.TemplateBody), model.MaxCFnTemplateBodySize)
+	}
+
+	changeSetType, createdStack, err := p.changeSetType(ctx, input.StackName, input.Region)
+	if err != nil {
+		return nil, err
+	}
+	created, err := p.CFnChangeSetCreator.CreateCFnChangeSet(ctx, &service.CFnChangeSetCreatorInput{
+		StackName:     input.StackName,
+		Region:        input.Region,
+		ChangeSetName: "rainbow-" + uuid.NewString(),
+		ChangeSetType: changeSetType,
+		TemplateBody:  input.TemplateBody,
+		Parameters:    input.Parameters,
+		Capabilities:  input.Capabilities,
+		Tags:          input.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet, err := p.waitCFnChangeSet(ctx, created.ChangeSetID, input.Region, input.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	if changeSet.Status == model.CFnChangeSetStatusCreateComplete {
+		return &usecase.CFnStackDeployPlannerOutput{
+			ChangeSetID:   created.ChangeSetID,
+			StackID:       created.StackID,
+			ChangeSetType: changeSetType,
+			CreatedStack:  createdStack,
+			Changes:       changeSet.Changes,
+		}, nil
+	}
+
+	// The failed change set is useless, so it is deleted with the stack that this change set created.
+	if err := cancelCFnChangeSet(ctx, p.CFnChangeSetDeleter, p.CFnStackDeleter, &usecase.CFnStackDeployCancelerInput{
+		ChangeSetID:  created.ChangeSetID,
+		StackID:      created.StackID,
+		Region:       input.Region,
+		CreatedStack: createdStack,
+	}); err != nil {
+		return nil, err
+	}
+	if isNoChangesReason(changeSet.StatusReason) {
+		return &usecase.CFnStackDeployPlannerOutput{
+			StackID:       created.StackID,
+			ChangeSetType: changeSetType,
+			CreatedStack:  createdStack,
+			Changes:       []*model.CFnResourceChange{},
+			NoChanges:     true,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrCFnChangeSetFailed, changeSet.StatusReason)
+}
+
+// changeSetType returns CREATE if the stack does not exist, otherwise UPDATE.
+// The stack that has only the change set that has not been executed (REVIEW_IN_PROGRESS) is also created.
+// createdStack is true only if the stack does not exist, because the change set creates it.
+func (p *CFnStackDeployPlanner) changeSetType(ctx context.Context, stackName string, region model.Region) (changeSetType model.CFnChangeSetType, createdStack bool, err error) {
+	described, err := p.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: stackName,
+		Region:    region,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrCFnStackNotFound) {
+			return model.CFnChangeSetTypeCreate, true, nil
+		}
+		return "", false, err
+	}
+
+	switch described.Stack.StackStatus {
+	case model.StackStatusReviewInProgress:
+		// The stack and its pending change sets belong to someone else, so they must not be deleted on cancel.
+		return model.CFnChangeSetTypeCreate, false, nil
+	case model.StackStatusRollbackComplete, model.StackStatusRollbackFailed,
+		model.StackStatusCreateFailed, model.StackStatusDeleteFailed:
+		// The stack whose creation failed can not be updated. It must be deleted and created again.
+		return "", false, fmt.Errorf("%w: %s is %s", domain.ErrCFnStackNotUpdatable, stackName, described.Stack.StackStatus)
+	default:
+		return model.CFnChangeSetTypeUpdate, false, nil
+	}
+}
+
+// waitCFnChangeSet polls the change set until it is created or fails.
+func (p *CFnStackDeployPlanner) waitCFnChangeSet(ctx context.Context, changeSetID string, region model.Region, interval time.Duration) (*service.CFnChangeSetDescriberOutput, error) {
+	if interval <= 0 {
+		interval = model.CFnChangeSetPollInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, model.CFnChangeSetCreateTimeout)
+	defer cancel()
+
+	for {
+		changeSet, err := p.CFnChangeSetDescriber.DescribeCFnChangeSet(ctx, &service.CFnChangeSetDescriberInput{
+			ChangeSetName: changeSetID,
+			Region:        region,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if changeSet.Status == model.CFnChangeSetStatusCreateComplete || changeSet.Status == model.CFnChangeSetStatusFailed {
+			return changeSet, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("can not wait for the change set creation: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isNoChangesReason returns true if the change set failed because it contains no changes.
+func isNoChangesReason(reason string) bool {
+	return strings.Contains(reason, "didn't contain changes") || strings.Contains(reason, "No updates are to be performed")
+}
+
+// cancelCFnChangeSet deletes the change set. If the change set created the stack, the stack is deleted too.
+// The stack is in REVIEW_IN_PROGRESS and has no resources, so the deletion is not waited for.
+func cancelCFnChangeSet(
+	ctx context.Context,
+	changeSetDeleter service.CFnChangeSetDeleter,
+	stackDeleter service.CFnStackDeleter,
+	input *usecase.CFnStackDeployCancelerInput,
+) error {
+	if _, err := changeSetDeleter.DeleteCFnChangeSet(ctx, &service.CFnChangeSetDeleterInput{
+		ChangeSetName: input.ChangeSetID,
+		Region:        input.Region,
+	}); err != nil {
+		return err
+	}
+	if !input.CreatedStack {
+		return nil
+	}
+	_, err := stackDeleter.DeleteCFnStack(ctx, &service.CFnStackDeleterInput{
+		StackName: input.StackID,
+		Region:    input.Region,
+	})
+	return err
+}
+
+// CFnStackDeployCancelerSet is a set of CFnStackDeployCanceler.
+//
+//nolint:gochecknoglobals
+var CFnStackDeployCancelerSet = wire.NewSet(
+	NewCFnStackDeployCanceler,
+	wire.Bind(new(usecase.CFnStackDeployCanceler), new(*CFnStackDeployCanceler)),
+)
+
+var _ usecase.CFnStackDeployCanceler = (*CFnStackDeployCanceler)(nil)
+
+// CFnStackDeployCanceler is an implementation for CFnStackDeployCanceler.
+type CFnStackDeployCanceler struct {
+	service.CFnChangeSetDeleter
+	service.CFnStackDeleter
+}
+
+// NewCFnStackDeployCanceler returns a new CFnStackDeployCanceler struct.
+func NewCFnStackDeployCanceler(changeSetDeleter service.CFnChangeSetDeleter, stackDeleter service.CFnStackDeleter) *CFnStackDeployCanceler {
+	return &CFnStackDeployCanceler{
+		CFnChangeSetDeleter: changeSetDeleter,
+		CFnStackDeleter:     stackDeleter,
+	}
+}
+
+// CancelCFnStackDeploy deletes the change set that is not executed.
+func (c *CFnStackDeployCanceler) CancelCFnStackDeploy(ctx context.Context, input *usecase.CFnStackDeployCancelerInput) (*usecase.CFnStackDeployCancelerOutput, error) {
+	if err := cancelCFnChangeSet(ctx, c.CFnChangeSetDeleter, c.CFnStackDeleter, input); err != nil {
+		return nil, err
+	}
+	return &usecase.CFnStackDeployCancelerOutput{}, nil
+}
+
+// CFnStackDeployerSet is a set of CFnStackDeployer.
+//
+//nolint:gochecknoglobals
+var CFnStackDeployerSet = wire.NewSet(
+	NewCFnStackDeployer,
+	wire.Bind(new(usecase.CFnStackDeployer), new(*CFnStackDeployer)),
+)
+
+var _ usecase.CFnStackDeployer = (*CFnStackDeployer)(nil)
+
+// CFnStackDeployer is an implementation for CFnStackDeployer.
+type CFnStackDeployer struct {
+	service.CFnStackDescriber
+	service.CFnChangeSetExecutor
+	service.CFnStackEventsDescriber
+}
+
+// NewCFnStackDeployer returns a new CFnStackDeployer struct.
+func NewCFnStackDeployer(
+	describer service.CFnStackDescriber,
+	executor service.CFnChangeSetExecutor,
+	eventsDescriber service.CFnStackEventsDescriber,
+) *CFnStackDeployer {
+	return &CFnStackDeployer{
+		CFnStackDescriber:       describer,
+		CFnChangeSetExecutor:    executor,
+		CFnStackEventsDescriber: eventsDescriber,
+	}
+}
+
+// DeployCFnStack executes the change set and waits until the stack creation or update completes or fails.
+func (d *CFnStackDeployer) DeployCFnStack(ctx context.Context, input *usecase.CFnStackDeployerInput) (*usecase.CFnStackDeployerOutput, error) {
+	described, err := d.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: input.StackID,
+		Region:    input.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token := "rainbow-" + uuid.NewString()
+	if _, err := d.CFnChangeSetExecutor.ExecuteCFnChangeSet(ctx, &service.CFnChangeSetExecutorInput{
+		ChangeSetName:      input.ChangeSetID,
+		Region:             input.Region,
+		ClientRequestToken: token,
+	}); err != nil {
+		return nil, err
+	}
+
+	stack, failed, err := waitCFnStackOperation(ctx, d.CFnStackDescriber, d.CFnStackEventsDescriber, &cfnStackOperation{
+		stackName: input.StackName,
+		stackID:   input.StackID,
+		before:    described.Stack,
+		region:    input.Region,
+		token:     token,
+		onEvent:   input.OnEvent,
+		interval:  input.PollInterval,
+		timeout:   model.CFnStackDeployTimeout,
+		finished: func(status model.StackStatus) bool {
+			switch status {
+			case model.StackStatusCreateComplete, model.StackStatusCreateFailed,
+				model.StackStatusRollbackComplete, model.StackStatusRollbackFailed,
+				model.StackStatusUpdateComplete, model.StackStatusUpdateFailed,
+				model.StackStatusUpdateRollbackComplete, model.StackStatusUpdateRollbackFailed:
+				return true
+			default:
+				return false
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.CFnStackDeployerOutput{
+		Status:          stack.StackStatus,
+		StatusReason:    aws.ToString(stack.StackStatusReason),
+		FailedResources: failed,
+	}, nil
+}
+
+// CFnStackEventsTailerSet is a set of CFnStackEventsTailer.
+//
+//nolint:gochecknoglobals
+var CFnStackEventsTailerSet = wire.NewSet(
+	NewCFnStackEventsTailer,
+	wire.Bind(new(usecase.CFnStackEventsTailer), new(*CFnStackEventsTailer)),
+)
+
+var _ usecase.CFnStackEventsTailer = (*CFnStackEventsTailer)(nil)
+
+// CFnStackEventsTailer is an implementation for CFnStackEventsTailer.
+type CFnStackEventsTailer struct {
+	service.CFnStackDescriber
+	service.CFnStackEventsDescriber
+}
+
+// NewCFnStackEventsTailer returns a new CFnStackEventsTailer struct.
+func NewCFnStackEventsTailer(describer service.CFnStackDescriber, eventsDescriber service.CFnStackEventsDescriber) *CFnStackEventsTailer {
+	return &CFnStackEventsTailer{
+		CFnStackDescriber:       describer,
+		CFnStackEventsDescriber: eventsDescriber,
+	}
+}
+
+// cfnEventsPollMargin is the margin of the time from which the events are polled again.
+// The event may appear in the API a little after its timestamp.
+const cfnEventsPollMargin = time.Minute
+
+// TailCFnStackEvents reports the stack events. The nested stacks are found from the events of
+// the AWS::CloudFormation::Stack resources, and their events are reported too.
+// If input.Follow is true, it polls until the stack reaches a terminal status.
+func (t *CFnStackEventsTailer) TailCFnStackEvents(ctx context.Context, input *usecase.CFnStackEventsTailerInput) (*usecase.CFnStackEventsTailerOutput, error) {
+	interval := input.PollInterval
+	if interval <= 0 {
+		interval = model.CFnStackEventsPollInterval
+	}
+
+	described, err := t.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: input.StackName,
+		Region:    input.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The deleted stack can be described only by the stack ID.
+	rootID := aws.ToString(described.Stack.StackID)
+
+	// since is the time from which the events of each stack are polled. The nested stacks are added when they are found.
+	since := map[string]time.Time{rootID: input.Since}
+	stackIDs := []string{rootID}
+	seen := make(map[string]struct{})
+	for {
+		polledAt := time.Now()
+		// The status is described before the events, so that the events up to the terminal status are reported.
+		stack, err := t.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+			StackName: rootID,
+			Region:    input.Region,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		events := make([]*model.StackEvent, 0)
+		for i := 0; i < len(stackIDs); i++ { // stackIDs grows while the nested stacks are found.
+			stackID := stackIDs[i]
+			out, err := t.CFnStackEventsDescriber.DescribeCFnStackEvents(ctx, &service.CFnStackEventsDescriberInput{
+				StackName: stackID,
+				Region:    input.Region,
+				Since:     since[stackID],
+			})
+			if err != nil {
+				if stackID != rootID && errors.Is(err, domain.ErrCFnStackNotFound) {
+					continue
+				}
+				return nil, err
+			}
+			for _, event := range out.Events {
+				if _, ok := seen[aws.ToString(event.EventID)]; ok {
+					continue
+				}
+				seen[aws.ToString(event.EventID)] = struct{}{}
+				events = append(events, event)
+
+				nestedID := aws.ToString(event.PhysicalResourceID)
+				if aws.ToString(event.ResourceType) != model.CFnNestedStackResourceType || nestedID == "" || nestedID == stackID {
+					continue
+				}
+				if _, ok := since[nestedID]; !ok {
+					since[nestedID] = input.Since
+					stackIDs = append(stackIDs, nestedID)
+				}
+			}
+			since[stackID] = laterTime(input.Since, polledAt.Add(-cfnEventsPollMargin))
+		}
+
+		sort.SliceStable(events, func(i, j int) bool {
+			return aws.ToTime(events[i].Timestamp).Before(aws.ToTime(events[j].Timestamp))
+		})
+		if input.OnEvent != nil {
+			for _, event := range events {
+				input.OnEvent(event)
+			}
+		}
+
+		if !input.Follow || !stack.Stack.StackStatus.InProgress() {
+			return &usecase.CFnStackEventsTailerOutput{
+				Status:       stack.Stack.StackStatus,
+				StatusReason: aws.ToString(stack.Stack.StackStatusReason),
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// laterTime returns the later time of a and b.
+func laterTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// CFnStackResourceListerSet is a set of CFnStackResourceLister.
+//
+//nolint:gochecknoglobals
+var CFnStackResourceListerSet = wire.NewSet(
+	NewCFnStackResourceLister,
+	wire.Bind(new(usecase.CFnStackResourceLister), new(*CFnStackResourceLister)),
+)
+
+var _ usecase.CFnStackResourceLister = (*CFnStackResourceLister)(nil)
+
+// CFnStackResourceLister is an implementation for CFnStackResourceLister.
+type CFnStackResourceLister struct {
+	service.CFnStackDescriber
+	service.CFnStackLister
+	service.CFnStackResourceLister
+}
+
+// NewCFnStackResourceLister returns a new CFnStackResourceLister struct.
+func NewCFnStackResourceLister(
+	describer service.CFnStackDescriber,
+	stackLister service.CFnStackLister,
+	resourceLister service.CFnStackResourceLister,
+) *CFnStackResourceLister {
+	return &CFnStackResourceLister{
+		CFnStackDescriber:      describer,
+		CFnStackLister:         stackLister,
+		CFnStackResourceLister: resourceLister,
+	}
+}
+
+// ListCFnStackResource returns the resources of the stack. If input.Recursive is true, the resources of
+// the nested stacks are listed as the children of their AWS::CloudFormation::Stack resources.
+// The nested stacks are found by ParentID and RootID of the stacks in the region.
+func (l *CFnStackResourceLister) ListCFnStackResource(ctx context.Context, input *usecase.CFnStackResourceListerInput) (*usecase.CFnStackResourceListerOutput, error) {
+	described, err := l.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: input.StackName,
+		Region:    input.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stackID := aws.ToString(described.Stack.StackID)
+
+	// nested is the IDs of the nested stacks by the ID of their parent stack.
+	nested := make(map[string]map[string]struct{})
+	if input.Recursive {
+		rootID := aws.ToString(described.Stack.RootID)
+		if rootID == "" {
+			rootID = stackID
+		}
+		out, err := l.CFnStackLister.ListCFnStack(ctx, &service.CFnStackListerInput{Region: input.Region})
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range out.Stacks {
+			if aws.ToString(stack.RootID) != rootID || stack.StackStatus == model.StackStatusDeleteComplete {
+				continue
+			}
+			parentID := aws.ToString(stack.ParentID)
+			if nested[parentID] == nil {
+				nested[parentID] = make(map[string]struct{})
+			}
+			nested[parentID][aws.ToString(stack.StackID)] = struct{}{}
+		}
+	}
+
+	resources, err := l.listStackResourceNodes(ctx, stackID, input.Region, nested)
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.CFnStackResourceListerOutput{
+		Stack:     described.Stack,
+		Resources: resources,
+	}, nil
+}
+
+// listStackResourceNodes returns the resources of the stack, and expands the nested stacks found in nested.
+func (l *CFnStackResourceLister) listStackResourceNodes(
+	ctx context.Context,
+	stackID string,
+	region model.Region,
+	nested map[string]map[string]struct{},
+) ([]*model.StackResourceNode, error) {
+	out, err := l.CFnStackResourceLister.ListCFnStackResource(ctx, &service.CFnStackResourceListerInput{
+		StackName: stackID,
+		Region:    region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*model.StackResourceNode, 0, len(out.Resources))
+	for _, resource := range out.Resources {
+		node := &model.StackResourceNode{StackResource: resource}
+		nestedID := aws.ToString(resource.PhysicalResourceID)
+		if _, ok := nested[stackID][nestedID]; ok && resource.IsNestedStack() {
+			children, err := l.listStackResourceNodes(ctx, nestedID, region, nested)
+			if err != nil && !errors.Is(err, domain.ErrCFnStackNotFound) { // The nested stack may be deleted in the meantime.
+				return nil, err
+			}
+			node.Children = children
+		}
+		nodes = append(nodes, node)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return aws.ToString(nodes[i].LogicalResourceID) < aws.ToString(nodes[j].LogicalResourceID)
+	})
+	return nodes, nil
+}
+
+// CFnStackDriftDetectorSet is a set of CFnStackDriftDetector.
+//
+//nolint:gochecknoglobals
+var CFnStackDriftDetectorSet = wire.NewSet(
+	NewCFnStackDriftDetector,
+	wire.Bind(new(usecase.CFnStackDriftDetector), new(*CFnStackDriftDetector)),
+)
+
+var _ usecase.CFnStackDriftDetector = (*CFnStackDriftDetector)(nil)
+
+// CFnStackDriftDetector is an implementation for CFnStackDriftDetector.
+type CFnStackDriftDetector struct {
+	service.CFnStackDriftDetector
+	service.CFnStackDriftDetectionStatusDescriber
+	service.CFnStackResourceDriftsDescriber
+}
+
+// NewCFnStackDriftDetector returns a new CFnStackDriftDetector struct.
+func NewCFnStackDriftDetector(
+	detector service.CFnStackDriftDetector,
+	statusDescriber service.CFnStackDriftDetectionStatusDescriber,
+	driftsDescriber service.CFnStackResourceDriftsDescriber,
+) *CFnStackDriftDetector {
+	return &CFnStackDriftDetector{
+		CFnStackDriftDetector:                 detector,
+		CFnStackDriftDetectionStatusDescriber: statusDescriber,
+		CFnStackResourceDriftsDescriber:       driftsDescriber,
+	}
+}
+
+// DetectCFnStackDrift detects the drift of the stacks concurrently. For each stack, it waits until
+// the detection completes and describes the resources that drifted. The error of a stack does not stop
+// the detection of the other stacks; it is recorded in Err of the stack. Only the cancellation of ctx is returned.
+func (d *CFnStackDriftDetector) DetectCFnStackDrift(ctx context.Context, input *usecase.CFnStackDriftDetectorInput) (*usecase.CFnStackDriftDetectorOutput, error) {
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = model.MaxCFnDriftDetectionParallelsCount
+	}
+
+	// Each goroutine writes only its own index, so no lock is needed.
+	drifts := make([]*model.CFnStackDrift, len(input.StackNames))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+	for i, stackName := range input.StackNames {
+		i, stackName := i, stackName
+		eg.Go(func() error {
+			drift, err := d.detectCFnStackDrift(ctx, stackName, input.Region, input.PollInterval)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				drift = &model.CFnStackDrift{
+					StackName: stackName,
+					Err:       fmt.Errorf("can not detect the drift of %s: %w", stackName, err),
+				}
+			}
+			drifts[i] = drift
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return &usecase.CFnStackDriftDetectorOutput{
+		Drifts: drifts,
+	}, nil
+}
+
+// detectCFnStackDrift detects the drift of the stack and waits until the detection completes.
+func (d *CFnStackDriftDetector) detectCFnStackDrift(ctx context.Context, stackName string, region model.Region, interval time.Duration) (*model.CFnStackDrift, error) {
+	if interval <= 0 {
+		interval = model.CFnDriftDetectionPollInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, model.CFnDriftDetectionTimeout)
+	defer cancel()
+
+	detected, err := d.CFnStackDriftDetector.DetectCFnStackDrift(ctx, &service.CFnStackDriftDetectorInput{
+		StackName: stackName,
+		Region:    region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		status, err := d.CFnStackDriftDetectionStatusDescriber.DescribeCFnStackDriftDetectionStatus(ctx, &service.CFnStackDriftDetectionStatusDescriberInput{
+			DetectionID: detected.DetectionID,
+			Region:      region,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if status.DetectionStatus != model.CFnDriftDetectionStatusInProgress {
+			drift := &model.CFnStackDrift{
+				StackName:             stackName,
+				DetectionStatus:       status.DetectionStatus,
+				DetectionStatusReason: status.DetectionStatusReason,
+				DriftStatus:           status.DriftStatus,
+				DriftedResourceCount:  status.DriftedResourceCount,
+				Resources:             []*model.CFnStackResourceDrift{},
+			}
+			// The results of the resources are available even if the detection failed for some resources.
+			if status.DriftedResourceCount > 0 {
+				out, err := d.CFnStackResourceDriftsDescriber.DescribeCFnStackResourceDrifts(ctx, &service.CFnStackResourceDriftsDescriberInput{
+					StackName: stackName,
+					Region:    region,
+				})
+				if err != nil {
+					return nil, err
+				}
+				drift.Resources = out.Drifts
+			}
+			return drift, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("can not wait for the drift detection: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// CFnExportsListerSet is a set of CFnExportsLister.
+//
+//nolint:gochecknoglobals
+var CFnExportsListerSet = wire.NewSet(
+	NewCFnExportsLister,
+	wire.Bind(new(usecase.CFnExportsLister), new(*CFnExportsLister)),
+)
+
+var _ usecase.CFnExportsLister = (*CFnExportsLister)(nil)
+
+// CFnExportsLister is an implementation for CFnExportsLister.
+type CFnExportsLister struct {
+	service.CFnExportsLister
+	service.CFnImportsLister
+}
+
+// NewCFnExportsLister returns a new CFnExportsLister struct.
+func NewCFnExportsLister(exportsLister service.CFnExportsLister, importsLister service.CFnImportsLister) *CFnExportsLister {
+	return &CFnExportsLister{
+		CFnExportsLister: exportsLister,
+		CFnImportsLister: importsLister,
+	}
+}
+
+// ListCFnExports returns the exports in the region sorted by the name.
+// If input.Importers is true, the importers of the exports are listed concurrently.
+func (l *CFnExportsLister) ListCFnExports(ctx context.Context, input *usecase.CFnExportsListerInput) (*usecase.CFnExportsListerOutput, error) {
+	out, err := l.CFnExportsLister.ListCFnExports(ctx, &service.CFnExportsListerInput{
+		Region: input.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]*model.CFnExport, 0, len(out.Exports))
+	for _, export := range out.Exports {
+		if input.StackName != "" && input.StackName != export.ExportingStackID && input.StackName != model.StackNameFromID(export.ExportingStackID) {
+			continue
+		}
+		exports = append(exports, export)
+	}
+	sort.Slice(exports, func(i, j int) bool {
+		return exports[i].Name < exports[j].Name
+	})
+
+	if input.Importers {
+		concurrency := input.Concurrency
+		if concurrency <= 0 {
+			concurrency = model.MaxCFnImportsParallelsCount
+		}
+		// Each goroutine writes only its own export, so no lock is needed.
+		eg, ctx := errgroup.WithContext(ctx)
+		eg.SetLimit(concurrency)
+		for _, export := range exports {
+			export := export
+			eg.Go(func() error {
+				imports, err := l.CFnImportsLister.ListCFnImports(ctx, &service.CFnImportsListerInput{
+					ExportName: export.Name,
+					Region:     input.Region,
+				})
+				if err != nil {
+					return fmt.Errorf("can not list the importers of %s: %w", export.Name, err)
+				}
+				export.Importers = imports.StackNames
+				sort.Strings(export.Importers)
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return nil, err
+		}
+	}
+	return &usecase.CFnExportsListerOutput{
+		Exports: exports,
+	}, nil
+}
+
+// CFnTemplateGetterSet is a set of CFnTemplateGetter.
+//
+//nolint:gochecknoglobals
+var CFnTemplateGetterSet = wire.NewSet(
+	NewCFnTemplateGetter,
+	wire.Bind(new(usecase.CFnTemplateGetter), new(*CFnTemplateGetter)),
+)
+
+var _ usecase.CFnTemplateGetter = (*CFnTemplateGetter)(nil)
+
+// CFnTemplateGetter is an implementation for CFnTemplateGetter.
+type CFnTemplateGetter struct {
+	service.CFnTemplateGetter
+}
+
+// NewCFnTemplateGetter returns a new CFnTemplateGetter struct.
+func NewCFnTemplateGetter(getter service.CFnTemplateGetter) *CFnTemplateGetter {
+	return &CFnTemplateGetter{
+		CFnTemplateGetter: getter,
+	}
+}
+
+// GetCFnTemplate returns the deployed template of the stack.
+func (g *CFnTemplateGetter) GetCFnTemplate(ctx context.Context, input *usecase.CFnTemplateGetterInput) (*usecase.CFnTemplateGetterOutput, error) {
+	stage := input.Stage
+	if stage == "" {
+		stage = model.CFnTemplateStageOriginal
+	}
+	out, err := g.CFnTemplateGetter.GetCFnTemplate(ctx, &service.CFnTemplateGetterInput{
+		StackName: input.StackName,
+		Region:    input.Region,
+		Stage:     stage,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usecase.CFnTemplateGetterOutput{
+		TemplateBody: out.TemplateBody,
+	}, nil
+}
+
+// CFnTemplateDifferSet is a set of CFnTemplateDiffer.
+//
+//nolint:gochecknoglobals
+var CFnTemplateDifferSet = wire.NewSet(
+	NewCFnTemplateDiffer,
+	wire.Bind(new(usecase.CFnTemplateDiffer), new(*CFnTemplateDiffer)),
+)
+
+var _ usecase.CFnTemplateDiffer = (*CFnTemplateDiffer)(nil)
+
+// CFnTemplateDiffer is an implementation for CFnTemplateDiffer.
+type CFnTemplateDiffer struct {
+	service.CFnTemplateGetter
+}
+
+// NewCFnTemplateDiffer returns a new CFnTemplateDiffer struct.
+func NewCFnTemplateDiffer(getter service.CFnTemplateGetter) *CFnTemplateDiffer {
+	return &CFnTemplateDiffer{
+		CFnTemplateGetter: getter,
+	}
+}
+
+// DiffCFnTemplate compares the original template of the stack with the local template.
+// The original template is compared because the local template is not processed by the transforms.
+func (d *CFnTemplateDiffer) DiffCFnTemplate(ctx context.Context, input *usecase.CFnTemplateDifferInput) (*usecase.CFnTemplateDifferOutput, error) {
+	local, err := model.NewCFnTemplate(input.TemplateBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local template: %w", err)
+	}
+	out, err := d.CFnTemplateGetter.GetCFnTemplate(ctx, &service.CFnTemplateGetterInput{
+		StackName: input.StackName,
+		Region:    input.Region,
+		Stage:     model.CFnTemplateStageOriginal,
+	})
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := model.NewCFnTemplate([]byte(out.TemplateBody))
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployed template: %w", err)
+	}
+	return &usecase.CFnTemplateDifferOutput{
+		Differences: deployed.Diff(local),
+	}, nil
+}
+
+// CFnStaleStacksFinderSet is a set of CFnStaleStacksFinder.
+//
+//nolint:gochecknoglobals
+var CFnStaleStacksFinderSet = wire.NewSet(
+	NewCFnStaleStacksFinder,
+	wire.Bind(new(usecase.CFnStaleStacksFinder), new(*CFnStaleStacksFinder)),
+)
+
+var _ usecase.CFnStaleStacksFinder = (*CFnStaleStacksFinder)(nil)
+
+// CFnStaleStacksFinder is an implementation for CFnStaleStacksFinder.
+type CFnStaleStacksFinder struct {
+	service.CFnStackLister
+	service.CFnStackDescriber
+	service.CFnImportsLister
+}
+
+// NewCFnStaleStacksFinder returns a new CFnStaleStacksFinder struct.
+func NewCFnStaleStacksFinder(
+	lister service.CFnStackLister,
+	describer service.CFnStackDescriber,
+	importsLister service.CFnImportsLister,
+) *CFnStaleStacksFinder {
+	return &CFnStaleStacksFinder{
+		CFnStackLister:    lister,
+		CFnStackDescriber: describer,
+		CFnImportsLister:  importsLister,
+	}
+}
+
+// FindCFnStaleStacks returns the stale stacks in the order that they can be deleted.
+// The stack is blocked if the termination protection is enabled or a healthy stack imports its export.
+func (f *CFnStaleStacksFinder) FindCFnStaleStacks(ctx context.Context, input *usecase.CFnStaleStacksFinderInput) (*usecase.CFnStaleStacksFinderOutput, error) {
+	out, err := f.CFnStackLister.ListCFnStack(ctx, &service.CFnStackListerInput{Region: input.Region})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stale := make([]*model.CFnStaleStack, 0)
+	names := make(map[string]bool)
+	for _, stack := range out.Stacks {
+		if s, ok := model.NewCFnStaleStack(stack, input.OlderThan, now); ok {
+			stale = append(stale, s)
+			names[*stack.StackName] = true
+		}
+	}
+
+	// Each goroutine writes only its own stack, so no lock is needed.
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(model.MaxCFnStackDescribeParallelsCount)
+	for _, s := range stale {
+		s := s
+		eg.Go(func() error {
+			return f.checkDependencies(egCtx, input.Region, s, names)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	deletable, blocked := model.SortStaleStacksForDeletion(stale)
+	if blocked == nil {
+		blocked = []*model.CFnStaleStack{}
+	}
+	return &usecase.CFnStaleStacksFinderOutput{
+		Deletable: deletable,
+		Blocked:   blocked,
+	}, nil
+}
+
+// checkDependencies sets the stale importers of the exports to DependsOn, and sets BlockedReason
+// if the stack can not be deleted. names is the names of the stale stacks.
+func (f *CFnStaleStacksFinder) checkDependencies(ctx context.Context, region model.Region, stack *model.CFnStaleStack, names map[string]bool) error {
+	name := aws.ToString(stack.Stack.StackName)
+	out, err := f.CFnStackDescriber.DescribeCFnStack(ctx, &service.CFnStackDescriberInput{
+		StackName: aws.ToString(stack.Stack.StackID),
+		Region:    region,
+	})
+	if err != nil {
+		return fmt.Errorf("can not describe %s: %w", name, err)
+	}
+	if out.TerminationProtection {
+		stack.BlockedReason = "termination protection is enabled"
+		return nil
+	}
+
+	for _, export := range out.Outputs.ExportNames() {
+		imports, err := f.CFnImportsLister.ListCFnImports(ctx, &service.CFnImportsListerInput{
+			ExportName: export,
+			Region:     region,
+		})
+		if err != nil {
+			return fmt.Errorf("can not list the importers of %s: %w", export, err)
+		}
+		for _, importer := range imports.StackNames {
+			if !names[importer] {
+				stack.BlockedReason = fmt.Sprintf("%s is imported by %s", export, importer)
+				return nil
+			}
+			if !slices.Contains(stack.DependsOn, importer) {
+				stack.DependsOn = append(stack.DependsOn, importer)
+			}
+		}
+	}
+	sort.Strings(stack.DependsOn)
+	return nil
+}
@@ -3,10 +3,16 @@ package interactor
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain"
 	"github.com/nao1215/rainbow/app/domain/model"
 	"github.com/nao1215/rainbow/app/domain/service"
 	"github.com/nao1215/rainbow/app/external/mock"
@@ -41,7 +47,7 @@ func TestCFnStackLister_ListCFnStack(t *testing.T) {
 			}, nil
 		})
 
-		lister := NewCFnStackLister(stackLister)
+		lister := NewCFnStackLister(stackLister, nil)
 		output, err := lister.ListCFnStack(context.Background(), &usecase.CFnStackListerInput{
 			Region: model.RegionAPEast1,
 		})
@@ -73,7 +79,7 @@ func TestCFnStackLister_ListCFnStack(t *testing.T) {
 			return nil, errors.New("some error")
 		})
 
-		lister := NewCFnStackLister(stackLister)
+		lister := NewCFnStackLister(stackLister, nil)
 		_, err := lister.ListCFnStack(context.Background(), &usecase.CFnStackListerInput{
 			Region: model.RegionAPEast1,
 		})
@@ -81,4 +87,1378 @@ func TestCFnStackLister_ListCFnStack(t *testing.T) {
 			t.Error("expected error, but nil")
 		}
 	})
+
+	t.Run("filter the stacks by the status, the name, the age and the tags and sort them", func(t *testing.T) {
+		t.Parallel()
+
+		old := time.Now().Add(-60 * 24 * time.Hour)
+		recent := time.Now().Add(-time.Hour)
+		stackLister := mock.CFnStackLister(func(_ context.Context, _ *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
+			return &service.CFnStackListerOutput{
+				Stacks: []*model.Stack{
+					{StackName: aws.String("dev-web"), StackID: aws.String("id-web"), StackStatus: model.StackStatusUpdateComplete, CreationTime: &old},
+					{StackName: aws.String("dev-db"), StackID: aws.String("id-db"), StackStatus: model.StackStatusCreateComplete, CreationTime: &old},
+					{StackName: aws.String("dev-api"), StackID: aws.String("id-api"), StackStatus: model.StackStatusCreateComplete, CreationTime: &old},
+					{StackName: aws.String("dev-new"), StackID: aws.String("id-new"), StackStatus: model.StackStatusCreateComplete, CreationTime: &recent},
+					{StackName: aws.String("dev-deleted"), StackID: aws.String("id-deleted"), StackStatus: model.StackStatusDeleteComplete, CreationTime: &old},
+					{StackName: aws.String("prd-web"), StackID: aws.String("id-prd"), StackStatus: model.StackStatusCreateComplete, CreationTime: &old},
+					{StackName: aws.String("dev-failed"), StackID: aws.String("id-failed"), StackStatus: model.StackStatusRollbackComplete, CreationTime: &old},
+				},
+			}, nil
+		})
+		describer := mock.CFnStackDescriber(func(_ context.Context, input *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			switch input.StackName {
+			case "id-web", "id-api":
+				return &service.CFnStackDescriberOutput{Tags: model.CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: "a"}}}, nil
+			case "id-db":
+				return &service.CFnStackDescriberOutput{Tags: model.CFnTags{{Key: "env", Value: "prd"}}}, nil
+			default:
+				t.Errorf("unexpected stack is described: %s", input.StackName)
+				return nil, domain.ErrCFnStackNotFound
+			}
+		})
+
+		output, err := NewCFnStackLister(stackLister, describer).ListCFnStack(context.Background(), &usecase.CFnStackListerInput{
+			Region: model.RegionAPEast1,
+			Filter: &model.CFnStackFilter{
+				Statuses:  []model.StackStatus{model.StackStatusCreateComplete, model.StackStatusUpdateComplete, model.StackStatusDeleteComplete},
+				Name:      regexp.MustCompile("^dev-"),
+				Tags:      model.CFnTags{{Key: "env", Value: "dev"}},
+				OlderThan: 30 * 24 * time.Hour,
+			},
+			SortKey: model.CFnStackSortKeyName,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []*model.Stack{
+			{StackName: aws.String("dev-api"), StackID: aws.String("id-api"), StackStatus: model.StackStatusCreateComplete, CreationTime: &old},
+			{StackName: aws.String("dev-web"), StackID: aws.String("id-web"), StackStatus: model.StackStatusUpdateComplete, CreationTime: &old},
+		}
+		if diff := cmp.Diff(want, output.Stacks); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+}
+
+func TestCFnStacksInRegionsLister_ListCFnStacksInRegions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success to list stacks in regions and skip the region that is not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		stackLister := mock.CFnStackLister(func(ctx context.Context, input *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
+			switch input.Region {
+			case model.RegionUSEast1:
+				return &service.CFnStackListerOutput{
+					Stacks: []*model.Stack{{StackName: aws.String("stackName1")}},
+				}, nil
+			case model.RegionAPEast1:
+				return nil, domain.ErrRegionNotEnabled
+			default:
+				return &service.CFnStackListerOutput{
+					Stacks: []*model.Stack{{StackName: aws.String("stackName2")}, {StackName: aws.String("stackName3")}},
+				}, nil
+			}
+		})
+
+		lister := NewCFnStacksInRegionsLister(stackLister, nil)
+		output, err := lister.ListCFnStacksInRegions(context.Background(), &usecase.CFnStacksInRegionsListerInput{
+			Regions:     []model.Region{model.RegionAPNortheast1, model.RegionAPEast1, model.RegionUSEast1},
+			Concurrency: 2,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := &usecase.CFnStacksInRegionsListerOutput{
+			Stacks: []*model.RegionalStack{
+				{Region: model.RegionAPNortheast1, Stack: &model.Stack{StackName: aws.String("stackName2")}},
+				{Region: model.RegionAPNortheast1, Stack: &model.Stack{StackName: aws.String("stackName3")}},
+				{Region: model.RegionUSEast1, Stack: &model.Stack{StackName: aws.String("stackName1")}},
+			},
+			SkippedRegions: []model.Region{model.RegionAPEast1},
+		}
+		if diff := cmp.Diff(want, output); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("success to sort the stacks across the regions", func(t *testing.T) {
+		t.Parallel()
+
+		stackLister := mock.CFnStackLister(func(ctx context.Context, input *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
+			if input.Region == model.RegionUSEast1 {
+				return &service.CFnStackListerOutput{
+					Stacks: []*model.Stack{{StackName: aws.String("d")}, {StackName: aws.String("b")}},
+				}, nil
+			}
+			return &service.CFnStackListerOutput{
+				Stacks: []*model.Stack{{StackName: aws.String("c")}, {StackName: aws.String("a")}, {StackName: aws.String("b")}},
+			}, nil
+		})
+
+		lister := NewCFnStacksInRegionsLister(stackLister, nil)
+		output, err := lister.ListCFnStacksInRegions(context.Background(), &usecase.CFnStacksInRegionsListerInput{
+			Regions: []model.Region{model.RegionUSEast1, model.RegionAPNortheast1},
+			SortKey: model.CFnStackSortKeyName,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []*model.RegionalStack{
+			{Region: model.RegionAPNortheast1, Stack: &model.Stack{StackName: aws.String("a")}},
+			{Region: model.RegionAPNortheast1, Stack: &model.Stack{StackName: aws.String("b")}},
+			{Region: model.RegionUSEast1, Stack: &model.Stack{StackName: aws.String("b")}},
+			{Region: model.RegionAPNortheast1, Stack: &model.Stack{StackName: aws.String("c")}},
+			{Region: model.RegionUSEast1, Stack: &model.Stack{StackName: aws.String("d")}},
+		}
+		if diff := cmp.Diff(want, output.Stacks); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("fail to list stacks in one of the regions", func(t *testing.T) {
+		t.Parallel()
+
+		stackLister := mock.CFnStackLister(func(ctx context.Context, input *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
+			if input.Region == model.RegionUSWest2 {
+				return nil, errors.New("some error")
+			}
+			return &service.CFnStackListerOutput{}, nil
+		})
+
+		lister := NewCFnStacksInRegionsLister(stackLister, nil)
+		_, err := lister.ListCFnStacksInRegions(context.Background(), &usecase.CFnStacksInRegionsListerInput{
+			Regions: []model.Region{model.RegionUSEast1, model.RegionUSWest2},
+		})
+		if err == nil {
+			t.Error("expected error, but nil")
+		}
+	})
+
+	t.Run("fail to validate the region", func(t *testing.T) {
+		t.Parallel()
+
+		stackLister := mock.CFnStackLister(func(ctx context.Context, input *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
+			t.Error("ListCFnStack must not be called")
+			return &service.CFnStackListerOutput{}, nil
+		})
+
+		lister := NewCFnStacksInRegionsLister(stackLister, nil)
+		_, err := lister.ListCFnStacksInRegions(context.Background(), &usecase.CFnStacksInRegionsListerInput{
+			Regions: []model.Region{model.Region("invalid-region")},
+		})
+		if !errors.Is(err, domain.ErrInvalidRegion) {
+			t.Errorf("want ErrInvalidRegion, but got %v", err)
+		}
+	})
+}
+
+func TestCFnStackDeleter_DeleteCFnStack(t *testing.T) {
+	t.Parallel()
+
+	const stackID = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+
+	// newDeleter returns the deleter whose stack changes the status in the order of statuses on each describe.
+	newDeleter := func(t *testing.T, protected bool, statuses []model.StackStatus, events []*model.StackEvent) (*CFnStackDeleter, *string) {
+		t.Helper()
+		var token string
+		describeCount := 0
+		describer := mock.CFnStackDescriber(func(_ context.Context, input *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			status := statuses[min(describeCount, len(statuses)-1)]
+			describeCount++
+			return &service.CFnStackDescriberOutput{
+				Stack: &model.Stack{
+					StackName:         aws.String("app"),
+					StackID:           aws.String(stackID),
+					StackStatus:       status,
+					StackStatusReason: aws.String("The following resource(s) failed to delete: [Bucket]."),
+				},
+				TerminationProtection: protected,
+			}, nil
+		})
+		deleter := mock.CFnStackDeleter(func(_ context.Context, input *service.CFnStackDeleterInput) (*service.CFnStackDeleterOutput, error) {
+			if input.StackName != stackID {
+				t.Errorf("StackName = %s, want %s", input.StackName, stackID)
+			}
+			token = input.ClientRequestToken
+			return &service.CFnStackDeleterOutput{}, nil
+		})
+		eventsDescriber := mock.CFnStackEventsDescriber(func(_ context.Context, _ *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error) {
+			// The events of this deletion have the token of the DeleteStack request. They are sorted in reverse chronological order.
+			out := make([]*model.StackEvent, 0, len(events))
+			for i := len(events) - 1; i >= 0; i-- {
+				e := *events[i]
+				if e.ClientRequestToken == nil {
+					e.ClientRequestToken = aws.String(token)
+				}
+				out = append(out, &e)
+			}
+			return &service.CFnStackEventsDescriberOutput{Events: out}, nil
+		})
+		importsLister := mock.CFnImportsLister(func(_ context.Context, _ *service.CFnImportsListerInput) (*service.CFnImportsListerOutput, error) {
+			return &service.CFnImportsListerOutput{StackNames: []string{}}, nil
+		})
+		return NewCFnStackDeleter(describer, deleter, eventsDescriber, importsLister), &token
+	}
+
+	t.Run("delete the stack and report the events in chronological order", func(t *testing.T) {
+		t.Parallel()
+
+		deleter, _ := newDeleter(t, false,
+			[]model.StackStatus{model.StackStatusCreateComplete, model.StackStatusDeleteInProgress, model.StackStatusDeleteComplete},
+			[]*model.StackEvent{
+				{EventID: aws.String("old"), ClientRequestToken: aws.String("other"), ResourceStatus: model.ResourceStatusCreateComplete},
+				{EventID: aws.String("1"), LogicalResourceID: aws.String("Bucket"), ResourceStatus: model.ResourceStatusDeleteInProgress},
+				{EventID: aws.String("2"), LogicalResourceID: aws.String("Bucket"), ResourceStatus: model.ResourceStatusDeleteComplete},
+			})
+
+		got := []string{}
+		output, err := deleter.DeleteCFnStack(context.Background(), &usecase.CFnStackDeleterInput{
+			StackName:    "app",
+			Region:       model.RegionUSEast1,
+			PollInterval: time.Millisecond,
+			OnEvent: func(event *model.StackEvent) {
+				got = append(got, aws.ToString(event.EventID))
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Status != model.StackStatusDeleteComplete {
+			t.Errorf("Status = %s, want %s", output.Status, model.StackStatusDeleteComplete)
+		}
+		if diff := cmp.Diff([]string{"1", "2"}, got); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("report the resources that blocked the deletion", func(t *testing.T) {
+		t.Parallel()
+
+		deleter, _ := newDeleter(t, false,
+			[]model.StackStatus{model.StackStatusCreateComplete, model.StackStatusDeleteFailed},
+			[]*model.StackEvent{
+				{EventID: aws.String("1"), LogicalResourceID: aws.String("Bucket"), PhysicalResourceID: aws.String("bucket"), ResourceStatus: model.ResourceStatusDeleteFailed},
+				{EventID: aws.String("2"), LogicalResourceID: aws.String("app"), PhysicalResourceID: aws.String(stackID), ResourceStatus: model.ResourceStatusDeleteFailed},
+			})
+
+		output, err := deleter.DeleteCFnStack(context.Background(), &usecase.CFnStackDeleterInput{
+			StackName:    "app",
+			Region:       model.RegionUSEast1,
+			PollInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Status != model.StackStatusDeleteFailed {
+			t.Errorf("Status = %s, want %s", output.Status, model.StackStatusDeleteFailed)
+		}
+		if len(output.FailedResources) != 1 || aws.ToString(output.FailedResources[0].LogicalResourceID) != "Bucket" {
+			t.Errorf("FailedResources = %v, want only Bucket", output.FailedResources)
+		}
+	})
+
+	t.Run("do not delete the stack with termination protection", func(t *testing.T) {
+		t.Parallel()
+
+		deleter, token := newDeleter(t, true, []model.StackStatus{model.StackStatusCreateComplete}, nil)
+		_, err := deleter.DeleteCFnStack(context.Background(), &usecase.CFnStackDeleterInput{
+			StackName: "app",
+			Region:    model.RegionUSEast1,
+		})
+		if !errors.Is(err, domain.ErrCFnStackTerminationProtection) {
+			t.Errorf("error = %v, want %v", err, domain.ErrCFnStackTerminationProtection)
+		}
+		if *token != "" {
+			t.Error("DeleteStack must not be called")
+		}
+	})
+
+	t.Run("do not delete the stack whose exports are imported", func(t *testing.T) {
+		t.Parallel()
+
+		describer := mock.CFnStackDescriber(func(_ context.Context, _ *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			return &service.CFnStackDescriberOutput{
+				Stack: &model.Stack{StackName: aws.String("app"), StackID: aws.String(stackID), StackStatus: model.StackStatusCreateComplete},
+				Outputs: model.CFnOutputs{
+					{Key: "Region", Value: "us-east-1"},
+					{Key: "BucketName", Value: "bucket", ExportName: "app-BucketName"},
+				},
+			}, nil
+		})
+		deleter := mock.CFnStackDeleter(func(_ context.Context, _ *service.CFnStackDeleterInput) (*service.CFnStackDeleterOutput, error) {
+			t.Error("DeleteStack must not be called")
+			return &service.CFnStackDeleterOutput{}, nil
+		})
+		importsLister := mock.CFnImportsLister(func(_ context.Context, input *service.CFnImportsListerInput) (*service.CFnImportsListerOutput, error) {
+			if input.ExportName != "app-BucketName" {
+				t.Errorf("ExportName = %s, want app-BucketName", input.ExportName)
+			}
+			return &service.CFnImportsListerOutput{StackNames: []string{"web"}}, nil
+		})
+
+		_, err := NewCFnStackDeleter(describer, deleter, nil, importsLister).DeleteCFnStack(context.Background(), &usecase.CFnStackDeleterInput{
+			StackName: "app",
+			Region:    model.RegionUSEast1,
+		})
+		if !errors.Is(err, domain.ErrCFnStackExportsImported) {
+			t.Errorf("error = %v, want %v", err, domain.ErrCFnStackExportsImported)
+		}
+	})
+}
+
+func TestCFnStackTagger_TagCFnStack(t *testing.T) {
+	t.Parallel()
+
+	const stackID = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+
+	// newTagger returns the tagger whose stack changes the status in the order of statuses on each describe.
+	// The updater mock stores its input to updated.
+	newTagger := func(t *testing.T, statuses []model.StackStatus, updated **service.CFnStackTagsUpdaterInput) *CFnStackTagger {
+		t.Helper()
+		describeCount := 0
+		describer := mock.CFnStackDescriber(func(_ context.Context, _ *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			status := statuses[min(describeCount, len(statuses)-1)]
+			describeCount++
+			return &service.CFnStackDescriberOutput{
+				Stack: &model.Stack{
+					StackName:         aws.String("app"),
+					StackID:           aws.String(stackID),
+					StackStatus:       status,
+					StackStatusReason: aws.String("reason"),
+				},
+				Tags:          model.CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: "infra"}},
+				ParameterKeys: []string{"Env"},
+				Capabilities:  []string{"CAPABILITY_IAM"},
+			}, nil
+		})
+		updater := mock.CFnStackTagsUpdater(func(_ context.Context, input *service.CFnStackTagsUpdaterInput) (*service.CFnStackTagsUpdaterOutput, error) {
+			*updated = input
+			return &service.CFnStackTagsUpdaterOutput{}, nil
+		})
+		eventsDescriber := mock.CFnStackEventsDescriber(func(_ context.Context, _ *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error) {
+			if *updated == nil {
+				return &service.CFnStackEventsDescriberOutput{}, nil
+			}
+			return &service.CFnStackEventsDescriberOutput{
+				Events: []*model.StackEvent{
+					{
+						EventID:            aws.String("1"),
+						ClientRequestToken: aws.String((*updated).ClientRequestToken),
+						LogicalResourceID:  aws.String("Bucket"),
+						PhysicalResourceID: aws.String("bucket"),
+						ResourceStatus:     model.ResourceStatusUpdateFailed,
+					},
+				},
+			}, nil
+		})
+		return NewCFnStackTagger(describer, updater, eventsDescriber)
+	}
+
+	t.Run("update the stack with the previous parameters and the new tag set", func(t *testing.T) {
+		t.Parallel()
+
+		var updated *service.CFnStackTagsUpdaterInput
+		tagger := newTagger(t, []model.StackStatus{model.StackStatusCreateComplete, model.StackStatusUpdateComplete}, &updated)
+		output, err := tagger.TagCFnStack(context.Background(), &usecase.CFnStackTaggerInput{
+			StackName:    "app",
+			Region:       model.RegionUSEast1,
+			Tags:         model.CFnTags{{Key: "env", Value: "prd"}},
+			RemoveKeys:   []string{"team"},
+			PollInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := model.CFnTags{{Key: "env", Value: "prd"}}
+		if diff := cmp.Diff(want, output.Tags); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+		if !output.Changed || output.Status != model.StackStatusUpdateComplete {
+			t.Errorf("Changed = %v, Status = %s, want true, %s", output.Changed, output.Status, model.StackStatusUpdateComplete)
+		}
+		if updated.StackName != stackID {
+			t.Errorf("StackName = %s, want %s", updated.StackName, stackID)
+		}
+		if diff := cmp.Diff(want, updated.Tags); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"Env"}, updated.ParameterKeys); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"CAPABILITY_IAM"}, updated.Capabilities); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("do not update the stack if the tags are not changed", func(t *testing.T) {
+		t.Parallel()
+
+		var updated *service.CFnStackTagsUpdaterInput
+		tagger := newTagger(t, []model.StackStatus{model.StackStatusCreateComplete}, &updated)
+		output, err := tagger.TagCFnStack(context.Background(), &usecase.CFnStackTaggerInput{
+			StackName:  "app",
+			Region:     model.RegionUSEast1,
+			Tags:       model.CFnTags{{Key: "env", Value: "dev"}},
+			RemoveKeys: []string{"owner"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Changed {
+			t.Error("Changed = true, want false")
+		}
+		if updated != nil {
+			t.Error("UpdateStack must not be called")
+		}
+	})
+
+	t.Run("report the resources that failed to be updated", func(t *testing.T) {
+		t.Parallel()
+
+		var updated *service.CFnStackTagsUpdaterInput
+		tagger := newTagger(t, []model.StackStatus{model.StackStatusCreateComplete, model.StackStatusUpdateRollbackComplete}, &updated)
+		output, err := tagger.TagCFnStack(context.Background(), &usecase.CFnStackTaggerInput{
+			StackName:    "app",
+			Region:       model.RegionUSEast1,
+			Tags:         model.CFnTags{{Key: "owner", Value: "nao"}},
+			PollInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Status != model.StackStatusUpdateRollbackComplete {
+			t.Errorf("Status = %s, want %s", output.Status, model.StackStatusUpdateRollbackComplete)
+		}
+		if len(output.FailedResources) != 1 || aws.ToString(output.FailedResources[0].LogicalResourceID) != "Bucket" {
+			t.Errorf("FailedResources = %v, want only Bucket", output.FailedResources)
+		}
+		// The tags are rolled back.
+		want := model.CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: "infra"}}
+		if diff := cmp.Diff(want, output.Tags); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+}
+
+func TestCFnStackDeployPlanner_PlanCFnStackDeploy(t *testing.T) {
+	t.Parallel()
+
+	const (
+		stackID     = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+		changeSetID = "arn:aws:cloudformation:us-east-1:123456789012:changeSet/rainbow/1"
+	)
+
+	// deleted records the change sets and the stacks deleted by the planner.
+	type deleted struct {
+		changeSets []string
+		stacks     []string
+	}
+
+	// newPlanner returns the planner for the stack in the status (empty means the stack does not exist).
+	// The change set becomes changeSet after it is pending once.
+	newPlanner := func(t *testing.T, status model.StackStatus, changeSet *service.CFnChangeSetDescriberOutput, created *service.CFnChangeSetCreatorInput, d *deleted) *CFnStackDeployPlanner {
+		t.Helper()
+		describer := mock.CFnStackDescriber(func(_ context.Context, input *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			if status == "" {
+				return nil, domain.ErrCFnStackNotFound
+			}
+			return &service.CFnStackDescriberOutput{
+				Stack: &model.Stack{StackName: aws.String(input.StackName), StackID: aws.String(stackID), StackStatus: status},
+			}, nil
+		})
+		creator := mock.CFnChangeSetCreator(func(_ context.Context, input *service.CFnChangeSetCreatorInput) (*service.CFnChangeSetCreatorOutput, error) {
+			*created = *input
+			return &service.CFnChangeSetCreatorOutput{ChangeSetID: changeSetID, StackID: stackID}, nil
+		})
+		describeCount := 0
+		changeSetDescriber := mock.CFnChangeSetDescriber(func(_ context.Context, input *service.CFnChangeSetDescriberInput) (*service.CFnChangeSetDescriberOutput, error) {
+			if input.ChangeSetName != changeSetID {
+				t.Errorf("ChangeSetName = %s, want %s", input.ChangeSetName, changeSetID)
+			}
+			describeCount++
+			if describeCount == 1 {
+				return &service.CFnChangeSetDescriberOutput{Status: model.CFnChangeSetStatusCreatePending}, nil
+			}
+			return changeSet, nil
+		})
+		changeSetDeleter := mock.CFnChangeSetDeleter(func(_ context.Context, input *service.CFnChangeSetDeleterInput) (*service.CFnChangeSetDeleterOutput, error) {
+			d.changeSets = append(d.changeSets, input.ChangeSetName)
+			return &service.CFnChangeSetDeleterOutput{}, nil
+		})
+		stackDeleter := mock.CFnStackDeleter(func(_ context.Context, input *service.CFnStackDeleterInput) (*service.CFnStackDeleterOutput, error) {
+			d.stacks = append(d.stacks, input.StackName)
+			return &service.CFnStackDeleterOutput{}, nil
+		})
+		return NewCFnStackDeployPlanner(describer, creator, changeSetDescriber, changeSetDeleter, stackDeleter)
+	}
+
+	changes := []*model.CFnResourceChange{
+		{Action: model.CFnChangeActionAdd, LogicalResourceID: "Bucket", ResourceType: "AWS::S3::Bucket"},
+	}
+	input := &usecase.CFnStackDeployPlannerInput{
+		StackName:    "app",
+		Region:       model.RegionUSEast1,
+		TemplateBody: "Resources: {}",
+		Parameters:   model.CFnParameters{{Key: "Env", Value: "dev"}},
+		Capabilities: []model.CFnCapability{model.CFnCapabilityIAM},
+		PollInterval: time.Millisecond,
+	}
+
+	tests := []struct {
+		name          string
+		status        model.StackStatus
+		changeSet     *service.CFnChangeSetDescriberOutput
+		want          *usecase.CFnStackDeployPlannerOutput
+		wantErr       error
+		wantDeleted   deleted
+		wantCreatedAs model.CFnChangeSetType
+	}{
+		{
+			name:      "create the stack that does not exist",
+			changeSet: &service.CFnChangeSetDescriberOutput{Status: model.CFnChangeSetStatusCreateComplete, Changes: changes},
+			want: &usecase.CFnStackDeployPlannerOutput{
+				ChangeSetID: changeSetID, StackID: stackID, ChangeSetType: model.CFnChangeSetTypeCreate, CreatedStack: true, Changes: changes,
+			},
+			wantCreatedAs: model.CFnChangeSetTypeCreate,
+		},
+		{
+			name:      "create the stack that has only the change set that has not been executed",
+			status:    model.StackStatusReviewInProgress,
+			changeSet: &service.CFnChangeSetDescriberOutput{Status: model.CFnChangeSetStatusCreateComplete, Changes: changes},
+			want: &usecase.CFnStackDeployPlannerOutput{
+				ChangeSetID: changeSetID, StackID: stackID, ChangeSetType: model.CFnChangeSetTypeCreate, Changes: changes,
+			},
+			wantCreatedAs: model.CFnChangeSetTypeCreate,
+		},
+		{
+			name:      "update the existing stack",
+			status:    model.StackStatusUpdateComplete,
+			changeSet: &service.CFnChangeSetDescriberOutput{Status: model.CFnChangeSetStatusCreateComplete, Changes: changes},
+			want: &usecase.CFnStackDeployPlannerOutput{
+				ChangeSetID: changeSetID, StackID: stackID, ChangeSetType: model.CFnChangeSetTypeUpdate, Changes: changes,
+			},
+			wantCreatedAs: model.CFnChangeSetTypeUpdate,
+		},
+		{
+			name:   "delete the change set that contains no changes",
+			status: model.StackStatusUpdateComplete,
+			changeSet: &service.CFnChangeSetDescriberOutput{
+				Status:       model.CFnChangeSetStatusFailed,
+				StatusReason: "The submitted information didn't contain changes. Submit different information to create a change set.",
+			},
+			want: &usecase.CFnStackDeployPlannerOutput{
+				StackID: stackID, ChangeSetType: model.CFnChangeSetTypeUpdate, Changes: []*model.CFnResourceChange{}, NoChanges: true,
+			},
+			wantDeleted:   deleted{changeSets: []string{changeSetID}},
+			wantCreatedAs: model.CFnChangeSetTypeUpdate,
+		},
+		{
+			name: "delete the failed change set and the stack that has no resources",
+			changeSet: &service.CFnChangeSetDescriberOutput{
+				Status:       model.CFnChangeSetStatusFailed,
+				StatusReason: "Template format error",
+			},
+			wantErr:       domain.ErrCFnChangeSetFailed,
+			wantDeleted:   deleted{changeSets: []string{changeSetID}, stacks: []string{stackID}},
+			wantCreatedAs: model.CFnChangeSetTypeCreate,
+		},
+		{
+			name:   "delete only the failed change set of the stack that existed in REVIEW_IN_PROGRESS",
+			status: model.StackStatusReviewInProgress,
+			changeSet: &service.CFnChangeSetDescriberOutput{
+				Status:       model.CFnChangeSetStatusFailed,
+				StatusReason: "Template format error",
+			},
+			wantErr:       domain.ErrCFnChangeSetFailed,
+			wantDeleted:   deleted{changeSets: []string{changeSetID}},
+			wantCreatedAs: model.CFnChangeSetTypeCreate,
+		},
+		{
+			name:    "the stack whose creation failed can not be updated",
+			status:  model.StackStatusRollbackComplete,
+			wantErr: domain.ErrCFnStackNotUpdatable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var created service.CFnChangeSetCreatorInput
+			var d deleted
+			planner := newPlanner(t, tt.status, tt.changeSet, &created, &d)
+			got, err := planner.PlanCFnStackDeploy(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PlanCFnStackDeploy() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDeleted, d, cmp.AllowUnexported(deleted{})); diff != "" {
+				t.Errorf("deleted differs: (-want +got)\n%s", diff)
+			}
+			if created.ChangeSetType != tt.wantCreatedAs {
+				t.Errorf("ChangeSetType = %s, want %s", created.ChangeSetType, tt.wantCreatedAs)
+			}
+			if tt.wantCreatedAs != "" {
+				if diff := cmp.Diff(input.Parameters, created.Parameters); diff != "" {
+					t.Errorf("parameters differs: (-want +got)\n%s", diff)
+				}
+			}
+		})
+	}
+
+	t.Run("reject the template larger than the limit", func(t *testing.T) {
+		t.Parallel()
+
+		var created service.CFnChangeSetCreatorInput
+		planner := newPlanner(t, "", nil, &created, &deleted{})
+		_, err := planner.PlanCFnStackDeploy(context.Background(), &usecase.CFnStackDeployPlannerInput{
+			StackName:    "app",
+			Region:       model.RegionUSEast1,
+			TemplateBody: string(make([]byte, model.MaxCFnTemplateBodySize+1)),
+		})
+		if !errors.Is(err, domain.ErrCFnTemplateTooLarge) {
+			t.Errorf("PlanCFnStackDeploy() error = %v, want %v", err, domain.ErrCFnTemplateTooLarge)
+		}
+	})
+}
+
+func TestCFnStackDeployCanceler_CancelCFnStackDeploy(t *testing.T) {
+	t.Parallel()
+
+	const (
+		stackID     = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+		changeSetID = "arn:aws:cloudformation:us-east-1:123456789012:changeSet/rainbow/1"
+	)
+
+	tests := []struct {
+		name          string
+		createdStack  bool
+		wantDeleted   []string
+		wantChangeSet string
+	}{
+		{
+			name:          "delete the change set and the stack that the change set created",
+			createdStack:  true,
+			wantDeleted:   []string{stackID},
+			wantChangeSet: changeSetID,
+		},
+		{
+			name:          "keep the stack that existed in REVIEW_IN_PROGRESS before the deploy",
+			createdStack:  false,
+			wantDeleted:   nil,
+			wantChangeSet: changeSetID,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var deletedChangeSet string
+			var deletedStacks []string
+			changeSetDeleter := mock.CFnChangeSetDeleter(func(_ context.Context, input *service.CFnChangeSetDeleterInput) (*service.CFnChangeSetDeleterOutput, error) {
+				deletedChangeSet = input.ChangeSetName
+				return &service.CFnChangeSetDeleterOutput{}, nil
+			})
+			stackDeleter := mock.CFnStackDeleter(func(_ context.Context, input *service.CFnStackDeleterInput) (*service.CFnStackDeleterOutput, error) {
+				deletedStacks = append(deletedStacks, input.StackName)
+				return &service.CFnStackDeleterOutput{}, nil
+			})
+
+			_, err := NewCFnStackDeployCanceler(changeSetDeleter, stackDeleter).CancelCFnStackDeploy(context.Background(), &usecase.CFnStackDeployCancelerInput{
+				ChangeSetID:  changeSetID,
+				StackID:      stackID,
+				Region:       model.RegionUSEast1,
+				CreatedStack: tt.createdStack,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deletedChangeSet != tt.wantChangeSet {
+				t.Errorf("deleted change set = %s, want %s", deletedChangeSet, tt.wantChangeSet)
+			}
+			if diff := cmp.Diff(tt.wantDeleted, deletedStacks); diff != "" {
+				t.Errorf("deleted stacks differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCFnStackDeployer_DeployCFnStack(t *testing.T) {
+	t.Parallel()
+
+	const stackID = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+
+	t.Run("wait for the update even if the stack is still in the previous final status", func(t *testing.T) {
+		t.Parallel()
+
+		// The stack is UPDATE_COMPLETE of the previous update until the events of this update appear.
+		statuses := []model.StackStatus{
+			model.StackStatusUpdateComplete, // before the execution
+			model.StackStatusUpdateComplete, // the update has not started yet
+			model.StackStatusUpdateComplete, // the update has started, but the status is described before the events
+			model.StackStatusUpdateInProgress,
+			model.StackStatusUpdateComplete,
+		}
+		updated := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+		describeCount := 0
+		describer := mock.CFnStackDescriber(func(_ context.Context, _ *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			status := statuses[min(describeCount, len(statuses)-1)]
+			describeCount++
+			return &service.CFnStackDescriberOutput{
+				Stack: &model.Stack{StackID: aws.String(stackID), StackStatus: status, LastUpdatedTime: &updated},
+			}, nil
+		})
+		var token string
+		executor := mock.CFnChangeSetExecutor(func(_ context.Context, input *service.CFnChangeSetExecutorInput) (*service.CFnChangeSetExecutorOutput, error) {
+			token = input.ClientRequestToken
+			return &service.CFnChangeSetExecutorOutput{}, nil
+		})
+		eventsCount := 0
+		eventsDescriber := mock.CFnStackEventsDescriber(func(_ context.Context, _ *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error) {
+			eventsCount++
+			if eventsCount == 1 {
+				return &service.CFnStackEventsDescriberOutput{}, nil
+			}
+			return &service.CFnStackEventsDescriberOutput{
+				Events: []*model.StackEvent{
+					{EventID: aws.String("1"), ClientRequestToken: aws.String(token), ResourceStatus: model.ResourceStatusUpdateInProgress},
+				},
+			}, nil
+		})
+
+		got := 0
+		out, err := NewCFnStackDeployer(describer, executor, eventsDescriber).DeployCFnStack(context.Background(), &usecase.CFnStackDeployerInput{
+			ChangeSetID:  "change-set",
+			StackID:      stackID,
+			StackName:    "app",
+			Region:       model.RegionUSEast1,
+			OnEvent:      func(_ *model.StackEvent) { got++ },
+			PollInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Status != model.StackStatusUpdateComplete {
+			t.Errorf("Status = %s, want %s", out.Status, model.StackStatusUpdateComplete)
+		}
+		if describeCount != len(statuses) {
+			t.Errorf("DescribeCFnStack() is called %d times, want %d", describeCount, len(statuses))
+		}
+		if got != 1 {
+			t.Errorf("OnEvent() is called %d times, want 1", got)
+		}
+	})
+}
+
+func TestCFnStackEventsTailer_TailCFnStackEvents(t *testing.T) {
+	t.Parallel()
+
+	const (
+		rootID   = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+		nestedID = "arn:aws:cloudformation:us-east-1:123456789012:stack/app-Nested-1/2"
+	)
+	base := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	at := func(sec int) *time.Time {
+		ts := base.Add(time.Duration(sec) * time.Second)
+		return &ts
+	}
+
+	t.Run("follow the events of the stack and the nested stack until the terminal status", func(t *testing.T) {
+		t.Parallel()
+
+		statuses := []model.StackStatus{
+			model.StackStatusUpdateInProgress, // the first describe resolves the stack ID
+			model.StackStatusUpdateInProgress,
+			model.StackStatusUpdateComplete,
+		}
+		describeCount := 0
+		describer := mock.CFnStackDescriber(func(_ context.Context, _ *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			status := statuses[min(describeCount, len(statuses)-1)]
+			describeCount++
+			return &service.CFnStackDescriberOutput{
+				Stack: &model.Stack{StackName: aws.String("app"), StackID: aws.String(rootID), StackStatus: status},
+			}, nil
+		})
+
+		// The events are returned in reverse chronological order, and the same events are returned on each poll.
+		pollCount := map[string]int{}
+		eventsDescriber := mock.CFnStackEventsDescriber(func(_ context.Context, input *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error) {
+			pollCount[input.StackName]++
+			switch input.StackName {
+			case rootID:
+				events := []*model.StackEvent{
+					{EventID: aws.String("root-2"), StackName: aws.String("app"), Timestamp: at(2), ResourceType: aws.String("AWS::CloudFormation::Stack"), PhysicalResourceID: aws.String(nestedID)},
+					{EventID: aws.String("root-1"), StackName: aws.String("app"), Timestamp: at(0), ResourceType: aws.String("AWS::CloudFormation::Stack"), PhysicalResourceID: aws.String(rootID)},
+				}
+				if pollCount[rootID] > 1 {
+					events = append([]*model.StackEvent{
+						{EventID: aws.String("root-3"), StackName: aws.String("app"), Timestamp: at(5), ResourceType: aws.String("AWS::CloudFormation::Stack"), PhysicalResourceID: aws.String(rootID)},
+					}, events...)
+				}
+				return &service.CFnStackEventsDescriberOutput{Events: events}, nil
+			case nestedID:
+				return &service.CFnStackEventsDescriberOutput{Events: []*model.StackEvent{
+					{EventID: aws.String("nested-1"), StackName: aws.String("app-Nested-1"), Timestamp: at(1), ResourceType: aws.String("AWS::S3::Bucket"), PhysicalResourceID: aws.String("bucket")},
+				}}, nil
+			default:
+				t.Errorf("unexpected stack: %s", input.StackName)
+				return &service.CFnStackEventsDescriberOutput{}, nil
+			}
+		})
+
+		got := []string{}
+		out, err := NewCFnStackEventsTailer(describer, eventsDescriber).TailCFnStackEvents(context.Background(), &usecase.CFnStackEventsTailerInput{
+			StackName:    "app",
+			Region:       model.RegionUSEast1,
+			Follow:       true,
+			OnEvent:      func(event *model.StackEvent) { got = append(got, aws.ToString(event.EventID)) },
+			PollInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Status != model.StackStatusUpdateComplete {
+			t.Errorf("Status = %s, want %s", out.Status, model.StackStatusUpdateComplete)
+		}
+		// The events of the first poll are sorted across the stacks. The events of the second poll follow them.
+		if diff := cmp.Diff([]string{"root-1", "nested-1", "root-2", "root-3"}, got); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("report the events once without follow", func(t *testing.T) {
+		t.Parallel()
+
+		describer := mock.CFnStackDescriber(func(_ context.Context, _ *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+			return &service.CFnStackDescriberOutput{
+				Stack: &model.Stack{StackName: aws.String("app"), StackID: aws.String(rootID), StackStatus: model.StackStatusUpdateInProgress},
+			}, nil
+		})
+		since := base.Add(-time.Hour)
+		eventsDescriber := mock.CFnStackEventsDescriber(func(_ context.Context, input *service.CFnStackEventsDescriberInput) (*service.CFnStackEventsDescriberOutput, error) {
+			if !input.Since.Equal(since) {
+				t.Errorf("Since = %s, want %s", input.Since, since)
+			}
+			return &service.CFnStackEventsDescriberOutput{Events: []*model.StackEvent{
+				{EventID: aws.String("root-1"), Timestamp: at(0)},
+			}}, nil
+		})
+
+		calls := 0
+		_, err := NewCFnStackEventsTailer(describer, eventsDescriber).TailCFnStackEvents(context.Background(), &usecase.CFnStackEventsTailerInput{
+			StackName: "app",
+			Region:    model.RegionUSEast1,
+			Since:     since,
+			OnEvent:   func(_ *model.StackEvent) { calls++ },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 1 {
+			t.Errorf("OnEvent() is called %d times, want 1", calls)
+		}
+	})
+}
+
+func TestCFnStackResourceLister_ListCFnStackResource(t *testing.T) {
+	t.Parallel()
+
+	const (
+		rootID   = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+		nestedID = "arn:aws:cloudformation:us-east-1:123456789012:stack/app-Nested-1/2"
+		deepID   = "arn:aws:cloudformation:us-east-1:123456789012:stack/app-Nested-1-Deep-1/3"
+		otherID  = "arn:aws:cloudformation:us-east-1:123456789012:stack/other-Nested-1/4"
+	)
+	describer := mock.CFnStackDescriber(func(_ context.Context, _ *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+		return &service.CFnStackDescriberOutput{
+			Stack: &model.Stack{StackName: aws.String("app"), StackID: aws.String(rootID)},
+		}, nil
+	})
+	stackLister := mock.CFnStackLister(func(_ context.Context, _ *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
+		return &service.CFnStackListerOutput{Stacks: []*model.Stack{
+			{StackID: aws.String(rootID)},
+			{StackID: aws.String(nestedID), ParentID: aws.String(rootID), RootID: aws.String(rootID)},
+			{StackID: aws.String(deepID), ParentID: aws.String(nestedID), RootID: aws.String(rootID)},
+			{StackID: aws.String(otherID), ParentID: aws.String("other"), RootID: aws.String("other")},
+		}}, nil
+	})
+	resourceLister := mock.CFnStackResourceLister(func(_ context.Context, input *service.CFnStackResourceListerInput) (*service.CFnStackResourceListerOutput, error) {
+		nested := aws.String(model.CFnNestedStackResourceType)
+		switch input.StackName {
+		case rootID:
+			return &service.CFnStackResourceListerOutput{Resources: []*model.StackResource{
+				{LogicalResourceID: aws.String("Nested"), ResourceType: nested, PhysicalResourceID: aws.String(nestedID)},
+				{LogicalResourceID: aws.String("Bucket"), ResourceType: aws.String("AWS::S3::Bucket"), PhysicalResourceID: aws.String("bucket")},
+				// The stack that is not nested in this stack is not expanded.
+				{LogicalResourceID: aws.String("Other"), ResourceType: nested, PhysicalResourceID: aws.String(otherID)},
+			}}, nil
+		case nestedID:
+			return &service.CFnStackResourceListerOutput{Resources: []*model.StackResource{
+				{LogicalResourceID: aws.String("Deep"), ResourceType: nested, PhysicalResourceID: aws.String(deepID)},
+			}}, nil
+		case deepID:
+			return &service.CFnStackResourceListerOutput{Resources: []*model.StackResource{
+				{LogicalResourceID: aws.String("Queue"), ResourceType: aws.String("AWS::SQS::Queue"), PhysicalResourceID: aws.String("queue")},
+			}}, nil
+		default:
+			t.Errorf("unexpected stack: %s", input.StackName)
+			return &service.CFnStackResourceListerOutput{}, nil
+		}
+	})
+
+	// tree returns the logical IDs of the resources with the depth.
+	var tree func(nodes []*model.StackResourceNode, indent string) []string
+	tree = func(nodes []*model.StackResourceNode, indent string) []string {
+		got := []string{}
+		for _, node := range nodes {
+			got = append(got, indent+aws.ToString(node.LogicalResourceID))
+			got = append(got, tree(node.Children, indent+"  ")...)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name      string
+		recursive bool
+		want      []string
+	}{
+		{
+			name:      "list the resources of the stack",
+			recursive: false,
+			want:      []string{"Bucket", "Nested", "Other"},
+		},
+		{
+			name:      "expand the nested stacks recursively",
+			recursive: true,
+			want:      []string{"Bucket", "Nested", "  Deep", "    Queue", "Other"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := NewCFnStackResourceLister(describer, stackLister, resourceLister).ListCFnStackResource(context.Background(), &usecase.CFnStackResourceListerInput{
+				StackName: "app",
+				Region:    model.RegionUSEast1,
+				Recursive: tt.recursive,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, tree(out.Resources, "")); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCFnStackDriftDetector_DetectCFnStackDrift(t *testing.T) {
+	t.Parallel()
+
+	bucketDrift := &model.CFnStackResourceDrift{
+		LogicalResourceID: "Bucket",
+		ResourceType:      "AWS::S3::Bucket",
+		DriftStatus:       model.StackResourceDriftStatusModified,
+		PropertyDifferences: []model.CFnPropertyDifference{
+			{PropertyPath: "/VersioningConfiguration/Status", ExpectedValue: "Enabled", ActualValue: "Suspended", DifferenceType: model.CFnPropertyDifferenceTypeNotEqual},
+		},
+	}
+
+	t.Run("wait for the detection of each stack and describe the drifted resources", func(t *testing.T) {
+		t.Parallel()
+
+		detector := mock.CFnStackDriftDetector(func(_ context.Context, input *service.CFnStackDriftDetectorInput) (*service.CFnStackDriftDetectorOutput, error) {
+			return &service.CFnStackDriftDetectorOutput{DetectionID: input.StackName + "-detection"}, nil
+		})
+		var mu sync.Mutex
+		polls := map[string]int{}
+		statusDescriber := mock.CFnStackDriftDetectionStatusDescriber(func(_ context.Context, input *service.CFnStackDriftDetectionStatusDescriberInput) (*service.CFnStackDriftDetectionStatusDescriberOutput, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			polls[input.DetectionID]++
+			if polls[input.DetectionID] == 1 {
+				return &service.CFnStackDriftDetectionStatusDescriberOutput{DetectionStatus: model.CFnDriftDetectionStatusInProgress}, nil
+			}
+			if input.DetectionID == "drifted-detection" {
+				return &service.CFnStackDriftDetectionStatusDescriberOutput{
+					DetectionStatus:      model.CFnDriftDetectionStatusComplete,
+					DriftStatus:          model.StackDriftStatusDrifted,
+					DriftedResourceCount: 1,
+				}, nil
+			}
+			return &service.CFnStackDriftDetectionStatusDescriberOutput{
+				DetectionStatus: model.CFnDriftDetectionStatusComplete,
+				DriftStatus:     model.StackDriftStatusInSync,
+			}, nil
+		})
+		driftsDescriber := mock.CFnStackResourceDriftsDescriber(func(_ context.Context, input *service.CFnStackResourceDriftsDescriberInput) (*service.CFnStackResourceDriftsDescriberOutput, error) {
+			if input.StackName != "drifted" {
+				t.Errorf("the resource drifts of %s are described, but it is in sync", input.StackName)
+			}
+			return &service.CFnStackResourceDriftsDescriberOutput{Drifts: []*model.CFnStackResourceDrift{bucketDrift}}, nil
+		})
+
+		out, err := NewCFnStackDriftDetector(detector, statusDescriber, driftsDescriber).DetectCFnStackDrift(context.Background(), &usecase.CFnStackDriftDetectorInput{
+			StackNames:   []string{"drifted", "in-sync"},
+			Region:       model.RegionUSEast1,
+			PollInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []*model.CFnStackDrift{
+			{
+				StackName:            "drifted",
+				DetectionStatus:      model.CFnDriftDetectionStatusComplete,
+				DriftStatus:          model.StackDriftStatusDrifted,
+				DriftedResourceCount: 1,
+				Resources:            []*model.CFnStackResourceDrift{bucketDrift},
+			},
+			{
+				StackName:       "in-sync",
+				DetectionStatus: model.CFnDriftDetectionStatusComplete,
+				DriftStatus:     model.StackDriftStatusInSync,
+				Resources:       []*model.CFnStackResourceDrift{},
+			},
+		}
+		if diff := cmp.Diff(want, out.Drifts); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("record the error of the stack and continue the detection of the other stacks", func(t *testing.T) {
+		t.Parallel()
+
+		detector := mock.CFnStackDriftDetector(func(_ context.Context, input *service.CFnStackDriftDetectorInput) (*service.CFnStackDriftDetectorOutput, error) {
+			if input.StackName == "deleted" {
+				return nil, fmt.Errorf("%w: %s", domain.ErrCFnStackNotFound, input.StackName)
+			}
+			return &service.CFnStackDriftDetectorOutput{DetectionID: input.StackName + "-detection"}, nil
+		})
+		statusDescriber := mock.CFnStackDriftDetectionStatusDescriber(func(_ context.Context, _ *service.CFnStackDriftDetectionStatusDescriberInput) (*service.CFnStackDriftDetectionStatusDescriberOutput, error) {
+			return &service.CFnStackDriftDetectionStatusDescriberOutput{
+				DetectionStatus: model.CFnDriftDetectionStatusComplete,
+				DriftStatus:     model.StackDriftStatusInSync,
+			}, nil
+		})
+		out, err := NewCFnStackDriftDetector(detector, statusDescriber, nil).DetectCFnStackDrift(context.Background(), &usecase.CFnStackDriftDetectorInput{
+			StackNames:   []string{"deleted", "app"},
+			Region:       model.RegionUSEast1,
+			Concurrency:  1,
+			PollInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Drifts) != 2 {
+			t.Fatalf("len(Drifts) = %d, want 2", len(out.Drifts))
+		}
+
+		failed := out.Drifts[0]
+		if failed.StackName != "deleted" || !errors.Is(failed.Err, domain.ErrCFnStackNotFound) {
+			t.Errorf("Drifts[0] = %+v, want the stack not found error of deleted", failed)
+		}
+		if failed.Err == nil || !strings.Contains(failed.Err.Error(), "deleted") {
+			t.Errorf("error = %v, want the error with the stack name", failed.Err)
+		}
+		want := &model.CFnStackDrift{
+			StackName:       "app",
+			DetectionStatus: model.CFnDriftDetectionStatusComplete,
+			DriftStatus:     model.StackDriftStatusInSync,
+			Resources:       []*model.CFnStackResourceDrift{},
+		}
+		if diff := cmp.Diff(want, out.Drifts[1]); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("return the error if the context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		detector := mock.CFnStackDriftDetector(func(ctx context.Context, _ *service.CFnStackDriftDetectorInput) (*service.CFnStackDriftDetectorOutput, error) {
+			return nil, ctx.Err()
+		})
+		_, err := NewCFnStackDriftDetector(detector, nil, nil).DetectCFnStackDrift(ctx, &usecase.CFnStackDriftDetectorInput{
+			StackNames: []string{"app"},
+			Region:     model.RegionUSEast1,
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestCFnExportsLister_ListCFnExports(t *testing.T) {
+	t.Parallel()
+
+	const (
+		appID = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1"
+		dbID  = "arn:aws:cloudformation:us-east-1:123456789012:stack/db/2"
+	)
+	exportsLister := mock.CFnExportsLister(func(_ context.Context, _ *service.CFnExportsListerInput) (*service.CFnExportsListerOutput, error) {
+		// The importers are set by the interactor, so the mock returns the new exports on each call.
+		return &service.CFnExportsListerOutput{Exports: []*model.CFnExport{
+			{Name: "db-Endpoint", Value: "db.example.com", ExportingStackID: dbID},
+			{Name: "app-BucketName", Value: "bucket", ExportingStackID: appID},
+		}}, nil
+	})
+	importsLister := mock.CFnImportsLister(func(_ context.Context, input *service.CFnImportsListerInput) (*service.CFnImportsListerOutput, error) {
+		if input.ExportName == "db-Endpoint" {
+			return &service.CFnImportsListerOutput{StackNames: []string{"web", "app"}}, nil
+		}
+		return &service.CFnImportsListerOutput{StackNames: []string{}}, nil
+	})
+
+	tests := []struct {
+		name  string
+		input *usecase.CFnExportsListerInput
+		want  []*model.CFnExport
+	}{
+		{
+			name:  "list all exports sorted by the name without importers",
+			input: &usecase.CFnExportsListerInput{Region: model.RegionUSEast1},
+			want: []*model.CFnExport{
+				{Name: "app-BucketName", Value: "bucket", ExportingStackID: appID},
+				{Name: "db-Endpoint", Value: "db.example.com", ExportingStackID: dbID},
+			},
+		},
+		{
+			name:  "list the exports of the stack with importers",
+			input: &usecase.CFnExportsListerInput{Region: model.RegionUSEast1, StackName: "db", Importers: true},
+			want: []*model.CFnExport{
+				{Name: "db-Endpoint", Value: "db.example.com", ExportingStackID: dbID, Importers: []string{"app", "web"}},
+			},
+		},
+		{
+			name:  "the exports without importers have the empty importers",
+			input: &usecase.CFnExportsListerInput{Region: model.RegionUSEast1, StackName: appID, Importers: true},
+			want: []*model.CFnExport{
+				{Name: "app-BucketName", Value: "bucket", ExportingStackID: appID, Importers: []string{}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := NewCFnExportsLister(exportsLister, importsLister).ListCFnExports(context.Background(), tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, out.Exports); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCFnTemplateGetter_GetCFnTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get the original template by default", func(t *testing.T) {
+		t.Parallel()
+
+		getter := mock.CFnTemplateGetter(func(_ context.Context, input *service.CFnTemplateGetterInput) (*service.CFnTemplateGetterOutput, error) {
+			if input.Stage != model.CFnTemplateStageOriginal {
+				t.Errorf("unexpected stage: %s", input.Stage)
+			}
+			return &service.CFnTemplateGetterOutput{TemplateBody: "Resources: {}"}, nil
+		})
+		out, err := NewCFnTemplateGetter(getter).GetCFnTemplate(context.Background(), &usecase.CFnTemplateGetterInput{
+			StackName: "app",
+			Region:    model.RegionUSEast1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.TemplateBody != "Resources: {}" {
+			t.Errorf("unexpected template: %s", out.TemplateBody)
+		}
+	})
+
+	t.Run("stack not found", func(t *testing.T) {
+		t.Parallel()
+
+		getter := mock.CFnTemplateGetter(func(_ context.Context, _ *service.CFnTemplateGetterInput) (*service.CFnTemplateGetterOutput, error) {
+			return nil, domain.ErrCFnStackNotFound
+		})
+		_, err := NewCFnTemplateGetter(getter).GetCFnTemplate(context.Background(), &usecase.CFnTemplateGetterInput{
+			StackName: "app",
+			Region:    model.RegionUSEast1,
+			Stage:     model.CFnTemplateStageProcessed,
+		})
+		if !errors.Is(err, domain.ErrCFnStackNotFound) {
+			t.Errorf("want ErrCFnStackNotFound, got %v", err)
+		}
+	})
+}
+
+func TestCFnTemplateDiffer_DiffCFnTemplate(t *testing.T) {
+	t.Parallel()
+
+	getter := mock.CFnTemplateGetter(func(_ context.Context, input *service.CFnTemplateGetterInput) (*service.CFnTemplateGetterOutput, error) {
+		if input.Stage != model.CFnTemplateStageOriginal {
+			t.Errorf("unexpected stage: %s", input.Stage)
+		}
+		return &service.CFnTemplateGetterOutput{TemplateBody: `{
+  "Resources": {
+    "Bucket": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {"BucketName": {"Fn::Sub": "${AWS::StackName}-bucket"}}
+    }
+  }
+}`}, nil
+	})
+
+	tests := []struct {
+		name    string
+		local   string
+		want    []model.CFnTemplateDifference
+		wantErr bool
+	}{
+		{
+			name: "no differences between the short form in YAML and the long form in JSON",
+			local: `Resources:
+  Bucket:
+    Properties:
+      BucketName: !Sub ${AWS::StackName}-bucket
+    Type: AWS::S3::Bucket
+`,
+			want: []model.CFnTemplateDifference{},
+		},
+		{
+			name: "the changed property",
+			local: `Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub ${AWS::StackName}-logs
+`,
+			want: []model.CFnTemplateDifference{
+				{
+					Path: "Resources.Bucket.Properties.BucketName.Fn::Sub",
+					Type: model.CFnTemplateDifferenceTypeChange,
+					Old:  `"${AWS::StackName}-bucket"`,
+					New:  `"${AWS::StackName}-logs"`,
+				},
+			},
+		},
+		{
+			name:    "invalid local template",
+			local:   "- not a mapping",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := NewCFnTemplateDiffer(getter).DiffCFnTemplate(context.Background(), &usecase.CFnTemplateDifferInput{
+				StackName:    "app",
+				Region:       model.RegionUSEast1,
+				TemplateBody: []byte(tt.local),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, out.Differences); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCFnStaleStacksFinder_FindCFnStaleStacks(t *testing.T) {
+	t.Parallel()
+
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	stackLister := mock.CFnStackLister(func(_ context.Context, _ *service.CFnStackListerInput) (*service.CFnStackListerOutput, error) {
+		return &service.CFnStackListerOutput{
+			Stacks: []*model.Stack{
+				{StackName: aws.String("network"), StackID: aws.String("id-network"), StackStatus: model.StackStatusDeleteFailed, CreationTime: &old},
+				{StackName: aws.String("app"), StackID: aws.String("id-app"), StackStatus: model.StackStatusRollbackComplete, CreationTime: &old},
+				{StackName: aws.String("db"), StackID: aws.String("id-db"), StackStatus: model.StackStatusDeleteFailed, CreationTime: &old},
+				{StackName: aws.String("protected"), StackID: aws.String("id-protected"), StackStatus: model.StackStatusReviewInProgress, CreationTime: &old},
+				{StackName: aws.String("deploying"), StackID: aws.String("id-deploying"), StackStatus: model.StackStatusReviewInProgress, CreationTime: &recent},
+				{StackName: aws.String("web"), StackID: aws.String("id-web"), StackStatus: model.StackStatusUpdateComplete, CreationTime: &old},
+			},
+		}, nil
+	})
+	describer := mock.CFnStackDescriber(func(_ context.Context, input *service.CFnStackDescriberInput) (*service.CFnStackDescriberOutput, error) {
+		switch input.StackName {
+		case "id-network":
+			return &service.CFnStackDescriberOutput{Outputs: model.CFnOutputs{{Key: "VpcID", ExportName: "network-VpcID"}}}, nil
+		case "id-db":
+			return &service.CFnStackDescriberOutput{Outputs: model.CFnOutputs{{Key: "Endpoint", ExportName: "db-Endpoint"}}}, nil
+		case "id-protected":
+			return &service.CFnStackDescriberOutput{TerminationProtection: true}, nil
+		case "id-app":
+			return &service.CFnStackDescriberOutput{}, nil
+		default:
+			t.Errorf("unexpected stack is described: %s", input.StackName)
+			return nil, domain.ErrCFnStackNotFound
+		}
+	})
+	importsLister := mock.CFnImportsLister(func(_ context.Context, input *service.CFnImportsListerInput) (*service.CFnImportsListerOutput, error) {
+		switch input.ExportName {
+		case "network-VpcID":
+			return &service.CFnImportsListerOutput{StackNames: []string{"app"}}, nil
+		case "db-Endpoint":
+			return &service.CFnImportsListerOutput{StackNames: []string{"web"}}, nil
+		default:
+			return &service.CFnImportsListerOutput{StackNames: []string{}}, nil
+		}
+	})
+
+	out, err := NewCFnStaleStacksFinder(stackLister, describer, importsLister).FindCFnStaleStacks(context.Background(), &usecase.CFnStaleStacksFinderInput{
+		Region:    model.RegionUSEast1,
+		OlderThan: model.CFnCleanupDefaultAge,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Name          string
+		DependsOn     []string
+		BlockedReason string
+	}
+	toResults := func(stacks []*model.CFnStaleStack) []result {
+		results := make([]result, 0, len(stacks))
+		for _, s := range stacks {
+			results = append(results, result{Name: *s.Stack.StackName, DependsOn: s.DependsOn, BlockedReason: s.BlockedReason})
+		}
+		return results
+	}
+	wantDeletable := []result{
+		{Name: "app"},
+		{Name: "network", DependsOn: []string{"app"}},
+	}
+	if diff := cmp.Diff(wantDeletable, toResults(out.Deletable)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+	wantBlocked := []result{
+		{Name: "db", BlockedReason: "db-Endpoint is imported by web"},
+		{Name: "protected", BlockedReason: "termination protection is enabled"},
+	}
+	if diff := cmp.Diff(wantBlocked, toResults(out.Blocked)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
 }
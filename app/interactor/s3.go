@@ -3,11 +3,16 @@ package interactor
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/wire"
+	"github.com/nao1215/rainbow/app/domain"
 	"github.com/nao1215/rainbow/app/domain/model"
 	"github.com/nao1215/rainbow/app/domain/service"
 	"github.com/nao1215/rainbow/app/usecase"
+	"golang.org/x/sync/errgroup"
 )
 
 // S3BucketCreatorSet is a provider set for S3BucketCreator.
@@ -207,13 +212,18 @@ func (s *S3ObjectsDeleter) DeleteS3Objects(ctx context.Context, input *usecase.S
 		}
 	}
 
-	if _, err = s.S3ObjectsDeleter.DeleteS3Objects(ctx, &service.S3ObjectsDeleterInput{
+	out, err := s.S3ObjectsDeleter.DeleteS3Objects(ctx, &service.S3ObjectsDeleterInput{
 		Bucket:       input.Bucket,
 		Region:       location.Region,
 		S3ObjectSets: targets,
-	}); err != nil {
+	})
+	if err != nil {
 		return nil, err
 	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%w: %d objects in %s (e.g. key=%s, %s)",
+			domain.ErrS3ObjectsDelete, len(out.Errors), input.Bucket, out.Errors[0].S3Key, out.Errors[0].Error())
+	}
 
 	return &usecase.S3ObjectsDeleterOutput{}, nil
 }
@@ -476,3 +486,461 @@ func (s *S3ObjectCopier) CopyS3Object(ctx context.Context, input *usecase.S3Obje
 	}
 	return &usecase.S3ObjectCopierOutput{}, nil
 }
+
+// S3BucketReporterSet is a provider set for S3BucketReporter.
+//
+//nolint:gochecknoglobals
+var S3BucketReporterSet = wire.NewSet(
+	NewS3BucketReporter,
+	wire.Bind(new(usecase.S3BucketReporter), new(*S3BucketReporter)),
+)
+
+var _ usecase.S3BucketReporter = (*S3BucketReporter)(nil)
+
+// S3BucketReporter is an implementation for S3BucketReporter.
+type S3BucketReporter struct {
+	service.S3ObjectDetailsLister
+	service.S3ObjectVersionsLister
+	service.S3MultipartUploadsLister
+}
+
+// NewS3BucketReporter returns a new S3BucketReporter struct.
+func NewS3BucketReporter(
+	d service.S3ObjectDetailsLister,
+	v service.S3ObjectVersionsLister,
+	m service.S3MultipartUploadsLister,
+) *S3BucketReporter {
+	return &S3BucketReporter{
+		S3ObjectDetailsLister:    d,
+		S3ObjectVersionsLister:   v,
+		S3MultipartUploadsLister: m,
+	}
+}
+
+// ReportS3Bucket analyzes all objects, versions and incomplete multipart uploads in the bucket.
+func (s *S3BucketReporter) ReportS3Bucket(ctx context.Context, input *usecase.S3BucketReporterInput) (*usecase.S3BucketReporterOutput, error) {
+	if err := input.Bucket.Validate(); err != nil {
+		return nil, err
+	}
+
+	objects, err := s.S3ObjectDetailsLister.ListS3ObjectDetails(ctx, &service.S3ObjectDetailsListerInput{
+		Bucket: input.Bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.S3ObjectVersionsLister.ListS3ObjectVersions(ctx, &service.S3ObjectVersionsListerInput{
+		Bucket: input.Bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, err := s.S3MultipartUploadsLister.ListS3MultipartUploads(ctx, &service.S3MultipartUploadsListerInput{
+		Bucket: input.Bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecase.S3BucketReporterOutput{
+		Report: model.NewS3BucketReport(
+			input.Bucket, objects.Objects, versions.Versions, uploads.Uploads, input.TopN, time.Now()),
+	}, nil
+}
+
+// S3ObjectsFinderSet is a provider set for S3ObjectsFinder.
+//
+//nolint:gochecknoglobals
+var S3ObjectsFinderSet = wire.NewSet(
+	NewS3ObjectsFinder,
+	wire.Bind(new(usecase.S3ObjectsFinder), new(*S3ObjectsFinder)),
+)
+
+var _ usecase.S3ObjectsFinder = (*S3ObjectsFinder)(nil)
+
+// S3ObjectsFinder is an implementation for S3ObjectsFinder.
+type S3ObjectsFinder struct {
+	service.S3ObjectDetailsLister
+	service.S3ObjectTagsGetter
+	service.S3ObjectMetadataGetter
+}
+
+// NewS3ObjectsFinder returns a new S3ObjectsFinder struct.
+func NewS3ObjectsFinder(
+	l service.S3ObjectDetailsLister,
+	t service.S3ObjectTagsGetter,
+	m service.S3ObjectMetadataGetter,
+) *S3ObjectsFinder {
+	return &S3ObjectsFinder{
+		S3ObjectDetailsLister:  l,
+		S3ObjectTagsGetter:     t,
+		S3ObjectMetadataGetter: m,
+	}
+}
+
+// FindS3Objects finds the objects that match all predicates in the filter.
+// Predicates that need the per-object API (tags, content type) are evaluated only for
+// the objects that match the other predicates.
+func (s *S3ObjectsFinder) FindS3Objects(ctx context.Context, input *usecase.S3ObjectsFinderInput) (*usecase.S3ObjectsFinderOutput, error) {
+	if err := input.Bucket.Validate(); err != nil {
+		return nil, err
+	}
+	filter := input.Filter
+	if filter == nil {
+		filter = &model.S3ObjectFilter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	output, err := s.S3ObjectDetailsLister.ListS3ObjectDetails(ctx, &service.S3ObjectDetailsListerInput{
+		Bucket: input.Bucket,
+		Prefix: input.Prefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	candidates := make(model.S3ObjectDetails, 0, output.Objects.Len())
+	for _, o := range output.Objects {
+		if filter.MatchDetail(o, now) {
+			candidates = append(candidates, o)
+		}
+	}
+	if !filter.NeedsTags() && !filter.NeedsContentType() {
+		return &usecase.S3ObjectsFinderOutput{Objects: candidates}, nil
+	}
+
+	matched := make([]bool, len(candidates))
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(model.MaxS3FindParallelsCount)
+	for i, o := range candidates {
+		i, o := i, o
+		eg.Go(func() error {
+			ok, err := s.matchObject(ctx, input.Bucket, o.S3Key, filter)
+			if err != nil {
+				return err
+			}
+			matched[i] = ok
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	objects := make(model.S3ObjectDetails, 0, len(candidates))
+	for i, o := range candidates {
+		if matched[i] {
+			objects = append(objects, o)
+		}
+	}
+	return &usecase.S3ObjectsFinderOutput{Objects: objects}, nil
+}
+
+// matchObject evaluates the predicates that need the per-object API.
+func (s *S3ObjectsFinder) matchObject(ctx context.Context, bucket model.Bucket, key model.S3Key, filter *model.S3ObjectFilter) (bool, error) {
+	if filter.NeedsContentType() {
+		metadata, err := s.S3ObjectMetadataGetter.GetS3ObjectMetadata(ctx, &service.S3ObjectMetadataGetterInput{
+			Bucket: bucket,
+			S3Key:  key,
+		})
+		if err != nil {
+			return false, err
+		}
+		if !filter.MatchContentType(metadata.ContentType) {
+			return false, nil
+		}
+	}
+
+	if filter.NeedsTags() {
+		tags, err := s.S3ObjectTagsGetter.GetS3ObjectTags(ctx, &service.S3ObjectTagsGetterInput{
+			Bucket: bucket,
+			S3Key:  key,
+		})
+		if err != nil {
+			return false, err
+		}
+		if !filter.MatchTags(tags.Tags) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// S3BucketsFinderSet is a provider set for S3BucketsFinder.
+//
+//nolint:gochecknoglobals
+var S3BucketsFinderSet = wire.NewSet(
+	NewS3BucketsFinder,
+	wire.Bind(new(usecase.S3BucketsFinder), new(*S3BucketsFinder)),
+)
+
+var _ usecase.S3BucketsFinder = (*S3BucketsFinder)(nil)
+
+// S3BucketsFinder is an implementation for S3BucketsFinder.
+type S3BucketsFinder struct {
+	service.S3BucketLister
+	service.S3BucketLocationGetter
+	service.S3BucketTagsGetter
+}
+
+// NewS3BucketsFinder returns a new S3BucketsFinder struct.
+func NewS3BucketsFinder(
+	l service.S3BucketLister,
+	g service.S3BucketLocationGetter,
+	t service.S3BucketTagsGetter,
+) *S3BucketsFinder {
+	return &S3BucketsFinder{
+		S3BucketLister:         l,
+		S3BucketLocationGetter: g,
+		S3BucketTagsGetter:     t,
+	}
+}
+
+// FindS3Buckets finds the buckets that match all predicates in the filter.
+// The region and tags are fetched only for the buckets that match the name and creation date.
+func (s *S3BucketsFinder) FindS3Buckets(ctx context.Context, input *usecase.S3BucketsFinderInput) (*usecase.S3BucketsFinderOutput, error) {
+	filter := input.Filter
+	if filter == nil {
+		filter = &model.S3BucketFilter{}
+	}
+
+	out, err := s.S3BucketLister.ListS3Buckets(ctx, &service.S3BucketListerInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(model.BucketSets, 0, out.Buckets.Len())
+	for _, b := range out.Buckets {
+		if !filter.MatchBucket(b) {
+			continue
+		}
+
+		location, err := s.S3BucketLocationGetter.GetS3BucketLocation(ctx, &service.S3BucketLocationGetterInput{
+			Bucket: b.Bucket,
+		})
+		if err != nil {
+			return nil, err
+		}
+		b.Region = location.Region
+
+		if filter.NeedsTags() {
+			tags, err := s.S3BucketTagsGetter.GetS3BucketTags(ctx, &service.S3BucketTagsGetterInput{
+				Bucket: b.Bucket,
+				Region: b.Region,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !filter.MatchTags(tags.Tags) {
+				continue
+			}
+		}
+		buckets = append(buckets, b)
+	}
+	return &usecase.S3BucketsFinderOutput{Buckets: buckets}, nil
+}
+
+// S3BucketEmptierSet is a provider set for S3BucketEmptier.
+//
+//nolint:gochecknoglobals
+var S3BucketEmptierSet = wire.NewSet(
+	NewS3BucketEmptier,
+	wire.Bind(new(usecase.S3BucketEmptier), new(*S3BucketEmptier)),
+)
+
+var _ usecase.S3BucketEmptier = (*S3BucketEmptier)(nil)
+
+// S3BucketEmptier is an implementation for S3BucketEmptier.
+type S3BucketEmptier struct {
+	service.S3BucketLocationGetter
+	service.S3ObjectVersionsLister
+	service.S3ObjectsDeleter
+	service.S3MultipartUploadsLister
+	service.S3MultipartUploadAborter
+	service.S3ObjectLockGetter
+	service.S3ObjectLegalHoldReleaser
+}
+
+// NewS3BucketEmptier returns a new S3BucketEmptier struct.
+func NewS3BucketEmptier(
+	g service.S3BucketLocationGetter,
+	l service.S3ObjectVersionsLister,
+	d service.S3ObjectsDeleter,
+	ml service.S3MultipartUploadsLister,
+	ma service.S3MultipartUploadAborter,
+	lg service.S3ObjectLockGetter,
+	lr service.S3ObjectLegalHoldReleaser,
+) *S3BucketEmptier {
+	return &S3BucketEmptier{
+		S3BucketLocationGetter:    g,
+		S3ObjectVersionsLister:    l,
+		S3ObjectsDeleter:          d,
+		S3MultipartUploadsLister:  ml,
+		S3MultipartUploadAborter:  ma,
+		S3ObjectLockGetter:        lg,
+		S3ObjectLegalHoldReleaser: lr,
+	}
+}
+
+// EmptyS3Bucket deletes all object versions and delete markers, and aborts all multipart uploads in the bucket.
+// The object versions that are protected by the object lock are reported in the output instead of returning an error.
+func (s *S3BucketEmptier) EmptyS3Bucket(ctx context.Context, input *usecase.S3BucketEmptierInput) (*usecase.S3BucketEmptierOutput, error) {
+	if err := input.Bucket.Validate(); err != nil {
+		return nil, err
+	}
+
+	location, err := s.S3BucketLocationGetter.GetS3BucketLocation(ctx, &service.S3BucketLocationGetterInput{
+		Bucket: input.Bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := &usecase.S3BucketEmptierOutput{}
+
+	uploads, err := s.S3MultipartUploadsLister.ListS3MultipartUploads(ctx, &service.S3MultipartUploadsListerInput{
+		Bucket: input.Bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, upload := range uploads.Uploads {
+		if _, err := s.S3MultipartUploadAborter.AbortS3MultipartUpload(ctx, &service.S3MultipartUploadAborterInput{
+			Bucket: input.Bucket,
+			Region: location.Region,
+			Upload: upload,
+		}); err != nil {
+			return nil, err
+		}
+		output.AbortedUploads++
+	}
+
+	versions, err := s.S3ObjectVersionsLister.ListS3ObjectVersions(ctx, &service.S3ObjectVersionsListerInput{
+		Bucket: input.Bucket,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	failed, err := s.deleteVersions(ctx, input, location.Region, versions.Objects)
+	if err != nil {
+		return nil, err
+	}
+	output.DeletedObjects = versions.Objects.Len() - len(failed)
+	if len(failed) == 0 {
+		return output, nil
+	}
+
+	retries := make(model.S3ObjectIdentifiers, 0, len(failed))
+	for _, f := range failed {
+		reason, retry, err := s.inspectFailure(ctx, input, location.Region, f)
+		if err != nil {
+			return nil, err
+		}
+		if retry {
+			retries = append(retries, f.S3ObjectIdentifier)
+			continue
+		}
+		output.Undeletable = append(output.Undeletable, model.S3UndeletableObject{
+			S3ObjectIdentifier: f.S3ObjectIdentifier,
+			Reason:             reason,
+		})
+	}
+	if len(retries) == 0 {
+		return output, nil
+	}
+
+	failed, err = s.deleteVersions(ctx, input, location.Region, retries)
+	if err != nil {
+		return nil, err
+	}
+	output.DeletedObjects += len(retries) - len(failed)
+	for _, f := range failed {
+		output.Undeletable = append(output.Undeletable, model.S3UndeletableObject{
+			S3ObjectIdentifier: f.S3ObjectIdentifier,
+			Reason:             f.Error(),
+		})
+	}
+	return output, nil
+}
+
+// deleteVersions deletes the object versions in parallel with chunks and returns the versions that could not be deleted.
+func (s *S3BucketEmptier) deleteVersions(
+	ctx context.Context,
+	input *usecase.S3BucketEmptierInput,
+	region model.Region,
+	targets model.S3ObjectIdentifiers,
+) ([]model.S3ObjectDeleteError, error) {
+	var mu sync.Mutex
+	failed := make([]model.S3ObjectDeleteError, 0)
+
+	limiter := input.Limiter
+	if limiter == nil {
+		limiter = model.NewS3Limiter(model.MaxS3DeleteObjectsParallelsCount, 0)
+	}
+	chunks := (targets.Len() + model.S3DeleteObjectChunksSize - 1) / model.S3DeleteObjectChunksSize
+	if err := limiter.ForEach(ctx, chunks, func(ctx context.Context, i int) error {
+		start := i * model.S3DeleteObjectChunksSize
+		chunk := targets[start:min(start+model.S3DeleteObjectChunksSize, targets.Len())]
+		out, err := s.S3ObjectsDeleter.DeleteS3Objects(ctx, &service.S3ObjectsDeleterInput{
+			Bucket:           input.Bucket,
+			Region:           region,
+			S3ObjectSets:     chunk,
+			BypassGovernance: input.BypassGovernance,
+		})
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, out.Errors...)
+		if input.Progress != nil {
+			input.Progress(len(chunk) - len(out.Errors))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return failed, nil
+}
+
+// inspectFailure checks the object lock of the object version that could not be deleted.
+// It returns retry=true if the object version can be deleted by retrying (e.g. after releasing the legal hold).
+// Otherwise, it returns the reason why the object version can not be deleted.
+func (s *S3BucketEmptier) inspectFailure(
+	ctx context.Context,
+	input *usecase.S3BucketEmptierInput,
+	region model.Region,
+	failure model.S3ObjectDeleteError,
+) (string, bool, error) {
+	lock, err := s.S3ObjectLockGetter.GetS3ObjectLock(ctx, &service.S3ObjectLockGetterInput{
+		Bucket:             input.Bucket,
+		Region:             region,
+		S3ObjectIdentifier: failure.S3ObjectIdentifier,
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	if reason := lock.Lock.UndeletableReason(time.Now(), input.BypassGovernance, input.ReleaseLegalHold); reason != "" {
+		return reason, false, nil
+	}
+	if !lock.Lock.LegalHold {
+		// The failure is not caused by the object lock.
+		return failure.Error(), false, nil
+	}
+
+	if _, err := s.S3ObjectLegalHoldReleaser.ReleaseS3ObjectLegalHold(ctx, &service.S3ObjectLegalHoldReleaserInput{
+		Bucket:             input.Bucket,
+		Region:             region,
+		S3ObjectIdentifier: failure.S3ObjectIdentifier,
+	}); err != nil {
+		return "", false, err
+	}
+	return "", true, nil
+}
@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1023,3 +1025,355 @@ func TestS3ObjectCopier_CopyS3Object(t *testing.T) {
 		}
 	})
 }
+
+func TestS3BucketReporter_ReportS3Bucket(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success to report S3 bucket", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		detailsLister := mock.S3ObjectDetailsLister(func(ctx context.Context, input *service.S3ObjectDetailsListerInput) (*service.S3ObjectDetailsListerOutput, error) {
+			return &service.S3ObjectDetailsListerOutput{
+				Objects: model.S3ObjectDetails{
+					{S3Key: "logs/a.log", Size: 100, StorageClass: "STANDARD", LastModified: now},
+					{S3Key: "index.html", Size: 10, StorageClass: "GLACIER", LastModified: now},
+				},
+			}, nil
+		})
+		versionsLister := mock.S3ObjectVersionsLister(func(ctx context.Context, input *service.S3ObjectVersionsListerInput) (*service.S3ObjectVersionsListerOutput, error) {
+			return &service.S3ObjectVersionsListerOutput{
+				Versions: model.S3ObjectVersions{
+					{S3Key: "logs/a.log", VersionID: "1", Size: 100, IsLatest: true},
+					{S3Key: "logs/a.log", VersionID: "0", Size: 50},
+					{S3Key: "old.txt", VersionID: "2", IsDeleteMarker: true, IsLatest: true},
+				},
+			}, nil
+		})
+		uploadsLister := mock.S3MultipartUploadsLister(func(ctx context.Context, input *service.S3MultipartUploadsListerInput) (*service.S3MultipartUploadsListerOutput, error) {
+			return &service.S3MultipartUploadsListerOutput{
+				Uploads: model.S3MultipartUploads{{S3Key: "big.bin", UploadID: "upload-id"}},
+			}, nil
+		})
+
+		reporter := NewS3BucketReporter(detailsLister, versionsLister, uploadsLister)
+		got, err := reporter.ReportS3Bucket(context.Background(), &usecase.S3BucketReporterInput{
+			Bucket: "bucket-name",
+			TopN:   1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := got.Report
+		if r.TotalObjects != 2 || r.TotalBytes != 110 {
+			t.Errorf("total = (%d, %d), want (2, 110)", r.TotalObjects, r.TotalBytes)
+		}
+		if r.NoncurrentVersions != 1 || r.NoncurrentVersionBytes != 50 {
+			t.Errorf("noncurrent = (%d, %d), want (1, 50)", r.NoncurrentVersions, r.NoncurrentVersionBytes)
+		}
+		if r.DeleteMarkers != 1 {
+			t.Errorf("DeleteMarkers = %d, want 1", r.DeleteMarkers)
+		}
+		if r.IncompleteMultipartUploads != 1 {
+			t.Errorf("IncompleteMultipartUploads = %d, want 1", r.IncompleteMultipartUploads)
+		}
+		if len(r.LargestObjects) != 1 || r.LargestObjects[0].Key != "logs/a.log" {
+			t.Errorf("LargestObjects = %v, want [logs/a.log]", r.LargestObjects)
+		}
+	})
+
+	t.Run("If failed to list objects, return error", func(t *testing.T) {
+		t.Parallel()
+
+		wantErr := errors.New("some error")
+		detailsLister := mock.S3ObjectDetailsLister(func(ctx context.Context, input *service.S3ObjectDetailsListerInput) (*service.S3ObjectDetailsListerOutput, error) {
+			return nil, wantErr
+		})
+
+		reporter := NewS3BucketReporter(detailsLister, nil, nil)
+		_, err := reporter.ReportS3Bucket(context.Background(), &usecase.S3BucketReporterInput{Bucket: "bucket-name"})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("got %v, want %v", err, wantErr)
+		}
+	})
+
+	t.Run("If bucket name is invalid, return error", func(t *testing.T) {
+		t.Parallel()
+
+		reporter := NewS3BucketReporter(nil, nil, nil)
+		if _, err := reporter.ReportS3Bucket(context.Background(), &usecase.S3BucketReporterInput{Bucket: "b"}); err == nil {
+			t.Error("should be failed to report, however err is nil")
+		}
+	})
+}
+
+func TestS3ObjectsFinder_FindS3Objects(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	detailsLister := mock.S3ObjectDetailsLister(func(ctx context.Context, input *service.S3ObjectDetailsListerInput) (*service.S3ObjectDetailsListerOutput, error) {
+		if input.Prefix != "images/" {
+			t.Errorf("input.Prefix = %s, want images/", input.Prefix)
+		}
+		return &service.S3ObjectDetailsListerOutput{
+			Objects: model.S3ObjectDetails{
+				{S3Key: "images/a.png", Size: 300, LastModified: now},
+				{S3Key: "images/b.png", Size: 200, LastModified: now},
+				{S3Key: "images/c.jpg", Size: 100, LastModified: now},
+				{S3Key: "images/d.png", Size: 10, LastModified: now},
+			},
+		}, nil
+	})
+
+	t.Run("find objects with list predicates only", func(t *testing.T) {
+		t.Parallel()
+
+		finder := NewS3ObjectsFinder(detailsLister, nil, nil)
+		got, err := finder.FindS3Objects(context.Background(), &usecase.S3ObjectsFinderInput{
+			Bucket: "bucket-name",
+			Prefix: "images/",
+			Filter: &model.S3ObjectFilter{
+				Name: "*.png",
+				Size: &model.S3SizeCondition{Comparison: model.ComparisonGreater, Bytes: 100},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := model.S3ObjectDetails{
+			{S3Key: "images/a.png", Size: 300, LastModified: now},
+			{S3Key: "images/b.png", Size: 200, LastModified: now},
+		}
+		if diff := cmp.Diff(want, got.Objects); diff != "" {
+			t.Errorf("FindS3Objects() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("find objects with tags and content type", func(t *testing.T) {
+		t.Parallel()
+
+		tagsGetter := mock.S3ObjectTagsGetter(func(ctx context.Context, input *service.S3ObjectTagsGetterInput) (*service.S3ObjectTagsGetterOutput, error) {
+			if input.S3Key == "images/a.png" {
+				return &service.S3ObjectTagsGetterOutput{Tags: model.S3Tags{{Key: "env", Value: "dev"}}}, nil
+			}
+			return &service.S3ObjectTagsGetterOutput{}, nil
+		})
+		metadataGetter := mock.S3ObjectMetadataGetter(func(ctx context.Context, input *service.S3ObjectMetadataGetterInput) (*service.S3ObjectMetadataGetterOutput, error) {
+			if input.S3Key == "images/c.jpg" {
+				return &service.S3ObjectMetadataGetterOutput{ContentType: "image/jpeg"}, nil
+			}
+			return &service.S3ObjectMetadataGetterOutput{ContentType: "image/png"}, nil
+		})
+
+		finder := NewS3ObjectsFinder(detailsLister, tagsGetter, metadataGetter)
+		got, err := finder.FindS3Objects(context.Background(), &usecase.S3ObjectsFinderInput{
+			Bucket: "bucket-name",
+			Prefix: "images/",
+			Filter: &model.S3ObjectFilter{
+				Tags:        model.S3Tags{{Key: "env", Value: "dev"}},
+				ContentType: "image/png",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := model.S3ObjectDetails{{S3Key: "images/a.png", Size: 300, LastModified: now}}
+		if diff := cmp.Diff(want, got.Objects); diff != "" {
+			t.Errorf("FindS3Objects() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If failed to get tags, return error", func(t *testing.T) {
+		t.Parallel()
+
+		wantErr := errors.New("some error")
+		tagsGetter := mock.S3ObjectTagsGetter(func(ctx context.Context, input *service.S3ObjectTagsGetterInput) (*service.S3ObjectTagsGetterOutput, error) {
+			return nil, wantErr
+		})
+
+		finder := NewS3ObjectsFinder(detailsLister, tagsGetter, nil)
+		_, err := finder.FindS3Objects(context.Background(), &usecase.S3ObjectsFinderInput{
+			Bucket: "bucket-name",
+			Prefix: "images/",
+			Filter: &model.S3ObjectFilter{Tags: model.S3Tags{{Key: "env", Value: "dev"}}},
+		})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("got %v, want %v", err, wantErr)
+		}
+	})
+}
+
+func TestS3BucketsFinder_FindS3Buckets(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucketLister := mock.S3BucketLister(func(ctx context.Context, input *service.S3BucketListerInput) (*service.S3BucketListerOutput, error) {
+		return &service.S3BucketListerOutput{
+			Buckets: model.BucketSets{
+				{Bucket: "test-old", CreationDate: created},
+				{Bucket: "test-new", CreationDate: created.AddDate(1, 0, 0)},
+				{Bucket: "test-untagged", CreationDate: created},
+				{Bucket: "production", CreationDate: created},
+			},
+		}, nil
+	})
+	locationGetter := mock.S3BucketLocationGetter(func(ctx context.Context, input *service.S3BucketLocationGetterInput) (*service.S3BucketLocationGetterOutput, error) {
+		return &service.S3BucketLocationGetterOutput{Region: model.RegionAPNortheast1}, nil
+	})
+
+	t.Run("find buckets with name, creation date and tags", func(t *testing.T) {
+		t.Parallel()
+
+		tagsGetter := mock.S3BucketTagsGetter(func(ctx context.Context, input *service.S3BucketTagsGetterInput) (*service.S3BucketTagsGetterOutput, error) {
+			if input.Region != model.RegionAPNortheast1 {
+				t.Errorf("input.Region = %s, want %s", input.Region, model.RegionAPNortheast1)
+			}
+			if input.Bucket == "test-untagged" {
+				return &service.S3BucketTagsGetterOutput{}, nil
+			}
+			return &service.S3BucketTagsGetterOutput{Tags: model.S3Tags{{Key: "env", Value: "test"}}}, nil
+		})
+
+		finder := NewS3BucketsFinder(bucketLister, locationGetter, tagsGetter)
+		got, err := finder.FindS3Buckets(context.Background(), &usecase.S3BucketsFinderInput{
+			Filter: &model.S3BucketFilter{
+				Name:          regexp.MustCompile("^test-"),
+				CreatedBefore: created.AddDate(0, 6, 0),
+				Tags:          model.S3Tags{{Key: "env", Value: "test"}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := model.BucketSets{{Bucket: "test-old", Region: model.RegionAPNortheast1, CreationDate: created}}
+		if diff := cmp.Diff(want, got.Buckets); diff != "" {
+			t.Errorf("FindS3Buckets() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("If failed to get tags, return error", func(t *testing.T) {
+		t.Parallel()
+
+		wantErr := errors.New("some error")
+		tagsGetter := mock.S3BucketTagsGetter(func(ctx context.Context, input *service.S3BucketTagsGetterInput) (*service.S3BucketTagsGetterOutput, error) {
+			return nil, wantErr
+		})
+
+		finder := NewS3BucketsFinder(bucketLister, locationGetter, tagsGetter)
+		_, err := finder.FindS3Buckets(context.Background(), &usecase.S3BucketsFinderInput{
+			Filter: &model.S3BucketFilter{Tags: model.S3Tags{{Key: "env", Value: "test"}}},
+		})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("got %v, want %v", err, wantErr)
+		}
+	})
+}
+
+func TestS3BucketEmptier_EmptyS3Bucket(t *testing.T) {
+	t.Parallel()
+
+	locationGetter := mock.S3BucketLocationGetter(func(ctx context.Context, input *service.S3BucketLocationGetterInput) (*service.S3BucketLocationGetterOutput, error) {
+		return &service.S3BucketLocationGetterOutput{Region: model.RegionAPNortheast1}, nil
+	})
+	uploadsLister := mock.S3MultipartUploadsLister(func(ctx context.Context, input *service.S3MultipartUploadsListerInput) (*service.S3MultipartUploadsListerOutput, error) {
+		return &service.S3MultipartUploadsListerOutput{
+			Uploads: model.S3MultipartUploads{{S3Key: "big.bin", UploadID: "upload-1"}},
+		}, nil
+	})
+	versionsLister := mock.S3ObjectVersionsLister(func(ctx context.Context, input *service.S3ObjectVersionsListerInput) (*service.S3ObjectVersionsListerOutput, error) {
+		return &service.S3ObjectVersionsListerOutput{
+			Objects: model.S3ObjectIdentifiers{
+				{S3Key: "normal.txt", VersionID: "1"},
+				{S3Key: "deleted.txt", VersionID: "marker"},
+				{S3Key: "hold.txt", VersionID: "2"},
+				{S3Key: "compliance.txt", VersionID: "3"},
+			},
+		}, nil
+	})
+	lockGetter := mock.S3ObjectLockGetter(func(ctx context.Context, input *service.S3ObjectLockGetterInput) (*service.S3ObjectLockGetterOutput, error) {
+		switch input.S3ObjectIdentifier.S3Key {
+		case "hold.txt":
+			return &service.S3ObjectLockGetterOutput{Lock: &model.S3ObjectLock{LegalHold: true}}, nil
+		case "compliance.txt":
+			return &service.S3ObjectLockGetterOutput{Lock: &model.S3ObjectLock{
+				Mode:        model.S3RetentionModeCompliance,
+				RetainUntil: time.Now().Add(time.Hour),
+			}}, nil
+		default:
+			return &service.S3ObjectLockGetterOutput{Lock: &model.S3ObjectLock{}}, nil
+		}
+	})
+
+	newObjectsDeleter := func() mock.S3ObjectsDeleter {
+		var mu sync.Mutex
+		released := false
+		return mock.S3ObjectsDeleter(func(ctx context.Context, input *service.S3ObjectsDeleterInput) (*service.S3ObjectsDeleterOutput, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !input.BypassGovernance {
+				t.Error("input.BypassGovernance should be true")
+			}
+			errs := []model.S3ObjectDeleteError{}
+			for _, o := range input.S3ObjectSets {
+				if o.S3Key == "compliance.txt" || (o.S3Key == "hold.txt" && !released) {
+					errs = append(errs, model.S3ObjectDeleteError{S3ObjectIdentifier: o, Code: "AccessDenied", Message: "Access Denied"})
+				}
+			}
+			released = true // the legal hold is released after the first attempt
+			return &service.S3ObjectsDeleterOutput{Errors: errs}, nil
+		})
+	}
+
+	t.Run("delete versions, release legal hold and report compliance retention", func(t *testing.T) {
+		t.Parallel()
+
+		aborted := 0
+		uploadAborter := mock.S3MultipartUploadAborter(func(ctx context.Context, input *service.S3MultipartUploadAborterInput) (*service.S3MultipartUploadAborterOutput, error) {
+			aborted++
+			return &service.S3MultipartUploadAborterOutput{}, nil
+		})
+		legalHoldReleaser := mock.S3ObjectLegalHoldReleaser(func(ctx context.Context, input *service.S3ObjectLegalHoldReleaserInput) (*service.S3ObjectLegalHoldReleaserOutput, error) {
+			if input.S3ObjectIdentifier.S3Key != "hold.txt" {
+				t.Errorf("unexpected legal hold release: %s", input.S3ObjectIdentifier.S3Key)
+			}
+			return &service.S3ObjectLegalHoldReleaserOutput{}, nil
+		})
+
+		emptier := NewS3BucketEmptier(locationGetter, versionsLister, newObjectsDeleter(), uploadsLister, uploadAborter, lockGetter, legalHoldReleaser)
+		got, err := emptier.EmptyS3Bucket(context.Background(), &usecase.S3BucketEmptierInput{
+			Bucket:           "bucket-name",
+			BypassGovernance: true,
+			ReleaseLegalHold: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.DeletedObjects != 3 || got.AbortedUploads != 1 || aborted != 1 {
+			t.Errorf("DeletedObjects = %d, AbortedUploads = %d, want 3, 1", got.DeletedObjects, got.AbortedUploads)
+		}
+		if got.Undeletable.Len() != 1 || got.Undeletable[0].S3Key != "compliance.txt" {
+			t.Fatalf("Undeletable = %v, want [compliance.txt]", got.Undeletable)
+		}
+		if !strings.Contains(got.Undeletable[0].Reason, "COMPLIANCE") {
+			t.Errorf("Reason = %s, want to contain COMPLIANCE", got.Undeletable[0].Reason)
+		}
+	})
+
+	t.Run("If failed to list versions, return error", func(t *testing.T) {
+		t.Parallel()
+
+		wantErr := errors.New("some error")
+		failedLister := mock.S3ObjectVersionsLister(func(ctx context.Context, input *service.S3ObjectVersionsListerInput) (*service.S3ObjectVersionsListerOutput, error) {
+			return nil, wantErr
+		})
+		uploadAborter := mock.S3MultipartUploadAborter(func(ctx context.Context, input *service.S3MultipartUploadAborterInput) (*service.S3MultipartUploadAborterOutput, error) {
+			return &service.S3MultipartUploadAborterOutput{}, nil
+		})
+
+		emptier := NewS3BucketEmptier(locationGetter, failedLister, nil, uploadsLister, uploadAborter, nil, nil)
+		if _, err := emptier.EmptyS3Bucket(context.Background(), &usecase.S3BucketEmptierInput{Bucket: "bucket-name"}); !errors.Is(err, wantErr) {
+			t.Errorf("got %v, want %v", err, wantErr)
+		}
+	})
+}
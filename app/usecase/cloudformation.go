@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/nao1215/rainbow/app/domain/model"
 )
@@ -10,6 +11,10 @@ import (
 type CFnStackListerInput struct {
 	// Region is the region of the stack.
 	Region model.Region
+	// Filter is the condition of the stacks. If it is nil, all the stacks including the deleted stacks are returned.
+	Filter *model.CFnStackFilter
+	// SortKey is the key to sort the stacks. If it is empty, the stacks are returned in the API order.
+	SortKey model.CFnStackSortKey
 }
 
 // CFnStackListerOutput is the output of the CFnStackLister method.
@@ -23,12 +28,40 @@ type CFnStackLister interface {
 	ListCFnStack(ctx context.Context, input *CFnStackListerInput) (*CFnStackListerOutput, error)
 }
 
+// CFnStacksInRegionsListerInput is the input of the CFnStacksInRegionsLister method.
+type CFnStacksInRegionsListerInput struct {
+	// Regions is the list of regions where the stacks are listed.
+	Regions []model.Region
+	// Concurrency is the number of regions listed in parallel. 0 means model.MaxCFnRegionsParallelsCount.
+	Concurrency int
+	// Filter is the condition of the stacks. If it is nil, all the stacks including the deleted stacks are returned.
+	Filter *model.CFnStackFilter
+	// SortKey is the key to sort the stacks across the regions. The stacks that have the same key are sorted by the region.
+	// If it is empty, the stacks are returned in the order of Regions and in the API order.
+	SortKey model.CFnStackSortKey
+}
+
+// CFnStacksInRegionsListerOutput is the output of the CFnStacksInRegionsLister method.
+type CFnStacksInRegionsListerOutput struct {
+	// Stacks is a list of CloudFormation stacks sorted by the order of the regions in the input.
+	Stacks []*model.RegionalStack
+	// SkippedRegions is the list of regions where the account is not opted in.
+	SkippedRegions []model.Region
+}
+
+// CFnStacksInRegionsLister is the interface that wraps the basic ListCFnStacksInRegions method.
+type CFnStacksInRegionsLister interface {
+	ListCFnStacksInRegions(ctx context.Context, input *CFnStacksInRegionsListerInput) (*CFnStacksInRegionsListerOutput, error)
+}
+
 // CFnStackEventsDescriberInput is the input of the CFnStackEventsDescriber method.
 type CFnStackEventsDescriberInput struct {
 	// StackName is the name of the stack.
 	StackName string
 	// Region is the region of the stack.
 	Region model.Region
+	// Since is the time from which the events are returned. If it is zero, only the latest page of the events is returned.
+	Since time.Time
 }
 
 // CFnStackEventsDescriberOutput is the output of the CFnStackEventsDescriber method.
@@ -41,3 +74,357 @@ type CFnStackEventsDescriberOutput struct {
 type CFnStackEventsDescriber interface {
 	DescribeCFnStackEvents(ctx context.Context, input *CFnStackEventsDescriberInput) (*CFnStackEventsDescriberOutput, error)
 }
+
+// CFnStackDescriberInput is the input of the CFnStackDescriber method.
+type CFnStackDescriberInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+}
+
+// CFnStackDescriberOutput is the output of the CFnStackDescriber method.
+type CFnStackDescriberOutput struct {
+	// Stack is the CloudFormation stack.
+	Stack *model.Stack
+	// TerminationProtection is whether the termination protection is enabled.
+	TerminationProtection bool
+	// Tags is the tags of the stack.
+	Tags model.CFnTags
+	// Outputs is the outputs of the stack.
+	Outputs model.CFnOutputs
+}
+
+// CFnStackDescriber is the interface that wraps the basic DescribeCFnStack method.
+type CFnStackDescriber interface {
+	DescribeCFnStack(ctx context.Context, input *CFnStackDescriberInput) (*CFnStackDescriberOutput, error)
+}
+
+// CFnStackDeleterInput is the input of the CFnStackDeleter method.
+type CFnStackDeleterInput struct {
+	// StackName is the name of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// RetainResources is the logical IDs of the resources that are retained. It is only for the DELETE_FAILED stack.
+	RetainResources []string
+	// OnEvent is called with each stack event of the deletion in chronological order. It may be nil.
+	OnEvent func(event *model.StackEvent)
+	// PollInterval is the interval of polling the stack events. 0 means model.CFnStackEventsPollInterval.
+	PollInterval time.Duration
+}
+
+// CFnStackDeleterOutput is the output of the CFnStackDeleter method.
+type CFnStackDeleterOutput struct {
+	// Status is the final status of the stack. DELETE_COMPLETE or DELETE_FAILED.
+	Status model.StackStatus
+	// StatusReason is the reason of the final status.
+	StatusReason string
+	// FailedResources is the events of the resources that blocked the deletion.
+	// They can be retained by deleting the stack again with RetainResources.
+	FailedResources []*model.StackEvent
+}
+
+// CFnStackDeleter is the interface that wraps the basic DeleteCFnStack method.
+// It deletes the stack and waits until the deletion completes or fails.
+type CFnStackDeleter interface {
+	DeleteCFnStack(ctx context.Context, input *CFnStackDeleterInput) (*CFnStackDeleterOutput, error)
+}
+
+// CFnStackTaggerInput is the input of the CFnStackTagger method.
+type CFnStackTaggerInput struct {
+	// StackName is the name of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// Tags is the tags added to the stack. The value of the existing key is overwritten.
+	Tags model.CFnTags
+	// RemoveKeys is the keys of the tags removed from the stack.
+	RemoveKeys []string
+	// OnEvent is called with each stack event of the update in chronological order. It may be nil.
+	OnEvent func(event *model.StackEvent)
+	// PollInterval is the interval of polling the stack events. 0 means model.CFnStackEventsPollInterval.
+	PollInterval time.Duration
+}
+
+// CFnStackTaggerOutput is the output of the CFnStackTagger method.
+type CFnStackTaggerOutput struct {
+	// Changed is whether the tags are changed. If false, the stack is not updated.
+	Changed bool
+	// Tags is the tag set of the stack after the update.
+	Tags model.CFnTags
+	// Status is the final status of the stack. UPDATE_COMPLETE if the update succeeded.
+	Status model.StackStatus
+	// StatusReason is the reason of the final status.
+	StatusReason string
+	// FailedResources is the events of the resources that failed to be updated.
+	FailedResources []*model.StackEvent
+}
+
+// CFnStackTagger is the interface that wraps the basic TagCFnStack method.
+// It updates the stack with the previous template and parameters and the new tag set,
+// and waits until the update completes or fails.
+type CFnStackTagger interface {
+	TagCFnStack(ctx context.Context, input *CFnStackTaggerInput) (*CFnStackTaggerOutput, error)
+}
+
+// CFnStackDeployPlannerInput is the input of the CFnStackDeployPlanner method.
+type CFnStackDeployPlannerInput struct {
+	// StackName is the name of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// TemplateBody is the template body.
+	TemplateBody string
+	// Parameters is the parameters of the stack. The parameters that are not specified use the default values of the template.
+	Parameters model.CFnParameters
+	// Capabilities is the capabilities that acknowledge the template contains the certain resources.
+	Capabilities []model.CFnCapability
+	// Tags is the tags of the stack. If nil, the tags of the existing stack are not changed.
+	Tags model.CFnTags
+	// PollInterval is the interval of polling the change set. 0 means model.CFnChangeSetPollInterval.
+	PollInterval time.Duration
+}
+
+// CFnStackDeployPlannerOutput is the output of the CFnStackDeployPlanner method.
+type CFnStackDeployPlannerOutput struct {
+	// ChangeSetID is the ID of the created change set. It is empty if NoChanges is true.
+	ChangeSetID string
+	// StackID is the ID of the stack.
+	StackID string
+	// ChangeSetType is CREATE for the new stack and UPDATE for the existing stack.
+	ChangeSetType model.CFnChangeSetType
+	// CreatedStack is true if the stack did not exist and was created with the change set.
+	// It is false for the stack that already existed in REVIEW_IN_PROGRESS, even if ChangeSetType is CREATE.
+	CreatedStack bool
+	// Changes is the proposed resource changes.
+	Changes []*model.CFnResourceChange
+	// NoChanges is true if the template and the parameters do not change the stack. The change set is deleted.
+	NoChanges bool
+}
+
+// CFnStackDeployPlanner is the interface that wraps the basic PlanCFnStackDeploy method.
+// It creates the change set of the stack and waits until the change set is created.
+// The change set is executed by CFnStackDeployer or deleted by CFnStackDeployCanceler.
+type CFnStackDeployPlanner interface {
+	PlanCFnStackDeploy(ctx context.Context, input *CFnStackDeployPlannerInput) (*CFnStackDeployPlannerOutput, error)
+}
+
+// CFnStackDeployCancelerInput is the input of the CFnStackDeployCanceler method.
+type CFnStackDeployCancelerInput struct {
+	// ChangeSetID is the ID of the change set to delete.
+	ChangeSetID string
+	// StackID is the ID of the stack.
+	StackID string
+	// Region is the region of the stack.
+	Region model.Region
+	// CreatedStack is whether the stack was created with the change set. If true, the stack that has no resources yet
+	// is deleted too. The stack that existed before (e.g. in REVIEW_IN_PROGRESS) is never deleted.
+	CreatedStack bool
+}
+
+// CFnStackDeployCancelerOutput is the output of the CFnStackDeployCanceler method.
+type CFnStackDeployCancelerOutput struct{}
+
+// CFnStackDeployCanceler is the interface that wraps the basic CancelCFnStackDeploy method.
+type CFnStackDeployCanceler interface {
+	CancelCFnStackDeploy(ctx context.Context, input *CFnStackDeployCancelerInput) (*CFnStackDeployCancelerOutput, error)
+}
+
+// CFnStackDeployerInput is the input of the CFnStackDeployer method.
+type CFnStackDeployerInput struct {
+	// ChangeSetID is the ID of the change set to execute.
+	ChangeSetID string
+	// StackID is the ID of the stack.
+	StackID string
+	// StackName is the name of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// OnEvent is called with each stack event of the deployment in chronological order. It may be nil.
+	OnEvent func(event *model.StackEvent)
+	// PollInterval is the interval of polling the stack events. 0 means model.CFnStackEventsPollInterval.
+	PollInterval time.Duration
+}
+
+// CFnStackDeployerOutput is the output of the CFnStackDeployer method.
+type CFnStackDeployerOutput struct {
+	// Status is the final status of the stack. CREATE_COMPLETE or UPDATE_COMPLETE if the deployment succeeded.
+	Status model.StackStatus
+	// StatusReason is the reason of the final status.
+	StatusReason string
+	// FailedResources is the events of the resources that failed to be created or updated.
+	FailedResources []*model.StackEvent
+}
+
+// CFnStackDeployer is the interface that wraps the basic DeployCFnStack method.
+// It executes the change set and waits until the stack creation or update completes or fails.
+type CFnStackDeployer interface {
+	DeployCFnStack(ctx context.Context, input *CFnStackDeployerInput) (*CFnStackDeployerOutput, error)
+}
+
+// CFnStackEventsTailerInput is the input of the CFnStackEventsTailer method.
+type CFnStackEventsTailerInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// Since is the time from which the events are reported. If it is zero, the latest events are reported.
+	Since time.Time
+	// Follow is whether to keep polling the events until the stack reaches a terminal status.
+	Follow bool
+	// OnEvent is called with each stack event in chronological order, including the events of the nested stacks.
+	OnEvent func(event *model.StackEvent)
+	// PollInterval is the interval of polling the stack events. 0 means model.CFnStackEventsPollInterval.
+	PollInterval time.Duration
+}
+
+// CFnStackEventsTailerOutput is the output of the CFnStackEventsTailer method.
+type CFnStackEventsTailerOutput struct {
+	// Status is the last status of the stack.
+	Status model.StackStatus
+	// StatusReason is the reason of the last status.
+	StatusReason string
+}
+
+// CFnStackEventsTailer is the interface that wraps the basic TailCFnStackEvents method.
+// It reports the stack events and the events of the nested stacks without duplication.
+type CFnStackEventsTailer interface {
+	TailCFnStackEvents(ctx context.Context, input *CFnStackEventsTailerInput) (*CFnStackEventsTailerOutput, error)
+}
+
+// CFnStackResourceListerInput is the input of the CFnStackResourceLister method.
+type CFnStackResourceListerInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// Recursive is whether to list the resources of the nested stacks as the children of their stack resources.
+	Recursive bool
+}
+
+// CFnStackResourceListerOutput is the output of the CFnStackResourceLister method.
+type CFnStackResourceListerOutput struct {
+	// Stack is the CloudFormation stack.
+	Stack *model.Stack
+	// Resources is the resources of the stack sorted by the logical ID.
+	Resources []*model.StackResourceNode
+}
+
+// CFnStackResourceLister is the interface that wraps the basic ListCFnStackResource method.
+type CFnStackResourceLister interface {
+	ListCFnStackResource(ctx context.Context, input *CFnStackResourceListerInput) (*CFnStackResourceListerOutput, error)
+}
+
+// CFnStackDriftDetectorInput is the input of the CFnStackDriftDetector method.
+type CFnStackDriftDetectorInput struct {
+	// StackNames is the names or the IDs of the stacks.
+	StackNames []string
+	// Region is the region of the stacks.
+	Region model.Region
+	// Concurrency is the number of stacks detected in parallel. 0 means model.MaxCFnDriftDetectionParallelsCount.
+	Concurrency int
+	// PollInterval is the interval of polling the detection status. 0 means model.CFnDriftDetectionPollInterval.
+	PollInterval time.Duration
+}
+
+// CFnStackDriftDetectorOutput is the output of the CFnStackDriftDetector method.
+type CFnStackDriftDetectorOutput struct {
+	// Drifts is the drift detection results sorted by the order of the stacks in the input.
+	// The stack whose detection failed has the error in Err.
+	Drifts []*model.CFnStackDrift
+}
+
+// CFnStackDriftDetector is the interface that wraps the basic DetectCFnStackDrift method.
+// It detects the drift of the stacks, waits until the detection completes, and returns the drifted resources.
+type CFnStackDriftDetector interface {
+	DetectCFnStackDrift(ctx context.Context, input *CFnStackDriftDetectorInput) (*CFnStackDriftDetectorOutput, error)
+}
+
+// CFnExportsListerInput is the input of the CFnExportsLister method.
+type CFnExportsListerInput struct {
+	// Region is the region of the exports.
+	Region model.Region
+	// StackName is the name or the ID of the stack whose exports are listed. If it is empty, all exports in the region are listed.
+	StackName string
+	// Importers is whether to list the stacks that import each export.
+	Importers bool
+	// Concurrency is the number of exports whose importers are listed in parallel. 0 means model.MaxCFnImportsParallelsCount.
+	Concurrency int
+}
+
+// CFnExportsListerOutput is the output of the CFnExportsLister method.
+type CFnExportsListerOutput struct {
+	// Exports is the exports sorted by the name.
+	Exports []*model.CFnExport
+}
+
+// CFnExportsLister is the interface that wraps the basic ListCFnExports method.
+type CFnExportsLister interface {
+	ListCFnExports(ctx context.Context, input *CFnExportsListerInput) (*CFnExportsListerOutput, error)
+}
+
+// CFnTemplateGetterInput is the input of the CFnTemplateGetter method.
+type CFnTemplateGetterInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// Stage is the stage of the template. Empty means model.CFnTemplateStageOriginal.
+	Stage model.CFnTemplateStage
+}
+
+// CFnTemplateGetterOutput is the output of the CFnTemplateGetter method.
+type CFnTemplateGetterOutput struct {
+	// TemplateBody is the template body in YAML or JSON as it is deployed.
+	TemplateBody string
+}
+
+// CFnTemplateGetter is the interface that wraps the basic GetCFnTemplate method.
+type CFnTemplateGetter interface {
+	GetCFnTemplate(ctx context.Context, input *CFnTemplateGetterInput) (*CFnTemplateGetterOutput, error)
+}
+
+// CFnTemplateDifferInput is the input of the CFnTemplateDiffer method.
+type CFnTemplateDifferInput struct {
+	// StackName is the name or the ID of the stack.
+	StackName string
+	// Region is the region of the stack.
+	Region model.Region
+	// TemplateBody is the local template body in YAML or JSON compared with the deployed template.
+	TemplateBody []byte
+}
+
+// CFnTemplateDifferOutput is the output of the CFnTemplateDiffer method.
+type CFnTemplateDifferOutput struct {
+	// Differences is the differences from the deployed template to the local template sorted by the path.
+	Differences []model.CFnTemplateDifference
+}
+
+// CFnTemplateDiffer is the interface that wraps the basic DiffCFnTemplate method.
+// It compares the templates semantically: the key ordering and the forms of the intrinsic functions are ignored.
+type CFnTemplateDiffer interface {
+	DiffCFnTemplate(ctx context.Context, input *CFnTemplateDifferInput) (*CFnTemplateDifferOutput, error)
+}
+
+// CFnStaleStacksFinderInput is the input of the CFnStaleStacksFinder method.
+type CFnStaleStacksFinderInput struct {
+	// Region is the region of the stacks.
+	Region model.Region
+	// OlderThan is the minimum time elapsed since the stale stack was last updated. 0 means any age.
+	OlderThan time.Duration
+}
+
+// CFnStaleStacksFinderOutput is the output of the CFnStaleStacksFinder method.
+type CFnStaleStacksFinderOutput struct {
+	// Deletable is the stale stacks in the order that they can be deleted. The importers come before the exporters.
+	Deletable []*model.CFnStaleStack
+	// Blocked is the stale stacks that can not be deleted, with the reason.
+	Blocked []*model.CFnStaleStack
+}
+
+// CFnStaleStacksFinder is the interface that wraps the basic FindCFnStaleStacks method.
+// The stale stacks are the root stacks in ROLLBACK_COMPLETE, DELETE_FAILED or REVIEW_IN_PROGRESS.
+type CFnStaleStacksFinder interface {
+	FindCFnStaleStacks(ctx context.Context, input *CFnStaleStacksFinderInput) (*CFnStaleStacksFinderOutput, error)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/utils/aimd"
 )
 
 // S3BucketCreatorInput is the input of the CreateBucket method.
@@ -156,3 +157,90 @@ type S3ObjectCopierOutput struct{}
 type S3ObjectCopier interface {
 	CopyS3Object(ctx context.Context, input *S3ObjectCopierInput) (*S3ObjectCopierOutput, error)
 }
+
+// S3BucketReporterInput is the input of the ReportS3Bucket method.
+type S3BucketReporterInput struct {
+	// Bucket is the name of the bucket that you want to analyze.
+	Bucket model.Bucket
+	// TopN is the number of the largest objects in the report.
+	TopN int
+}
+
+// S3BucketReporterOutput is the output of the ReportS3Bucket method.
+type S3BucketReporterOutput struct {
+	// Report is the analysis result of the bucket.
+	Report *model.S3BucketReport
+}
+
+// S3BucketReporter is the interface that wraps the basic ReportS3Bucket method.
+type S3BucketReporter interface {
+	ReportS3Bucket(ctx context.Context, input *S3BucketReporterInput) (*S3BucketReporterOutput, error)
+}
+
+// S3ObjectsFinderInput is the input of the FindS3Objects method.
+type S3ObjectsFinderInput struct {
+	// Bucket is the name of the bucket to search.
+	Bucket model.Bucket
+	// Prefix limits the search to keys that begin with the specified prefix.
+	Prefix model.S3Key
+	// Filter is the set of predicates. If Filter is nil, all objects under Prefix are returned.
+	Filter *model.S3ObjectFilter
+}
+
+// S3ObjectsFinderOutput is the output of the FindS3Objects method.
+type S3ObjectsFinderOutput struct {
+	// Objects is the list of the objects that match all predicates.
+	Objects model.S3ObjectDetails
+}
+
+// S3ObjectsFinder is the interface that wraps the basic FindS3Objects method.
+type S3ObjectsFinder interface {
+	FindS3Objects(ctx context.Context, input *S3ObjectsFinderInput) (*S3ObjectsFinderOutput, error)
+}
+
+// S3BucketsFinderInput is the input of the FindS3Buckets method.
+type S3BucketsFinderInput struct {
+	// Filter is the set of predicates. If Filter is empty, all buckets are returned.
+	Filter *model.S3BucketFilter
+}
+
+// S3BucketsFinderOutput is the output of the FindS3Buckets method.
+type S3BucketsFinderOutput struct {
+	// Buckets is the list of the buckets that match all predicates.
+	Buckets model.BucketSets
+}
+
+// S3BucketsFinder is the interface that wraps the basic FindS3Buckets method.
+type S3BucketsFinder interface {
+	FindS3Buckets(ctx context.Context, input *S3BucketsFinderInput) (*S3BucketsFinderOutput, error)
+}
+
+// S3BucketEmptierInput is the input of the EmptyS3Bucket method.
+type S3BucketEmptierInput struct {
+	// Bucket is the name of the bucket that you want to empty.
+	Bucket model.Bucket
+	// BypassGovernance is whether to delete the object versions under GOVERNANCE mode retention.
+	BypassGovernance bool
+	// ReleaseLegalHold is whether to turn off the legal hold before deleting the object versions.
+	ReleaseLegalHold bool
+	// Progress is called with the number of deleted object versions. It may be nil.
+	Progress func(deleted int)
+	// Limiter is the adaptive concurrency limiter of DeleteObjects. If it is nil, a new limiter is used.
+	Limiter *aimd.Limiter
+}
+
+// S3BucketEmptierOutput is the output of the EmptyS3Bucket method.
+type S3BucketEmptierOutput struct {
+	// DeletedObjects is the number of deleted object versions and delete markers.
+	DeletedObjects int
+	// AbortedUploads is the number of aborted multipart uploads.
+	AbortedUploads int
+	// Undeletable is the list of the object versions that could not be deleted.
+	// If it is not empty, the bucket can not be deleted.
+	Undeletable model.S3UndeletableObjects
+}
+
+// S3BucketEmptier is the interface that wraps the basic EmptyS3Bucket method.
+type S3BucketEmptier interface {
+	EmptyS3Bucket(ctx context.Context, input *S3BucketEmptierInput) (*S3BucketEmptierOutput, error)
+}
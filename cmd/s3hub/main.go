@@ -5,12 +5,13 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/nao1215/rainbow/cmd/subcmd"
 	"github.com/nao1215/rainbow/cmd/subcmd/s3hub"
 )
 
 func main() {
 	if err := s3hub.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
-		os.Exit(1)
+		os.Exit(subcmd.ExitCode(err))
 	}
 }
@@ -0,0 +1,161 @@
+package cfn
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newCleanupCmd return cleanup command.
+func newCleanupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup [flags]",
+		Short: "Find and delete the broken or stale CloudFormation stacks",
+		Long: `Find and delete the broken or stale CloudFormation stacks.
+
+The stacks in the following statuses are stale if they have not been updated for --older-than:
+  ROLLBACK_COMPLETE   the creation failed and the stack can only be deleted
+  DELETE_FAILED       the deletion failed and the resources remain
+  REVIEW_IN_PROGRESS  the change set that created the stack was never executed
+
+The stacks are deleted in the order that the importers are deleted before the exporters.
+The stacks with termination protection or whose exports are imported by healthy stacks are skipped.`,
+		Example: `  cfn cleanup -p myprofile -r us-east-1 --dry-run
+  cfn cleanup --older-than 7d`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &cleanupCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().Bool("dry-run", false, "show the stale stacks and the reasons without deleting them")
+	cmd.Flags().String("older-than", formatAge(model.CFnCleanupDefaultAge), "clean up the stacks that have not been updated for the age (e.g. 30d, 12h, 2w)")
+	cmd.Flags().BoolP("force", "f", false, "delete without confirmation")
+	return cmd
+}
+
+// cleanupCmd is the command for cleanup.
+type cleanupCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// dryRun is the flag to show the stale stacks without deleting them.
+	dryRun bool
+	// olderThan is the minimum age of the stale stacks.
+	olderThan time.Duration
+	// force is the flag to delete without confirmation.
+	force bool
+}
+
+// Parse parses command line arguments.
+func (c *cleanupCmd) Parse(cmd *cobra.Command, _ []string) error {
+	var err error
+	if c.dryRun, err = cmd.Flags().GetBool("dry-run"); err != nil {
+		return err
+	}
+	olderThan, err := cmd.Flags().GetString("older-than")
+	if err != nil {
+		return err
+	}
+	if c.olderThan, err = model.NewCFnStackAge(olderThan); err != nil {
+		return err
+	}
+	if c.force, err = cmd.Flags().GetBool("force"); err != nil {
+		return err
+	}
+
+	c.cfn = newCFn()
+	return c.cfn.parse(cmd)
+}
+
+// Do executes cleanup command.
+func (c *cleanupCmd) Do() error {
+	out, err := c.CFnStaleStacksFinder.FindCFnStaleStacks(c.ctx, &usecase.CFnStaleStacksFinderInput{
+		Region:    c.region,
+		OlderThan: c.olderThan,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, c.region)
+	}
+
+	for _, s := range out.Blocked {
+		c.printf("%s: skip %s (%s): %s\n",
+			color.YellowString("WARN"), aws.ToString(s.Stack.StackName), s.Stack.StackStatus, s.BlockedReason)
+	}
+	if len(out.Deletable) == 0 {
+		c.printf("no stale stacks older than %s in %s\n", formatAge(c.olderThan), c.region)
+		return nil
+	}
+
+	w := c.command.OutOrStdout()
+	if err := writeStaleStacks(w, out.Deletable); err != nil {
+		return err
+	}
+	if c.dryRun {
+		return nil
+	}
+	if !c.force {
+		if !subcmd.Question(w, fmt.Sprintf("delete %s stacks in this order?", color.YellowString("%d", len(out.Deletable)))) {
+			return nil
+		}
+	}
+
+	// rm offers to retain the resources that blocked the deletion of the DELETE_FAILED stack.
+	rm := &rmCmd{cfn: c.cfn, force: c.force}
+	failed := make(map[string]bool, len(out.Deletable))
+	for _, s := range out.Deletable {
+		name := aws.ToString(s.Stack.StackName)
+		if importer, ok := failedImporter(s, failed); ok {
+			c.printf("%s: skip %s because the importer %s was not deleted\n", color.YellowString("WARN"), name, importer)
+			failed[name] = true
+			continue
+		}
+		if err := rm.deleteStack(name); err != nil {
+			c.printf("%s: %v\n", color.RedString("ERROR"), err)
+			failed[name] = true
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %d of %d stacks", domain.ErrCFnStackDeleteFailed, len(failed), len(out.Deletable))
+	}
+	return nil
+}
+
+// failedImporter returns the importer of the stack that failed to be deleted.
+func failedImporter(stack *model.CFnStaleStack, failed map[string]bool) (string, bool) {
+	for _, importer := range stack.DependsOn {
+		if failed[importer] {
+			return importer, true
+		}
+	}
+	return "", false
+}
+
+// writeStaleStacks writes the stale stacks in the deletion order with the reasons.
+func writeStaleStacks(w io.Writer, stacks []*model.CFnStaleStack) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ORDER\tSTACK\tSTATUS\tAGE\tAFTER\tREASON")
+	for i, s := range stacks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			i+1, aws.ToString(s.Stack.StackName), s.Stack.StackStatus, formatAge(s.Age), orHyphen(strings.Join(s.DependsOn, ",")), s.Reason)
+	}
+	return tw.Flush()
+}
+
+// formatAge returns the age in days if it is one day or more, otherwise in hours. e.g. 12d, 5h
+func formatAge(d time.Duration) string {
+	day := 24 * time.Hour
+	if d >= day {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return fmt.Sprintf("%dh", d/time.Hour)
+}
@@ -0,0 +1,86 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeStaleStacks(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	err := writeStaleStacks(buf, []*model.CFnStaleStack{
+		{
+			Stack:  &model.Stack{StackName: aws.String("app"), StackStatus: model.StackStatusRollbackComplete},
+			Reason: "the creation failed",
+			Age:    50 * time.Hour,
+		},
+		{
+			Stack:     &model.Stack{StackName: aws.String("network"), StackStatus: model.StackStatusDeleteFailed},
+			Reason:    "the deletion failed",
+			Age:       30 * 24 * time.Hour,
+			DependsOn: []string{"app"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `ORDER  STACK    STATUS             AGE  AFTER  REASON
+1      app      ROLLBACK_COMPLETE  2d   -      the creation failed
+2      network  DELETE_FAILED      30d  app    the deletion failed
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_failedImporter(t *testing.T) {
+	t.Parallel()
+
+	stack := &model.CFnStaleStack{DependsOn: []string{"app", "web"}}
+	if importer, ok := failedImporter(stack, map[string]bool{"web": true}); !ok || importer != "web" {
+		t.Errorf("want web, got %s", importer)
+	}
+	if _, ok := failedImporter(stack, map[string]bool{"db": true}); ok {
+		t.Error("the stack whose importers were deleted must not be skipped")
+	}
+}
+
+func Test_formatAge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 5*time.Hour + 30*time.Minute, want: "5h"},
+		{d: 24 * time.Hour, want: "1d"},
+		{d: 47 * time.Hour, want: "1d"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.d); got != tt.want {
+			t.Errorf("formatAge(%s) = %s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
+
+func Test_newCleanupCmd_olderThanDefault(t *testing.T) {
+	t.Parallel()
+
+	olderThan, err := newCleanupCmd().Flags().GetString("older-than")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := model.NewCFnStackAge(olderThan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != model.CFnCleanupDefaultAge {
+		t.Errorf("the default of --older-than = %s, want %s", got, model.CFnCleanupDefaultAge)
+	}
+}
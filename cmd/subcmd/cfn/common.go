@@ -5,6 +5,7 @@ import (
 
 	"github.com/nao1215/rainbow/app/di"
 	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/cmd/subcmd"
 	"github.com/nao1215/rainbow/utils/errfmt"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +22,10 @@ type cfn struct {
 	profile model.AWSProfile
 	// region is the AWS region name.
 	region model.Region
+	// awsConfigOptions is the options for the endpoint and debug mode.
+	awsConfigOptions *model.AWSConfigOptions
+	// concurrency is the number of parallel API calls. 0 means the default of each command.
+	concurrency int
 }
 
 // newCFn returns a new cfn.
@@ -45,10 +50,28 @@ func (c *cfn) parse(cmd *cobra.Command) error {
 	}
 	c.region = model.Region(r)
 
-	cfg, err := model.NewAWSConfig(c.ctx, c.profile, c.region)
+	if c.awsConfigOptions, err = subcmd.NewAWSConfigOptions(cmd); err != nil {
+		return err
+	}
+	if cmd.Flags().Lookup("concurrency") != nil {
+		if c.concurrency, err = cmd.Flags().GetInt("concurrency"); err != nil {
+			return err
+		}
+	}
+	if c.awsConfigOptions.Debug {
+		subcmd.PrintDebugBanner(cmd.ErrOrStderr(), c.awsConfigOptions)
+	}
+
+	cfg, err := model.NewAWSConfig(c.ctx, c.profile, c.region, c.awsConfigOptions)
 	if err != nil {
 		return errfmt.Wrap(err, "can not get aws config")
 	}
+	if c.awsConfigOptions.RoleARN != "" {
+		// Assume the role here so that the MFA token code is asked before the command starts.
+		if _, err := cfg.Credentials.Retrieve(c.ctx); err != nil {
+			return errfmt.Wrap(err, "can not assume role "+c.awsConfigOptions.RoleARN)
+		}
+	}
 	if c.region == "" {
 		if cfg.Config.Region == "" {
 			c.region = model.RegionUSEast1
@@ -57,7 +80,7 @@ func (c *cfn) parse(cmd *cobra.Command) error {
 		}
 	}
 
-	c.CFnApp, err = di.NewCFnApp(c.ctx, c.profile, c.region)
+	c.CFnApp, err = di.NewCFnApp(c.ctx, c.profile, c.region, c.awsConfigOptions)
 	if err != nil {
 		return errfmt.Wrap(err, "can not create cloudformation application service")
 	}
@@ -0,0 +1,244 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newDeployCmd return deploy command.
+func newDeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy [flags]",
+		Short: "Create or update a CloudFormation stack from a template",
+		Long: `Create or update a CloudFormation stack from a template.
+deploy creates a change set, shows the proposed resource changes and asks for confirmation before executing it.
+If the stack does not exist, it is created.`,
+		Example: `  cfn deploy -p myprofile -r ap-northeast-1 --stack static-web-site \
+    --template cloudformation/static-web-site-distribution/template.yml \
+    --parameters cloudformation/static-web-site-distribution/parameters.json \
+    --capabilities CAPABILITY_NAMED_IAM --tags env=dev`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &deployCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().StringP("stack", "s", "", "stack name to create or update")
+	cmd.Flags().StringP("template", "t", "", "template file (YAML or JSON)")
+	cmd.Flags().String("parameters", "", "parameters file in AWS CLI format ([{\"ParameterKey\": \"KEY\", \"ParameterValue\": \"VALUE\"}])")
+	cmd.Flags().StringSlice("capabilities", nil, "comma separated capabilities (CAPABILITY_IAM, CAPABILITY_NAMED_IAM, CAPABILITY_AUTO_EXPAND)")
+	cmd.Flags().StringSlice("tags", nil, "comma separated stack tags in KEY=VALUE format. they replace the tags of the existing stack")
+	cmd.Flags().BoolP("force", "f", false, "execute the change set without confirmation")
+	return cmd
+}
+
+// deployCmd is the command for deploy.
+type deployCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stack is the name of the stack to create or update.
+	stack string
+	// template is the template body.
+	template string
+	// parameters is the parameters of the stack.
+	parameters model.CFnParameters
+	// capabilities is the capabilities that acknowledge the template contains the certain resources.
+	capabilities []model.CFnCapability
+	// tags is the tags of the stack. If nil, the tags of the existing stack are not changed.
+	tags model.CFnTags
+	// force is the flag to execute the change set without confirmation.
+	force bool
+}
+
+// Parse parses command line arguments.
+func (d *deployCmd) Parse(cmd *cobra.Command, _ []string) error {
+	var err error
+	if d.stack, err = cmd.Flags().GetString("stack"); err != nil {
+		return err
+	}
+	if d.stack == "" {
+		return errors.New("you must specify a stack name with --stack")
+	}
+
+	templateFile, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return err
+	}
+	if templateFile == "" {
+		return errors.New("you must specify a template file with --template")
+	}
+	body, err := os.ReadFile(filepath.Clean(templateFile))
+	if err != nil {
+		return fmt.Errorf("can not read the template: %w", err)
+	}
+	d.template = string(body)
+
+	parametersFile, err := cmd.Flags().GetString("parameters")
+	if err != nil {
+		return err
+	}
+	if parametersFile != "" {
+		data, err := os.ReadFile(filepath.Clean(parametersFile))
+		if err != nil {
+			return fmt.Errorf("can not read the parameters: %w", err)
+		}
+		if d.parameters, err = model.NewCFnParameters(data); err != nil {
+			return fmt.Errorf("%w: %s", err, parametersFile)
+		}
+	}
+
+	capabilities, err := cmd.Flags().GetStringSlice("capabilities")
+	if err != nil {
+		return err
+	}
+	for _, c := range capabilities {
+		capability, err := model.NewCFnCapability(c)
+		if err != nil {
+			return err
+		}
+		d.capabilities = append(d.capabilities, capability)
+	}
+
+	tags, err := cmd.Flags().GetStringSlice("tags")
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("tags") {
+		if d.tags, _, err = parseTagArgs(tags, false); err != nil {
+			return err
+		}
+	}
+
+	if d.force, err = cmd.Flags().GetBool("force"); err != nil {
+		return err
+	}
+
+	d.cfn = newCFn()
+	return d.cfn.parse(cmd)
+}
+
+// Do executes deploy command.
+func (d *deployCmd) Do() error {
+	d.printf("[%s] creating change set\n", color.YellowString(d.stack))
+	plan, err := d.CFnStackDeployPlanner.PlanCFnStackDeploy(d.ctx, &usecase.CFnStackDeployPlannerInput{
+		StackName:    d.stack,
+		Region:       d.region,
+		TemplateBody: d.template,
+		Parameters:   d.parameters,
+		Capabilities: d.capabilities,
+		Tags:         d.tags,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrCFnStackNotUpdatable) {
+			return fmt.Errorf("%w (delete it with 'cfn rm %s' and deploy again)", err, d.stack)
+		}
+		return err
+	}
+	if plan.NoChanges {
+		d.printf("%s: no changes\n", color.GreenString(d.stack))
+		return nil
+	}
+
+	w := d.command.OutOrStdout()
+	fmt.Fprintf(w, "%s %s (%s)\n", planTitle(plan.ChangeSetType), color.YellowString(d.stack), changeSummary(plan.Changes))
+	if err := writeResourceChanges(w, plan.Changes); err != nil {
+		return err
+	}
+	if !d.force && !subcmd.Question(w, fmt.Sprintf("execute the change set of %s?", color.YellowString(d.stack))) {
+		if _, err := d.CFnStackDeployCanceler.CancelCFnStackDeploy(d.ctx, &usecase.CFnStackDeployCancelerInput{
+			ChangeSetID:  plan.ChangeSetID,
+			StackID:      plan.StackID,
+			Region:       d.region,
+			CreatedStack: plan.CreatedStack,
+		}); err != nil {
+			return fmt.Errorf("can not delete the change set: %w", err)
+		}
+		return nil
+	}
+
+	out, err := d.CFnStackDeployer.DeployCFnStack(d.ctx, &usecase.CFnStackDeployerInput{
+		ChangeSetID: plan.ChangeSetID,
+		StackID:     plan.StackID,
+		StackName:   d.stack,
+		Region:      d.region,
+		OnEvent: func(event *model.StackEvent) {
+			d.printf("[%s] %s\n", color.YellowString(d.stack), formatStackEvent(event))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if out.Status == model.StackStatusCreateComplete || out.Status == model.StackStatusUpdateComplete {
+		d.printf("deploy %s (%s)\n", color.GreenString(d.stack), out.Status)
+		return nil
+	}
+
+	fmt.Fprintf(w, "\n%s is %s: %s\n", color.YellowString(d.stack), out.Status.StringWithColor(), out.StatusReason)
+	if len(out.FailedResources) > 0 {
+		if err := writeFailedResources(w, out.FailedResources); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s is %s", domain.ErrCFnStackDeployFailed, d.stack, out.Status)
+}
+
+// planTitle returns the title of the change set.
+func planTitle(changeSetType model.CFnChangeSetType) string {
+	if changeSetType == model.CFnChangeSetTypeCreate {
+		return "create stack"
+	}
+	return "update stack"
+}
+
+// changeSummary returns the number of the resources to add, modify and remove.
+// e.g. "2 to add, 1 to modify, 0 to remove"
+func changeSummary(changes []*model.CFnResourceChange) string {
+	var add, modify, remove int
+	for _, change := range changes {
+		switch change.Action {
+		case model.CFnChangeActionAdd, model.CFnChangeActionImport:
+			add++
+		case model.CFnChangeActionModify, model.CFnChangeActionDynamic:
+			modify++
+		case model.CFnChangeActionRemove:
+			remove++
+		}
+	}
+	return fmt.Sprintf("%d to add, %d to modify, %d to remove", add, modify, remove)
+}
+
+// writeResourceChanges writes the proposed resource changes in table format.
+func writeResourceChanges(w io.Writer, changes []*model.CFnResourceChange) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tLOGICAL_ID\tTYPE\tREPLACEMENT")
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", change.Action, change.LogicalResourceID, change.ResourceType, replacement(change))
+	}
+	return tw.Flush()
+}
+
+// replacement returns whether the resource is recreated. It is meaningful only for the Modify action.
+func replacement(change *model.CFnResourceChange) string {
+	if change.Action != model.CFnChangeActionModify {
+		return "-"
+	}
+	switch change.Replacement {
+	case model.CFnReplacementTrue:
+		return "yes"
+	case model.CFnReplacementConditional:
+		return "conditional"
+	default:
+		return "no"
+	}
+}
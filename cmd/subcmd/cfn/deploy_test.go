@@ -0,0 +1,39 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeResourceChanges(t *testing.T) {
+	t.Parallel()
+
+	changes := []*model.CFnResourceChange{
+		{Action: model.CFnChangeActionAdd, LogicalResourceID: "LogGroup", ResourceType: "AWS::Logs::LogGroup"},
+		{Action: model.CFnChangeActionModify, LogicalResourceID: "Bucket", ResourceType: "AWS::S3::Bucket", Replacement: model.CFnReplacementTrue},
+		{Action: model.CFnChangeActionModify, LogicalResourceID: "Function", ResourceType: "AWS::Lambda::Function", Replacement: model.CFnReplacementFalse},
+		{Action: model.CFnChangeActionModify, LogicalResourceID: "Role", ResourceType: "AWS::IAM::Role", Replacement: model.CFnReplacementConditional},
+		{Action: model.CFnChangeActionRemove, LogicalResourceID: "Topic", ResourceType: "AWS::SNS::Topic"},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeResourceChanges(buf, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "ACTION  LOGICAL_ID  TYPE                   REPLACEMENT\n" +
+		"Add     LogGroup    AWS::Logs::LogGroup    -\n" +
+		"Modify  Bucket      AWS::S3::Bucket        yes\n" +
+		"Modify  Function    AWS::Lambda::Function  no\n" +
+		"Modify  Role        AWS::IAM::Role         conditional\n" +
+		"Remove  Topic       AWS::SNS::Topic        -\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+	if got, want := changeSummary(changes), "1 to add, 3 to modify, 1 to remove"; got != want {
+		t.Errorf("changeSummary() = %q, want %q", got, want)
+	}
+}
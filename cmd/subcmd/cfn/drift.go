@@ -0,0 +1,201 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newDriftCmd return drift command.
+func newDriftCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift [flags] STACK_NAME...",
+		Short: "Detect drift of CloudFormation stacks",
+		Long: `Detect drift of CloudFormation stacks, and show the property differences (expected vs actual) of the drifted resources.
+With --all, the drift of every stack in the region is detected concurrently, and the summary is shown.
+If the detection of a stack fails, the detection of the other stacks continues and the command exits with 1 at the end.`,
+		Example: `  cfn drift -p myprofile -r us-east-1 STACK_NAME_1 STACK_NAME_2
+
+  [Detect drift of all stacks in the region]
+    cfn drift --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &driftCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().BoolP("all", "a", false, "detect drift of all stacks in the region and show the summary")
+	return cmd
+}
+
+// driftCmd is the command for drift.
+type driftCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stacks is the names of the stacks whose drift is detected. It is empty if all is true.
+	stacks []string
+	// all is the flag to detect drift of all stacks in the region.
+	all bool
+}
+
+// Parse parses command line arguments.
+func (d *driftCmd) Parse(cmd *cobra.Command, args []string) error {
+	var err error
+	if d.all, err = cmd.Flags().GetBool("all"); err != nil {
+		return err
+	}
+	if d.all && len(args) > 0 {
+		return errors.New("you can not specify a stack name with --all")
+	}
+	if !d.all && len(args) == 0 {
+		return errors.New("you must specify a stack name or --all")
+	}
+	d.stacks = args
+
+	d.cfn = newCFn()
+	return d.cfn.parse(cmd)
+}
+
+// Do executes drift command.
+func (d *driftCmd) Do() error {
+	stacks := d.stacks
+	if d.all {
+		var err error
+		if stacks, err = d.detectableStacks(); err != nil {
+			return fmt.Errorf("%w: region=%s", err, d.region)
+		}
+		if len(stacks) == 0 {
+			d.printf("no stacks support drift detection in %s\n", d.region)
+			return nil
+		}
+	}
+
+	d.printf("detecting drift of %d stack(s) in %s\n", len(stacks), d.region)
+	out, err := d.CFnStackDriftDetector.DetectCFnStackDrift(d.ctx, &usecase.CFnStackDriftDetectorInput{
+		StackNames:  stacks,
+		Region:      d.region,
+		Concurrency: d.concurrency,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, d.region)
+	}
+
+	w := d.command.OutOrStdout()
+	if d.all {
+		if err := writeStackDriftSummary(w, out.Drifts); err != nil {
+			return err
+		}
+		d.printf("%d of %d stacks drifted\n", countDriftedStacks(out.Drifts), len(out.Drifts))
+	} else {
+		for _, drift := range out.Drifts {
+			writeStackDrift(w, drift)
+		}
+	}
+	if failed := countFailedDetections(out.Drifts); failed > 0 {
+		return fmt.Errorf("%w: %d of %d stacks", domain.ErrCFnDriftDetectionFailed, failed, len(out.Drifts))
+	}
+	return nil
+}
+
+// detectableStacks returns the names of the stacks whose status supports drift detection.
+func (d *driftCmd) detectableStacks() ([]string, error) {
+	out, err := d.CFnStackLister.ListCFnStack(d.ctx, &usecase.CFnStackListerInput{
+		Region: d.region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(out.Stacks))
+	skipped := make([]string, 0)
+	for _, stack := range out.Stacks {
+		switch {
+		case stack.StackStatus == model.StackStatusDeleteComplete:
+			continue
+		case !stack.StackStatus.DriftDetectable():
+			skipped = append(skipped, fmt.Sprintf("%s(%s)", aws.ToString(stack.StackName), stack.StackStatus))
+		default:
+			names = append(names, aws.ToString(stack.StackName))
+		}
+	}
+	if len(skipped) > 0 {
+		d.printf("skip the stacks whose status does not support drift detection: %s\n", strings.Join(skipped, ", "))
+	}
+	return names, nil
+}
+
+// writeStackDrift writes the drift status of the stack and the property differences of the drifted resources.
+func writeStackDrift(w io.Writer, drift *model.CFnStackDrift) {
+	if drift.Err != nil {
+		fmt.Fprintf(w, "%s: %v\n", color.RedString("ERROR"), drift.Err)
+		return
+	}
+	fmt.Fprintf(w, "%s is %s\n", color.YellowString(drift.StackName), drift.DriftStatus.StringWithColor())
+	if drift.DetectionStatus == model.CFnDriftDetectionStatusFailed {
+		fmt.Fprintf(w, "  %s: %s\n", color.RedString("detection failed for some resources"), drift.DetectionStatusReason)
+	}
+	for _, resource := range drift.Resources {
+		fmt.Fprintf(w, "  %s %s (%s)\n", color.RedString(string(resource.DriftStatus)), resource.LogicalResourceID, resource.ResourceType)
+		for _, diff := range resource.PropertyDifferences {
+			switch diff.DifferenceType {
+			case model.CFnPropertyDifferenceTypeAdd:
+				fmt.Fprintf(w, "    + %s\n", diff.PropertyPath)
+				fmt.Fprintf(w, "        actual:   %s\n", color.GreenString(diff.ActualValue))
+			case model.CFnPropertyDifferenceTypeRemove:
+				fmt.Fprintf(w, "    - %s\n", diff.PropertyPath)
+				fmt.Fprintf(w, "        expected: %s\n", color.RedString(diff.ExpectedValue))
+			default:
+				fmt.Fprintf(w, "    ~ %s\n", diff.PropertyPath)
+				fmt.Fprintf(w, "        expected: %s\n", color.RedString(diff.ExpectedValue))
+				fmt.Fprintf(w, "        actual:   %s\n", color.GreenString(diff.ActualValue))
+			}
+		}
+	}
+}
+
+// writeStackDriftSummary writes the drift status of the stacks in table format.
+// The stacks whose detection failed have the error in the ERROR column.
+func writeStackDriftSummary(w io.Writer, drifts []*model.CFnStackDrift) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STACK\tDRIFT\tDRIFTED_RESOURCES\tDETECTION\tERROR")
+	for _, drift := range drifts {
+		if drift.Err != nil {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t%v\n", drift.StackName, drift.Err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t-\n", drift.StackName, drift.DriftStatus, drift.DriftedResourceCount, drift.DetectionStatus)
+	}
+	return tw.Flush()
+}
+
+// countDriftedStacks returns the number of the stacks that drifted.
+func countDriftedStacks(drifts []*model.CFnStackDrift) int {
+	count := 0
+	for _, drift := range drifts {
+		if drift.DriftStatus == model.StackDriftStatusDrifted {
+			count++
+		}
+	}
+	return count
+}
+
+// countFailedDetections returns the number of the stacks whose drift detection failed.
+func countFailedDetections(drifts []*model.CFnStackDrift) int {
+	count := 0
+	for _, drift := range drifts {
+		if drift.Err != nil {
+			count++
+		}
+	}
+	return count
+}
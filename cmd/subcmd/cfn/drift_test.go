@@ -0,0 +1,91 @@
+package cfn
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeStackDrift(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	writeStackDrift(buf, &model.CFnStackDrift{
+		StackName:             "app",
+		DetectionStatus:       model.CFnDriftDetectionStatusFailed,
+		DetectionStatusReason: "Failed to detect drift on resource [Function]",
+		DriftStatus:           model.StackDriftStatusDrifted,
+		DriftedResourceCount:  2,
+		Resources: []*model.CFnStackResourceDrift{
+			{
+				LogicalResourceID: "Bucket",
+				ResourceType:      "AWS::S3::Bucket",
+				DriftStatus:       model.StackResourceDriftStatusModified,
+				PropertyDifferences: []model.CFnPropertyDifference{
+					{PropertyPath: "/VersioningConfiguration/Status", ExpectedValue: "Enabled", ActualValue: "Suspended", DifferenceType: model.CFnPropertyDifferenceTypeNotEqual},
+					{PropertyPath: "/Tags/1", ActualValue: `{"Key":"owner","Value":"me"}`, DifferenceType: model.CFnPropertyDifferenceTypeAdd},
+					{PropertyPath: "/Tags/0", ExpectedValue: `{"Key":"env","Value":"dev"}`, DifferenceType: model.CFnPropertyDifferenceTypeRemove},
+				},
+			},
+			{
+				LogicalResourceID: "Queue",
+				ResourceType:      "AWS::SQS::Queue",
+				DriftStatus:       model.StackResourceDriftStatusDeleted,
+			},
+		},
+	})
+
+	want := `app is DRIFTED
+  detection failed for some resources: Failed to detect drift on resource [Function]
+  MODIFIED Bucket (AWS::S3::Bucket)
+    ~ /VersioningConfiguration/Status
+        expected: Enabled
+        actual:   Suspended
+    + /Tags/1
+        actual:   {"Key":"owner","Value":"me"}
+    - /Tags/0
+        expected: {"Key":"env","Value":"dev"}
+  DELETED Queue (AWS::SQS::Queue)
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+
+	buf.Reset()
+	writeStackDrift(buf, &model.CFnStackDrift{StackName: "db", Err: errors.New("can not detect the drift of db: Rate exceeded")})
+	if diff := cmp.Diff("ERROR: can not detect the drift of db: Rate exceeded\n", buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_writeStackDriftSummary(t *testing.T) {
+	t.Parallel()
+
+	drifts := []*model.CFnStackDrift{
+		{StackName: "app", DetectionStatus: model.CFnDriftDetectionStatusComplete, DriftStatus: model.StackDriftStatusDrifted, DriftedResourceCount: 1},
+		{StackName: "db", DetectionStatus: model.CFnDriftDetectionStatusComplete, DriftStatus: model.StackDriftStatusInSync},
+		{StackName: "web", Err: errors.New("can not detect the drift of web: Rate exceeded")},
+	}
+	buf := &bytes.Buffer{}
+	if err := writeStackDriftSummary(buf, drifts); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `STACK  DRIFT    DRIFTED_RESOURCES  DETECTION           ERROR
+app    DRIFTED  1                  DETECTION_COMPLETE  -
+db     IN_SYNC  0                  DETECTION_COMPLETE  -
+web    -        -                  -                   can not detect the drift of web: Rate exceeded
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+	if got := countDriftedStacks(drifts); got != 1 {
+		t.Errorf("countDriftedStacks() = %d, want 1", got)
+	}
+	if got := countFailedDetections(drifts); got != 1 {
+		t.Errorf("countFailedDetections() = %d, want 1", got)
+	}
+}
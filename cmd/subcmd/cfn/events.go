@@ -0,0 +1,113 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newEventsCmd return events command.
+func newEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events [flags] STACK_NAME",
+		Short: "Show CloudFormation stack events",
+		Long: `Show CloudFormation stack events including the events of the nested stacks.
+With --follow, the events are printed as they occur until the stack reaches a terminal status.`,
+		Example: `  cfn events -p myprofile -r us-east-1 STACK_NAME
+
+  [Watch the deployment in progress]
+    cfn events --follow STACK_NAME
+
+  [Show the failed events in the last 30 minutes]
+    cfn events --since 30m --failed-only STACK_NAME`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &eventsCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().BoolP("follow", "f", false, "keep printing the events until the stack reaches a terminal status")
+	cmd.Flags().Duration("since", 0, "show the events newer than the relative duration (e.g. 10m, 1h). default is the latest events")
+	cmd.Flags().Bool("failed-only", false, "show only the failed events")
+	return cmd
+}
+
+// eventsCmd is the command for events.
+type eventsCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stack is the name of the stack.
+	stack string
+	// follow is the flag to keep printing the events until the stack reaches a terminal status.
+	follow bool
+	// since is the time from which the events are printed. If it is zero, the latest events are printed.
+	since time.Time
+	// failedOnly is the flag to print only the failed events.
+	failedOnly bool
+}
+
+// Parse parses command line arguments.
+func (e *eventsCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("you must specify a stack name")
+	}
+	e.stack = args[0]
+
+	var err error
+	if e.follow, err = cmd.Flags().GetBool("follow"); err != nil {
+		return err
+	}
+	since, err := cmd.Flags().GetDuration("since")
+	if err != nil {
+		return err
+	}
+	if since < 0 {
+		return fmt.Errorf("--since must be positive: %s", since)
+	}
+	if since > 0 {
+		e.since = time.Now().Add(-since)
+	}
+	if e.failedOnly, err = cmd.Flags().GetBool("failed-only"); err != nil {
+		return err
+	}
+
+	e.cfn = newCFn()
+	return e.cfn.parse(cmd)
+}
+
+// Do executes events command.
+func (e *eventsCmd) Do() error {
+	w := e.command.OutOrStdout()
+	out, err := e.CFnStackEventsTailer.TailCFnStackEvents(e.ctx, &usecase.CFnStackEventsTailerInput{
+		StackName: e.stack,
+		Region:    e.region,
+		Since:     e.since,
+		Follow:    e.follow,
+		OnEvent: func(event *model.StackEvent) {
+			if e.failedOnly && !event.ResourceStatus.Failed() {
+				return
+			}
+			writeStackEvent(w, event)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, e.region)
+	}
+	if e.follow {
+		e.printf("%s is %s\n", color.YellowString(e.stack), out.Status.StringWithColor())
+	}
+	return nil
+}
+
+// writeStackEvent writes the stack event with the name of the stack, so that the events of the nested stacks can be distinguished.
+func writeStackEvent(w io.Writer, event *model.StackEvent) {
+	fmt.Fprintf(w, "[%s] %s\n", color.YellowString(aws.ToString(event.StackName)), formatStackEvent(event))
+}
@@ -0,0 +1,24 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeStackEvent(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	writeStackEvent(buf, &model.StackEvent{
+		StackName:         aws.String("app-Nested-1"),
+		ResourceStatus:    model.ResourceStatusCreateComplete,
+		ResourceType:      aws.String("AWS::S3::Bucket"),
+		LogicalResourceID: aws.String("Bucket"),
+	})
+	if got, want := buf.String(), "[app-Nested-1] - CREATE_COMPLETE AWS::S3::Bucket Bucket\n"; got != want {
+		t.Errorf("writeStackEvent() = %q, want %q", got, want)
+	}
+}
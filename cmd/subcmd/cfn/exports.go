@@ -0,0 +1,98 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newExportsCmd return exports command.
+func newExportsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exports [flags]",
+		Short: "List CloudFormation exports and the stacks importing them",
+		Long: `List CloudFormation exports in the region.
+With --importers, the stacks that import each export (Fn::ImportValue) are listed too.
+The stack whose exports are imported can not be deleted until the importers stop importing them.`,
+		Example: `  cfn exports -p myprofile -r us-east-1
+
+  [Show the stacks that import the exports of STACK_NAME]
+    cfn exports --importers --stack STACK_NAME`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &exportsCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().BoolP("importers", "i", false, "list the stacks that import each export")
+	cmd.Flags().StringP("stack", "s", "", "list only the exports of the stack")
+	return cmd
+}
+
+// exportsCmd is the command for exports.
+type exportsCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// importers is the flag to list the stacks that import each export.
+	importers bool
+	// stack is the name of the stack whose exports are listed. If it is empty, all exports are listed.
+	stack string
+}
+
+// Parse parses command line arguments.
+func (e *exportsCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return errors.New("exports does not take arguments. use --stack to filter the exports")
+	}
+
+	var err error
+	if e.importers, err = cmd.Flags().GetBool("importers"); err != nil {
+		return err
+	}
+	if e.stack, err = cmd.Flags().GetString("stack"); err != nil {
+		return err
+	}
+
+	e.cfn = newCFn()
+	return e.cfn.parse(cmd)
+}
+
+// Do executes exports command.
+func (e *exportsCmd) Do() error {
+	out, err := e.CFnExportsLister.ListCFnExports(e.ctx, &usecase.CFnExportsListerInput{
+		Region:      e.region,
+		StackName:   e.stack,
+		Importers:   e.importers,
+		Concurrency: e.concurrency,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, e.region)
+	}
+	return writeExports(e.command.OutOrStdout(), out.Exports, e.importers)
+}
+
+// writeExports writes the exports in table format. If importers is true, the IMPORTERS column is written.
+func writeExports(w io.Writer, exports []*model.CFnExport, importers bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if importers {
+		fmt.Fprintln(tw, "NAME\tVALUE\tEXPORTING_STACK\tIMPORTERS")
+	} else {
+		fmt.Fprintln(tw, "NAME\tVALUE\tEXPORTING_STACK")
+	}
+	for _, export := range exports {
+		stack := model.StackNameFromID(export.ExportingStackID)
+		if importers {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", export.Name, export.Value, stack, orHyphen(strings.Join(export.Importers, ",")))
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", export.Name, export.Value, stack)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,55 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeExports(t *testing.T) {
+	t.Parallel()
+
+	exports := []*model.CFnExport{
+		{Name: "app-BucketName", Value: "bucket", ExportingStackID: "arn:aws:cloudformation:us-east-1:123456789012:stack/app/1", Importers: []string{}},
+		{Name: "db-Endpoint", Value: "db.example.com", ExportingStackID: "arn:aws:cloudformation:us-east-1:123456789012:stack/db/2", Importers: []string{"app", "web"}},
+	}
+
+	tests := []struct {
+		name      string
+		importers bool
+		want      string
+	}{
+		{
+			name:      "without importers",
+			importers: false,
+			want: `NAME            VALUE           EXPORTING_STACK
+app-BucketName  bucket          app
+db-Endpoint     db.example.com  db
+`,
+		},
+		{
+			name:      "with importers",
+			importers: true,
+			want: `NAME            VALUE           EXPORTING_STACK  IMPORTERS
+app-BucketName  bucket          app              -
+db-Endpoint     db.example.com  db               app,web
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			if err := writeExports(buf, exports, tt.importers); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
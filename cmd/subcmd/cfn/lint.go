@@ -0,0 +1,167 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newLintCmd return lint command.
+func newLintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint TEMPLATE...",
+		Short: "Check CloudFormation templates without AWS",
+		Long: `Check CloudFormation templates without AWS.
+
+lint reports the undefined Ref, Fn::GetAtt and Fn::Sub targets, the unused parameters,
+the invalid DependsOn, the circular dependencies between the resources and the missing
+Resources section. The short form of the intrinsic functions (e.g. !Ref, !Sub) is supported.
+
+If TEMPLATE is a directory, the templates (*.yml, *.yaml, *.json and *.template) under it are
+checked. The files that are not CloudFormation templates (e.g. parameters.json) are skipped.
+
+Exit status is 0 if no errors are found, and 1 if errors are found. Warnings do not fail.`,
+		Example: `  cfn lint template.yml
+  cfn lint cloudformation/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &lintCmd{})
+		},
+	}
+}
+
+// lintCmd is the command for lint. It does not embed cfn because it does not use AWS.
+type lintCmd struct {
+	// command is the cobra command.
+	command *cobra.Command
+	// files is the paths of the templates to check.
+	files []string
+}
+
+// Parse parses command line arguments.
+func (l *lintCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("you must specify a template file or a directory")
+	}
+	l.command = cmd
+
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return fmt.Errorf("can not read the template: %w", err)
+		}
+		if !info.IsDir() {
+			l.files = append(l.files, arg)
+			continue
+		}
+		files, err := findTemplateFiles(arg)
+		if err != nil {
+			return err
+		}
+		l.files = append(l.files, files...)
+	}
+	return nil
+}
+
+// Do executes lint command.
+func (l *lintCmd) Do() error {
+	w := l.command.OutOrStdout()
+	errorCount, warningCount := 0, 0
+	for _, file := range l.files {
+		issues, err := lintTemplateFile(file)
+		if err != nil {
+			issues = []model.CFnLintIssue{{Severity: model.CFnLintSeverityError, Message: err.Error()}}
+		}
+		writeLintIssues(w, file, issues)
+		for _, issue := range issues {
+			if issue.Severity == model.CFnLintSeverityError {
+				errorCount++
+			} else {
+				warningCount++
+			}
+		}
+	}
+
+	l.command.Printf("%d templates, %d errors, %d warnings\n", len(l.files), errorCount, warningCount)
+	if errorCount > 0 {
+		return fmt.Errorf("found %d errors in the templates", errorCount)
+	}
+	return nil
+}
+
+// lintTemplateFile parses the template file and returns the lint issues.
+func lintTemplateFile(path string) ([]model.CFnLintIssue, error) {
+	body, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("can not read the template: %w", err)
+	}
+	template, err := model.NewCFnTemplate(body)
+	if err != nil {
+		return nil, err
+	}
+	return template.Lint(), nil
+}
+
+// findTemplateFiles returns the CloudFormation templates under the directory in lexical order.
+// The files that do not have the Resources or the AWSTemplateFormatVersion section are not templates.
+func findTemplateFiles(dir string) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isTemplateExtension(path) {
+			return nil
+		}
+		body, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		template, err := model.NewCFnTemplate(body)
+		if err != nil {
+			return nil //nolint:nilerr // The file that can not be parsed is not a template.
+		}
+		if template.Section("Resources") != nil || template.HasSection("AWSTemplateFormatVersion") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not find the templates in %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// isTemplateExtension returns true if the file has the extension of CloudFormation templates.
+func isTemplateExtension(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml", ".json", ".template":
+		return true
+	default:
+		return false
+	}
+}
+
+// writeLintIssues writes the lint issues of the template file.
+// e.g. "template.yml: Resources.Bucket.Properties.Name.Ref: ERROR Ref refers to the undefined parameter or resource Name"
+func writeLintIssues(w io.Writer, file string, issues []model.CFnLintIssue) {
+	for _, issue := range issues {
+		severity := color.YellowString(issue.Severity.String())
+		if issue.Severity == model.CFnLintSeverityError {
+			severity = color.RedString(issue.Severity.String())
+		}
+		location := file
+		if issue.Path != "" {
+			location += ": " + issue.Path
+		}
+		fmt.Fprintf(w, "%s: %s %s\n", location, severity, issue.Message)
+	}
+}
@@ -0,0 +1,86 @@
+package cfn
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_lintTemplates(t *testing.T) {
+	t.Parallel()
+
+	// The templates in this repository must pass the linter.
+	files, err := findTemplateFiles(filepath.Join("..", "..", "..", "cloudformation"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no templates are found")
+	}
+	for _, file := range files {
+		issues, err := lintTemplateFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, issue := range issues {
+			t.Errorf("%s: %s: %s %s", file, issue.Path, issue.Severity, issue.Message)
+		}
+	}
+}
+
+func Test_findTemplateFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"app/template.yml":       "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n",
+		"app/parameters.json":    `[{"ParameterKey": "Env", "ParameterValue": "dev"}]`,
+		"db/template.json":       `{"AWSTemplateFormatVersion": "2010-09-09", "Resources": {}}`,
+		"docs/README.md":         "# README",
+		"docs/mkdocs.yml":        "site_name: docs",
+		"network/vpc.template":   "Resources:\n  Vpc:\n    Type: AWS::EC2::VPC\n",
+		"network/.github/ci.yml": "on: push\n",
+	}
+	for name, data := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := findTemplateFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "app/template.yml"),
+		filepath.Join(dir, "db/template.json"),
+		filepath.Join(dir, "network/vpc.template"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_writeLintIssues(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	writeLintIssues(buf, "template.yml", []model.CFnLintIssue{
+		{Severity: model.CFnLintSeverityWarning, Path: "Parameters.Env", Message: "the parameter Env is not used"},
+		{Severity: model.CFnLintSeverityError, Message: "the template is empty"},
+	})
+	want := `template.yml: Parameters.Env: WARNING the parameter Env is not used
+template.yml: ERROR the template is empty
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
@@ -1,6 +1,14 @@
 package cfn
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/fatih/color"
 	"github.com/nao1215/rainbow/app/domain/model"
 	"github.com/nao1215/rainbow/app/usecase"
@@ -14,7 +22,19 @@ func newLsCmd() *cobra.Command {
 		Use:     "ls [flags]",
 		Aliases: []string{"list"},
 		Short:   "List CloudFormation stacks",
-		Example: `  cfn ls -p myprofile -r us-east-1`,
+		Example: `  cfn ls -p myprofile -r us-east-1
+
+  [List stacks in all regions]
+    cfn ls --all-regions
+
+  [List stacks in the specified regions]
+    cfn ls --regions us-east-1,ap-northeast-1,eu-west-1
+
+  [List failed stacks of the dev environment that have not been updated for 30 days]
+    cfn ls --status failed --name '^dev-' --tag env=dev --older-than 30d
+
+  [List stacks including the deleted stacks, the most recently updated first]
+    cfn ls --include-deleted --sort updated`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return subcmd.Run(cmd, args, &lsCmd{})
 		},
@@ -22,6 +42,14 @@ func newLsCmd() *cobra.Command {
 	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
 	// not used. however, this is common flag.
 	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().Bool("all-regions", false, "list stacks in all regions of the partition (regions that are not enabled are skipped)")
+	cmd.Flags().StringSlice("regions", nil, "comma separated list of regions where stacks are listed")
+	cmd.Flags().StringSlice("status", nil, "list stacks in the statuses (e.g. CREATE_COMPLETE) or the groups (failed, in-progress, complete). repeatable")
+	cmd.Flags().String("name", "", "list stacks whose names match the regular expression")
+	cmd.Flags().StringArray("tag", nil, "list stacks that have the tag in KEY=VALUE format. repeatable")
+	cmd.Flags().String("older-than", "", "list stacks that have not been updated for the age (e.g. 30d, 12h, 2w)")
+	cmd.Flags().String("sort", model.CFnStackSortKeyName.String(), "sort stacks by name, updated, created or status")
+	cmd.Flags().Bool("include-deleted", false, "list the deleted stacks too with the deletion time and the reason")
 	return cmd
 }
 
@@ -29,17 +57,126 @@ func newLsCmd() *cobra.Command {
 type lsCmd struct {
 	// cfn have common fields and methods for cfn commands.
 	*cfn
+	// regions is the list of regions where stacks are listed. If it is empty, only the region of cfn is listed.
+	regions []model.Region
+	// filter is the condition of the stacks to be listed.
+	filter *model.CFnStackFilter
+	// sortKey is the key to sort the stacks.
+	sortKey model.CFnStackSortKey
 }
 
 // Parse parses command line arguments.
 func (l *lsCmd) Parse(cmd *cobra.Command, _ []string) error {
+	allRegions, err := cmd.Flags().GetBool("all-regions")
+	if err != nil {
+		return err
+	}
+	regions, err := cmd.Flags().GetStringSlice("regions")
+	if err != nil {
+		return err
+	}
+	if allRegions && len(regions) > 0 {
+		return errors.New("--all-regions and --regions can not be specified at the same time")
+	}
+	for _, r := range regions {
+		region := model.Region(r)
+		if err := region.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", err, color.YellowString(r))
+		}
+		l.regions = append(l.regions, region)
+	}
+	if l.filter, err = newStackFilter(cmd); err != nil {
+		return err
+	}
+	sortKey, err := cmd.Flags().GetString("sort")
+	if err != nil {
+		return err
+	}
+	if l.sortKey, err = model.NewCFnStackSortKey(sortKey); err != nil {
+		return err
+	}
+
 	l.cfn = newCFn()
-	return l.cfn.parse(cmd)
+	if err := l.cfn.parse(cmd); err != nil {
+		return err
+	}
+	if allRegions {
+		l.regions = regionsInPartition(l.cfn.region.Partition())
+	}
+	return nil
+}
+
+// newStackFilter returns the filter of the stacks from the command line flags.
+func newStackFilter(cmd *cobra.Command) (*model.CFnStackFilter, error) {
+	filter := &model.CFnStackFilter{}
+
+	statuses, err := cmd.Flags().GetStringSlice("status")
+	if err != nil {
+		return nil, err
+	}
+	if filter.Statuses, err = model.NewStackStatuses(statuses); err != nil {
+		return nil, err
+	}
+
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if filter.Name, err = regexp.Compile(name); err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
+
+	tags, err := cmd.Flags().GetStringArray("tag")
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		tag, err := model.NewCFnTag(t)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+
+	olderThan, err := cmd.Flags().GetString("older-than")
+	if err != nil {
+		return nil, err
+	}
+	if olderThan != "" {
+		if filter.OlderThan, err = model.NewCFnStackAge(olderThan); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.IncludeDeleted, err = cmd.Flags().GetBool("include-deleted"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// regionsInPartition returns the regions in the partition. The credentials of a partition can not be used in other partitions.
+func regionsInPartition(partition model.Partition) []model.Region {
+	regions := []model.Region{}
+	for _, info := range model.RegionInfos() {
+		if info.Partition == partition {
+			regions = append(regions, info.Region)
+		}
+	}
+	return regions
 }
 
+// Do executes ls command.
 func (l *lsCmd) Do() error {
+	if len(l.regions) > 0 {
+		return l.listStacksInRegions()
+	}
+
 	out, err := l.CFnStackLister.ListCFnStack(l.ctx, &usecase.CFnStackListerInput{
-		Region: l.cfn.region,
+		Region:  l.cfn.region,
+		Filter:  l.filter,
+		SortKey: l.sortKey,
 	})
 	if err != nil {
 		return err
@@ -50,16 +187,90 @@ func (l *lsCmd) Do() error {
 		l.printf("  No stacks\n")
 		return nil
 	}
-
 	for _, stack := range out.Stacks {
-		if stack.StackName == nil || stack.StackStatus == model.StackStatusDeleteComplete {
+		l.printf("  %s\n", formatStack(stack))
+	}
+	return nil
+}
+
+// formatStack returns the stack name with the status and the last updated time.
+// The deleted stack is shown with the deletion time and the reason instead.
+func formatStack(stack *model.Stack) string {
+	if stack.StackStatus == model.StackStatusDeleteComplete {
+		return fmt.Sprintf("%s (status=%s, deleted_at=%s, reason=%s)",
+			color.HiBlackString(aws.ToString(stack.StackName)),
+			stack.StackStatus.StringWithColor(),
+			stackDeletedAt(stack),
+			orHyphen(aws.ToString(stack.StackStatusReason)))
+	}
+	return fmt.Sprintf("%s (status=%s, updated_at=%s)",
+		color.GreenString(aws.ToString(stack.StackName)),
+		stack.StackStatus.StringWithColor(),
+		stackUpdatedAt(stack))
+}
+
+// listStacksInRegions lists the stacks in the regions concurrently and prints them with the region column.
+func (l *lsCmd) listStacksInRegions() error {
+	out, err := l.CFnStacksInRegionsLister.ListCFnStacksInRegions(l.ctx, &usecase.CFnStacksInRegionsListerInput{
+		Regions:     l.regions,
+		Concurrency: l.concurrency,
+		Filter:      l.filter,
+		SortKey:     l.sortKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, region := range out.SkippedRegions {
+		l.printf("%s: skip %s (the region is not enabled for the account)\n", color.YellowString("WARN"), region)
+	}
+	return writeRegionalStacks(l.command.OutOrStdout(), out.Stacks, l.filter.IncludeDeleted)
+}
+
+// writeRegionalStacks writes the stacks in table format. If history is true, the deleted stacks are written
+// with the deletion time and the reason. Otherwise, the deleted stacks are not written.
+func writeRegionalStacks(w io.Writer, stacks []*model.RegionalStack, history bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if history {
+		fmt.Fprintln(tw, "REGION\tSTACK\tSTATUS\tUPDATED_AT\tDELETED_AT\tREASON")
+	} else {
+		fmt.Fprintln(tw, "REGION\tSTACK\tSTATUS\tUPDATED_AT")
+	}
+	for _, stack := range stacks {
+		if stack.StackName == nil {
 			continue
 		}
+		if !history {
+			if stack.StackStatus == model.StackStatusDeleteComplete {
+				continue
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", stack.Region, *stack.StackName, stack.StackStatus, stackUpdatedAt(stack.Stack))
+			continue
+		}
+		reason := "-"
+		if stack.StackStatus == model.StackStatusDeleteComplete {
+			reason = orHyphen(aws.ToString(stack.StackStatusReason))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			stack.Region, *stack.StackName, stack.StackStatus, stackUpdatedAt(stack.Stack), stackDeletedAt(stack.Stack), reason)
+	}
+	return tw.Flush()
+}
 
-		l.printf("  %s (status=%s, updated_at=%s)\n",
-			color.GreenString(*stack.StackName),
-			stack.StackStatus.StringWithColor(),
-			stack.LastUpdatedTime.Format("2006-01-02 15:04:05"))
+// stackUpdatedAt returns the last updated time of the stack. If the stack has never been updated, it returns the creation time.
+func stackUpdatedAt(stack *model.Stack) string {
+	return formatStackTime(stack.UpdatedAt())
+}
+
+// stackDeletedAt returns the deletion time of the stack. If the stack is not deleted, it returns "-".
+func stackDeletedAt(stack *model.Stack) string {
+	return formatStackTime(stack.DeletionTime)
+}
+
+// formatStackTime returns the time in the format of the ls command. If the time is unknown, it returns "-".
+func formatStackTime(t *time.Time) string {
+	if t == nil {
+		return "-"
 	}
-	return nil
+	return t.Format("2006-01-02 15:04:05")
 }
@@ -0,0 +1,142 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeRegionalStacks(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2023, 12, 1, 9, 30, 0, 0, time.UTC)
+	updated := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	stacks := []*model.RegionalStack{
+		{
+			Region: model.RegionAPNortheast1,
+			Stack: &model.Stack{
+				StackName:       aws.String("app-stack"),
+				StackStatus:     model.StackStatusUpdateComplete,
+				CreationTime:    &created,
+				LastUpdatedTime: &updated,
+			},
+		},
+		{
+			Region: model.RegionAPNortheast1,
+			Stack: &model.Stack{
+				StackName:   aws.String("deleted-stack"),
+				StackStatus: model.StackStatusDeleteComplete,
+			},
+		},
+		{
+			Region: model.RegionUSEast1,
+			Stack: &model.Stack{
+				StackName:    aws.String("cdk-assets"),
+				StackStatus:  model.StackStatusCreateComplete,
+				CreationTime: &created,
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeRegionalStacks(buf, stacks, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "REGION          STACK       STATUS           UPDATED_AT\n" +
+		"ap-northeast-1  app-stack   UPDATE_COMPLETE  2024-01-10 12:00:00\n" +
+		"us-east-1       cdk-assets  CREATE_COMPLETE  2023-12-01 09:30:00\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_writeRegionalStacks_history(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2023, 12, 1, 9, 30, 0, 0, time.UTC)
+	deleted := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	stacks := []*model.RegionalStack{
+		{
+			Region: model.RegionUSEast1,
+			Stack: &model.Stack{
+				StackName:    aws.String("app-stack"),
+				StackStatus:  model.StackStatusCreateComplete,
+				CreationTime: &created,
+			},
+		},
+		{
+			Region: model.RegionUSEast1,
+			Stack: &model.Stack{
+				StackName:         aws.String("old-stack"),
+				StackStatus:       model.StackStatusDeleteComplete,
+				CreationTime:      &created,
+				DeletionTime:      &deleted,
+				StackStatusReason: aws.String("User Initiated"),
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeRegionalStacks(buf, stacks, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "REGION     STACK      STATUS           UPDATED_AT           DELETED_AT           REASON\n" +
+		"us-east-1  app-stack  CREATE_COMPLETE  2023-12-01 09:30:00  -                    -\n" +
+		"us-east-1  old-stack  DELETE_COMPLETE  2023-12-01 09:30:00  2024-01-10 12:00:00  User Initiated\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_formatStack(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2023, 12, 1, 9, 30, 0, 0, time.UTC)
+	deleted := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		stack *model.Stack
+		want  string
+	}{
+		{
+			name:  "stack",
+			stack: &model.Stack{StackName: aws.String("app"), StackStatus: model.StackStatusCreateComplete, CreationTime: &created},
+			want:  "app (status=CREATE_COMPLETE, updated_at=2023-12-01 09:30:00)",
+		},
+		{
+			name: "deleted stack",
+			stack: &model.Stack{
+				StackName:    aws.String("app"),
+				StackStatus:  model.StackStatusDeleteComplete,
+				CreationTime: &created,
+				DeletionTime: &deleted,
+			},
+			want: "app (status=DELETE_COMPLETE, deleted_at=2024-01-10 12:00:00, reason=-)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, formatStack(tt.stack)); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_regionsInPartition(t *testing.T) {
+	t.Parallel()
+
+	want := []model.Region{model.RegionCNNorth1, model.RegionCNNorthwest1}
+	if diff := cmp.Diff(want, regionsInPartition(model.PartitionAWSCN)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,129 @@
+package cfn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newOutputsCmd return outputs command.
+func newOutputsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outputs [flags] STACK_NAME",
+		Short: "Show outputs of the CloudFormation stack",
+		Example: `  cfn outputs -p myprofile -r us-east-1 STACK_NAME
+  cfn outputs -o json STACK_NAME
+
+  [Print only the value of the output for scripts]
+    BUCKET=$(cfn outputs --query BucketName STACK_NAME)`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &outputsCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().StringP("output", "o", subcmd.OutputFormatTable.String(), "output format (table, json)")
+	cmd.Flags().StringP("query", "q", "", "print only the value of the output with the key")
+	return cmd
+}
+
+// outputsCmd is the command for outputs.
+type outputsCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stack is the name of the stack.
+	stack string
+	// format is the output format.
+	format subcmd.OutputFormat
+	// query is the key of the output whose value is printed. If it is empty, all outputs are printed.
+	query string
+}
+
+// Parse parses command line arguments.
+func (o *outputsCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("you must specify a stack name")
+	}
+	o.stack = args[0]
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if o.format, err = subcmd.NewOutputFormat(output); err != nil {
+		return err
+	}
+	if o.format == subcmd.OutputFormatCSV {
+		if cmd.Flags().Changed("output") {
+			return fmt.Errorf("unsupported output format: %s (table or json)", o.format)
+		}
+		o.format = subcmd.OutputFormatTable // csv in the config file is the default for other commands.
+	}
+	if o.query, err = cmd.Flags().GetString("query"); err != nil {
+		return err
+	}
+
+	o.cfn = newCFn()
+	return o.cfn.parse(cmd)
+}
+
+// Do executes outputs command.
+func (o *outputsCmd) Do() error {
+	out, err := o.CFnStackDescriber.DescribeCFnStack(o.ctx, &usecase.CFnStackDescriberInput{
+		StackName: o.stack,
+		Region:    o.region,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, o.region)
+	}
+
+	w := o.command.OutOrStdout()
+	if o.query != "" {
+		output, ok := out.Outputs.Find(o.query)
+		if !ok {
+			return fmt.Errorf("%w: %s in %s", domain.ErrCFnOutputNotFound, o.query, o.stack)
+		}
+		_, err := fmt.Fprintln(w, output.Value)
+		return err
+	}
+	if o.format == subcmd.OutputFormatJSON {
+		return writeOutputsJSON(w, out.Outputs)
+	}
+	return writeOutputsTable(w, out.Outputs)
+}
+
+// writeOutputsTable writes the stack outputs in table format.
+func writeOutputsTable(w io.Writer, outputs model.CFnOutputs) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tEXPORT_NAME\tDESCRIPTION")
+	for _, output := range outputs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", output.Key, output.Value, orHyphen(output.ExportName), orHyphen(output.Description))
+	}
+	return tw.Flush()
+}
+
+// writeOutputsJSON writes the stack outputs in JSON format.
+func writeOutputsJSON(w io.Writer, outputs model.CFnOutputs) error {
+	b, err := json.MarshalIndent(outputs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can not marshal outputs: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// orHyphen returns "-" if s is empty, so that the empty cell is distinguishable in the table.
+func orHyphen(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeOutputs(t *testing.T) {
+	t.Parallel()
+
+	outputs := model.CFnOutputs{
+		{Key: "BucketName", Value: "bucket", Description: "the name of the bucket", ExportName: "app-BucketName"},
+		{Key: "Region", Value: "us-east-1"},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		if err := writeOutputsTable(buf, outputs); err != nil {
+			t.Fatal(err)
+		}
+		want := `KEY         VALUE      EXPORT_NAME     DESCRIPTION
+BucketName  bucket     app-BucketName  the name of the bucket
+Region      us-east-1  -               -
+`
+		if diff := cmp.Diff(want, buf.String()); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		if err := writeOutputsJSON(buf, outputs); err != nil {
+			t.Fatal(err)
+		}
+		want := `[
+  {
+    "key": "BucketName",
+    "value": "bucket",
+    "description": "the name of the bucket",
+    "export_name": "app-BucketName"
+  },
+  {
+    "key": "Region",
+    "value": "us-east-1"
+  }
+]
+`
+		if diff := cmp.Diff(want, buf.String()); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+}
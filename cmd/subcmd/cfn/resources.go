@@ -0,0 +1,113 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newResourcesCmd return resources command.
+func newResourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resources [flags] STACK_NAME",
+		Short: "List resources of the CloudFormation stack",
+		Long: `List resources of the CloudFormation stack with the physical ID, the status and the drift status.
+With --tree, the resources of the nested stacks are listed under their AWS::CloudFormation::Stack resources.`,
+		Example: `  cfn resources -p myprofile -r us-east-1 STACK_NAME
+
+  [Show the resources of the nested stacks too]
+    cfn resources --tree STACK_NAME`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &resourcesCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().BoolP("tree", "t", false, "expand the nested stacks recursively in tree view")
+	return cmd
+}
+
+// resourcesCmd is the command for resources.
+type resourcesCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stack is the name of the stack.
+	stack string
+	// tree is the flag to expand the nested stacks recursively.
+	tree bool
+}
+
+// Parse parses command line arguments.
+func (r *resourcesCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("you must specify a stack name")
+	}
+	r.stack = args[0]
+
+	var err error
+	if r.tree, err = cmd.Flags().GetBool("tree"); err != nil {
+		return err
+	}
+
+	r.cfn = newCFn()
+	return r.cfn.parse(cmd)
+}
+
+// Do executes resources command.
+func (r *resourcesCmd) Do() error {
+	out, err := r.CFnStackResourceLister.ListCFnStackResource(r.ctx, &usecase.CFnStackResourceListerInput{
+		StackName: r.stack,
+		Region:    r.region,
+		Recursive: r.tree,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, r.region)
+	}
+	return writeStackResources(r.command.OutOrStdout(), out.Resources)
+}
+
+// writeStackResources writes the resources in table format. The resources of the nested stacks
+// are written under their stack resource with the tree branches in the LOGICAL_ID column.
+func writeStackResources(w io.Writer, resources []*model.StackResourceNode) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOGICAL_ID\tPHYSICAL_ID\tTYPE\tSTATUS\tDRIFT")
+	writeStackResourceNodes(tw, resources, "", true)
+	return tw.Flush()
+}
+
+// writeStackResourceNodes writes the resources and their children recursively.
+// indent is the prefix of the tree branches. The resources at the top are written without the tree branches.
+func writeStackResourceNodes(w io.Writer, nodes []*model.StackResourceNode, indent string, top bool) {
+	for i, node := range nodes {
+		branch, childIndent := indent+"├── ", indent+"│   "
+		if i == len(nodes)-1 {
+			branch, childIndent = indent+"└── ", indent+"    "
+		}
+		if top {
+			branch, childIndent = "", ""
+		}
+		fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\n",
+			branch,
+			aws.ToString(node.LogicalResourceID),
+			aws.ToString(node.PhysicalResourceID),
+			aws.ToString(node.ResourceType),
+			node.ResourceStatus,
+			driftStatus(node.StackResource))
+		writeStackResourceNodes(w, node.Children, childIndent, false)
+	}
+}
+
+// driftStatus returns the drift status of the resource. It returns "-" if the drift information is unknown.
+func driftStatus(resource *model.StackResource) string {
+	if resource.DriftInformation == nil || resource.DriftInformation.StackResourceDriftStatus == "" {
+		return "-"
+	}
+	return string(resource.DriftInformation.StackResourceDriftStatus)
+}
@@ -0,0 +1,53 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeStackResources(t *testing.T) {
+	t.Parallel()
+
+	resource := func(logicalID, resourceType string, children ...*model.StackResourceNode) *model.StackResourceNode {
+		return &model.StackResourceNode{
+			StackResource: &model.StackResource{
+				LogicalResourceID:  aws.String(logicalID),
+				PhysicalResourceID: aws.String("id-" + logicalID),
+				ResourceType:       aws.String(resourceType),
+				ResourceStatus:     model.ResourceStatusCreateComplete,
+				DriftInformation: &model.StackResourceDriftInformationSummary{
+					StackResourceDriftStatus: model.StackResourceDriftStatusInSync,
+				},
+			},
+			Children: children,
+		}
+	}
+	noDrift := resource("Topic", "AWS::SNS::Topic")
+	noDrift.DriftInformation = nil
+
+	buf := &bytes.Buffer{}
+	if err := writeStackResources(buf, []*model.StackResourceNode{
+		resource("Bucket", "AWS::S3::Bucket"),
+		resource("Nested", model.CFnNestedStackResourceType,
+			resource("Deep", model.CFnNestedStackResourceType, resource("Queue", "AWS::SQS::Queue")),
+			noDrift,
+		),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `LOGICAL_ID     PHYSICAL_ID  TYPE                        STATUS           DRIFT
+Bucket         id-Bucket    AWS::S3::Bucket             CREATE_COMPLETE  IN_SYNC
+Nested         id-Nested    AWS::CloudFormation::Stack  CREATE_COMPLETE  IN_SYNC
+├── Deep       id-Deep      AWS::CloudFormation::Stack  CREATE_COMPLETE  IN_SYNC
+│   └── Queue  id-Queue     AWS::SQS::Queue             CREATE_COMPLETE  IN_SYNC
+└── Topic      id-Topic     AWS::SNS::Topic             CREATE_COMPLETE  -
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
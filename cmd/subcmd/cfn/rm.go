@@ -0,0 +1,257 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newRmCmd return rm command.
+func newRmCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm [flags] STACK_NAME...",
+		Aliases: []string{"remove"},
+		Short:   "Delete CloudFormation stacks",
+		Example: `  cfn rm -p myprofile -r us-east-1 STACK_NAME
+
+  [Delete stacks without confirmation]
+    cfn rm --force STACK_NAME_1 STACK_NAME_2
+
+  [Delete the DELETE_FAILED stack retaining the resources that blocked the deletion]
+    cfn rm --retain-resources MyBucket,MyLogGroup STACK_NAME`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &rmCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().BoolP("force", "f", false, "delete without confirmation")
+	cmd.Flags().StringSlice("retain-resources", nil,
+		"comma separated logical IDs of the resources retained when deleting the DELETE_FAILED stack")
+	return cmd
+}
+
+// rmCmd is the command for rm.
+type rmCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stacks is the names of the stacks to delete.
+	stacks []string
+	// force is the flag to delete without confirmation.
+	force bool
+	// retainResources is the logical IDs of the resources retained when deleting the DELETE_FAILED stack.
+	retainResources []string
+}
+
+// Parse parses command line arguments.
+func (r *rmCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("you must specify a stack name")
+	}
+	r.stacks = args
+
+	var err error
+	if r.force, err = cmd.Flags().GetBool("force"); err != nil {
+		return err
+	}
+	if r.retainResources, err = cmd.Flags().GetStringSlice("retain-resources"); err != nil {
+		return err
+	}
+
+	r.cfn = newCFn()
+	return r.cfn.parse(cmd)
+}
+
+// Do executes rm command.
+func (r *rmCmd) Do() error {
+	stacks := make([]*model.Stack, 0, len(r.stacks))
+	for _, name := range r.stacks {
+		out, err := r.CFnStackDescriber.DescribeCFnStack(r.ctx, &usecase.CFnStackDescriberInput{
+			StackName: name,
+			Region:    r.region,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: region=%s", err, r.region)
+		}
+		if out.TerminationProtection {
+			return fmt.Errorf("%w: %s (disable it with 'aws cloudformation update-termination-protection --no-enable-termination-protection --stack-name %s')",
+				domain.ErrCFnStackTerminationProtection, color.YellowString(name), name)
+		}
+		if len(out.Outputs.ExportNames()) > 0 {
+			if err := r.checkImporters(name); err != nil {
+				return err
+			}
+		}
+		if len(r.retainResources) > 0 && out.Stack.StackStatus != model.StackStatusDeleteFailed {
+			return fmt.Errorf("--retain-resources can be used only for the DELETE_FAILED stack: %s is %s",
+				color.YellowString(name), out.Stack.StackStatus)
+		}
+		stacks = append(stacks, out.Stack)
+	}
+
+	w := r.command.OutOrStdout()
+	if err := writeStackTable(w, r.region, stacks); err != nil {
+		return err
+	}
+	if !r.force {
+		if !subcmd.Question(w, fmt.Sprintf("delete %s stacks?", color.YellowString("%d", len(stacks)))) {
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, name := range r.stacks {
+		if err := r.deleteStack(name); err != nil {
+			r.printf("%s: %v\n", color.RedString("ERROR"), err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d stacks", domain.ErrCFnStackDeleteFailed, failed, len(r.stacks))
+	}
+	return nil
+}
+
+// checkImporters returns an error if other stacks import the exports of the stack.
+// CloudFormation can not delete the export while it is imported.
+func (r *rmCmd) checkImporters(name string) error {
+	out, err := r.CFnExportsLister.ListCFnExports(r.ctx, &usecase.CFnExportsListerInput{
+		Region:      r.region,
+		StackName:   name,
+		Importers:   true,
+		Concurrency: r.concurrency,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, r.region)
+	}
+
+	imported := importedExports(out.Exports)
+	if len(imported) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (%s). remove the imports first, see 'cfn exports --importers --stack %s'",
+		domain.ErrCFnStackExportsImported, color.YellowString(name), strings.Join(imported, "; "), name)
+}
+
+// importedExports returns the exports that are imported in the format "EXPORT_NAME is imported by STACK,...".
+func importedExports(exports []*model.CFnExport) []string {
+	imported := make([]string, 0, len(exports))
+	for _, export := range exports {
+		if len(export.Importers) > 0 {
+			imported = append(imported, fmt.Sprintf("%s is imported by %s", export.Name, strings.Join(export.Importers, ",")))
+		}
+	}
+	return imported
+}
+
+// deleteStack deletes the stack while printing the stack events.
+// If the stack ends in DELETE_FAILED, it offers to delete the stack again retaining the resources that blocked the deletion.
+func (r *rmCmd) deleteStack(name string) error {
+	out, err := r.deleteStackWithEvents(name, r.retainResources)
+	if err != nil {
+		return err
+	}
+	if out.Status == model.StackStatusDeleteComplete {
+		r.printf("delete %s\n", color.GreenString(name))
+		return nil
+	}
+
+	w := r.command.OutOrStdout()
+	fmt.Fprintf(w, "\n%s is %s: %s\n", color.YellowString(name), out.Status.StringWithColor(), out.StatusReason)
+	if err := writeFailedResources(w, out.FailedResources); err != nil {
+		return err
+	}
+	logicalIDs := failedLogicalIDs(out.FailedResources)
+	if len(logicalIDs) == 0 || len(r.retainResources) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrCFnStackDeleteFailed, name)
+	}
+
+	retain := strings.Join(logicalIDs, ",")
+	if r.force || !subcmd.Question(w, fmt.Sprintf("delete %s again retaining %s?", color.YellowString(name), color.YellowString(retain))) {
+		return fmt.Errorf("%w: %s (to retain the resources, run 'cfn rm --retain-resources %s %s')",
+			domain.ErrCFnStackDeleteFailed, name, retain, name)
+	}
+
+	if out, err = r.deleteStackWithEvents(name, logicalIDs); err != nil {
+		return err
+	}
+	if out.Status != model.StackStatusDeleteComplete {
+		return fmt.Errorf("%w: %s is %s: %s", domain.ErrCFnStackDeleteFailed, name, out.Status, out.StatusReason)
+	}
+	r.printf("delete %s (retained: %s)\n", color.GreenString(name), retain)
+	return nil
+}
+
+// deleteStackWithEvents deletes the stack and prints the stack events until the deletion completes or fails.
+func (r *rmCmd) deleteStackWithEvents(name string, retainResources []string) (*usecase.CFnStackDeleterOutput, error) {
+	r.printf("[%s] start deleting\n", color.YellowString(name))
+	return r.CFnStackDeleter.DeleteCFnStack(r.ctx, &usecase.CFnStackDeleterInput{
+		StackName:       name,
+		Region:          r.region,
+		RetainResources: retainResources,
+		OnEvent: func(event *model.StackEvent) {
+			r.printf("[%s] %s\n", color.YellowString(name), formatStackEvent(event))
+		},
+	})
+}
+
+// writeStackTable writes the stacks in table format.
+func writeStackTable(w io.Writer, region model.Region, stacks []*model.Stack) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REGION\tSTACK\tSTATUS\tUPDATED_AT")
+	for _, stack := range stacks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", region, aws.ToString(stack.StackName), stack.StackStatus, stackUpdatedAt(stack))
+	}
+	return tw.Flush()
+}
+
+// writeFailedResources writes the resources that blocked the deletion with the reason.
+func writeFailedResources(w io.Writer, events []*model.StackEvent) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOGICAL_ID\tTYPE\tREASON")
+	for _, event := range events {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n",
+			aws.ToString(event.LogicalResourceID), aws.ToString(event.ResourceType), aws.ToString(event.ResourceStatusReason))
+	}
+	return tw.Flush()
+}
+
+// failedLogicalIDs returns the unique logical IDs of the resources that blocked the deletion.
+func failedLogicalIDs(events []*model.StackEvent) []string {
+	ids := make([]string, 0, len(events))
+	seen := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		id := aws.ToString(event.LogicalResourceID)
+		if _, ok := seen[id]; ok || id == "" {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// formatStackEvent returns the one-line representation of the stack event.
+// e.g. "2024-01-10 12:00:00 DELETE_IN_PROGRESS AWS::S3::Bucket MyBucket"
+func formatStackEvent(event *model.StackEvent) string {
+	timestamp := "-"
+	if event.Timestamp != nil {
+		timestamp = event.Timestamp.Local().Format("2006-01-02 15:04:05")
+	}
+	line := fmt.Sprintf("%s %s %s %s",
+		timestamp, event.ResourceStatus.StringWithColor(), aws.ToString(event.ResourceType), aws.ToString(event.LogicalResourceID))
+	if reason := aws.ToString(event.ResourceStatusReason); reason != "" {
+		line += " (" + reason + ")"
+	}
+	return line
+}
@@ -0,0 +1,107 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeFailedResources(t *testing.T) {
+	t.Parallel()
+
+	events := []*model.StackEvent{
+		{
+			LogicalResourceID:    aws.String("Bucket"),
+			ResourceType:         aws.String("AWS::S3::Bucket"),
+			ResourceStatusReason: aws.String("The bucket you tried to delete is not empty"),
+		},
+		{
+			LogicalResourceID:    aws.String("LogGroup"),
+			ResourceType:         aws.String("AWS::Logs::LogGroup"),
+			ResourceStatusReason: aws.String("Access denied"),
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeFailedResources(buf, events); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "LOGICAL_ID  TYPE                 REASON\n" +
+		"Bucket      AWS::S3::Bucket      The bucket you tried to delete is not empty\n" +
+		"LogGroup    AWS::Logs::LogGroup  Access denied\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_failedLogicalIDs(t *testing.T) {
+	t.Parallel()
+
+	events := []*model.StackEvent{
+		{LogicalResourceID: aws.String("Bucket")},
+		{LogicalResourceID: aws.String("LogGroup")},
+		{LogicalResourceID: aws.String("Bucket")},
+		{},
+	}
+	if diff := cmp.Diff([]string{"Bucket", "LogGroup"}, failedLogicalIDs(events)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_formatStackEvent(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		event *model.StackEvent
+		want  string
+	}{
+		{
+			name: "event without reason",
+			event: &model.StackEvent{
+				Timestamp:         &timestamp,
+				ResourceStatus:    model.ResourceStatusDeleteInProgress,
+				ResourceType:      aws.String("AWS::S3::Bucket"),
+				LogicalResourceID: aws.String("Bucket"),
+			},
+			want: timestamp.Local().Format("2006-01-02 15:04:05") + " DELETE_IN_PROGRESS AWS::S3::Bucket Bucket",
+		},
+		{
+			name: "event with reason and without timestamp",
+			event: &model.StackEvent{
+				ResourceStatus:       model.ResourceStatusDeleteFailed,
+				ResourceType:         aws.String("AWS::S3::Bucket"),
+				LogicalResourceID:    aws.String("Bucket"),
+				ResourceStatusReason: aws.String("not empty"),
+			},
+			want: "- DELETE_FAILED AWS::S3::Bucket Bucket (not empty)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := formatStackEvent(tt.event); got != tt.want {
+				t.Errorf("formatStackEvent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_importedExports(t *testing.T) {
+	t.Parallel()
+
+	got := importedExports([]*model.CFnExport{
+		{Name: "app-BucketName", Importers: []string{}},
+		{Name: "app-QueueURL", Importers: []string{"web", "worker"}},
+	})
+	if diff := cmp.Diff([]string{"app-QueueURL is imported by web,worker"}, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
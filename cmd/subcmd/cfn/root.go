@@ -4,6 +4,7 @@ package cfn
 import (
 	"os"
 
+	"github.com/nao1215/rainbow/cmd/subcmd"
 	"github.com/spf13/cobra"
 )
 
@@ -12,7 +13,11 @@ func Execute() error {
 	if len(os.Args) == 1 {
 		return interactive()
 	}
-	if err := newRootCmd().Execute(); err != nil {
+
+	cfg := subcmd.LoadConfig()
+	cmd := newRootCmd()
+	subcmd.ApplyConfig(cmd, cfg, cfg.CFn)
+	if err := cmd.Execute(); err != nil {
 		return err
 	}
 	return nil
@@ -29,8 +34,24 @@ func newRootCmd() *cobra.Command {
 	cmd.SilenceUsage = true
 	cmd.SilenceErrors = true
 	cmd.DisableFlagParsing = true
+	subcmd.AddAWSConfigFlags(cmd)
+	cmd.PersistentFlags().Int("concurrency", 0, "number of parallel API calls (0: default of each command)")
 
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newLsCmd())
+	cmd.AddCommand(newRmCmd())
+	cmd.AddCommand(newTagCmd())
+	cmd.AddCommand(newUntagCmd())
+	cmd.AddCommand(newTagsCmd())
+	cmd.AddCommand(newDeployCmd())
+	cmd.AddCommand(newEventsCmd())
+	cmd.AddCommand(newResourcesCmd())
+	cmd.AddCommand(newDriftCmd())
+	cmd.AddCommand(newOutputsCmd())
+	cmd.AddCommand(newExportsCmd())
+	cmd.AddCommand(newTemplateCmd())
+	cmd.AddCommand(newCleanupCmd())
+	cmd.AddCommand(newLintCmd())
+	cmd.AddCommand(subcmd.NewConfigCmd())
 	return cmd
 }
@@ -0,0 +1,249 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newTagCmd return tag command.
+func newTagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag [flags] STACK_NAME KEY=VALUE...",
+		Short: "Add tags to CloudFormation stacks",
+		Long: `Add tags to CloudFormation stacks.
+The stack is updated with the previous template and parameters, and the tags are propagated to the resources of the stack.`,
+		Example: `  cfn tag -p myprofile -r us-east-1 STACK_NAME env=dev team=infra
+
+  [Add tags to the stacks whose names match the regular expression]
+    cfn tag --match '^app-' env=dev`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &tagCmd{})
+		},
+	}
+	addTagFlags(cmd)
+	return cmd
+}
+
+// newUntagCmd return untag command.
+func newUntagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "untag [flags] STACK_NAME KEY...",
+		Short: "Remove tags from CloudFormation stacks",
+		Long: `Remove tags from CloudFormation stacks.
+The stack is updated with the previous template and parameters.`,
+		Example: `  cfn untag -p myprofile -r us-east-1 STACK_NAME env team
+
+  [Remove tags from the stacks whose names match the regular expression]
+    cfn untag --match '^app-' env`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &tagCmd{untag: true})
+		},
+	}
+	addTagFlags(cmd)
+	return cmd
+}
+
+// addTagFlags adds the flags of tag and untag commands.
+func addTagFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().StringP("match", "m", "", "update the stacks whose names match the regular expression instead of STACK_NAME")
+	cmd.Flags().BoolP("force", "f", false, "update the matched stacks without confirmation")
+}
+
+// tagCmd is the command for tag and untag.
+type tagCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// untag is true if the command removes the tags.
+	untag bool
+	// stack is the name of the stack to update. It is empty if match is specified.
+	stack string
+	// match is the regular expression of the stack names to update.
+	match *regexp.Regexp
+	// force is the flag to update the matched stacks without confirmation.
+	force bool
+	// tags is the tags to add.
+	tags model.CFnTags
+	// keys is the keys of the tags to remove.
+	keys []string
+}
+
+// Parse parses command line arguments.
+func (t *tagCmd) Parse(cmd *cobra.Command, args []string) error {
+	match, err := cmd.Flags().GetString("match")
+	if err != nil {
+		return err
+	}
+	if t.force, err = cmd.Flags().GetBool("force"); err != nil {
+		return err
+	}
+
+	if match != "" {
+		if t.match, err = regexp.Compile(match); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	} else {
+		if len(args) == 0 {
+			return errors.New("you must specify a stack name or --match")
+		}
+		t.stack, args = args[0], args[1:]
+	}
+	if t.tags, t.keys, err = parseTagArgs(args, t.untag); err != nil {
+		return err
+	}
+
+	t.cfn = newCFn()
+	return t.cfn.parse(cmd)
+}
+
+// parseTagArgs parses the tags (KEY=VALUE) or the tag keys (KEY) if untag is true.
+func parseTagArgs(args []string, untag bool) (model.CFnTags, []string, error) {
+	if len(args) == 0 {
+		if untag {
+			return nil, nil, errors.New("you must specify tag keys to remove")
+		}
+		return nil, nil, errors.New("you must specify tags in KEY=VALUE format")
+	}
+
+	if untag {
+		keys := make([]string, 0, len(args))
+		for _, arg := range args {
+			key, err := model.NewCFnTagKey(arg)
+			if err != nil {
+				return nil, nil, err
+			}
+			keys = append(keys, key)
+		}
+		return nil, keys, nil
+	}
+
+	tags := model.CFnTags{}
+	for _, arg := range args {
+		tag, err := model.NewCFnTag(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags = tags.Merge(model.CFnTags{tag})
+	}
+	return tags, nil, nil
+}
+
+// Do executes tag or untag command.
+func (t *tagCmd) Do() error {
+	stacks := []string{t.stack}
+	if t.match != nil {
+		matched, err := t.matchedStacks()
+		if err != nil {
+			return err
+		}
+		if len(matched) == 0 {
+			t.printf("no stacks match %s\n", color.YellowString(t.match.String()))
+			return nil
+		}
+		if !t.force {
+			w := t.command.OutOrStdout()
+			if err := writeStackTable(w, t.region, matched); err != nil {
+				return err
+			}
+			if !subcmd.Question(w, fmt.Sprintf("update tags of %s stacks?", color.YellowString("%d", len(matched)))) {
+				return nil
+			}
+		}
+		stacks = make([]string, 0, len(matched))
+		for _, stack := range matched {
+			stacks = append(stacks, *stack.StackName)
+		}
+	}
+
+	failed := 0
+	for _, name := range stacks {
+		if err := t.tagStack(name); err != nil {
+			t.printf("%s: %v\n", color.RedString("ERROR"), err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d stacks", domain.ErrCFnStackUpdateFailed, failed, len(stacks))
+	}
+	return nil
+}
+
+// matchedStacks returns the stacks whose names match the regular expression. Deleted stacks are not returned.
+func (t *tagCmd) matchedStacks() ([]*model.Stack, error) {
+	out, err := t.CFnStackLister.ListCFnStack(t.ctx, &usecase.CFnStackListerInput{
+		Region: t.region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filterStacksByName(out.Stacks, t.match), nil
+}
+
+// filterStacksByName returns the stacks whose names match the regular expression. Deleted stacks are not returned.
+func filterStacksByName(stacks []*model.Stack, match *regexp.Regexp) []*model.Stack {
+	matched := make([]*model.Stack, 0, len(stacks))
+	for _, stack := range stacks {
+		if stack.StackName == nil || stack.StackStatus == model.StackStatusDeleteComplete || !match.MatchString(*stack.StackName) {
+			continue
+		}
+		matched = append(matched, stack)
+	}
+	return matched
+}
+
+// tagStack updates the tags of the stack while printing the stack events.
+func (t *tagCmd) tagStack(name string) error {
+	t.printf("[%s] start updating tags\n", color.YellowString(name))
+	out, err := t.CFnStackTagger.TagCFnStack(t.ctx, &usecase.CFnStackTaggerInput{
+		StackName:  name,
+		Region:     t.region,
+		Tags:       t.tags,
+		RemoveKeys: t.keys,
+		OnEvent: func(event *model.StackEvent) {
+			t.printf("[%s] %s\n", color.YellowString(name), formatStackEvent(event))
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, name)
+	}
+
+	if !out.Changed {
+		t.printf("%s: tags are not changed\n", color.GreenString(name))
+		return nil
+	}
+	if out.Status == model.StackStatusUpdateComplete {
+		t.printf("%s: %s\n", color.GreenString(name), formatTags(out.Tags))
+		return nil
+	}
+
+	w := t.command.OutOrStdout()
+	fmt.Fprintf(w, "\n%s is %s: %s\n", color.YellowString(name), out.Status.StringWithColor(), out.StatusReason)
+	if len(out.FailedResources) > 0 {
+		if err := writeFailedResources(w, out.FailedResources); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s is %s", domain.ErrCFnStackUpdateFailed, name, out.Status)
+}
+
+// formatTags returns the tags in "key=value, key=value" format.
+func formatTags(tags model.CFnTags) string {
+	if len(tags) == 0 {
+		return "no tags"
+	}
+	pairs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		pairs = append(pairs, tag.Key+"="+tag.Value)
+	}
+	return strings.Join(pairs, ", ")
+}
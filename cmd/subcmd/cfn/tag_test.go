@@ -0,0 +1,120 @@
+package cfn
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_parseTagArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		args     []string
+		untag    bool
+		wantTags model.CFnTags
+		wantKeys []string
+		wantErr  bool
+	}{
+		{
+			name:     "tags in KEY=VALUE format. the last value of the same key is used",
+			args:     []string{"env=dev", "team=infra", "env=prd"},
+			wantTags: model.CFnTags{{Key: "env", Value: "prd"}, {Key: "team", Value: "infra"}},
+		},
+		{
+			name:     "tag keys for untag",
+			args:     []string{"env", "team"},
+			untag:    true,
+			wantKeys: []string{"env", "team"},
+		},
+		{
+			name:    "tag without value",
+			args:    []string{"env"},
+			wantErr: true,
+		},
+		{
+			name:    "reserved tag key",
+			args:    []string{"aws:cloudformation:stack-name"},
+			untag:   true,
+			wantErr: true,
+		},
+		{
+			name:    "no tags",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tags, keys, err := parseTagArgs(tt.args, tt.untag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTagArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantTags, tags); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantKeys, keys); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_filterStacksByName(t *testing.T) {
+	t.Parallel()
+
+	stacks := []*model.Stack{
+		{StackName: aws.String("app-api"), StackStatus: model.StackStatusCreateComplete},
+		{StackName: aws.String("app-web"), StackStatus: model.StackStatusDeleteComplete},
+		{StackName: aws.String("cdk-assets"), StackStatus: model.StackStatusUpdateComplete},
+		{StackName: aws.String("app-db"), StackStatus: model.StackStatusUpdateComplete},
+	}
+
+	got := []string{}
+	for _, stack := range filterStacksByName(stacks, regexp.MustCompile("^app-")) {
+		got = append(got, aws.ToString(stack.StackName))
+	}
+	if diff := cmp.Diff([]string{"app-api", "app-db"}, got); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func Test_formatTags(t *testing.T) {
+	t.Parallel()
+
+	if got := formatTags(model.CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: ""}}); got != "env=dev, team=" {
+		t.Errorf("formatTags() = %q, want %q", got, "env=dev, team=")
+	}
+	if got := formatTags(model.CFnTags{}); got != "no tags" {
+		t.Errorf("formatTags() = %q, want %q", got, "no tags")
+	}
+}
+
+func Test_writeStackTags(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	err := writeStackTags(buf, []stackTags{
+		{stack: "app-stack", tags: model.CFnTags{{Key: "env", Value: "dev"}, {Key: "team", Value: "infra"}}},
+		{stack: "no-tags", tags: model.CFnTags{}},
+		{stack: "cdk-assets", tags: model.CFnTags{{Key: "owner", Value: "nao"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "STACK       KEY    VALUE\n" +
+		"app-stack   env    dev\n" +
+		"app-stack   team   infra\n" +
+		"cdk-assets  owner  nao\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
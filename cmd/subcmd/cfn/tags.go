@@ -0,0 +1,82 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newTagsCmd return tags command.
+func newTagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "tags [flags] STACK_NAME...",
+		Short:   "List tags of CloudFormation stacks",
+		Example: `  cfn tags -p myprofile -r us-east-1 STACK_NAME_1 STACK_NAME_2`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &tagsCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	return cmd
+}
+
+// tagsCmd is the command for tags.
+type tagsCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stacks is the names of the stacks whose tags are listed.
+	stacks []string
+}
+
+// Parse parses command line arguments.
+func (t *tagsCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("you must specify a stack name")
+	}
+	t.stacks = args
+	t.cfn = newCFn()
+	return t.cfn.parse(cmd)
+}
+
+// Do executes tags command.
+func (t *tagsCmd) Do() error {
+	tags := make([]stackTags, 0, len(t.stacks))
+	for _, name := range t.stacks {
+		out, err := t.CFnStackDescriber.DescribeCFnStack(t.ctx, &usecase.CFnStackDescriberInput{
+			StackName: name,
+			Region:    t.region,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: region=%s", err, t.region)
+		}
+		tags = append(tags, stackTags{stack: name, tags: out.Tags})
+	}
+	return writeStackTags(t.command.OutOrStdout(), tags)
+}
+
+// stackTags is the tags of the stack.
+type stackTags struct {
+	// stack is the name of the stack.
+	stack string
+	// tags is the tags of the stack.
+	tags model.CFnTags
+}
+
+// writeStackTags writes the tags of the stacks in table format.
+func writeStackTags(w io.Writer, stacks []stackTags) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STACK\tKEY\tVALUE")
+	for _, s := range stacks {
+		for _, tag := range s.tags {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.stack, tag.Key, tag.Value)
+		}
+	}
+	return tw.Flush()
+}
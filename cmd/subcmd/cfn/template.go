@@ -0,0 +1,189 @@
+package cfn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/spf13/cobra"
+)
+
+// newTemplateCmd return template command.
+func newTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Get or diff the template of the CloudFormation stack",
+	}
+	cmd.AddCommand(newTemplateGetCmd())
+	cmd.AddCommand(newTemplateDiffCmd())
+	return cmd
+}
+
+// newTemplateGetCmd return template get command.
+func newTemplateGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get [flags] STACK_NAME",
+		Short: "Get the deployed template of the CloudFormation stack",
+		Example: `  cfn template get -p myprofile -r us-east-1 STACK_NAME
+  cfn template get --stage Processed STACK_NAME
+  cfn template get -o template.yml STACK_NAME`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &templateGetCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().StringP("stage", "s", model.CFnTemplateStageOriginal.String(), "template stage (Original, Processed)")
+	cmd.Flags().StringP("output-file", "o", "", "write the template to the file instead of stdout")
+	return cmd
+}
+
+// templateGetCmd is the command for template get.
+type templateGetCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stack is the name of the stack.
+	stack string
+	// stage is the stage of the template.
+	stage model.CFnTemplateStage
+	// outputFile is the file the template is written to. If it is empty, the template is written to stdout.
+	outputFile string
+}
+
+// Parse parses command line arguments.
+func (t *templateGetCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("you must specify a stack name")
+	}
+	t.stack = args[0]
+
+	stage, err := cmd.Flags().GetString("stage")
+	if err != nil {
+		return err
+	}
+	if t.stage, err = model.NewCFnTemplateStage(stage); err != nil {
+		return err
+	}
+	if t.outputFile, err = cmd.Flags().GetString("output-file"); err != nil {
+		return err
+	}
+
+	t.cfn = newCFn()
+	return t.cfn.parse(cmd)
+}
+
+// Do executes template get command.
+func (t *templateGetCmd) Do() error {
+	out, err := t.CFnTemplateGetter.GetCFnTemplate(t.ctx, &usecase.CFnTemplateGetterInput{
+		StackName: t.stack,
+		Region:    t.region,
+		Stage:     t.stage,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, t.region)
+	}
+
+	body := out.TemplateBody
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	if t.outputFile == "" {
+		_, err := io.WriteString(t.command.OutOrStdout(), body)
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(t.outputFile), []byte(body), 0600); err != nil {
+		return fmt.Errorf("can not write the template: %w", err)
+	}
+	t.printf("wrote the %s template of %s to %s\n", t.stage, t.stack, t.outputFile)
+	return nil
+}
+
+// newTemplateDiffCmd return template diff command.
+func newTemplateDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [flags] STACK_NAME LOCAL_FILE",
+		Short: "Show the differences between the deployed template and the local template",
+		Long: `Show the differences between the deployed template and the local template.
+
+The templates are compared semantically. The key ordering, YAML or JSON, and
+the short form (e.g. !Ref) or the long form (e.g. Ref:) of the intrinsic
+functions are not regarded as differences.`,
+		Example: `  cfn template diff -p myprofile -r us-east-1 STACK_NAME template.yml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &templateDiffCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	return cmd
+}
+
+// templateDiffCmd is the command for template diff.
+type templateDiffCmd struct {
+	// cfn have common fields and methods for cfn commands.
+	*cfn
+	// stack is the name of the stack.
+	stack string
+	// localFile is the path of the local template.
+	localFile string
+	// template is the local template body.
+	template []byte
+}
+
+// Parse parses command line arguments.
+func (t *templateDiffCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return errors.New("you must specify a stack name and a local template file")
+	}
+	t.stack = args[0]
+	t.localFile = args[1]
+
+	body, err := os.ReadFile(filepath.Clean(t.localFile))
+	if err != nil {
+		return fmt.Errorf("can not read the template: %w", err)
+	}
+	t.template = body
+
+	t.cfn = newCFn()
+	return t.cfn.parse(cmd)
+}
+
+// Do executes template diff command.
+func (t *templateDiffCmd) Do() error {
+	out, err := t.CFnTemplateDiffer.DiffCFnTemplate(t.ctx, &usecase.CFnTemplateDifferInput{
+		StackName:    t.stack,
+		Region:       t.region,
+		TemplateBody: t.template,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: region=%s", err, t.region)
+	}
+
+	if len(out.Differences) == 0 {
+		t.printf("no differences between %s and %s\n", t.stack, t.localFile)
+		return nil
+	}
+	writeTemplateDifferences(t.command.OutOrStdout(), out.Differences)
+	return nil
+}
+
+// writeTemplateDifferences writes the template differences. "+" is added, "-" is removed, and "~" is changed.
+func writeTemplateDifferences(w io.Writer, diffs []model.CFnTemplateDifference) {
+	for _, d := range diffs {
+		switch d.Type {
+		case model.CFnTemplateDifferenceTypeAdd:
+			fmt.Fprintln(w, color.GreenString("+ %s: %s", d.Path, d.New))
+		case model.CFnTemplateDifferenceTypeRemove:
+			fmt.Fprintln(w, color.RedString("- %s: %s", d.Path, d.Old))
+		default:
+			fmt.Fprintln(w, color.YellowString("~ %s: %s -> %s", d.Path, d.Old, d.New))
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package cfn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeTemplateDifferences(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	writeTemplateDifferences(buf, []model.CFnTemplateDifference{
+		{Path: "Outputs.BucketName", Type: model.CFnTemplateDifferenceTypeAdd, New: `{"Value":{"Ref":"Bucket"}}`},
+		{Path: "Resources.Bucket.Properties.BucketName", Type: model.CFnTemplateDifferenceTypeChange, Old: `"old"`, New: `"new"`},
+		{Path: "Resources.Queue", Type: model.CFnTemplateDifferenceTypeRemove, Old: `{"Type":"AWS::SQS::Queue"}`},
+	})
+	want := `+ Outputs.BucketName: {"Value":{"Ref":"Bucket"}}
+~ Resources.Bucket.Properties.BucketName: "old" -> "new"
+- Resources.Queue: {"Type":"AWS::SQS::Queue"}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
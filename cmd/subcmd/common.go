@@ -1,13 +1,20 @@
 package subcmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/log"
 	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
 // Doer is an interface that represents the behavior of a command.
@@ -24,9 +31,17 @@ type SubCommand interface {
 // Run runs the subcommand.
 func Run(cmd *cobra.Command, args []string, subCmd SubCommand) error {
 	if err := subCmd.Parse(cmd, args); err != nil {
-		return err
+		return explainAWSError(err)
+	}
+	return explainAWSError(subCmd.Do())
+}
+
+// explainAWSError adds the hint to the AWS error that the user can fix by themselves.
+func explainAWSError(err error) error {
+	if model.IsSSOSessionExpired(err) {
+		return fmt.Errorf("%w (run 'aws sso login --profile PROFILE' and try again): %w", domain.ErrSSOSessionExpired, err)
 	}
-	return subCmd.Do()
+	return err
 }
 
 // FmtScanln is wrapper for fmt.Scanln(). It's for unit test.
@@ -55,3 +70,276 @@ func Question(w io.Writer, ask string) bool {
 		return Question(w, ask)
 	}
 }
+
+// OutputFormat is the format of the command output.
+type OutputFormat string
+
+const (
+	// OutputFormatTable is the human-readable table format.
+	OutputFormatTable OutputFormat = "table"
+	// OutputFormatJSON is the JSON format.
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatCSV is the CSV format.
+	OutputFormatCSV OutputFormat = "csv"
+)
+
+// NewOutputFormat returns the OutputFormat. If s is empty, it returns OutputFormatTable.
+func NewOutputFormat(s string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(s)) {
+	case "", OutputFormatTable:
+		return OutputFormatTable, nil
+	case OutputFormatJSON:
+		return OutputFormatJSON, nil
+	case OutputFormatCSV:
+		return OutputFormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported output format: %s (table, json or csv)", color.YellowString(s))
+	}
+}
+
+// String returns the string representation of the OutputFormat.
+func (o OutputFormat) String() string {
+	return string(o)
+}
+
+// ExitError is the error that has the exit code of the process.
+type ExitError struct {
+	// Code is the exit code.
+	Code int
+	// Err is the original error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for err. If err is nil, it returns 0.
+// If err is not ExitError, it returns 1.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// ParseDate parses the date string. Supported formats are "2006-01-02" (in local time) and RFC3339.
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s: use YYYY-MM-DD or RFC3339 format", color.YellowString(s))
+	}
+	return t, nil
+}
+
+// AddAWSConfigFlags adds the global flags for the endpoint and debug mode to the root command.
+// "--local" is the alias of "--debug".
+func AddAWSConfigFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("endpoint-url", "", "endpoint URL of the S3-compatible storage (e.g. http://localhost:9000)")
+	cmd.PersistentFlags().Bool("path-style", false, "use the path-style addressing (http://ENDPOINT/BUCKET/KEY)")
+	cmd.PersistentFlags().Bool("no-verify-ssl", false, "do not verify the TLS certificate of the endpoint")
+	cmd.PersistentFlags().BoolP("debug", "d", false,
+		"run debug mode (alias: --local). all requests are sent to "+model.DebugLocalstackEndpoint+" (or --endpoint-url) with dummy credentials")
+	cmd.PersistentFlags().Int("retry-max-attempts", 0, "maximum number of attempts of each AWS API call including the first one")
+	cmd.PersistentFlags().Duration("retry-max-backoff", 0, "upper limit of the delay between retries (e.g. 30s)")
+	AddAssumeRoleFlags(cmd.PersistentFlags())
+	AddTraceFlags(cmd.PersistentFlags())
+	cmd.SetGlobalNormalizationFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "local" {
+			name = "debug"
+		}
+		return pflag.NormalizedName(name)
+	})
+}
+
+// AddAssumeRoleFlags adds the flags for assuming the role.
+func AddAssumeRoleFlags(flags *pflag.FlagSet) {
+	flags.String("role-arn", "", "ARN of the role to assume with the credentials of the profile")
+	flags.String("external-id", "", "external ID required by the trust policy of the role")
+	flags.String("mfa-serial", "", "serial number (or ARN) of the MFA device. the token code is asked in the terminal")
+	flags.Duration("duration", 0, "duration of the assumed role session (e.g. 1h). default is 15m")
+	flags.String("role-session-name", "", "name of the assumed role session")
+}
+
+// AddTraceFlags adds the flags for the API tracing.
+func AddTraceFlags(flags *pflag.FlagSet) {
+	flags.Bool("verbose", false, "log each AWS API call (operation, region, endpoint, latency, retries and request ID) to stderr")
+	flags.Bool("trace", false, "log each HTTP attempt of AWS API calls with redacted URL and headers in addition to --verbose")
+	flags.String("log-format", "text", "format of the API logs (text or json)")
+}
+
+// NewAWSConfigOptions creates the options for the endpoint and debug mode from the global flags.
+// The global flags are defined in the root command, so they do not exist if the command is used alone.
+func NewAWSConfigOptions(cmd *cobra.Command) (*model.AWSConfigOptions, error) {
+	opts := &model.AWSConfigOptions{}
+	var err error
+
+	if cmd.Flags().Lookup("endpoint-url") != nil {
+		if opts.EndpointURL, err = cmd.Flags().GetString("endpoint-url"); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Lookup("path-style") != nil {
+		if opts.PathStyle, err = cmd.Flags().GetBool("path-style"); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Lookup("no-verify-ssl") != nil {
+		if opts.NoVerifySSL, err = cmd.Flags().GetBool("no-verify-ssl"); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Lookup("debug") != nil {
+		if opts.Debug, err = cmd.Flags().GetBool("debug"); err != nil {
+			return nil, err
+		}
+	}
+	if err := parseAssumeRoleFlags(cmd, opts); err != nil {
+		return nil, err
+	}
+	if opts.Retry, err = newRetryPolicyFromFlags(cmd); err != nil {
+		return nil, err
+	}
+	if opts.Logger, err = newAPILogger(cmd); err != nil {
+		return nil, err
+	}
+	opts.MFATokenProvider = MFATokenPrompt(cmd.ErrOrStderr())
+	return opts, nil
+}
+
+// newAPILogger returns the logger of the AWS API calls that writes the structured logs to stderr.
+// If neither --verbose nor --trace is specified, it returns nil.
+func newAPILogger(cmd *cobra.Command) (model.APILogger, error) {
+	if cmd.Flags().Lookup("verbose") == nil {
+		return nil, nil
+	}
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return nil, err
+	}
+	trace, err := cmd.Flags().GetBool("trace")
+	if err != nil {
+		return nil, err
+	}
+	format, err := cmd.Flags().GetString("log-format")
+	if err != nil {
+		return nil, err
+	}
+
+	opts := log.Options{
+		ReportTimestamp: true,
+		TimeFormat:      time.RFC3339Nano,
+		Level:           log.InfoLevel,
+	}
+	switch strings.ToLower(format) {
+	case "", "text":
+		opts.Formatter = log.TextFormatter
+	case "json":
+		opts.Formatter = log.JSONFormatter
+	default:
+		return nil, fmt.Errorf("unsupported log format: %s (text or json)", color.YellowString(format))
+	}
+	if !verbose && !trace {
+		return nil, nil
+	}
+	if trace {
+		opts.Level = log.DebugLevel
+	}
+	return log.NewWithOptions(cmd.ErrOrStderr(), opts), nil
+}
+
+// newRetryPolicyFromFlags returns the retry policy. The precedence is flags > configuration file > default.
+// The flags are applied to the per-operation overrides too, so --retry-max-attempts=1 disables all retries.
+func newRetryPolicyFromFlags(cmd *cobra.Command) (*model.RetryPolicy, error) {
+	override := model.RetryPolicy{}
+	var err error
+	if cmd.Flags().Lookup("retry-max-attempts") != nil {
+		if override.MaxAttempts, err = cmd.Flags().GetInt("retry-max-attempts"); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Lookup("retry-max-backoff") != nil {
+		if override.MaxBackoff, err = cmd.Flags().GetDuration("retry-max-backoff"); err != nil {
+			return nil, err
+		}
+	}
+	if err := override.Validate(); err != nil {
+		return nil, err
+	}
+	policy := model.DefaultRetryPolicy().Merge(configRetryPolicy).Merge(override)
+	for name, op := range policy.Operations {
+		op = op.Merge(override)
+		op.Operations = nil
+		policy.Operations[name] = op
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// parseAssumeRoleFlags sets the values of the assume role flags to opts.
+func parseAssumeRoleFlags(cmd *cobra.Command, opts *model.AWSConfigOptions) error {
+	if cmd.Flags().Lookup("role-arn") == nil {
+		return nil
+	}
+	var err error
+	if opts.RoleARN, err = cmd.Flags().GetString("role-arn"); err != nil {
+		return err
+	}
+	if opts.ExternalID, err = cmd.Flags().GetString("external-id"); err != nil {
+		return err
+	}
+	if opts.MFASerial, err = cmd.Flags().GetString("mfa-serial"); err != nil {
+		return err
+	}
+	if opts.Duration, err = cmd.Flags().GetDuration("duration"); err != nil {
+		return err
+	}
+	if opts.RoleSessionName, err = cmd.Flags().GetString("role-session-name"); err != nil {
+		return err
+	}
+	if opts.RoleARN == "" && (opts.ExternalID != "" || opts.MFASerial != "" || opts.Duration != 0 || opts.RoleSessionName != "") {
+		return errors.New("--external-id, --mfa-serial, --duration and --role-session-name require --role-arn")
+	}
+	return nil
+}
+
+// IsTerminal returns true if the file is a terminal. It is for unit test.
+var IsTerminal = func(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// MFATokenPrompt returns the function that asks the MFA token code in the terminal.
+// The prompt is written to w (stderr) so that it does not mix with the output of the command.
+func MFATokenPrompt(w io.Writer) func() (string, error) {
+	return func() (string, error) {
+		if !IsTerminal(os.Stdin) {
+			return "", errors.New("the MFA token code is required, but stdin is not a terminal")
+		}
+		fmt.Fprintf(w, "%s: ", color.GreenString("MFA token code"))
+		var code string
+		if _, err := FmtScanln(&code); err != nil {
+			return "", fmt.Errorf("can not read the MFA token code: %w", err)
+		}
+		return strings.TrimSpace(code), nil
+	}
+}
+
+// PrintDebugBanner prints the banner that shows the command runs in debug mode.
+func PrintDebugBanner(w io.Writer, opts *model.AWSConfigOptions) {
+	fmt.Fprintln(w, color.HiYellowString("[DEBUG MODE] all requests are sent to %s with dummy credentials", opts.Endpoint()))
+}
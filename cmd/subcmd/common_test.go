@@ -1,11 +1,22 @@
 package subcmd
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/nao1215/rainbow/app/domain"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/config/rainbow"
+	"github.com/spf13/cobra"
 )
 
 func TestQuestion(t *testing.T) {
@@ -123,3 +134,338 @@ func mockStdin(t *testing.T, dummyInput string) (funcDefer func(), err error) {
 		}
 	}, nil
 }
+
+func TestNewOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{name: "empty is table", s: "", want: OutputFormatTable},
+		{name: "table", s: "table", want: OutputFormatTable},
+		{name: "json with upper case", s: "JSON", want: OutputFormatJSON},
+		{name: "csv", s: "csv", want: OutputFormatCSV},
+		{name: "unsupported", s: "xml", want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewOutputFormat(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewOutputFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewOutputFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: 0},
+		{name: "normal error", err: errors.New("some error"), want: 1},
+		{name: "exit error", err: &ExitError{Code: 2, Err: errors.New("some error")}, want: 2},
+		{name: "wrapped exit error", err: fmt.Errorf("wrap: %w", &ExitError{Code: 3, Err: errors.New("some error")}), want: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseDate("2024-01-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("ParseDate() = %v, want %v", got, want)
+	}
+
+	got, err = ParseDate("2024-01-02T03:04:05Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ParseDate() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseDate("yesterday"); err == nil {
+		t.Error("ParseDate() should return error")
+	}
+}
+
+func TestNewAWSConfigOptions(t *testing.T) {
+	t.Parallel()
+
+	root := &cobra.Command{Use: "root"}
+	AddAWSConfigFlags(root)
+	child := &cobra.Command{Use: "child", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	root.AddCommand(child)
+	root.SetArgs([]string{"child", "--local", "--endpoint-url", "http://localhost:9000", "--path-style"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewAWSConfigOptions(child)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &model.AWSConfigOptions{EndpointURL: "http://localhost:9000", PathStyle: true, Debug: true}
+	opt := cmpopts.IgnoreFields(model.AWSConfigOptions{}, "MFATokenProvider", "Retry", "Logger")
+	if diff := cmp.Diff(want, got, opt); diff != "" {
+		t.Errorf("NewAWSConfigOptions() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The command without the global flags returns the zero value.
+	got, err = NewAWSConfigOptions(&cobra.Command{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&model.AWSConfigOptions{}, got, opt); diff != "" {
+		t.Errorf("NewAWSConfigOptions() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewAWSConfigOptions_Retry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default retry policy", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := NewAWSConfigOptions(&cobra.Command{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(model.DefaultRetryPolicy(), got.Retry); diff != "" {
+			t.Errorf("NewAWSConfigOptions() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("flags override the default and the per-operation overrides", func(t *testing.T) {
+		t.Parallel()
+
+		root := &cobra.Command{Use: "root"}
+		AddAWSConfigFlags(root)
+		child := &cobra.Command{Use: "child", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+		root.AddCommand(child)
+		root.SetArgs([]string{"child", "--retry-max-attempts", "1", "--retry-max-backoff", "3s"})
+		if err := root.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := NewAWSConfigOptions(child)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Retry.MaxAttempts != 1 || got.Retry.MaxBackoff != 3*time.Second {
+			t.Errorf("MaxAttempts = %d, MaxBackoff = %s", got.Retry.MaxAttempts, got.Retry.MaxBackoff)
+		}
+		if op := got.Retry.ForOperation("DeleteObjects"); op.MaxAttempts != 1 || op.MaxBackoff != 3*time.Second {
+			t.Errorf("DeleteObjects: MaxAttempts = %d, MaxBackoff = %s", op.MaxAttempts, op.MaxBackoff)
+		}
+	})
+
+	t.Run("invalid flag value", func(t *testing.T) {
+		t.Parallel()
+
+		root := &cobra.Command{Use: "root"}
+		AddAWSConfigFlags(root)
+		child := &cobra.Command{Use: "child", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+		root.AddCommand(child)
+		root.SetArgs([]string{"child", "--retry-max-attempts", "-1"})
+		if err := root.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewAWSConfigOptions(child); err == nil {
+			t.Error("NewAWSConfigOptions() should return error")
+		}
+	})
+}
+
+func TestNewAWSConfigOptions_Logger(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantLogger bool
+		wantErr    bool
+	}{
+		{name: "no tracing", args: []string{"child"}, wantLogger: false},
+		{name: "verbose", args: []string{"child", "--verbose"}, wantLogger: true},
+		{name: "trace with json", args: []string{"child", "--trace", "--log-format", "json"}, wantLogger: true},
+		{name: "unsupported log format", args: []string{"child", "--verbose", "--log-format", "xml"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := &cobra.Command{Use: "root"}
+			AddAWSConfigFlags(root)
+			child := &cobra.Command{Use: "child", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+			root.AddCommand(child)
+			root.SetArgs(tt.args)
+			if err := root.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := NewAWSConfigOptions(child)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAWSConfigOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (got.Logger != nil) != tt.wantLogger {
+				t.Errorf("Logger = %v, wantLogger %v", got.Logger, tt.wantLogger)
+			}
+		})
+	}
+}
+
+func Test_newRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	got, err := newRetryPolicy(rainbow.Retry{
+		MaxAttempts: 3,
+		MaxBackoff:  "10s",
+		Operations: map[string]rainbow.Retry{
+			"ListObjectsV2": {BaseDelay: "500ms"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.RetryPolicy{
+		MaxAttempts: 3,
+		MaxBackoff:  10 * time.Second,
+		Operations: map[string]model.RetryPolicy{
+			"ListObjectsV2": {BaseDelay: 500 * time.Millisecond},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newRetryPolicy() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := newRetryPolicy(rainbow.Retry{BaseDelay: "soon"}); err == nil {
+		t.Error("newRetryPolicy() should return error")
+	}
+}
+
+func TestNewAWSConfigOptions_AssumeRole(t *testing.T) {
+	t.Parallel()
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "child"}
+		AddAssumeRoleFlags(cmd.Flags())
+		return cmd
+	}
+
+	t.Run("parse assume role flags", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := newCmd()
+		if err := cmd.ParseFlags([]string{
+			"--role-arn", "arn:aws:iam::123456789012:role/admin",
+			"--external-id", "ext",
+			"--mfa-serial", "arn:aws:iam::123456789012:mfa/user",
+			"--duration", "1h",
+			"--role-session-name", "session",
+		}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := NewAWSConfigOptions(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &model.AWSConfigOptions{
+			RoleARN:         "arn:aws:iam::123456789012:role/admin",
+			ExternalID:      "ext",
+			MFASerial:       "arn:aws:iam::123456789012:mfa/user",
+			Duration:        time.Hour,
+			RoleSessionName: "session",
+		}
+		if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(model.AWSConfigOptions{}, "MFATokenProvider", "Retry")); diff != "" {
+			t.Errorf("NewAWSConfigOptions() mismatch (-want +got):\n%s", diff)
+		}
+		if got.MFATokenProvider == nil {
+			t.Error("MFATokenProvider should be set")
+		}
+	})
+
+	t.Run("--mfa-serial without --role-arn", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := newCmd()
+		if err := cmd.ParseFlags([]string{"--mfa-serial", "arn:aws:iam::123456789012:mfa/user"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewAWSConfigOptions(cmd); err == nil {
+			t.Error("NewAWSConfigOptions() should return error")
+		}
+	})
+}
+
+func TestMFATokenPrompt(t *testing.T) { //nolint:paralleltest // IsTerminal and FmtScanln are replaced.
+	orgIsTerminal, orgFmtScanln := IsTerminal, FmtScanln
+	t.Cleanup(func() { IsTerminal, FmtScanln = orgIsTerminal, orgFmtScanln })
+
+	IsTerminal = func(_ *os.File) bool { return false }
+	if _, err := MFATokenPrompt(io.Discard)(); err == nil {
+		t.Error("MFATokenPrompt() should return error if stdin is not a terminal")
+	}
+
+	IsTerminal = func(_ *os.File) bool { return true }
+	FmtScanln = func(a ...any) (int, error) {
+		*(a[0].(*string)) = "123456"
+		return 1, nil
+	}
+	var buf bytes.Buffer
+	got, err := MFATokenPrompt(&buf)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "123456" || !strings.Contains(buf.String(), "MFA token code") {
+		t.Errorf("MFATokenPrompt() = %s, prompt = %q", got, buf.String())
+	}
+}
+
+func Test_explainAWSError(t *testing.T) {
+	t.Parallel()
+
+	ssoErr := errors.New("failed to refresh cached credentials, refresh cached SSO token failed, unable to refresh SSO token")
+	if err := explainAWSError(ssoErr); !errors.Is(err, domain.ErrSSOSessionExpired) || !strings.Contains(err.Error(), "aws sso login") {
+		t.Errorf("explainAWSError() = %v, want the hint for aws sso login", err)
+	}
+
+	exitErr := &ExitError{Code: 2, Err: ssoErr}
+	if ExitCode(explainAWSError(exitErr)) != 2 {
+		t.Error("explainAWSError() should keep the exit code")
+	}
+
+	otherErr := errors.New("access denied")
+	if err := explainAWSError(otherErr); err != otherErr { //nolint:errorlint
+		t.Errorf("explainAWSError() = %v, want %v", err, otherErr)
+	}
+}
@@ -0,0 +1,270 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/config/rainbow"
+	"github.com/spf13/cobra"
+)
+
+// configRetryPolicy is the retry policy in the configuration file. It is set by LoadConfig and
+// merged into the default retry policy by NewAWSConfigOptions.
+var configRetryPolicy model.RetryPolicy //nolint:gochecknoglobals
+
+// LoadConfig reads the user configuration file and applies the color setting.
+// If the configuration file is broken, it prints the warning and returns the empty Config
+// so that the user can fix it with the config command.
+func LoadConfig() *rainbow.Config {
+	cfg, err := rainbow.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: ignore the config file: %s\n", color.YellowString("WARN"), err.Error())
+		cfg = rainbow.NewConfig()
+		cfg.OverrideWithEnv(os.Getenv)
+	}
+
+	policy, err := newRetryPolicy(cfg.Retry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: ignore the retry policy in the config file: %s\n", color.YellowString("WARN"), err.Error())
+	}
+	configRetryPolicy = policy
+
+	if err := registerRegions(cfg.Regions); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: ignore the regions in the config file: %s\n", color.YellowString("WARN"), err.Error())
+	}
+
+	switch cfg.Color {
+	case rainbow.ColorAlways:
+		color.NoColor = false
+	case rainbow.ColorNever:
+		color.NoColor = true
+	}
+	return cfg
+}
+
+// newRetryPolicy converts the retry policy in the configuration file. Zero values are left as they are
+// so that the policy can be merged into the default one.
+func newRetryPolicy(r rainbow.Retry) (model.RetryPolicy, error) {
+	policy := model.RetryPolicy{
+		MaxAttempts: r.MaxAttempts,
+		Budget:      r.Budget,
+	}
+	for _, d := range []struct {
+		dst *time.Duration
+		v   string
+	}{
+		{dst: &policy.BaseDelay, v: r.BaseDelay},
+		{dst: &policy.ThrottleBaseDelay, v: r.ThrottleBaseDelay},
+		{dst: &policy.MaxBackoff, v: r.MaxBackoff},
+	} {
+		if d.v == "" {
+			continue
+		}
+		var err error
+		if *d.dst, err = time.ParseDuration(d.v); err != nil {
+			return model.RetryPolicy{}, err
+		}
+	}
+	if len(r.Operations) > 0 {
+		policy.Operations = make(map[string]model.RetryPolicy, len(r.Operations))
+		for name, op := range r.Operations {
+			opPolicy, err := newRetryPolicy(op)
+			if err != nil {
+				return model.RetryPolicy{}, fmt.Errorf("%s: %w", name, err)
+			}
+			policy.Operations[name] = opPolicy
+		}
+	}
+	return policy, policy.Validate()
+}
+
+// registerRegions adds the regions in the configuration file to the region catalog.
+func registerRegions(regions []rainbow.Region) error {
+	infos := make([]model.RegionInfo, 0, len(regions))
+	for _, r := range regions {
+		infos = append(infos, model.RegionInfo{
+			Region:           model.Region(r.Name),
+			Partition:        model.Partition(r.Partition),
+			DisplayName:      r.DisplayName,
+			S3EndpointSuffix: r.S3EndpointSuffix,
+			OptIn:            r.OptIn,
+		})
+	}
+	return model.RegisterRegions(infos...)
+}
+
+// ApplyConfig sets the values in the Config to the default values of the flags in the root command and its subcommands.
+// The flags specified in the command line take precedence because they overwrite the default values.
+// tool is the per-tool settings (e.g. cfg.S3hub).
+func ApplyConfig(root *cobra.Command, cfg *rainbow.Config, tool rainbow.Tool) {
+	defaults := map[string]string{
+		"profile":      cfg.Profile,
+		"region":       cfg.Region,
+		"output":       cfg.Output,
+		"endpoint-url": cfg.Endpoint.URL,
+	}
+	if cfg.Endpoint.PathStyle {
+		defaults["path-style"] = "true"
+	}
+	if cfg.Endpoint.NoVerifySSL {
+		defaults["no-verify-ssl"] = "true"
+	}
+	if cfg.Confirm == rainbow.ConfirmNever {
+		defaults["force"] = "true"
+	}
+	if tool.Concurrency > 0 {
+		defaults["concurrency"] = strconv.Itoa(tool.Concurrency)
+	}
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for name, value := range defaults {
+			if value == "" {
+				continue
+			}
+			if flag := c.Flags().Lookup(name); flag != nil && flag.Value.Set(value) == nil {
+				flag.DefValue = value
+			}
+			if flag := c.PersistentFlags().Lookup(name); flag != nil && flag.Value.Set(value) == nil {
+				flag.DefValue = value
+			}
+		}
+		for _, child := range c.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+// NewConfigCmd returns the config command that manages the user configuration file.
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get or set the user configuration shared by rainbow tools",
+		Long: `Get or set the user configuration shared by rainbow tools (s3hub, cfn).
+The configuration file is $XDG_CONFIG_HOME/rainbow/config.yml ($HOME/.config/rainbow/config.yml).
+The precedence is command line flags > environment variables > configuration file.`,
+		Example: `  s3hub config set region ap-northeast-1
+  s3hub config set aliases.logs s3://my-log-bucket/app
+  s3hub ls @logs/2024`,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get KEY",
+		Short: "Print the value of the key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &configGetCmd{})
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set the value of the key. The empty value resets the key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &configSetCmd{})
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the keys that are set in the configuration file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd, args, &configListCmd{})
+		},
+	})
+	return cmd
+}
+
+// configCmd have common fields for config subcommands.
+type configCmd struct {
+	// command is the cobra command.
+	command *cobra.Command
+	// path is the path of the configuration file.
+	path string
+	// config is the configuration in the file. The environment variables are not applied.
+	config *rainbow.Config
+}
+
+// parse reads the configuration file.
+func (c *configCmd) parse(cmd *cobra.Command) error {
+	c.command = cmd
+	path, err := rainbow.FilePath()
+	if err != nil {
+		return err
+	}
+	c.path = path
+	c.config, err = rainbow.ReadFile(path)
+	return err
+}
+
+// configGetCmd is the command for config get.
+type configGetCmd struct {
+	configCmd
+	// key is the key to get.
+	key string
+}
+
+// Parse parses command line arguments.
+func (c *configGetCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("you must specify KEY")
+	}
+	c.key = args[0]
+	return c.parse(cmd)
+}
+
+// Do executes config get command.
+func (c *configGetCmd) Do() error {
+	v, err := c.config.Get(c.key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.command.OutOrStdout(), v)
+	return nil
+}
+
+// configSetCmd is the command for config set.
+type configSetCmd struct {
+	configCmd
+	// key is the key to set.
+	key string
+	// value is the value to set.
+	value string
+}
+
+// Parse parses command line arguments.
+func (c *configSetCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return errors.New("you must specify KEY and VALUE")
+	}
+	c.key, c.value = args[0], args[1]
+	return c.parse(cmd)
+}
+
+// Do executes config set command.
+func (c *configSetCmd) Do() error {
+	if err := c.config.Set(c.key, c.value); err != nil {
+		return err
+	}
+	return c.config.WriteFile(c.path)
+}
+
+// configListCmd is the command for config list.
+type configListCmd struct {
+	configCmd
+}
+
+// Parse parses command line arguments.
+func (c *configListCmd) Parse(cmd *cobra.Command, _ []string) error {
+	return c.parse(cmd)
+}
+
+// Do executes config list command.
+func (c *configListCmd) Do() error {
+	for _, kv := range c.config.List() {
+		fmt.Fprintf(c.command.OutOrStdout(), "%s=%s\n", kv[0], kv[1])
+	}
+	return nil
+}
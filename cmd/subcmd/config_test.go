@@ -0,0 +1,71 @@
+package subcmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nao1215/rainbow/config/rainbow"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyConfig(t *testing.T) {
+	t.Parallel()
+
+	root := &cobra.Command{Use: "root"}
+	AddAWSConfigFlags(root)
+	child := &cobra.Command{Use: "child", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	child.Flags().StringP("region", "r", "", "")
+	child.Flags().StringP("output", "o", "table", "")
+	child.Flags().BoolP("force", "f", false, "")
+	root.AddCommand(child)
+
+	cfg := rainbow.NewConfig()
+	cfg.Region = "ap-northeast-1"
+	cfg.Output = "json"
+	cfg.Confirm = rainbow.ConfirmNever
+	cfg.Endpoint.URL = "http://localhost:9000"
+	ApplyConfig(root, cfg, cfg.S3hub)
+
+	// The flag in the command line takes precedence over the config.
+	root.SetArgs([]string{"child", "--output", "csv"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"region":       "ap-northeast-1",
+		"output":       "csv",
+		"force":        "true",
+		"endpoint-url": "http://localhost:9000",
+		"path-style":   "false",
+	} {
+		if got := child.Flags().Lookup(name).Value.String(); got != want {
+			t.Errorf("--%s = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestConfigCmd(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := NewConfigCmd()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	run("set", "region", "ap-northeast-1")
+	run("set", "aliases.logs", "s3://log-bucket")
+	if got := run("get", "region"); got != "ap-northeast-1\n" {
+		t.Errorf("config get region = %q", got)
+	}
+	if got, want := run("list"), "region=ap-northeast-1\naliases.logs=s3://log-bucket\n"; got != want {
+		t.Errorf("config list = %q, want %q", got, want)
+	}
+}
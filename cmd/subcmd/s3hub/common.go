@@ -2,9 +2,12 @@ package s3hub
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/nao1215/rainbow/app/di"
 	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/nao1215/rainbow/utils/aimd"
 	"github.com/nao1215/rainbow/utils/errfmt"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +24,10 @@ type s3hub struct {
 	profile model.AWSProfile
 	// region is the AWS region name.
 	region model.Region
+	// awsConfigOptions is the options for the S3-compatible storage.
+	awsConfigOptions *model.AWSConfigOptions
+	// concurrency is the number of parallel API calls. 0 means the default of each command.
+	concurrency int
 }
 
 // newS3hub returns a new s3hub.
@@ -45,10 +52,28 @@ func (s *s3hub) parse(cmd *cobra.Command) error {
 	}
 	s.region = model.Region(r)
 
-	cfg, err := model.NewAWSConfig(s.ctx, s.profile, s.region)
+	if s.awsConfigOptions, err = subcmd.NewAWSConfigOptions(cmd); err != nil {
+		return err
+	}
+	if cmd.Flags().Lookup("concurrency") != nil {
+		if s.concurrency, err = cmd.Flags().GetInt("concurrency"); err != nil {
+			return err
+		}
+	}
+	if s.awsConfigOptions.Debug {
+		subcmd.PrintDebugBanner(cmd.ErrOrStderr(), s.awsConfigOptions)
+	}
+
+	cfg, err := model.NewAWSConfig(s.ctx, s.profile, s.region, s.awsConfigOptions)
 	if err != nil {
 		return errfmt.Wrap(err, "can not get aws config")
 	}
+	if s.awsConfigOptions.RoleARN != "" {
+		// Assume the role here so that the MFA token code is asked before the command starts.
+		if _, err := cfg.Credentials.Retrieve(s.ctx); err != nil {
+			return errfmt.Wrap(err, "can not assume role "+s.awsConfigOptions.RoleARN)
+		}
+	}
 	if s.region == "" {
 		if cfg.Config.Region == "" {
 			s.region = model.RegionUSEast1
@@ -57,13 +82,31 @@ func (s *s3hub) parse(cmd *cobra.Command) error {
 		}
 	}
 
-	s.S3App, err = di.NewS3App(s.ctx, s.profile, s.region)
+	s.S3App, err = di.NewS3App(s.ctx, s.profile, s.region, s.awsConfigOptions)
 	if err != nil {
 		return errfmt.Wrap(err, "can not create s3 application service")
 	}
 	return nil
 }
 
+// parallels returns the number of parallel API calls. If --concurrency is not specified, it returns def.
+func (s *s3hub) parallels(def int) int {
+	if s.concurrency > 0 {
+		return s.concurrency
+	}
+	return def
+}
+
+// newLimiter returns the adaptive concurrency limiter shared by the API calls of the command.
+// The concurrency starts with def and grows up to model.MaxS3AdaptiveParallelsCount while the calls are not throttled.
+// If --concurrency is specified, it is the upper limit.
+func (s *s3hub) newLimiter(def int) *aimd.Limiter {
+	if s.concurrency > 0 {
+		return model.NewS3Limiter(min(def, s.concurrency), s.concurrency)
+	}
+	return model.NewS3Limiter(def, 0)
+}
+
 // printf prints a formatted string.
 func (s *s3hub) printf(format string, a ...interface{}) {
 	s.command.Printf(format, a...)
@@ -73,3 +116,33 @@ func (s *s3hub) printf(format string, a ...interface{}) {
 func commandName() string {
 	return "s3hub"
 }
+
+// formatBytes returns the human-readable representation of the size in bytes.
+// e.g. 1536 -> "1.5 KiB"
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// divideIntoChunks divides a slice into chunks of the specified size.
+func divideIntoChunks(slice []model.S3ObjectIdentifier, chunkSize int) [][]model.S3ObjectIdentifier {
+	var chunks [][]model.S3ObjectIdentifier
+
+	for i := 0; i < len(slice); i += chunkSize {
+		end := i + chunkSize
+		if end > len(slice) {
+			end = len(slice)
+		}
+		chunks = append(chunks, slice[i:end])
+	}
+
+	return chunks
+}
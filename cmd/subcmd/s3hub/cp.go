@@ -1,10 +1,12 @@
 package s3hub
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 
 	"github.com/fatih/color"
 	"github.com/gogf/gf/os/gfile"
@@ -150,14 +152,16 @@ func (c *cpCmd) localToS3() error {
 
 	toBucket, toKey := model.NewBucketWithoutProtocol(c.pair.To).Split()
 	fileNum := len(targets)
+	var copied atomic.Int64
 
-	for i, v := range targets {
+	return c.newLimiter(model.S3CopyObjectsParallelsCount).ForEach(c.ctx, fileNum, func(ctx context.Context, i int) error {
+		v := targets[i]
 		data, err := os.ReadFile(filepath.Clean(v))
 		if err != nil {
 			return fmt.Errorf("can not read file %s: %w", color.YellowString(v), err)
 		}
 
-		if _, err := c.s3hub.FileUploader.UploadFile(c.ctx, &usecase.FileUploaderInput{
+		if _, err := c.s3hub.FileUploader.UploadFile(ctx, &usecase.FileUploaderInput{
 			Bucket: toBucket,
 			Region: c.s3hub.region,
 			Key:    model.S3Key(filepath.Join(toKey.String(), filepath.Base(v))),
@@ -166,13 +170,13 @@ func (c *cpCmd) localToS3() error {
 			return fmt.Errorf("can not upload file %s: %w", color.YellowString(v), err)
 		}
 		c.printf("[%d/%d] copy %s to %s\n",
-			i+1,
+			copied.Add(1),
 			fileNum,
 			color.YellowString(v),
 			color.YellowString(toBucket.Join(toKey).WithProtocol().String()),
 		)
-	}
-	return nil
+		return nil
+	})
 }
 
 // s3ToLocal copies from S3 to local.
@@ -184,8 +188,11 @@ func (c *cpCmd) s3ToLocal() error {
 	}
 
 	fileNum := len(targets)
-	for i, v := range targets {
-		downloadOutput, err := c.s3hub.S3ObjectDownloader.DownloadS3Object(c.ctx, &usecase.S3ObjectDownloaderInput{
+	var copied atomic.Int64
+
+	return c.newLimiter(model.S3CopyObjectsParallelsCount).ForEach(c.ctx, fileNum, func(ctx context.Context, i int) error {
+		v := targets[i]
+		downloadOutput, err := c.s3hub.S3ObjectDownloader.DownloadS3Object(ctx, &usecase.S3ObjectDownloaderInput{
 			Bucket: fromBucket,
 			Key:    v,
 		})
@@ -194,7 +201,7 @@ func (c *cpCmd) s3ToLocal() error {
 				color.YellowString(fromBucket.Join(v).WithProtocol().String()), err)
 		}
 
-		destinationPath := filepath.Clean(filepath.Join(c.pair.To, fromKey.String()))
+		destinationPath := filepath.Clean(filepath.Join(c.pair.To, v.String()))
 		if err := os.MkdirAll(filepath.Dir(destinationPath), 0750); err != nil {
 			return fmt.Errorf("can not create directory %s: %w", color.YellowString(filepath.Dir(destinationPath)), err)
 		}
@@ -204,13 +211,13 @@ func (c *cpCmd) s3ToLocal() error {
 		}
 
 		c.printf("[%d/%d] copy %s to %s\n",
-			i+1,
+			copied.Add(1),
 			fileNum,
 			color.YellowString(fromBucket.Join(v).WithProtocol().String()),
 			color.YellowString(destinationPath),
 		)
-	}
-	return nil
+		return nil
+	})
 }
 
 // filterS3Objects returns a slice of S3Key that matches the fromKey.
@@ -260,10 +267,13 @@ func (c *cpCmd) s3ToS3() error {
 	}
 
 	fileNum := len(targets)
-	for i, v := range targets {
+	var copied atomic.Int64
+
+	return c.newLimiter(model.S3CopyObjectsParallelsCount).ForEach(c.ctx, fileNum, func(ctx context.Context, i int) error {
+		v := targets[i]
 		destinationKey := model.S3Key(filepath.Clean(filepath.Join(toKey.String(), v.String())))
 
-		if _, err := c.s3hub.S3ObjectCopier.CopyS3Object(c.ctx, &usecase.S3ObjectCopierInput{
+		if _, err := c.s3hub.S3ObjectCopier.CopyS3Object(ctx, &usecase.S3ObjectCopierInput{
 			SourceBucket:      fromBucket,
 			SourceKey:         v, // from key
 			DestinationBucket: toBucket,
@@ -272,11 +282,11 @@ func (c *cpCmd) s3ToS3() error {
 			return err
 		}
 		c.printf("[%d/%d] copy %s to %s\n",
-			i+1,
+			copied.Add(1),
 			fileNum,
 			color.YellowString(fromBucket.Join(v).WithProtocol().String()),
 			color.YellowString(toBucket.Join(destinationKey).WithProtocol().String()),
 		)
-	}
-	return nil
+		return nil
+	})
 }
@@ -0,0 +1,307 @@
+package s3hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/nao1215/rainbow/utils/errfmt"
+	"github.com/nao1215/rainbow/utils/file"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// diffExitCodeDifferent is the exit code when differences are found.
+	diffExitCodeDifferent = 1
+	// diffExitCodeTrouble is the exit code when an error occurs. It is the same as diff(1).
+	diffExitCodeTrouble = 2
+)
+
+// newDiffCmd return diff command. diff compares objects between S3 and S3 (or local).
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [flags] A B",
+		Short: "Compare objects between S3 bucket and S3 bucket (or local directory)",
+		Long: `Compare objects between S3 bucket and S3 bucket (or local directory).
+A and B are s3://BUCKET_NAME/PREFIX or local path. The prefix is treated as a directory.
+
+Exit status is 0 if no differences are found, 1 if some differences are found, and 2 if trouble.`,
+		Example: `  [S3 bucket to local directory]
+    s3hub diff s3://mybucket/dist ./dist
+
+  [S3 bucket to S3 bucket, output as JSON]
+    s3hub diff -o json s3://mybucket1/path s3://mybucket2/path`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := subcmd.Run(cmd, args, &diffCmd{})
+			var exitErr *subcmd.ExitError
+			if err != nil && !errors.As(err, &exitErr) {
+				return &subcmd.ExitError{Code: diffExitCodeTrouble, Err: err}
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().String("compare", string(model.S3DiffCompareETag), "how to compare contents (etag: size and ETag/MD5, size: size only)")
+	cmd.Flags().StringP("output", "o", subcmd.OutputFormatTable.String(), "output format (table, json)")
+	return cmd
+}
+
+// diffCmd is the command for diff.
+type diffCmd struct {
+	// s3hub have common fields and methods for s3hub commands.
+	*s3hub
+	// a is the A side.
+	a *diffSide
+	// b is the B side.
+	b *diffSide
+	// mode is how to compare contents.
+	mode model.S3DiffCompareMode
+	// format is the output format.
+	format subcmd.OutputFormat
+}
+
+// diffSide is the one side of the comparison.
+type diffSide struct {
+	// name is the path specified by the user.
+	name string
+	// bucket is the name of the bucket. It is empty if the side is local.
+	bucket model.Bucket
+	// prefix is the prefix of the keys. It ends with "/" if it is not empty.
+	prefix model.S3Key
+}
+
+// newDiffSide returns a new diffSide.
+func newDiffSide(name string) *diffSide {
+	side := &diffSide{name: name}
+	if !strings.HasPrefix(name, model.S3Protocol) {
+		return side
+	}
+	side.bucket, side.prefix = model.NewBucketWithoutProtocol(name).Split()
+	if !side.prefix.Empty() {
+		side.prefix = model.S3Key(side.prefix.String() + "/")
+	}
+	return side
+}
+
+// isS3 returns true if the side is S3.
+func (d *diffSide) isS3() bool {
+	return !d.bucket.Empty()
+}
+
+// Parse parses command line arguments.
+func (d *diffCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("you must specify %s and %s", color.YellowString("A(arg1)"), color.YellowString("B(arg2)"))
+	}
+	d.a = newDiffSide(args[0])
+	d.b = newDiffSide(args[1])
+
+	compare, err := cmd.Flags().GetString("compare")
+	if err != nil {
+		return err
+	}
+	if d.mode, err = model.NewS3DiffCompareMode(compare); err != nil {
+		return err
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if d.format, err = subcmd.NewOutputFormat(output); err != nil {
+		return err
+	}
+	if d.format == subcmd.OutputFormatCSV {
+		if cmd.Flags().Changed("output") {
+			return errors.New("diff command does not support csv output")
+		}
+		d.format = subcmd.OutputFormatTable // csv in the config file is the default for other commands.
+	}
+
+	d.s3hub = newS3hub()
+	if !d.a.isS3() && !d.b.isS3() {
+		// Both sides are local. AWS credentials are not required.
+		d.s3hub.command = cmd
+		return nil
+	}
+	return d.s3hub.parse(cmd)
+}
+
+// Do executes diff command.
+func (d *diffCmd) Do() error {
+	objectsA, err := d.listObjects(d.a)
+	if err != nil {
+		return err
+	}
+	objectsB, err := d.listObjects(d.b)
+	if err != nil {
+		return err
+	}
+
+	if d.mode == model.S3DiffCompareETag {
+		if err := fillLocalMD5(d.a, objectsA, objectsB); err != nil {
+			return err
+		}
+		if err := fillLocalMD5(d.b, objectsB, objectsA); err != nil {
+			return err
+		}
+	}
+
+	result := model.NewS3DiffResult(d.a.name, d.b.name, objectsA, objectsB, d.mode)
+	w := d.command.OutOrStdout()
+	if d.format == subcmd.OutputFormatJSON {
+		if err := writeDiffJSON(w, result); err != nil {
+			return err
+		}
+	} else {
+		writeDiffUnified(w, result)
+	}
+
+	if result.HasDifference() {
+		return &subcmd.ExitError{
+			Code: diffExitCodeDifferent,
+			Err:  fmt.Errorf("found %d differences", len(result.Entries)),
+		}
+	}
+	return nil
+}
+
+// listObjects returns the objects in the side. The key is relative to the root of the side.
+func (d *diffCmd) listObjects(side *diffSide) ([]model.S3DiffObject, error) {
+	if side.isS3() {
+		return d.listS3Objects(side)
+	}
+	return listLocalObjects(side.name)
+}
+
+// listS3Objects returns the objects under the prefix.
+func (d *diffCmd) listS3Objects(side *diffSide) ([]model.S3DiffObject, error) {
+	output, err := d.S3ObjectsFinder.FindS3Objects(d.ctx, &usecase.S3ObjectsFinderInput{
+		Bucket: side.bucket,
+		Prefix: side.prefix,
+	})
+	if err != nil {
+		return nil, errfmt.Wrap(err, "can not list objects in "+side.name)
+	}
+
+	objects := make([]model.S3DiffObject, 0, output.Objects.Len())
+	for _, o := range output.Objects {
+		if strings.HasSuffix(o.S3Key.String(), "/") {
+			continue // directory placeholder object
+		}
+		objects = append(objects, model.S3DiffObject{
+			Key:  trimS3Prefix(o.S3Key, side.prefix),
+			Size: o.Size,
+			ETag: o.ETag,
+		})
+	}
+	return objects, nil
+}
+
+// listLocalObjects returns the files under the root. If root is a file, the key is the base name of the file.
+func listLocalObjects(root string) ([]model.S3DiffObject, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []model.S3DiffObject{{Key: filepath.Base(root), Size: info.Size()}}, nil
+	}
+
+	paths, err := file.WalkDir(root)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]model.S3DiffObject, 0, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, model.S3DiffObject{Key: filepath.ToSlash(rel), Size: info.Size()})
+	}
+	return objects, nil
+}
+
+// fillLocalMD5 calculates the MD5 of the local files that may be the same as the other side.
+// Files whose counterpart does not exist or has a different size are skipped because they differ anyway.
+func fillLocalMD5(side *diffSide, objects, others []model.S3DiffObject) error {
+	if side.isS3() {
+		return nil
+	}
+	sizes := make(map[string]int64, len(others))
+	for _, o := range others {
+		sizes[o.Key] = o.Size
+	}
+
+	info, err := os.Stat(side.name)
+	if err != nil {
+		return err
+	}
+	for i, o := range objects {
+		if size, ok := sizes[o.Key]; !ok || size != o.Size {
+			continue
+		}
+		p := side.name
+		if info.IsDir() {
+			p = filepath.Join(side.name, filepath.FromSlash(o.Key))
+		}
+		if objects[i].ETag, err = file.MD5(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trimS3Prefix returns the key relative to the prefix.
+// If the key is the same as the prefix, it returns the base name of the key.
+func trimS3Prefix(key, prefix model.S3Key) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(key.String(), prefix.String()), "/")
+	if rel == "" {
+		return path.Base(key.String())
+	}
+	return rel
+}
+
+// writeDiffJSON writes the diff result in JSON format.
+func writeDiffJSON(w io.Writer, result *model.S3DiffResult) error {
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errfmt.Wrap(err, "can not marshal diff result")
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// writeDiffUnified writes the diff result in unified diff-like format.
+// "-" means only in A, "+" means only in B and "!" means the content differs.
+func writeDiffUnified(w io.Writer, result *model.S3DiffResult) {
+	fmt.Fprintf(w, "--- %s\n", result.A)
+	fmt.Fprintf(w, "+++ %s\n", result.B)
+	for _, e := range result.Entries {
+		switch e.Status {
+		case model.S3DiffOnlyInA:
+			fmt.Fprintln(w, color.RedString("- %s", e.Key))
+		case model.S3DiffOnlyInB:
+			fmt.Fprintln(w, color.GreenString("+ %s", e.Key))
+		case model.S3DiffContentDiffers:
+			fmt.Fprintln(w, color.YellowString("! %s (%s)", e.Key, e.Reason))
+		}
+	}
+	fmt.Fprintf(w, "only in A: %d, only in B: %d, content differs: %d, identical: %d\n",
+		result.OnlyInA, result.OnlyInB, result.ContentDiffers, result.Identical)
+}
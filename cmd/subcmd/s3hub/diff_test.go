@@ -0,0 +1,79 @@
+package s3hub
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nao1215/rainbow/cmd/subcmd"
+)
+
+func Test_diff(t *testing.T) {
+	t.Parallel()
+
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for name, data := range files {
+			p := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p, []byte(data), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	t.Run("compare local directories", func(t *testing.T) {
+		t.Parallel()
+
+		a := writeFiles(t, map[string]string{"same.txt": "abc", "dir/changed.txt": "abc", "only-a.txt": "a"})
+		b := writeFiles(t, map[string]string{"same.txt": "abc", "dir/changed.txt": "abd", "only-b.txt": "b"})
+
+		cmd := newDiffCmd()
+		stdout := bytes.NewBufferString("")
+		cmd.SetOut(stdout)
+
+		err := cmd.RunE(cmd, []string{a, b})
+		if got := subcmd.ExitCode(err); got != diffExitCodeDifferent {
+			t.Fatalf("exit code = %d, want %d: %v", got, diffExitCodeDifferent, err)
+		}
+
+		want := "--- " + a + "\n" +
+			"+++ " + b + "\n" +
+			"! dir/changed.txt (etag 900150983cd24fb0d6963f7d28e17f72 != 4911e516e5aa21d327512e0c8b197616)\n" +
+			"- only-a.txt\n" +
+			"+ only-b.txt\n" +
+			"only in A: 1, only in B: 1, content differs: 1, identical: 1\n"
+		if got := stdout.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("no difference", func(t *testing.T) {
+		t.Parallel()
+
+		a := writeFiles(t, map[string]string{"same.txt": "abc"})
+		b := writeFiles(t, map[string]string{"same.txt": "abc"})
+
+		cmd := newDiffCmd()
+		cmd.SetOut(bytes.NewBufferString(""))
+		if err := cmd.RunE(cmd, []string{a, b}); err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+	})
+
+	t.Run("If path does not exist, exit code is 2", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := newDiffCmd()
+		cmd.SetOut(bytes.NewBufferString(""))
+		err := cmd.RunE(cmd, []string{filepath.Join(t.TempDir(), "not-exist"), t.TempDir()})
+		if got := subcmd.ExitCode(err); got != diffExitCodeTrouble {
+			t.Errorf("exit code = %d, want %d: %v", got, diffExitCodeTrouble, err)
+		}
+	})
+}
@@ -0,0 +1,320 @@
+package s3hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/nao1215/rainbow/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newFindCmd return find command. find searches objects in S3 bucket like find(1).
+func newFindCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "find [flags] s3://BUCKET_NAME/PREFIX",
+		Short: "Find objects in S3 bucket with predicates (name, size, mtime, tag, etc.)",
+		Example: `  [Find objects larger than 100 MiB under the prefix]
+    s3hub find --size +100M s3://mybucket/logs/
+
+  [Find png files modified within 7 days, separated by NUL]
+    s3hub find --name '*.png' --mtime -7d --print0 s3://mybucket | xargs -0 echo
+
+  [Find objects with the tag and the storage class, then delete them]
+    s3hub find --tag env=dev --storage-class GLACIER --delete s3://mybucket
+
+  [Copy matched objects to another bucket (or local directory)]
+    s3hub find --regex '\.csv$' --exec-cp s3://backup/csv s3://mybucket/data/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &findCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().String("name", "", "glob pattern matched against the base name of the key (e.g. '*.log')")
+	cmd.Flags().String("regex", "", "regular expression matched against the whole key")
+	cmd.Flags().String("size", "", "object size: +N (larger), -N (smaller), N (exactly). suffix: B, K, M, G, T")
+	cmd.Flags().String("mtime", "", "last modified: -N (within), +N (before), N (exactly). suffix: s, m, h, d, w (default d)")
+	cmd.Flags().StringSlice("storage-class", nil, "storage class (e.g. STANDARD, GLACIER). can be specified multiple times")
+	cmd.Flags().StringArray("tag", nil, "object tag in key=value format. can be specified multiple times")
+	cmd.Flags().String("content-type", "", "glob pattern matched against the Content-Type (e.g. 'image/*')")
+	cmd.Flags().Bool("print", false, "print the matched objects (default action)")
+	cmd.Flags().Bool("print0", false, "print the matched objects followed by a null character")
+	cmd.Flags().Bool("delete", false, "delete the matched objects")
+	cmd.Flags().String("exec-cp", "", "copy the matched objects to the destination (s3://BUCKET/PREFIX or local directory)")
+	cmd.Flags().BoolP("force", "f", false, "delete without confirmation")
+	return cmd
+}
+
+// findCmd is the command for find.
+type findCmd struct {
+	// s3hub have common fields and methods for s3hub commands.
+	*s3hub
+	// bucket is the name of the bucket to search.
+	bucket model.Bucket
+	// prefix is the prefix of the keys to search.
+	prefix model.S3Key
+	// filter is the set of predicates.
+	filter *model.S3ObjectFilter
+	// print is the flag to print the matched objects.
+	print bool
+	// print0 is the flag to print the matched objects separated by a null character.
+	print0 bool
+	// delete is the flag to delete the matched objects.
+	delete bool
+	// copyDestination is the destination of --exec-cp.
+	copyDestination string
+	// force is the flag to delete without confirmation.
+	force bool
+}
+
+// Parse parses command line arguments.
+func (f *findCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("you must specify s3://BUCKET_NAME/PREFIX")
+	}
+	f.bucket, f.prefix = model.NewBucketWithoutProtocol(args[0]).Split()
+	if !f.prefix.Empty() && strings.HasSuffix(args[0], "/") {
+		// Split() removes the trailing slash. Keep it so that "logs/" does not match "logs2/".
+		f.prefix = model.S3Key(f.prefix.String() + "/")
+	}
+
+	filter, err := newS3ObjectFilter(cmd)
+	if err != nil {
+		return err
+	}
+	f.filter = filter
+
+	if f.print, err = cmd.Flags().GetBool("print"); err != nil {
+		return err
+	}
+	if f.print0, err = cmd.Flags().GetBool("print0"); err != nil {
+		return err
+	}
+	if f.delete, err = cmd.Flags().GetBool("delete"); err != nil {
+		return err
+	}
+	if f.copyDestination, err = cmd.Flags().GetString("exec-cp"); err != nil {
+		return err
+	}
+	if f.force, err = cmd.Flags().GetBool("force"); err != nil {
+		return err
+	}
+	if f.print && f.print0 {
+		return errors.New("--print and --print0 can not be specified at the same time")
+	}
+	if !f.print0 && !f.delete && f.copyDestination == "" {
+		f.print = true
+	}
+
+	f.s3hub = newS3hub()
+	return f.s3hub.parse(cmd)
+}
+
+// newS3ObjectFilter creates the filter from the predicate flags.
+func newS3ObjectFilter(cmd *cobra.Command) (*model.S3ObjectFilter, error) {
+	filter := &model.S3ObjectFilter{}
+	var err error
+
+	if filter.Name, err = cmd.Flags().GetString("name"); err != nil {
+		return nil, err
+	}
+	if filter.ContentType, err = cmd.Flags().GetString("content-type"); err != nil {
+		return nil, err
+	}
+	if filter.StorageClasses, err = cmd.Flags().GetStringSlice("storage-class"); err != nil {
+		return nil, err
+	}
+
+	regex, err := cmd.Flags().GetString("regex")
+	if err != nil {
+		return nil, err
+	}
+	if regex != "" {
+		if filter.Regex, err = regexp.Compile(regex); err != nil {
+			return nil, fmt.Errorf("invalid regular expression %s: %w", color.YellowString(regex), err)
+		}
+	}
+
+	size, err := cmd.Flags().GetString("size")
+	if err != nil {
+		return nil, err
+	}
+	if size != "" {
+		if filter.Size, err = model.NewS3SizeCondition(size); err != nil {
+			return nil, err
+		}
+	}
+
+	mtime, err := cmd.Flags().GetString("mtime")
+	if err != nil {
+		return nil, err
+	}
+	if mtime != "" {
+		if filter.Age, err = model.NewS3AgeCondition(mtime); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := cmd.Flags().GetStringArray("tag")
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		tag, err := model.NewS3Tag(t)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// Do executes find command.
+// Actions are executed in the order of print, exec-cp and delete.
+func (f *findCmd) Do() error {
+	output, err := f.S3ObjectsFinder.FindS3Objects(f.ctx, &usecase.S3ObjectsFinderInput{
+		Bucket: f.bucket,
+		Prefix: f.prefix,
+		Filter: f.filter,
+	})
+	if err != nil {
+		return errfmt.Wrap(err, "can not find objects")
+	}
+
+	if f.print || f.print0 {
+		f.printObjects(output.Objects)
+	}
+	if f.copyDestination != "" {
+		if err := f.copyObjects(output.Objects); err != nil {
+			return err
+		}
+	}
+	if f.delete {
+		return f.deleteObjects(output.Objects)
+	}
+	return nil
+}
+
+// printObjects prints the matched objects to stdout.
+func (f *findCmd) printObjects(objects model.S3ObjectDetails) {
+	separator := "\n"
+	if f.print0 {
+		separator = "\x00"
+	}
+	w := f.command.OutOrStdout()
+	for _, o := range objects {
+		fmt.Fprint(w, f.bucket.Join(o.S3Key).WithProtocol().String()+separator)
+	}
+}
+
+// relativeKey returns the key relative to the search prefix.
+func (f *findCmd) relativeKey(key model.S3Key) string {
+	return trimS3Prefix(key, f.prefix)
+}
+
+// copyObjects copies the matched objects to the destination.
+// The key relative to the search prefix is kept under the destination.
+func (f *findCmd) copyObjects(objects model.S3ObjectDetails) error {
+	toS3 := strings.HasPrefix(f.copyDestination, model.S3Protocol)
+	toBucket, toKey := model.NewBucketWithoutProtocol(f.copyDestination).Split()
+
+	for i, o := range objects {
+		from := f.bucket.Join(o.S3Key).WithProtocol().String()
+		rel := f.relativeKey(o.S3Key)
+
+		var to string
+		if toS3 {
+			destinationKey := model.S3Key(path.Join(toKey.String(), rel))
+			if _, err := f.S3ObjectCopier.CopyS3Object(f.ctx, &usecase.S3ObjectCopierInput{
+				SourceBucket:      f.bucket,
+				SourceKey:         o.S3Key,
+				DestinationBucket: toBucket,
+				DestinationKey:    destinationKey,
+			}); err != nil {
+				return fmt.Errorf("can not copy %s: %w", color.YellowString(from), err)
+			}
+			to = toBucket.Join(destinationKey).WithProtocol().String()
+		} else {
+			var ok bool
+			if to, ok = localCopyPath(f.copyDestination, rel); !ok {
+				f.printf("%s: skip %s because the key escapes the destination %s\n",
+					color.YellowString("WARN"), color.YellowString(from), color.YellowString(f.copyDestination))
+				continue
+			}
+			downloadOutput, err := f.S3ObjectDownloader.DownloadS3Object(f.ctx, &usecase.S3ObjectDownloaderInput{
+				Bucket: f.bucket,
+				Key:    o.S3Key,
+			})
+			if err != nil {
+				return fmt.Errorf("can not download s3 object=%s: %w", color.YellowString(from), err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(to), 0750); err != nil {
+				return fmt.Errorf("can not create directory %s: %w", color.YellowString(filepath.Dir(to)), err)
+			}
+			if err := downloadOutput.S3Object.ToFile(to, 0644); err != nil {
+				return fmt.Errorf("can not write file to %s: %w", color.YellowString(to), err)
+			}
+		}
+		f.printf("[%d/%d] copy %s to %s\n", i+1, len(objects), color.YellowString(from), color.YellowString(to))
+	}
+	return nil
+}
+
+// localCopyPath returns the local path of the key relative to the search prefix under the destination directory.
+// It returns false if the key escapes the destination with ".." (e.g. a/../../.ssh/authorized_keys).
+func localCopyPath(destination, rel string) (string, bool) {
+	dir := filepath.Clean(destination)
+	to := filepath.Join(dir, filepath.FromSlash(rel))
+	r, err := filepath.Rel(dir, to)
+	if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return to, true
+}
+
+// deleteObjects deletes the matched objects after confirmation.
+func (f *findCmd) deleteObjects(objects model.S3ObjectDetails) error {
+	if objects.Len() == 0 {
+		f.printf("no objects to delete in %s\n", color.YellowString("%s", f.bucket))
+		return nil
+	}
+	if !f.force {
+		if !subcmd.Question(f.command.OutOrStdout(),
+			fmt.Sprintf("delete %s objects in %s?", color.YellowString("%d", objects.Len()), color.YellowString("%s", f.bucket))) {
+			return nil
+		}
+	}
+
+	identifiers := make(model.S3ObjectIdentifiers, 0, objects.Len())
+	for _, o := range objects {
+		identifiers = append(identifiers, model.S3ObjectIdentifier{S3Key: o.S3Key})
+	}
+
+	chunks := divideIntoChunks(identifiers, model.S3DeleteObjectChunksSize)
+	if err := f.newLimiter(model.MaxS3DeleteObjectsParallelsCount).ForEach(f.ctx, len(chunks), func(ctx context.Context, i int) error {
+		_, err := f.S3ObjectsDeleter.DeleteS3Objects(ctx, &usecase.S3ObjectsDeleterInput{
+			Bucket:              f.bucket,
+			S3ObjectIdentifiers: chunks[i],
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+	f.printf("delete %s objects in %s\n", color.YellowString("%d", objects.Len()), color.YellowString("%s", f.bucket))
+	return nil
+}
@@ -0,0 +1,146 @@
+package s3hub
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_newS3ObjectFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse all predicates", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := newFindCmd()
+		if err := cmd.ParseFlags([]string{
+			"--name", "*.log",
+			"--regex", `^logs/`,
+			"--size", "+1K",
+			"--mtime", "-7d",
+			"--storage-class", "STANDARD,GLACIER",
+			"--tag", "env=dev",
+			"--tag", "team=a",
+			"--content-type", "text/*",
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := newS3ObjectFilter(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "*.log" || got.Regex.String() != "^logs/" || got.ContentType != "text/*" {
+			t.Errorf("unexpected filter: %+v", got)
+		}
+		if got.Size.Bytes != 1024 || got.Size.Comparison != model.ComparisonGreater {
+			t.Errorf("unexpected size condition: %+v", got.Size)
+		}
+		if got.Age.Comparison != model.ComparisonLess {
+			t.Errorf("unexpected age condition: %+v", got.Age)
+		}
+		if len(got.StorageClasses) != 2 || len(got.Tags) != 2 {
+			t.Errorf("unexpected storage classes or tags: %v, %v", got.StorageClasses, got.Tags)
+		}
+	})
+
+	t.Run("invalid predicates", func(t *testing.T) {
+		t.Parallel()
+
+		for _, args := range [][]string{
+			{"--regex", "("},
+			{"--size", "big"},
+			{"--mtime", "yesterday"},
+			{"--tag", "env"},
+			{"--name", "[a-"},
+		} {
+			cmd := newFindCmd()
+			if err := cmd.ParseFlags(args); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := newS3ObjectFilter(cmd); err == nil {
+				t.Errorf("newS3ObjectFilter(%v) should return error", args)
+			}
+		}
+	})
+}
+
+func Test_findCmd_printObjects(t *testing.T) {
+	t.Parallel()
+
+	objects := model.S3ObjectDetails{{S3Key: "logs/a.log"}, {S3Key: "logs/b.log"}}
+	tests := []struct {
+		name   string
+		print0 bool
+		want   string
+	}{
+		{name: "print", want: "s3://mybucket/logs/a.log\ns3://mybucket/logs/b.log\n"},
+		{name: "print0", print0: true, want: "s3://mybucket/logs/a.log\x00s3://mybucket/logs/b.log\x00"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := newFindCmd()
+			stdout := bytes.NewBufferString("")
+			cmd.SetOut(stdout)
+			f := &findCmd{s3hub: &s3hub{command: cmd}, bucket: "mybucket", print0: tt.print0}
+			f.printObjects(objects)
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findCmd_relativeKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		prefix model.S3Key
+		key    model.S3Key
+		want   string
+	}{
+		{prefix: "", key: "logs/a.log", want: "logs/a.log"},
+		{prefix: "logs", key: "logs/a/b.log", want: "a/b.log"},
+		{prefix: "logs/", key: "logs/a/b.log", want: "a/b.log"},
+	}
+	for _, tt := range tests {
+		f := &findCmd{prefix: tt.prefix}
+		if got := f.relativeKey(tt.key); got != tt.want {
+			t.Errorf("relativeKey(%s) with prefix %s = %s, want %s", tt.key, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func Test_localCopyPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		destination string
+		rel         string
+		want        string
+		wantOK      bool
+	}{
+		{name: "key under the destination", destination: "out", rel: "logs/a.log", want: filepath.Join("out", "logs", "a.log"), wantOK: true},
+		{name: "key with .. inside the destination", destination: "./out/", rel: "logs/../a.log", want: filepath.Join("out", "a.log"), wantOK: true},
+		{name: "key that starts with .. in the name", destination: "out", rel: "..a.log", want: filepath.Join("out", "..a.log"), wantOK: true},
+		{name: "key that escapes the destination", destination: "out", rel: "a/../../../.ssh/authorized_keys", wantOK: false},
+		{name: "key that is the parent of the destination", destination: "out/logs", rel: "..", wantOK: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := localCopyPath(tt.destination, tt.rel)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("localCopyPath(%s, %s) = (%s, %v), want (%s, %v)", tt.destination, tt.rel, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
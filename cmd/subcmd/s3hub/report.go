@@ -0,0 +1,173 @@
+package s3hub
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/app/usecase"
+	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/nao1215/rainbow/utils/errfmt"
+	"github.com/spf13/cobra"
+)
+
+// newReportCmd return report command. report analyzes all objects in the S3 bucket.
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report [flags] BUCKET_NAME",
+		Short: "Report S3 bucket inventory (size, storage class, age, etc.)",
+		Example: `  s3hub report -p myprofile -r us-east-1 BUCKET_NAME
+  s3hub report --top 20 -o json BUCKET_NAME`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return subcmd.Run(cmd, args, &reportCmd{})
+		},
+	}
+	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
+	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
+	cmd.Flags().IntP("top", "n", model.DefaultS3ReportTopN, "number of the largest objects to report")
+	cmd.Flags().StringP("output", "o", subcmd.OutputFormatTable.String(), "output format (table, json, csv)")
+	return cmd
+}
+
+// reportCmd is the command for report.
+type reportCmd struct {
+	// s3hub have common fields and methods for s3hub commands.
+	*s3hub
+	// bucket is the name of the bucket to analyze.
+	bucket model.Bucket
+	// topN is the number of the largest objects to report.
+	topN int
+	// format is the output format.
+	format subcmd.OutputFormat
+}
+
+// Parse parses command line arguments.
+func (r *reportCmd) Parse(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("you must specify a bucket name")
+	}
+	r.bucket = model.NewBucketWithoutProtocol(args[0])
+
+	topN, err := cmd.Flags().GetInt("top")
+	if err != nil {
+		return err
+	}
+	if topN < 1 {
+		return fmt.Errorf("--top must be greater than 0: %d", topN)
+	}
+	r.topN = topN
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if r.format, err = subcmd.NewOutputFormat(output); err != nil {
+		return err
+	}
+
+	r.s3hub = newS3hub()
+	return r.s3hub.parse(cmd)
+}
+
+// Do executes report command.
+func (r *reportCmd) Do() error {
+	output, err := r.S3BucketReporter.ReportS3Bucket(r.ctx, &usecase.S3BucketReporterInput{
+		Bucket: r.bucket,
+		TopN:   r.topN,
+	})
+	if err != nil {
+		return errfmt.Wrap(err, "can not create bucket report")
+	}
+
+	w := r.command.OutOrStdout()
+	switch r.format {
+	case subcmd.OutputFormatJSON:
+		return writeReportJSON(w, output.Report)
+	case subcmd.OutputFormatCSV:
+		return writeReportCSV(w, output.Report)
+	default:
+		return writeReportTable(w, output.Report)
+	}
+}
+
+// writeReportJSON writes the report in JSON format.
+func writeReportJSON(w io.Writer, report *model.S3BucketReport) error {
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errfmt.Wrap(err, "can not marshal report")
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// writeReportCSV writes the report in CSV format.
+// Each row is "section,name,objects,bytes".
+func writeReportCSV(w io.Writer, report *model.S3BucketReport) error {
+	cw := csv.NewWriter(w)
+	row := func(section, name string, objects, bytes int64) []string {
+		return []string{section, name, strconv.FormatInt(objects, 10), strconv.FormatInt(bytes, 10)}
+	}
+
+	records := [][]string{
+		{"section", "name", "objects", "bytes"},
+		row("summary", "current_objects", report.TotalObjects, report.TotalBytes),
+		row("summary", "noncurrent_versions", report.NoncurrentVersions, report.NoncurrentVersionBytes),
+		row("summary", "delete_markers", report.DeleteMarkers, 0),
+		row("summary", "incomplete_multipart_uploads", report.IncompleteMultipartUploads, 0),
+	}
+	for _, b := range report.StorageClasses {
+		records = append(records, row("storage_class", b.Name, b.Objects, b.Bytes))
+	}
+	for _, b := range report.Prefixes {
+		records = append(records, row("prefix", b.Name, b.Objects, b.Bytes))
+	}
+	for _, o := range report.LargestObjects {
+		records = append(records, row("largest_object", o.Key, 1, o.Bytes))
+	}
+	for _, a := range report.AgeHistogram {
+		records = append(records, row("age", a.Label, a.Objects, a.Bytes))
+	}
+
+	if err := cw.WriteAll(records); err != nil {
+		return errfmt.Wrap(err, "can not write report as csv")
+	}
+	return nil
+}
+
+// writeReportTable writes the report in human-readable table format.
+func writeReportTable(w io.Writer, report *model.S3BucketReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "[Bucket] %s (generated at %s)\n", color.YellowString("%s", report.Bucket), report.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(tw, "  current objects\t%d\t%s\n", report.TotalObjects, formatBytes(report.TotalBytes))
+	fmt.Fprintf(tw, "  noncurrent versions\t%d\t%s\n", report.NoncurrentVersions, formatBytes(report.NoncurrentVersionBytes))
+	fmt.Fprintf(tw, "  delete markers\t%d\t\n", report.DeleteMarkers)
+	fmt.Fprintf(tw, "  incomplete multipart uploads\t%d\t\n", report.IncompleteMultipartUploads)
+
+	writeBreakdowns := func(title string, breakdowns []model.S3ReportBreakdown) {
+		fmt.Fprintf(tw, "\n[%s]\n", title)
+		for _, b := range breakdowns {
+			fmt.Fprintf(tw, "  %s\t%d\t%s\n", b.Name, b.Objects, formatBytes(b.Bytes))
+		}
+	}
+	writeBreakdowns("Storage Class", report.StorageClasses)
+	writeBreakdowns("Top-Level Prefix", report.Prefixes)
+
+	fmt.Fprintf(tw, "\n[Largest Objects]\n")
+	for _, o := range report.LargestObjects {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", o.Key, formatBytes(o.Bytes), o.LastModified.Format(time.RFC3339))
+	}
+
+	fmt.Fprintf(tw, "\n[Age]\n")
+	for _, a := range report.AgeHistogram {
+		fmt.Fprintf(tw, "  %s\t%d\t%s\n", a.Label, a.Objects, formatBytes(a.Bytes))
+	}
+	return tw.Flush()
+}
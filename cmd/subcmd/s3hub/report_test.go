@@ -0,0 +1,72 @@
+package s3hub
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/app/domain/model"
+)
+
+func Test_writeReportCSV(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	report := model.NewS3BucketReport(
+		model.Bucket("bucket"),
+		model.S3ObjectDetails{
+			{S3Key: "logs/a.log", Size: 100, StorageClass: "STANDARD", LastModified: now},
+		},
+		model.S3ObjectVersions{{S3Key: "logs/a.log", VersionID: "0", Size: 40}},
+		nil,
+		1,
+		now,
+	)
+
+	var buf bytes.Buffer
+	if err := writeReportCSV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"section,name,objects,bytes",
+		"summary,current_objects,1,100",
+		"summary,noncurrent_versions,1,40",
+		"summary,delete_markers,0,0",
+		"summary,incomplete_multipart_uploads,0,0",
+		"storage_class,STANDARD,1,100",
+		"prefix,logs/,1,100",
+		"largest_object,logs/a.log,1,100",
+		"age,< 1 day,1,100",
+		"age,1-7 days,0,0",
+		"age,7-30 days,0,0",
+		"age,30-90 days,0,0",
+		"age,90-365 days,0,0",
+		"age,>= 1 year,0,0",
+	}, "\n") + "\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("writeReportCSV() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_formatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 1023, want: "1023 B"},
+		{size: 1536, want: "1.5 KiB"},
+		{size: 5 * 1024 * 1024, want: "5.0 MiB"},
+		{size: 3 * 1024 * 1024 * 1024 * 1024, want: "3.0 TiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.size); got != tt.want {
+			t.Errorf("formatBytes(%d) = %s, want %s", tt.size, got, tt.want)
+		}
+	}
+}
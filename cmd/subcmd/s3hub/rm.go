@@ -1,19 +1,25 @@
 package s3hub
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/nao1215/rainbow/app/domain"
 	"github.com/nao1215/rainbow/app/domain/model"
 	"github.com/nao1215/rainbow/app/usecase"
 	"github.com/nao1215/rainbow/cmd/subcmd"
+	"github.com/nao1215/rainbow/utils/aimd"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 )
 
 // newRmCmd return rm command.
@@ -31,7 +37,10 @@ func newRmCmd() *cobra.Command {
   [Delete S3 bucket and all objects]
     s3hub rm BUCKET_NAME
      or
-    s3hub rm BUCKET_NAME/`,
+    s3hub rm BUCKET_NAME/
+
+  [Delete S3 buckets that match the conditions]
+    s3hub rm --match '^test-' --created-before 2024-01-01 --tag env=test`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return subcmd.Run(cmd, args, &rmCmd{})
 		},
@@ -40,6 +49,11 @@ func newRmCmd() *cobra.Command {
 	// not used. however, this is common flag.
 	cmd.Flags().StringP("region", "r", "", "AWS region name, default is us-east-1")
 	cmd.Flags().BoolP("force", "f", false, "Force delete")
+	cmd.Flags().Bool("bypass-governance", false, "delete object versions under GOVERNANCE mode retention (requires s3:BypassGovernanceRetention)")
+	cmd.Flags().Bool("release-legal-hold", false, "turn off the legal hold of object versions before deleting them")
+	cmd.Flags().String("match", "", "delete buckets whose name matches the regular expression")
+	cmd.Flags().String("created-before", "", "delete buckets created before the date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().StringArray("tag", nil, "delete buckets that have the tag (key=value). can be specified multiple times")
 	return cmd
 }
 
@@ -50,13 +64,30 @@ type rmCmd struct {
 	buckets []model.Bucket
 	// force is the flag to force delete.
 	force bool
+	// bypassGovernance is the flag to delete object versions under GOVERNANCE mode retention.
+	bypassGovernance bool
+	// releaseLegalHold is the flag to turn off the legal hold before deleting object versions.
+	releaseLegalHold bool
+	// filter is the conditions for selecting buckets. If it is empty, buckets are specified by arguments.
+	filter *model.S3BucketFilter
+	// limiter is the adaptive concurrency limiter shared by the object deletions of all buckets.
+	limiter *aimd.Limiter
 }
 
 // Parse parses command line arguments.
 func (r *rmCmd) Parse(cmd *cobra.Command, args []string) error {
-	if len(args) == 0 {
+	filter, err := newS3BucketFilter(cmd)
+	if err != nil {
+		return err
+	}
+	r.filter = filter
+
+	if r.filter.Empty() && len(args) == 0 {
 		return errors.New("you must specify a bucket name")
 	}
+	if !r.filter.Empty() && len(args) != 0 {
+		return errors.New("bucket names can not be specified with --match, --created-before and --tag")
+	}
 
 	for _, arg := range args {
 		r.buckets = append(r.buckets, model.Bucket(arg))
@@ -69,11 +100,63 @@ func (r *rmCmd) Parse(cmd *cobra.Command, args []string) error {
 	}
 	r.force = force
 
-	return r.s3hub.parse(cmd)
+	if r.bypassGovernance, err = cmd.Flags().GetBool("bypass-governance"); err != nil {
+		return err
+	}
+	if r.releaseLegalHold, err = cmd.Flags().GetBool("release-legal-hold"); err != nil {
+		return err
+	}
+
+	if err := r.s3hub.parse(cmd); err != nil {
+		return err
+	}
+	r.limiter = r.newLimiter(model.MaxS3DeleteObjectsParallelsCount)
+	return nil
+}
+
+// newS3BucketFilter creates the filter from the bucket selection flags.
+func newS3BucketFilter(cmd *cobra.Command) (*model.S3BucketFilter, error) {
+	filter := &model.S3BucketFilter{}
+
+	match, err := cmd.Flags().GetString("match")
+	if err != nil {
+		return nil, err
+	}
+	if match != "" {
+		if filter.Name, err = regexp.Compile(match); err != nil {
+			return nil, fmt.Errorf("invalid regular expression %s: %w", color.YellowString(match), err)
+		}
+	}
+
+	createdBefore, err := cmd.Flags().GetString("created-before")
+	if err != nil {
+		return nil, err
+	}
+	if createdBefore != "" {
+		if filter.CreatedBefore, err = subcmd.ParseDate(createdBefore); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := cmd.Flags().GetStringArray("tag")
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		tag, err := model.NewS3Tag(t)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+	return filter, nil
 }
 
 // Do executes rm command.
 func (r *rmCmd) Do() error {
+	if !r.filter.Empty() {
+		return r.removeMatchedBuckets()
+	}
 	if err := r.existBuckets(); err != nil {
 		return err
 	}
@@ -95,9 +178,22 @@ func (r *rmCmd) remove(bucket model.Bucket, key model.S3Key) error {
 				return nil
 			}
 		}
-		if err := r.removeObjects(bucket); err != nil {
+		bar := progressbar.Default(-1, "deleting object versions")
+		output, err := r.emptyBucket(bucket, func(n int) {
+			_ = bar.Add(n) //nolint:errcheck // progress bar error is not critical
+		})
+		_ = bar.Finish() //nolint:errcheck // progress bar error is not critical
+		if err != nil {
+			if output != nil {
+				if werr := writeUndeletableObjects(r.command.OutOrStdout(), output.Undeletable); werr != nil {
+					return werr
+				}
+			}
 			return err
 		}
+		r.printf("delete %s object versions and abort %s multipart uploads in %s\n",
+			color.YellowString("%d", output.DeletedObjects), color.YellowString("%d", output.AbortedUploads), color.YellowString("%s", bucket))
+
 		if err := r.removeBucket(bucket); err != nil {
 			return err
 		}
@@ -160,49 +256,62 @@ func (r *rmCmd) removeObjects(bucket model.Bucket) error {
 		return nil
 	}
 
-	eg, ctx := errgroup.WithContext(r.ctx)
-	sem := semaphore.NewWeighted(model.MaxS3DeleteObjectsParallelsCount)
-	chunks := r.divideIntoChunks(output.Objects, model.S3DeleteObjectChunksSize)
-
 	bar := progressbar.Default(int64(output.Objects.Len()))
-	for _, chunk := range chunks {
-		chunk := chunk // Create a new variable to avoid concurrency issues
-		// Acquire semaphore to control the number of concurrent goroutines
-		if err := sem.Acquire(ctx, 1); err != nil {
-			return err
-		}
-
-		eg.Go(func() error {
-			defer sem.Release(1)
-			if _, err := r.S3App.S3ObjectsDeleter.DeleteS3Objects(ctx, &usecase.S3ObjectsDeleterInput{
-				Bucket:              bucket,
-				S3ObjectIdentifiers: chunk,
-			}); err != nil {
-				return err
-			}
-			return bar.Add(len(chunk))
-		})
-	}
-	if err := eg.Wait(); err != nil {
+	if err := r.deleteObjects(bucket, output.Objects, func(n int) error {
+		return bar.Add(n)
+	}); err != nil {
 		return err
 	}
 	r.printf("delete %s objects in %s\n", color.YellowString("%d", output.Objects.Len()), color.YellowString("%s", bucket))
 	return nil
 }
 
-// divideIntoChunks divides a slice into chunks of the specified size.
-func (r *rmCmd) divideIntoChunks(slice []model.S3ObjectIdentifier, chunkSize int) [][]model.S3ObjectIdentifier {
-	var chunks [][]model.S3ObjectIdentifier
-
-	for i := 0; i < len(slice); i += chunkSize {
-		end := i + chunkSize
-		if end > len(slice) {
-			end = len(slice)
+// deleteObjects deletes the objects in parallel with chunks.
+// progress is called with the number of deleted objects after each chunk is deleted. It may be nil.
+func (r *rmCmd) deleteObjects(bucket model.Bucket, objects model.S3ObjectIdentifiers, progress func(n int) error) error {
+	chunks := divideIntoChunks(objects, model.S3DeleteObjectChunksSize)
+	return r.limiter.ForEach(r.ctx, len(chunks), func(ctx context.Context, i int) error {
+		if _, err := r.S3App.S3ObjectsDeleter.DeleteS3Objects(ctx, &usecase.S3ObjectsDeleterInput{
+			Bucket:              bucket,
+			S3ObjectIdentifiers: chunks[i],
+		}); err != nil {
+			return err
+		}
+		if progress == nil {
+			return nil
 		}
-		chunks = append(chunks, slice[i:end])
+		return progress(len(chunks[i]))
+	})
+}
+
+// emptyBucket deletes all object versions and delete markers, and aborts all multipart uploads in the bucket.
+// If some object versions can not be deleted, it returns the output with the error.
+func (r *rmCmd) emptyBucket(bucket model.Bucket, progress func(n int)) (*usecase.S3BucketEmptierOutput, error) {
+	output, err := r.S3App.S3BucketEmptier.EmptyS3Bucket(r.ctx, &usecase.S3BucketEmptierInput{
+		Bucket:           bucket,
+		BypassGovernance: r.bypassGovernance,
+		ReleaseLegalHold: r.releaseLegalHold,
+		Progress:         progress,
+		Limiter:          r.limiter,
+	})
+	if err != nil {
+		return nil, err
 	}
+	if output.Undeletable.Len() > 0 {
+		return output, fmt.Errorf("%w: %s object versions in %s are protected and the bucket can not be deleted",
+			domain.ErrS3ObjectsDelete, color.YellowString("%d", output.Undeletable.Len()), color.YellowString("%s", bucket))
+	}
+	return output, nil
+}
 
-	return chunks
+// writeUndeletableObjects writes the object versions that could not be deleted with the reason.
+func writeUndeletableObjects(w io.Writer, objects model.S3UndeletableObjects) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVERSION ID\tREASON")
+	for _, o := range objects {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", o.S3Key, o.VersionID, o.Reason)
+	}
+	return tw.Flush()
 }
 
 // removeBucket removes a bucket.
@@ -237,3 +346,119 @@ func (r *rmCmd) existBuckets() error {
 	}
 	return fmt.Errorf("s3 bucket does not exist: %s", color.YellowString(strings.Join(notExistBuckets, ", ")))
 }
+
+// bucketDeletionResult is the result of deleting a bucket with objects.
+type bucketDeletionResult struct {
+	// bucket is the name of the deleted bucket.
+	bucket model.Bucket
+	// objects is the number of objects in the bucket.
+	objects int
+	// err is the error that occurred while deleting the bucket. It is nil if the deletion succeeded.
+	err error
+	// undeletable is the list of the object versions protected by the object lock.
+	undeletable model.S3UndeletableObjects
+}
+
+// removeMatchedBuckets removes the buckets that match the filter in parallel.
+func (r *rmCmd) removeMatchedBuckets() error {
+	output, err := r.S3App.S3BucketsFinder.FindS3Buckets(r.ctx, &usecase.S3BucketsFinderInput{
+		Filter: r.filter,
+	})
+	if err != nil {
+		return err
+	}
+	if output.Buckets.Empty() {
+		r.printf("no buckets match the conditions\n")
+		return nil
+	}
+
+	objects := make([]model.S3ObjectIdentifiers, output.Buckets.Len())
+	for i, b := range output.Buckets {
+		o, err := r.S3App.S3ObjectsLister.ListS3Objects(r.ctx, &usecase.S3ObjectsListerInput{
+			Bucket: b.Bucket,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: bucket=%s", err, color.YellowString(b.Bucket.String()))
+		}
+		objects[i] = o.Objects
+	}
+
+	w := r.command.OutOrStdout()
+	if err := writeBucketReviewTable(w, output.Buckets, objects); err != nil {
+		return err
+	}
+	if !r.force {
+		if !subcmd.Question(w, fmt.Sprintf("delete %s buckets with objects?", color.YellowString("%d", output.Buckets.Len()))) {
+			return nil
+		}
+	}
+
+	results := make([]bucketDeletionResult, output.Buckets.Len())
+	var eg errgroup.Group
+	eg.SetLimit(r.parallels(model.MaxS3DeleteBucketsParallelsCount))
+	for i, b := range output.Buckets {
+		i, b := i, b
+		eg.Go(func() error {
+			results[i] = bucketDeletionResult{bucket: b.Bucket, objects: objects[i].Len()}
+			output, err := r.emptyBucket(b.Bucket, nil)
+			if err != nil {
+				results[i].err = err
+				if output != nil {
+					results[i].undeletable = output.Undeletable
+				}
+				return nil
+			}
+			results[i].err = r.removeBucket(b.Bucket)
+			return nil // continue to delete other buckets
+		})
+	}
+	_ = eg.Wait() // errors are stored in results
+
+	if err := writeBucketDeletionSummary(w, results); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.undeletable.Len() == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n[%s]\n", color.YellowString("%s", result.bucket))
+		if err := writeUndeletableObjects(w, result.undeletable); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %s of %d buckets", color.YellowString("%d", failed), len(results))
+	}
+	return nil
+}
+
+// writeBucketReviewTable writes the buckets to be deleted with the number of objects.
+func writeBucketReviewTable(w io.Writer, buckets model.BucketSets, objects []model.S3ObjectIdentifiers) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BUCKET\tREGION\tCREATED\tOBJECTS")
+	for i, b := range buckets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", b.Bucket, b.Region, b.CreationDate.Format(time.RFC3339), objects[i].Len())
+	}
+	return tw.Flush()
+}
+
+// writeBucketDeletionSummary writes the success or failure of each bucket deletion.
+func writeBucketDeletionSummary(w io.Writer, results []bucketDeletionResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BUCKET\tOBJECTS\tRESULT")
+	for _, result := range results {
+		status := color.GreenString("deleted")
+		if result.err != nil {
+			status = color.RedString("failed: %s", result.err.Error())
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", result.bucket, result.objects, status)
+	}
+	return tw.Flush()
+}
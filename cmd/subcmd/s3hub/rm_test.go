@@ -2,7 +2,10 @@ package s3hub
 
 import (
 	"bytes"
+	"errors"
 	"testing"
+
+	"github.com/nao1215/rainbow/app/domain/model"
 )
 
 func Test_rm(t *testing.T) {
@@ -23,3 +26,68 @@ func Test_rm(t *testing.T) {
 		}
 	})
 }
+
+func Test_newS3BucketFilter(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRmCmd()
+	if err := cmd.ParseFlags([]string{"--match", "^test-", "--created-before", "2024-01-01", "--tag", "env=test"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := newS3BucketFilter(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name.String() != "^test-" || got.CreatedBefore.IsZero() || len(got.Tags) != 1 {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+
+	for _, args := range [][]string{{"--match", "("}, {"--created-before", "yesterday"}, {"--tag", "env"}} {
+		cmd := newRmCmd()
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newS3BucketFilter(cmd); err == nil {
+			t.Errorf("newS3BucketFilter(%v) should return error", args)
+		}
+	}
+}
+
+func Test_writeBucketDeletionSummary(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := writeBucketDeletionSummary(&buf, []bucketDeletionResult{
+		{bucket: "test-a", objects: 10},
+		{bucket: "test-b", objects: 0, err: errors.New("access denied")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "BUCKET  OBJECTS  RESULT\n" +
+		"test-a  10       deleted\n" +
+		"test-b  0        failed: access denied\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func Test_writeUndeletableObjects(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := writeUndeletableObjects(&buf, model.S3UndeletableObjects{
+		{
+			S3ObjectIdentifier: model.S3ObjectIdentifier{S3Key: "a.txt", VersionID: "v1"},
+			Reason:             "legal hold is ON (use --release-legal-hold)",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "KEY    VERSION ID  REASON\n" +
+		"a.txt  v1          legal hold is ON (use --release-legal-hold)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -4,6 +4,7 @@ package s3hub
 import (
 	"os"
 
+	"github.com/nao1215/rainbow/cmd/subcmd"
 	"github.com/spf13/cobra"
 )
 
@@ -12,7 +13,17 @@ func Execute() error {
 	if len(os.Args) == 1 {
 		return interactive()
 	}
-	if err := newRootCmd().Execute(); err != nil {
+
+	cfg := subcmd.LoadConfig()
+	cmd := newRootCmd()
+	subcmd.ApplyConfig(cmd, cfg, cfg.S3hub)
+	args := make([]string, 0, len(os.Args)-1)
+	for _, arg := range os.Args[1:] {
+		args = append(args, cfg.ExpandAlias(arg))
+	}
+	cmd.SetArgs(args)
+
+	if err := cmd.Execute(); err != nil {
 		return err
 	}
 	return nil
@@ -29,11 +40,17 @@ If you want to use interactive mode, run s3hub without any arguments.`,
 	cmd.SilenceUsage = true
 	cmd.SilenceErrors = true
 	cmd.DisableFlagParsing = true
+	subcmd.AddAWSConfigFlags(cmd)
+	cmd.PersistentFlags().Int("concurrency", 0, "number of parallel API calls (0: default of each command)")
 
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newMbCmd())
 	cmd.AddCommand(newLsCmd())
 	cmd.AddCommand(newRmCmd())
 	cmd.AddCommand(newCpCmd())
+	cmd.AddCommand(newReportCmd())
+	cmd.AddCommand(newFindCmd())
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(subcmd.NewConfigCmd())
 	return cmd
 }
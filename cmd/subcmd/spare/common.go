@@ -8,6 +8,7 @@ import (
 
 	"github.com/nao1215/rainbow/app/di"
 	"github.com/nao1215/rainbow/app/domain/model"
+	"github.com/nao1215/rainbow/cmd/subcmd"
 	"github.com/nao1215/rainbow/config/spare"
 	"github.com/nao1215/rainbow/utils/errfmt"
 	"github.com/spf13/cobra"
@@ -65,7 +66,14 @@ func (s *spareOption) parseCommon(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	spare, err := di.NewSpareApp(s.ctx, s.awsProfile, s.config.Region)
+	opts, err := subcmd.NewAWSConfigOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if opts.Debug {
+		opts.EndpointURL = s.config.DebugLocalstackEndpoint.String()
+	}
+	spare, err := di.NewSpareApp(s.ctx, s.awsProfile, s.config.Region, opts)
 	if err != nil {
 		return err
 	}
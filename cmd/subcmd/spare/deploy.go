@@ -16,8 +16,6 @@ import (
 	"github.com/nao1215/rainbow/utils/file"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 )
 
 // newDeployCmd return deploy sub command.
@@ -33,6 +31,8 @@ func newDeployCmd() *cobra.Command {
 	cmd.Flags().BoolP("debug", "d", false, "run debug mode. you must run localstack before using this flag")
 	cmd.Flags().StringP("profile", "p", "", "AWS profile name. if this is empty, use $AWS_PROFILE")
 	cmd.Flags().StringP("file", "f", spare.ConfigFilePath, "config file path")
+	subcmd.AddAssumeRoleFlags(cmd.Flags())
+	subcmd.AddTraceFlags(cmd.Flags())
 	return cmd
 }
 
@@ -76,24 +76,11 @@ func (d *deployCmd) Do() error {
 		return err
 	}
 
-	eg, ctx := errgroup.WithContext(d.ctx)
-	weighted := semaphore.NewWeighted(int64(runtime.NumCPU()))
-	for _, file := range files {
-		file := file
-		eg.Go(func() error {
-			if err := weighted.Acquire(ctx, 1); err != nil {
-				return err
-			}
-			defer weighted.Release(1)
-
-			return d.uploadFile(ctx, file)
-		})
-	}
-
-	if err := eg.Wait(); err != nil {
-		return err
-	}
-	return nil
+	// The concurrency adapts to the request rate of S3: it grows while uploads succeed and halves on SlowDown.
+	limiter := model.NewS3Limiter(runtime.NumCPU(), 0)
+	return limiter.ForEach(d.ctx, len(files), func(ctx context.Context, i int) error {
+		return d.uploadFile(ctx, files[i])
+	})
 }
 
 // uploadFile uploads a file to S3.
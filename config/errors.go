@@ -17,4 +17,8 @@ var (
 	ErrInvalidSpareTemplateVersion = errors.New("invalid spare template version")
 	// ErrInvalidDeployTarget is an error that occurs when the deploy target is invalid.
 	ErrInvalidDeployTarget = errors.New("invalid deploy target")
+	// ErrUnknownConfigKey is an error that occurs when the key does not exist in the rainbow config.
+	ErrUnknownConfigKey = errors.New("unknown config key")
+	// ErrInvalidConfigValue is an error that occurs when the value of the rainbow config is invalid.
+	ErrInvalidConfigValue = errors.New("invalid config value")
 )
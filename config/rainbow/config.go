@@ -0,0 +1,505 @@
+// Package rainbow is the user configuration shared by the rainbow tools (s3hub, cfn).
+package rainbow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nao1215/rainbow/config"
+	"github.com/nao1215/rainbow/utils/errfmt"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ColorAuto enables colors only when the output is a terminal.
+	ColorAuto = "auto"
+	// ColorAlways always enables colors.
+	ColorAlways = "always"
+	// ColorNever disables colors.
+	ColorNever = "never"
+
+	// ConfirmAlways asks the user before destructive operations. It is the default.
+	ConfirmAlways = "always"
+	// ConfirmNever never asks the user. It is the same as specifying --force every time.
+	ConfirmNever = "never"
+
+	// AliasPrefix is the prefix of the bucket alias in the command line arguments. e.g. "@logs/2024"
+	AliasPrefix = "@"
+)
+
+// Config is a struct that corresponds to the configuration file "$XDG_CONFIG_HOME/rainbow/config.yml".
+// Zero values mean that the default of each tool is used.
+type Config struct {
+	// Profile is the default AWS profile name.
+	Profile string `yaml:"profile,omitempty"`
+	// Region is the default AWS region.
+	Region string `yaml:"region,omitempty"`
+	// Output is the default output format (table, json, csv).
+	Output string `yaml:"output,omitempty"`
+	// Color is when to use colors (auto, always, never).
+	Color string `yaml:"color,omitempty"`
+	// Confirm is the confirmation policy for destructive operations (always, never).
+	Confirm string `yaml:"confirm,omitempty"`
+	// Endpoint is the endpoint override for the S3-compatible storage or the local stand-in.
+	Endpoint Endpoint `yaml:"endpoint,omitempty"`
+	// S3hub is the settings for s3hub.
+	S3hub Tool `yaml:"s3hub,omitempty"`
+	// CFn is the settings for cfn.
+	CFn Tool `yaml:"cfn,omitempty"`
+	// Aliases is the named bucket aliases. e.g. "logs: s3://my-log-bucket/app"
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+	// Retry is the retry policy of the AWS clients.
+	Retry Retry `yaml:"retry,omitempty"`
+	// Regions is the regions added to (or replaced in) the built-in region catalog.
+	// It is only editable in the configuration file.
+	Regions []Region `yaml:"regions,omitempty"`
+}
+
+// Retry is the retry policy. Zero values mean the default of rainbow.
+// The durations are the Go duration strings. e.g. "500ms", "30s"
+type Retry struct {
+	// MaxAttempts is the maximum number of attempts including the first request.
+	MaxAttempts int `yaml:"maxAttempts,omitempty"`
+	// BaseDelay is the base delay of the exponential backoff.
+	BaseDelay string `yaml:"baseDelay,omitempty"`
+	// ThrottleBaseDelay is the base delay of the exponential backoff for the throttling errors (e.g. SlowDown).
+	ThrottleBaseDelay string `yaml:"throttleBaseDelay,omitempty"`
+	// MaxBackoff is the upper limit of the delay between attempts.
+	MaxBackoff string `yaml:"maxBackoff,omitempty"`
+	// Budget is the number of tokens for retries per client.
+	Budget int `yaml:"budget,omitempty"`
+	// Operations is the per-operation overrides keyed by the API operation name. e.g. "DeleteObjects"
+	// It is only editable in the configuration file.
+	Operations map[string]Retry `yaml:"operations,omitempty"`
+}
+
+// Region is the region catalog entry in the configuration file.
+type Region struct {
+	// Name is the region name. e.g. "ap-southeast-6"
+	Name string `yaml:"name"`
+	// Partition is the partition (aws, aws-cn, aws-us-gov). The default is aws.
+	Partition string `yaml:"partition,omitempty"`
+	// DisplayName is the human-readable name.
+	DisplayName string `yaml:"displayName,omitempty"`
+	// S3EndpointSuffix is the DNS suffix of the S3 endpoint. The default is the DNS suffix of the partition.
+	S3EndpointSuffix string `yaml:"s3EndpointSuffix,omitempty"`
+	// OptIn is whether the region must be enabled for the account before use.
+	OptIn bool `yaml:"optIn,omitempty"`
+}
+
+// Endpoint is the endpoint override.
+type Endpoint struct {
+	// URL is the URL of the endpoint.
+	URL string `yaml:"url,omitempty"`
+	// PathStyle is whether to use the path-style addressing.
+	PathStyle bool `yaml:"pathStyle,omitempty"`
+	// NoVerifySSL is whether to skip the verification of the TLS certificate.
+	NoVerifySSL bool `yaml:"noVerifySSL,omitempty"`
+}
+
+// Tool is the per-tool settings.
+type Tool struct {
+	// Concurrency is the number of parallel API calls. 0 means the default of the tool.
+	Concurrency int `yaml:"concurrency,omitempty"`
+}
+
+// NewConfig returns a new empty Config.
+func NewConfig() *Config {
+	return &Config{Aliases: map[string]string{}}
+}
+
+// FilePath returns the path of the configuration file.
+// It is $XDG_CONFIG_HOME/rainbow/config.yml, or $HOME/.config/rainbow/config.yml if $XDG_CONFIG_HOME is empty.
+func FilePath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errfmt.Wrap(err, "can not get home directory")
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "rainbow", "config.yml"), nil
+}
+
+// Load reads the configuration file and applies the environment variables.
+// If the configuration file does not exist, it returns the Config that has only the environment variables.
+func Load() (*Config, error) {
+	path, err := FilePath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OverrideWithEnv(os.Getenv)
+	return cfg, nil
+}
+
+// ReadFile reads the configuration file. If the file does not exist, it returns an empty Config.
+func ReadFile(path string) (*Config, error) {
+	cfg := NewConfig()
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	if err := cfg.Read(f); err != nil {
+		return nil, errfmt.Wrap(err, "can not read "+path)
+	}
+	return cfg, nil
+}
+
+// WriteFile writes the Config to the configuration file. The parent directory is created if it does not exist.
+func (c *Config) WriteFile(path string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+	return c.Write(f)
+}
+
+// Write writes the Config to the io.Writer.
+func (c *Config) Write(w io.Writer) (err error) {
+	encoder := yaml.NewEncoder(w)
+	defer func() {
+		if closeErr := encoder.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+	return encoder.Encode(c)
+}
+
+// Read reads the Config from the io.Reader.
+func (c *Config) Read(r io.Reader) error {
+	if err := yaml.NewDecoder(r).Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if c.Aliases == nil {
+		c.Aliases = map[string]string{}
+	}
+	return c.Validate()
+}
+
+// Validate validates the Config.
+func (c *Config) Validate() error {
+	for _, key := range Keys() {
+		if err := c.Set(key, c.mustGet(key)); err != nil {
+			return err
+		}
+	}
+	for name, op := range c.Retry.Operations {
+		if err := op.validateDurations("retry.operations." + name); err != nil {
+			return err
+		}
+		if len(op.Operations) > 0 {
+			return fmt.Errorf("%w: retry.operations.%s can not have operations", config.ErrInvalidConfigValue, name)
+		}
+	}
+	for i, r := range c.Regions {
+		if r.Name == "" {
+			return fmt.Errorf("%w: regions[%d].name is empty", config.ErrInvalidConfigValue, i)
+		}
+	}
+	return nil
+}
+
+// OverrideWithEnv overrides the Config with the environment variables.
+// AWS_PROFILE, AWS_REGION (AWS_DEFAULT_REGION) and RAINBOW_<KEY> are used. e.g. RAINBOW_S3HUB_CONCURRENCY
+// Invalid values in the environment variables are ignored.
+func (c *Config) OverrideWithEnv(getenv func(string) string) {
+	if v := getenv("AWS_PROFILE"); v != "" {
+		c.Profile = v
+	}
+	for _, name := range []string{"AWS_DEFAULT_REGION", "AWS_REGION"} {
+		if v := getenv(name); v != "" {
+			c.Region = v
+		}
+	}
+	for _, key := range Keys() {
+		if v := getenv(EnvName(key)); v != "" {
+			_ = c.Set(key, v) //nolint:errcheck
+		}
+	}
+}
+
+// EnvName returns the environment variable name for the key. e.g. "s3hub.concurrency" -> "RAINBOW_S3HUB_CONCURRENCY"
+func EnvName(key string) string {
+	return "RAINBOW_" + strings.ToUpper(strings.NewReplacer(".", "_").Replace(key))
+}
+
+// configKey is the key that can be get/set with the config command.
+type configKey struct {
+	// get returns the value as string.
+	get func(c *Config) string
+	// set parses the value and sets it.
+	set func(c *Config, v string) error
+}
+
+// configKeys is the list of the keys except aliases.
+var configKeys = map[string]configKey{ //nolint:gochecknoglobals
+	"profile": {
+		get: func(c *Config) string { return c.Profile },
+		set: func(c *Config, v string) error { c.Profile = v; return nil },
+	},
+	"region": {
+		get: func(c *Config) string { return c.Region },
+		set: func(c *Config, v string) error { c.Region = v; return nil },
+	},
+	"output": {
+		get: func(c *Config) string { return c.Output },
+		set: func(c *Config, v string) error {
+			return setEnum(&c.Output, "output", v, "table", "json", "csv")
+		},
+	},
+	"color": {
+		get: func(c *Config) string { return c.Color },
+		set: func(c *Config, v string) error {
+			return setEnum(&c.Color, "color", v, ColorAuto, ColorAlways, ColorNever)
+		},
+	},
+	"confirm": {
+		get: func(c *Config) string { return c.Confirm },
+		set: func(c *Config, v string) error {
+			return setEnum(&c.Confirm, "confirm", v, ConfirmAlways, ConfirmNever)
+		},
+	},
+	"endpoint.url": {
+		get: func(c *Config) string { return c.Endpoint.URL },
+		set: func(c *Config, v string) error { c.Endpoint.URL = v; return nil },
+	},
+	"endpoint.pathStyle": {
+		get: func(c *Config) string { return formatBool(c.Endpoint.PathStyle) },
+		set: func(c *Config, v string) error { return setBool(&c.Endpoint.PathStyle, "endpoint.pathStyle", v) },
+	},
+	"endpoint.noVerifySSL": {
+		get: func(c *Config) string { return formatBool(c.Endpoint.NoVerifySSL) },
+		set: func(c *Config, v string) error { return setBool(&c.Endpoint.NoVerifySSL, "endpoint.noVerifySSL", v) },
+	},
+	"s3hub.concurrency": {
+		get: func(c *Config) string { return formatInt(c.S3hub.Concurrency) },
+		set: func(c *Config, v string) error { return setConcurrency(&c.S3hub.Concurrency, "s3hub.concurrency", v) },
+	},
+	"retry.maxAttempts": {
+		get: func(c *Config) string { return formatInt(c.Retry.MaxAttempts) },
+		set: func(c *Config, v string) error { return setConcurrency(&c.Retry.MaxAttempts, "retry.maxAttempts", v) },
+	},
+	"retry.baseDelay": {
+		get: func(c *Config) string { return c.Retry.BaseDelay },
+		set: func(c *Config, v string) error { return setDuration(&c.Retry.BaseDelay, "retry.baseDelay", v) },
+	},
+	"retry.throttleBaseDelay": {
+		get: func(c *Config) string { return c.Retry.ThrottleBaseDelay },
+		set: func(c *Config, v string) error { return setDuration(&c.Retry.ThrottleBaseDelay, "retry.throttleBaseDelay", v) },
+	},
+	"retry.maxBackoff": {
+		get: func(c *Config) string { return c.Retry.MaxBackoff },
+		set: func(c *Config, v string) error { return setDuration(&c.Retry.MaxBackoff, "retry.maxBackoff", v) },
+	},
+	"retry.budget": {
+		get: func(c *Config) string { return formatInt(c.Retry.Budget) },
+		set: func(c *Config, v string) error { return setConcurrency(&c.Retry.Budget, "retry.budget", v) },
+	},
+	"cfn.concurrency": {
+		get: func(c *Config) string { return formatInt(c.CFn.Concurrency) },
+		set: func(c *Config, v string) error { return setConcurrency(&c.CFn.Concurrency, "cfn.concurrency", v) },
+	},
+}
+
+// aliasKeyPrefix is the prefix of the alias key. e.g. "aliases.logs"
+const aliasKeyPrefix = "aliases."
+
+// Keys returns the sorted list of the keys except aliases.
+func Keys() []string {
+	keys := make([]string, 0, len(configKeys))
+	for k := range configKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of the key. The alias is specified as "aliases.NAME".
+func (c *Config) Get(key string) (string, error) {
+	if name, ok := strings.CutPrefix(key, aliasKeyPrefix); ok && name != "" {
+		return c.Aliases[name], nil
+	}
+	k, ok := configKeys[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", config.ErrUnknownConfigKey, key)
+	}
+	return k.get(c), nil
+}
+
+// mustGet returns the value of the known key.
+func (c *Config) mustGet(key string) string {
+	return configKeys[key].get(c)
+}
+
+// Set sets the value of the key. The empty value resets the key to the default (or removes the alias).
+func (c *Config) Set(key, value string) error {
+	if name, ok := strings.CutPrefix(key, aliasKeyPrefix); ok && name != "" {
+		if value == "" {
+			delete(c.Aliases, name)
+			return nil
+		}
+		if c.Aliases == nil {
+			c.Aliases = map[string]string{}
+		}
+		c.Aliases[name] = value
+		return nil
+	}
+	k, ok := configKeys[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", config.ErrUnknownConfigKey, key)
+	}
+	return k.set(c, value)
+}
+
+// List returns all key-value pairs that are set. Aliases are listed at the end.
+func (c *Config) List() [][2]string {
+	list := [][2]string{}
+	for _, key := range Keys() {
+		if v := c.mustGet(key); v != "" {
+			list = append(list, [2]string{key, v})
+		}
+	}
+	names := make([]string, 0, len(c.Aliases))
+	for name := range c.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list = append(list, [2]string{aliasKeyPrefix + name, c.Aliases[name]})
+	}
+	return list
+}
+
+// ExpandAlias expands the bucket alias in the argument. e.g. "@logs/2024" -> "s3://my-log-bucket/app/2024"
+// If the argument does not start with AliasPrefix or the alias is not defined, it returns the argument as it is.
+func (c *Config) ExpandAlias(arg string) string {
+	rest, ok := strings.CutPrefix(arg, AliasPrefix)
+	if !ok {
+		return arg
+	}
+	name, path, _ := strings.Cut(rest, "/")
+	target, ok := c.Aliases[name]
+	if !ok {
+		return arg
+	}
+	if path == "" {
+		return target
+	}
+	return strings.TrimSuffix(target, "/") + "/" + path
+}
+
+// setEnum sets the value if it is one of the candidates. The empty value is always allowed.
+func setEnum(dst *string, key, v string, candidates ...string) error {
+	v = strings.ToLower(v)
+	if v == "" {
+		*dst = ""
+		return nil
+	}
+	for _, c := range candidates {
+		if v == c {
+			*dst = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s=%s (must be one of %s)", config.ErrInvalidConfigValue, key, v, strings.Join(candidates, ", "))
+}
+
+// setBool parses the boolean value. The empty value means false.
+func setBool(dst *bool, key, v string) error {
+	if v == "" {
+		*dst = false
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%w: %s=%s (must be true or false)", config.ErrInvalidConfigValue, key, v)
+	}
+	*dst = b
+	return nil
+}
+
+// setConcurrency parses the concurrency. The empty value means the default (0).
+func setConcurrency(dst *int, key, v string) error {
+	if v == "" || v == "0" {
+		*dst = 0
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return fmt.Errorf("%w: %s=%s (must be a positive integer)", config.ErrInvalidConfigValue, key, v)
+	}
+	*dst = n
+	return nil
+}
+
+// setDuration validates the duration string such as "500ms". The empty value means the default.
+func setDuration(dst *string, key, v string) error {
+	if v == "" {
+		*dst = ""
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf("%w: %s=%s (must be a duration such as 500ms or 30s)", config.ErrInvalidConfigValue, key, v)
+	}
+	*dst = v
+	return nil
+}
+
+// validateDurations validates the durations in the Retry. prefix is the key prefix for the error message.
+func (r Retry) validateDurations(prefix string) error {
+	for key, v := range map[string]string{
+		"baseDelay":         r.BaseDelay,
+		"throttleBaseDelay": r.ThrottleBaseDelay,
+		"maxBackoff":        r.MaxBackoff,
+	} {
+		if err := setDuration(new(string), prefix+"."+key, v); err != nil {
+			return err
+		}
+	}
+	if r.MaxAttempts < 0 || r.Budget < 0 {
+		return fmt.Errorf("%w: %s.maxAttempts and %s.budget must be 0 or more", config.ErrInvalidConfigValue, prefix, prefix)
+	}
+	return nil
+}
+
+// formatBool returns "true" or "" (not set).
+func formatBool(b bool) string {
+	if !b {
+		return ""
+	}
+	return "true"
+}
+
+// formatInt returns the integer as string. 0 means not set, so it returns "".
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
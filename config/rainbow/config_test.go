@@ -0,0 +1,215 @@
+package rainbow
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/rainbow/config"
+)
+
+func TestConfig_SetAndGet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{name: "profile", key: "profile", value: "dev", want: "dev"},
+		{name: "output is case insensitive", key: "output", value: "JSON", want: "json"},
+		{name: "invalid output", key: "output", value: "xml", wantErr: config.ErrInvalidConfigValue},
+		{name: "color", key: "color", value: "never", want: "never"},
+		{name: "confirm", key: "confirm", value: "never", want: "never"},
+		{name: "path style", key: "endpoint.pathStyle", value: "true", want: "true"},
+		{name: "invalid bool", key: "endpoint.noVerifySSL", value: "yes!", wantErr: config.ErrInvalidConfigValue},
+		{name: "concurrency", key: "s3hub.concurrency", value: "8", want: "8"},
+		{name: "invalid concurrency", key: "cfn.concurrency", value: "-1", wantErr: config.ErrInvalidConfigValue},
+		{name: "retry max backoff", key: "retry.maxBackoff", value: "30s", want: "30s"},
+		{name: "invalid retry delay", key: "retry.baseDelay", value: "soon", wantErr: config.ErrInvalidConfigValue},
+		{name: "alias", key: "aliases.logs", value: "s3://log-bucket/app", want: "s3://log-bucket/app"},
+		{name: "unknown key", key: "unknown", value: "value", wantErr: config.ErrUnknownConfigKey},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewConfig()
+			err := c.Set(tt.key, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			got, err := c.Get(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Get() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_List(t *testing.T) {
+	t.Parallel()
+
+	c := NewConfig()
+	for _, kv := range [][2]string{
+		{"region", "ap-northeast-1"},
+		{"aliases.web", "s3://web-bucket"},
+		{"aliases.logs", "s3://log-bucket/app"},
+		{"s3hub.concurrency", "4"},
+	} {
+		if err := c.Set(kv[0], kv[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := [][2]string{
+		{"region", "ap-northeast-1"},
+		{"s3hub.concurrency", "4"},
+		{"aliases.logs", "s3://log-bucket/app"},
+		{"aliases.web", "s3://web-bucket"},
+	}
+	if diff := cmp.Diff(want, c.List()); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The empty value removes the alias.
+	if err := c.Set("aliases.web", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Aliases) != 1 {
+		t.Errorf("Aliases = %v, want only logs", c.Aliases)
+	}
+}
+
+func TestConfig_ExpandAlias(t *testing.T) {
+	t.Parallel()
+
+	c := NewConfig()
+	c.Aliases["logs"] = "s3://log-bucket/app/"
+
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{arg: "@logs", want: "s3://log-bucket/app/"},
+		{arg: "@logs/2024/01", want: "s3://log-bucket/app/2024/01"},
+		{arg: "@unknown/2024", want: "@unknown/2024"},
+		{arg: "s3://bucket", want: "s3://bucket"},
+	}
+	for _, tt := range tests {
+		if got := c.ExpandAlias(tt.arg); got != tt.want {
+			t.Errorf("ExpandAlias(%s) = %s, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_OverrideWithEnv(t *testing.T) {
+	t.Parallel()
+
+	c := NewConfig()
+	c.Profile = "from-config"
+	c.Region = "us-west-2"
+	c.Output = "json"
+	c.S3hub.Concurrency = 2
+
+	env := map[string]string{
+		"AWS_PROFILE":               "from-env",
+		"AWS_DEFAULT_REGION":        "eu-west-1",
+		"AWS_REGION":                "ap-northeast-1",
+		"RAINBOW_S3HUB_CONCURRENCY": "8",
+		"RAINBOW_OUTPUT":            "xml", // invalid value is ignored
+	}
+	c.OverrideWithEnv(func(key string) string { return env[key] })
+
+	if c.Profile != "from-env" || c.Region != "ap-northeast-1" || c.S3hub.Concurrency != 8 || c.Output != "json" {
+		t.Errorf("OverrideWithEnv() = %+v", c)
+	}
+}
+
+func TestConfig_ReadAndWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("write and read file", func(t *testing.T) {
+		t.Parallel()
+
+		want := NewConfig()
+		want.Region = "ap-northeast-1"
+		want.Endpoint = Endpoint{URL: "http://localhost:9000", PathStyle: true}
+		want.S3hub.Concurrency = 4
+		want.Aliases["logs"] = "s3://log-bucket"
+		want.Regions = []Region{{Name: "ap-southeast-6", DisplayName: "Asia Pacific (New Zealand)", OptIn: true}}
+
+		path := filepath.Join(t.TempDir(), "rainbow", "config.yml")
+		if err := want.WriteFile(path); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ReadFile() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("file does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := ReadFile(filepath.Join(t.TempDir(), "not-exist.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(NewConfig(), got); diff != "" {
+			t.Errorf("ReadFile() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid value in the file", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewConfig()
+		if err := c.Read(strings.NewReader("confirm: sometimes\n")); !errors.Is(err, config.ErrInvalidConfigValue) {
+			t.Errorf("Read() error = %v, want %v", err, config.ErrInvalidConfigValue)
+		}
+	})
+
+	t.Run("region without name in the file", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewConfig()
+		if err := c.Read(strings.NewReader("regions:\n  - partition: aws\n")); !errors.Is(err, config.ErrInvalidConfigValue) {
+			t.Errorf("Read() error = %v, want %v", err, config.ErrInvalidConfigValue)
+		}
+	})
+
+	t.Run("invalid retry operation in the file", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewConfig()
+		err := c.Read(strings.NewReader("retry:\n  operations:\n    DeleteObjects:\n      maxBackoff: later\n"))
+		if !errors.Is(err, config.ErrInvalidConfigValue) {
+			t.Errorf("Read() error = %v, want %v", err, config.ErrInvalidConfigValue)
+		}
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewConfig()
+		if err := c.Read(&bytes.Buffer{}); err != nil {
+			t.Error(err)
+		}
+	})
+}
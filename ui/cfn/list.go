@@ -46,12 +46,12 @@ const (
 func newCFnListStackModel(region model.Region) (*cfnListStackModel, error) {
 	ctx := context.Background()
 	profile := model.NewAWSProfile("")
-	cfg, err := model.NewAWSConfig(ctx, profile, region)
+	cfg, err := model.NewAWSConfig(ctx, profile, region, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	app, err := di.NewCFnApp(ctx, profile, region)
+	app, err := di.NewCFnApp(ctx, profile, region, nil)
 	if err != nil {
 		return nil, err
 	}
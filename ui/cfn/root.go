@@ -30,7 +30,7 @@ type cfnRootModel struct {
 func RunCfnUI() error {
 	ctx := context.Background()
 	profile := model.NewAWSProfile("")
-	cfg, err := model.NewAWSConfig(ctx, profile, "")
+	cfg, err := model.NewAWSConfig(ctx, profile, "", nil)
 	if err != nil {
 		return err
 	}
@@ -99,7 +99,7 @@ func (m cfnRootModel) View() string {
 		"Set the region for the CloudFormation stack you want to display.",
 		m.awsProfile.String(),
 		ui.Yellow("Region"),
-		ui.Green(m.region.String()),
+		ui.Green(m.region.String())+" "+ui.RegionDetail(m.region),
 		ui.Subtle("h/l, left/right: select region | <esc>, <Ctrl-C>, q: quit"),
 		ui.Subtle("<enter>: list up the CloudFormation stacks"))
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/muesli/termenv"
+	"github.com/nao1215/rainbow/app/domain/model"
 )
 
 // General stuff for styling the view
@@ -75,6 +76,20 @@ func GoodByeMessage() string {
 	return s
 }
 
+// RegionDetail returns the display name of the region for the region picker. e.g. "Asia Pacific (Tokyo)"
+// The opt-in region is marked because it can not be used until it is enabled for the account.
+// If the region is not in the region catalog, it returns "".
+func RegionDetail(r model.Region) string {
+	info, ok := r.Info()
+	if !ok {
+		return ""
+	}
+	if info.OptIn {
+		return Subtle(info.DisplayName + " [opt-in]")
+	}
+	return Subtle(info.DisplayName)
+}
+
 // ErrorMessage returns an error message.
 func ErrorMessage(err error) string {
 	message := fmt.Sprintf("%s\n", Red("[Error]"))
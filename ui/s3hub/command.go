@@ -17,8 +17,6 @@ import (
 	"github.com/nao1215/rainbow/app/usecase"
 	"github.com/nao1215/rainbow/config/s3hub"
 	"github.com/nao1215/rainbow/ui"
-	"golang.org/x/sync/errgroup"
-	"golang.org/x/sync/semaphore"
 )
 
 // createMsg is the message that is sent when the user wants to create the S3 bucket.
@@ -203,30 +201,15 @@ func deleteS3BucketCmd(ctx context.Context, app *di.S3App, bucket model.Bucket)
 		}
 
 		if len(output.Objects) != 0 {
-			eg, ctx := errgroup.WithContext(ctx)
-			sem := semaphore.NewWeighted(model.MaxS3DeleteObjectsParallelsCount)
 			chunks := divideIntoChunks(output.Objects, model.S3DeleteObjectChunksSize)
-
-			for _, chunk := range chunks {
-				chunk := chunk // Create a new variable to avoid concurrency issues
-				// Acquire semaphore to control the number of concurrent goroutines
-				if err := sem.Acquire(ctx, 1); err != nil {
-					return err
-				}
-
-				eg.Go(func() error {
-					defer sem.Release(1)
-					if _, err := app.S3ObjectsDeleter.DeleteS3Objects(ctx, &usecase.S3ObjectsDeleterInput{
-						Bucket:              bucket,
-						S3ObjectIdentifiers: chunk,
-					}); err != nil {
-						return err
-					}
-					return nil
+			limiter := model.NewS3Limiter(model.MaxS3DeleteObjectsParallelsCount, 0)
+			if err := limiter.ForEach(ctx, len(chunks), func(ctx context.Context, i int) error {
+				_, err := app.S3ObjectsDeleter.DeleteS3Objects(ctx, &usecase.S3ObjectsDeleterInput{
+					Bucket:              bucket,
+					S3ObjectIdentifiers: chunks[i],
 				})
-			}
-
-			if err := eg.Wait(); err != nil {
+				return err
+			}); err != nil {
 				return err
 			}
 		}
@@ -51,7 +51,7 @@ func newS3hubCreateBucketModel() (*s3hubCreateBucketModel, error) {
 
 	ctx := context.Background()
 	profile := model.NewAWSProfile("")
-	cfg, err := model.NewAWSConfig(ctx, profile, "")
+	cfg, err := model.NewAWSConfig(ctx, profile, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +105,7 @@ func (m *s3hubCreateBucketModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.bucketNameInput.Value() == "" || len(m.bucketNameInput.Value()) < model.MinBucketNameLength {
 				return m, nil
 			}
-			app, err := di.NewS3App(m.ctx, m.awsProfile, m.region)
+			app, err := di.NewS3App(m.ctx, m.awsProfile, m.region, nil)
 			if err != nil {
 				m.err = err
 				return m, tea.Quit
@@ -174,7 +174,7 @@ func (m *s3hubCreateBucketModel) View() string {
 				"[ AWS Profile ] %s\n[ ◀︎  %s ▶︎ ] %s\n[   S3 Name   ]%s\n\n%s\n\n%s\n%s\n",
 				m.awsProfile.String(),
 				ui.Yellow("Region"),
-				ui.Green(m.region.String()),
+				ui.Green(m.region.String())+" "+ui.RegionDetail(m.region),
 				m.bucketNameWithColor(),
 				m.bucketNameLengthString(),
 				ui.Subtle("<esc>: return to the top | <Ctrl-C>: quit | up/down: select"),
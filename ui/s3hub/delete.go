@@ -58,13 +58,13 @@ type s3hubDeleteBucketModel struct {
 func newS3hubDeleteBucketModel() (*s3hubDeleteBucketModel, error) {
 	ctx := context.Background()
 	profile := model.NewAWSProfile("")
-	cfg, err := model.NewAWSConfig(ctx, profile, "")
+	cfg, err := model.NewAWSConfig(ctx, profile, "", nil)
 	if err != nil {
 		return nil, err
 	}
 	region := cfg.Region()
 
-	app, err := di.NewS3App(ctx, profile, region)
+	app, err := di.NewS3App(ctx, profile, region, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -57,13 +57,13 @@ const (
 func newS3HubListBucketModel() (*s3hubListBucketModel, error) {
 	ctx := context.Background()
 	profile := model.NewAWSProfile("")
-	cfg, err := model.NewAWSConfig(ctx, profile, "")
+	cfg, err := model.NewAWSConfig(ctx, profile, "", nil)
 	if err != nil {
 		return nil, err
 	}
 	region := cfg.Region()
 
-	app, err := di.NewS3App(ctx, profile, region)
+	app, err := di.NewS3App(ctx, profile, region, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -378,13 +378,13 @@ type s3hubListS3ObjectModel struct {
 func newS3HubListS3ObjectModel() (*s3hubListS3ObjectModel, error) {
 	ctx := context.Background()
 	profile := model.NewAWSProfile("")
-	cfg, err := model.NewAWSConfig(ctx, profile, "")
+	cfg, err := model.NewAWSConfig(ctx, profile, "", nil)
 	if err != nil {
 		return nil, err
 	}
 	region := cfg.Region()
 
-	app, err := di.NewS3App(ctx, profile, region)
+	app, err := di.NewS3App(ctx, profile, region, nil)
 	if err != nil {
 		return nil, err
 	}
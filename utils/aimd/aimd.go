@@ -0,0 +1,231 @@
+// Package aimd provides the adaptive concurrency limiter with AIMD (additive increase, multiplicative decrease).
+// The limiter grows the number of concurrent API calls while they are healthy, and halves it when the calls are
+// throttled (e.g. S3 503 SlowDown) or become much slower than usual. It keeps the request rate under the limit
+// of the service without tuning the concurrency by hand.
+package aimd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultDecreaseFactor is the factor by which the limit is multiplied when the calls are throttled.
+	DefaultDecreaseFactor = 0.5
+	// DefaultLatencyTolerance is how many times slower than the smoothed latency a call can be before it is regarded as unhealthy.
+	DefaultLatencyTolerance = 4.0
+	// latencySmoothing is the weight of the latest latency in the exponentially weighted moving average.
+	latencySmoothing = 0.2
+	// minLatencySamples is the number of samples required before the latency is used as the signal.
+	minLatencySamples = 5
+)
+
+// Options is the options for the Limiter.
+type Options struct {
+	// Initial is the initial limit. If it is 0 or out of [Min, Max], it is clamped.
+	Initial int
+	// Min is the minimum limit. If it is less than 1, 1 is used.
+	Min int
+	// Max is the maximum limit. If it is less than Min, Min is used.
+	Max int
+	// DecreaseFactor is the factor by which the limit is multiplied on throttling. 0 means DefaultDecreaseFactor.
+	DecreaseFactor float64
+	// LatencyTolerance is how many times slower than the smoothed latency a call can be before the limit decreases.
+	// 0 means DefaultLatencyTolerance. A negative value disables the latency signal.
+	LatencyTolerance float64
+	// IsThrottle returns true if the error means the service is throttling the calls. nil means no error is throttling.
+	IsThrottle func(error) bool
+}
+
+// Limiter is the adaptive concurrency limiter. It is safe for concurrent use and is shared by all workers of a command.
+type Limiter struct {
+	// mu protects the fields below.
+	mu sync.Mutex
+	// opts is the options.
+	opts Options
+	// limit is the current limit. It is fractional so that it increases by 1 per window of successful calls.
+	limit float64
+	// inflight is the number of calls in flight.
+	inflight int
+	// changed is closed when inflight or limit changes, to wake up the waiting callers.
+	changed chan struct{}
+	// lastDecrease is the time the limit decreased last. The calls started before it do not decrease the limit again.
+	lastDecrease time.Time
+	// latency is the smoothed latency of the healthy calls.
+	latency time.Duration
+	// samples is the number of latency samples.
+	samples int
+	// now returns the current time. It is for unit test.
+	now func() time.Time
+}
+
+// NewLimiter returns a new Limiter.
+func NewLimiter(opts Options) *Limiter {
+	if opts.Min < 1 {
+		opts.Min = 1
+	}
+	if opts.Max < opts.Min {
+		opts.Max = opts.Min
+	}
+	if opts.Initial < opts.Min {
+		opts.Initial = opts.Min
+	}
+	if opts.Initial > opts.Max {
+		opts.Initial = opts.Max
+	}
+	if opts.DecreaseFactor <= 0 || opts.DecreaseFactor >= 1 {
+		opts.DecreaseFactor = DefaultDecreaseFactor
+	}
+	if opts.LatencyTolerance == 0 {
+		opts.LatencyTolerance = DefaultLatencyTolerance
+	}
+	return &Limiter{
+		opts:    opts,
+		limit:   float64(opts.Initial),
+		changed: make(chan struct{}),
+		now:     time.Now,
+	}
+}
+
+// Limit returns the current limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// Acquire blocks until the number of calls in flight is under the limit.
+// The returned context must be used for the call: the retryer reports the throttled retries through it.
+// release must be called with the result of the call.
+// If ctx is done, Acquire returns its error without taking a slot even if a slot is free.
+func (l *Limiter) Acquire(ctx context.Context) (context.Context, func(err error), error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		l.mu.Lock()
+		if l.inflight < int(l.limit) {
+			l.inflight++
+			l.mu.Unlock()
+			break
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-changed:
+		}
+	}
+
+	started := l.now()
+	var once sync.Once
+	release := func(err error) {
+		once.Do(func() { l.release(started, err) })
+	}
+	return WithThrottleObserver(ctx, func() { l.throttled(started) }), release, nil
+}
+
+// ForEach calls fn with 0 to n-1 in parallel while keeping the number of calls in flight under the limit.
+// fn must use the given context for the API call. ForEach returns the first error, and stops starting new calls.
+func (l *Limiter) ForEach(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i := 0; i < n; i++ {
+		i := i
+		callCtx, release, err := l.Acquire(egCtx)
+		if err != nil {
+			if waitErr := eg.Wait(); waitErr != nil {
+				return waitErr
+			}
+			return err
+		}
+		eg.Go(func() error {
+			err := fn(callCtx, i)
+			release(err)
+			return err
+		})
+	}
+	return eg.Wait()
+}
+
+// release updates the limit with the result of the call started at started.
+func (l *Limiter) release(started time.Time, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.notify()
+
+	l.inflight--
+	latency := l.now().Sub(started)
+	switch {
+	case err != nil && l.opts.IsThrottle != nil && l.opts.IsThrottle(err):
+		l.decrease(started)
+	case err != nil:
+		// The other errors say nothing about the request rate.
+	case l.slow(latency):
+		l.decrease(started)
+	default:
+		l.observe(latency)
+		l.limit = min(l.limit+1/l.limit, float64(l.opts.Max))
+	}
+}
+
+// throttled decreases the limit when the call started at started is throttled and retried.
+func (l *Limiter) throttled(started time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer l.notify()
+	l.decrease(started)
+}
+
+// decrease multiplies the limit by DecreaseFactor. The calls started before the last decrease are ignored,
+// because they were sent with the previous limit and one burst should halve the limit only once.
+func (l *Limiter) decrease(started time.Time) {
+	if started.Before(l.lastDecrease) {
+		return
+	}
+	l.limit = max(l.limit*l.opts.DecreaseFactor, float64(l.opts.Min))
+	l.lastDecrease = l.now()
+}
+
+// slow returns true if the latency is much longer than the smoothed latency.
+func (l *Limiter) slow(latency time.Duration) bool {
+	if l.opts.LatencyTolerance < 0 || l.samples < minLatencySamples {
+		return false
+	}
+	return float64(latency) > float64(l.latency)*l.opts.LatencyTolerance
+}
+
+// observe adds the latency of the healthy call to the smoothed latency.
+func (l *Limiter) observe(latency time.Duration) {
+	if l.samples == 0 {
+		l.latency = latency
+	} else {
+		l.latency = time.Duration(float64(l.latency)*(1-latencySmoothing) + float64(latency)*latencySmoothing)
+	}
+	l.samples++
+}
+
+// notify wakes up the callers waiting in Acquire. l.mu must be held.
+func (l *Limiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// throttleObserverKey is the context key of the throttle observer.
+type throttleObserverKey struct{}
+
+// WithThrottleObserver returns the context that carries the function called when the call is throttled and retried.
+func WithThrottleObserver(ctx context.Context, observer func()) context.Context {
+	return context.WithValue(ctx, throttleObserverKey{}, observer)
+}
+
+// NotifyThrottle calls the throttle observer in the context. It does nothing if the context has no observer.
+func NotifyThrottle(ctx context.Context) {
+	if observer, ok := ctx.Value(throttleObserverKey{}).(func()); ok {
+		observer()
+	}
+}
@@ -0,0 +1,363 @@
+package aimd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errThrottle = errors.New("api error SlowDown: Please reduce your request rate")
+
+func isThrottle(err error) bool {
+	return errors.Is(err, errThrottle)
+}
+
+// fakeClock is the clock that advances only by hand.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(opts Options) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewLimiter(opts)
+	l.now = clock.Now
+	return l, clock
+}
+
+func TestNewLimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts Options
+		want int
+	}{
+		{
+			name: "initial limit",
+			opts: Options{Initial: 5, Max: 10},
+			want: 5,
+		},
+		{
+			name: "initial limit is clamped to max",
+			opts: Options{Initial: 20, Max: 10},
+			want: 10,
+		},
+		{
+			name: "zero initial limit is clamped to min",
+			opts: Options{Min: 2, Max: 10},
+			want: 2,
+		},
+		{
+			name: "max less than min is regarded as min",
+			opts: Options{Initial: 5, Min: 3, Max: 1},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewLimiter(tt.opts).Limit(); got != tt.want {
+				t.Errorf("Limit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiter_AdditiveIncrease(t *testing.T) {
+	t.Parallel()
+
+	l, clock := newTestLimiter(Options{Initial: 2, Max: 3, IsThrottle: isThrottle})
+	// The limit increases by 1/limit per successful call, i.e. about 1 per window of successful calls.
+	for i := 0; i < 3; i++ {
+		_, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(10 * time.Millisecond)
+		release(nil)
+	}
+	if got := l.Limit(); got != 3 {
+		t.Errorf("Limit() = %d, want 3", got)
+	}
+
+	// The limit does not exceed Max.
+	for i := 0; i < 10; i++ {
+		_, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(10 * time.Millisecond)
+		release(nil)
+	}
+	if got := l.Limit(); got != 3 {
+		t.Errorf("Limit() = %d, want 3", got)
+	}
+}
+
+func TestLimiter_MultiplicativeDecrease(t *testing.T) {
+	t.Parallel()
+
+	t.Run("throttling error halves the limit once per burst", func(t *testing.T) {
+		t.Parallel()
+
+		l, clock := newTestLimiter(Options{Initial: 8, Max: 8, IsThrottle: isThrottle})
+		releases := make([]func(error), 0, 4)
+		for i := 0; i < 4; i++ {
+			_, release, err := l.Acquire(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			releases = append(releases, release)
+		}
+		clock.Advance(10 * time.Millisecond)
+		for _, release := range releases {
+			release(errThrottle)
+		}
+		if got := l.Limit(); got != 4 {
+			t.Errorf("Limit() = %d, want 4", got)
+		}
+
+		// The call started after the decrease halves the limit again.
+		_, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(10 * time.Millisecond)
+		release(errThrottle)
+		if got := l.Limit(); got != 2 {
+			t.Errorf("Limit() = %d, want 2", got)
+		}
+	})
+
+	t.Run("other errors do not change the limit", func(t *testing.T) {
+		t.Parallel()
+
+		l, clock := newTestLimiter(Options{Initial: 4, Max: 8, IsThrottle: isThrottle})
+		_, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(10 * time.Millisecond)
+		release(errors.New("access denied"))
+		if got := l.Limit(); got != 4 {
+			t.Errorf("Limit() = %d, want 4", got)
+		}
+	})
+
+	t.Run("limit does not fall below min", func(t *testing.T) {
+		t.Parallel()
+
+		l, clock := newTestLimiter(Options{Initial: 2, Min: 2, Max: 8, IsThrottle: isThrottle})
+		_, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(10 * time.Millisecond)
+		release(errThrottle)
+		if got := l.Limit(); got != 2 {
+			t.Errorf("Limit() = %d, want 2", got)
+		}
+	})
+
+	t.Run("throttled retry reported through the context halves the limit", func(t *testing.T) {
+		t.Parallel()
+
+		l, clock := newTestLimiter(Options{Initial: 8, Max: 8, IsThrottle: isThrottle})
+		ctx, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(10 * time.Millisecond)
+		NotifyThrottle(ctx)
+		if got := l.Limit(); got != 4 {
+			t.Errorf("Limit() = %d, want 4", got)
+		}
+		// The call succeeded after the retry. It was sent before the decrease, so it does not decrease the limit again.
+		release(nil)
+		if got := l.Limit(); got != 4 {
+			t.Errorf("Limit() = %d, want 4", got)
+		}
+	})
+
+	t.Run("slow call halves the limit", func(t *testing.T) {
+		t.Parallel()
+
+		l, clock := newTestLimiter(Options{Initial: 8, Max: 8, IsThrottle: isThrottle})
+		for i := 0; i < minLatencySamples; i++ {
+			_, release, err := l.Acquire(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			clock.Advance(10 * time.Millisecond)
+			release(nil)
+		}
+		_, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(time.Second)
+		release(nil)
+		if got := l.Limit(); got != 4 {
+			t.Errorf("Limit() = %d, want 4", got)
+		}
+	})
+}
+
+func TestLimiter_Acquire(t *testing.T) {
+	t.Parallel()
+
+	t.Run("blocks until a call is released", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewLimiter(Options{Initial: 1, Max: 1})
+		_, release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		acquired := make(chan struct{})
+		go func() {
+			_, release, err := l.Acquire(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			release(nil)
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("Acquire() must block while the limit is reached")
+		case <-time.After(50 * time.Millisecond):
+		}
+		release(nil)
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("Acquire() must return after the call is released")
+		}
+	})
+
+	t.Run("returns error when the context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewLimiter(Options{Initial: 1, Max: 1})
+		if _, _, err := l.Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := l.Acquire(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("Acquire() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestLimiter_ForEach(t *testing.T) {
+	t.Parallel()
+
+	t.Run("calls fn for all indexes under the limit", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewLimiter(Options{Initial: 2, Max: 3})
+		var inflight, peak, calls atomic.Int64
+		err := l.ForEach(context.Background(), 20, func(_ context.Context, _ int) error {
+			n := inflight.Add(1)
+			defer inflight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			calls.Add(1)
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := calls.Load(); got != 20 {
+			t.Errorf("calls = %d, want 20", got)
+		}
+		if got := peak.Load(); got > 3 {
+			t.Errorf("peak concurrency = %d, want <= 3", got)
+		}
+	})
+
+	t.Run("returns the first error", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewLimiter(Options{Initial: 1, Max: 1})
+		want := errors.New("some error")
+		err := l.ForEach(context.Background(), 10, func(_ context.Context, i int) error {
+			if i == 3 {
+				return want
+			}
+			return nil
+		})
+		if !errors.Is(err, want) {
+			t.Errorf("ForEach() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("stops starting new calls after the first error even if slots are free", func(t *testing.T) {
+		t.Parallel()
+
+		const limit = 10
+		l := NewLimiter(Options{Initial: limit, Max: limit})
+		want := errors.New("some error")
+		started := make(chan struct{}, limit)
+		var calls atomic.Int64
+		err := l.ForEach(context.Background(), 100, func(ctx context.Context, i int) error {
+			calls.Add(1)
+			if i == 0 {
+				// Fail after the other calls hold all the slots, so every later call is started after the error.
+				for j := 1; j < limit; j++ {
+					<-started
+				}
+				return want
+			}
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		if !errors.Is(err, want) {
+			t.Errorf("ForEach() error = %v, want %v", err, want)
+		}
+		if got := calls.Load(); got != limit {
+			t.Errorf("calls = %d, want %d (%d calls are started after the error)", got, limit, got-limit)
+		}
+	})
+}
+
+func TestNotifyThrottle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("calls the observer in the context", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		NotifyThrottle(WithThrottleObserver(context.Background(), func() { called = true }))
+		if !called {
+			t.Error("the observer must be called")
+		}
+	})
+
+	t.Run("does nothing without the observer", func(t *testing.T) {
+		t.Parallel()
+		NotifyThrottle(context.Background())
+	})
+}
@@ -2,7 +2,11 @@
 package file
 
 import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"io"
 	"io/fs"
+	"os"
 	"path/filepath"
 
 	"github.com/nao1215/spare/utils/errfmt"
@@ -24,3 +28,18 @@ func WalkDir(rootDir string) ([]string, error) {
 	})
 	return files, err
 }
+
+// MD5 returns the MD5 hex digest of the file.
+func MD5(path string) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", errfmt.Wrap(err, "failed to open file")
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := md5.New() //nolint:gosec // MD5 is used only to compare with S3 ETag.
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errfmt.Wrap(err, "failed to read file")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}